@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -104,6 +105,16 @@ func ApiSetup(client *gorm.DB) routes.Api {
 	userService := service.NewUserService(userRepo)
 	userController := controller.NewUserController(userService)
 
+	// exchange rates (used to convert financial reports into each user's reporting currency)
+	exchangeRateRepo := repository.NewExchangeRateRepository(client)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, userRepo)
+	exchangeRateController := controller.NewExchangeRateController(exchangeRateService)
+
+	// tax (rules applied to rent, commission and vendor invoices)
+	taxRepo := repository.NewTaxRepository(client)
+	taxService := service.NewTaxService(taxRepo, exchangeRateService)
+	taxController := controller.NewTaxController(taxService, exchangeRateService)
+
 	// property log
 	propLogRepo := repository.NewPropertyLogRepository(client)
 	propLogService := service.NewPropertyLogService(propLogRepo)
@@ -113,6 +124,7 @@ func ApiSetup(client *gorm.DB) routes.Api {
 	propRepo := repository.NewPropertyRepository(client)
 	propService := service.NewPropertyService(propRepo)
 	propController := controller.NewPropertyController(propService, propLogService)
+	unitRepo := repository.NewUnitRepository(client)
 
 	// property attach
 	objectService := db.NewObjectService()
@@ -135,19 +147,41 @@ func ApiSetup(client *gorm.DB) routes.Api {
 	taskLogService := service.NewTaskLogService(taskLogRepo)
 	taskLogController := controller.NewTaskLogController(taskLogService)
 
-	// task
+	// notifications (delivered in app, by email and by webhook)
 	taskRepo := repository.NewTaskRepository(client)
-	taskService := service.NewTaskService(taskRepo)
+	notificationRepo := repository.NewNotificationRepository(client)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, taskRepo,
+		service.NewInAppNotifier(notificationRepo), service.NewEmailNotifier(), service.NewWebhookNotifier())
+	notificationController := controller.NewNotificationController(notificationService)
+
+	// task comments
+	taskCommentRepo := repository.NewTaskCommentRepository(client)
+	taskCommentService := service.NewTaskCommentService(taskCommentRepo, userService, notificationService)
+	taskCommentController := controller.NewTaskCommentController(taskCommentService)
+
+	// task
+	taskService := service.NewTaskService(taskRepo, notificationService)
 	taskController := controller.NewTaskController(taskService, taskLogService)
 
-	// transaction
+	// transaction (commission calculated on completion, new transactions enter their pipeline)
 	transactionRepo := repository.NewTransactionRepository(client)
-	transactionService := service.NewTransactionService(transactionRepo)
+	commissionRepo := repository.NewCommissionRepository(client)
+	commissionService := service.NewCommissionService(commissionRepo, transactionRepo, userRepo, exchangeRateService, taxService)
+	commissionController := controller.NewCommissionController(commissionService, exchangeRateService)
+	pipelineRepo := repository.NewPipelineRepository(client)
+	transactionService := service.NewTransactionService(transactionRepo, pipelineRepo, commissionService, notificationService, taskRepo, unitRepo)
 	transactionController := controller.NewTransactionController(transactionService)
+	pipelineService := service.NewPipelineService(pipelineRepo, transactionService, exchangeRateService)
+	pipelineController := controller.NewPipelineController(pipelineService, exchangeRateService)
+
+	// Vendors
+	vendorRepo := repository.NewVendorRepository(client)
+	vendorService := service.NewVendorService(vendorRepo)
+	vendorController := controller.NewVendorController(vendorService)
 
 	// Maintenance requests
 	maintenanceRepo := repository.NewMaintenanceRequestRepository(client)
-	maintenanceService := service.NewMaintenanceRequestService(maintenanceRepo)
+	maintenanceService := service.NewMaintenanceRequestService(maintenanceRepo, notificationService, vendorRepo, taskRepo, unitRepo)
 	maintenanceController := controller.NewMaintenanceRequestController(maintenanceService)
 
 	// Work types
@@ -155,12 +189,82 @@ func ApiSetup(client *gorm.DB) routes.Api {
 	workTypeService := service.NewWorkTypeService(workTypeRepo)
 	workTypeController := controller.NewWorkTypeController(workTypeService)
 
-	// Vendors
-	vendorRepo := repository.NewVendorRepository(client)
-	vendorService := service.NewVendorService(vendorRepo)
-	vendorController := controller.NewVendorController(vendorService)
+	// task checklist items
+	taskChecklistItemRepo := repository.NewTaskChecklistItemRepository(client)
+	taskChecklistItemService := service.NewTaskChecklistItemService(taskChecklistItemRepo)
+	taskChecklistItemController := controller.NewTaskChecklistItemController(taskChecklistItemService)
+
+	// task dependencies
+	taskDependencyRepo := repository.NewTaskDependencyRepository(client)
+	taskDependencyService := service.NewTaskDependencyService(taskDependencyRepo, taskRepo)
+	taskDependencyController := controller.NewTaskDependencyController(taskDependencyService)
+
+	// time entries
+	timeEntryRepo := repository.NewTimeEntryRepository(client)
+	timeEntryService := service.NewTimeEntryService(timeEntryRepo, taskRepo)
+	timeEntryController := controller.NewTimeEntryController(timeEntryService)
+
+	// vendor documents
+	vendorDocumentRepo := repository.NewVendorDocumentRepository(client)
+	vendorDocumentService := service.NewVendorDocumentService(vendorDocumentRepo, vendorRepo, userRepo, notificationService, objectService, ioService)
+	vendorDocumentController := controller.NewVendorDocumentController(vendorDocumentService, ioService)
+
+	// vendor quotes
+	vendorQuoteRepo := repository.NewVendorQuoteRepository(client)
+	vendorQuoteService := service.NewVendorQuoteService(vendorQuoteRepo, maintenanceRepo, vendorRepo, propAttachRepo, vendorDocumentService, exchangeRateService)
+	vendorQuoteController := controller.NewVendorQuoteController(vendorQuoteService, exchangeRateService)
+
+	// work orders
+	workOrderRepo := repository.NewWorkOrderRepository(client)
+	workOrderService := service.NewWorkOrderService(workOrderRepo, maintenanceRepo, vendorRepo, taskLogService, vendorDocumentService)
+	workOrderController := controller.NewWorkOrderController(workOrderService)
+
+	// vendor invoices
+	vendorInvoiceRepo := repository.NewVendorInvoiceRepository(client)
+	vendorInvoiceService := service.NewVendorInvoiceService(vendorInvoiceRepo, vendorRepo, workOrderRepo, maintenanceRepo, exchangeRateService, taxService, workOrderService)
+	vendorInvoiceController := controller.NewVendorInvoiceController(vendorInvoiceService, exchangeRateService)
+
+	// vendor ratings
+	vendorRatingRepo := repository.NewVendorRatingRepository(client)
+	vendorRatingService := service.NewVendorRatingService(vendorRatingRepo, vendorRepo, maintenanceRepo, workOrderRepo, vendorQuoteRepo, vendorInvoiceRepo)
+	vendorRatingController := controller.NewVendorRatingController(vendorRatingService)
+
+	// maintenance budgets
+	maintenanceBudgetRepo := repository.NewMaintenanceBudgetRepository(client)
+	maintenanceBudgetService := service.NewMaintenanceBudgetService(maintenanceBudgetRepo, propRepo, workTypeRepo, userRepo, notificationService, exchangeRateService)
+	maintenanceBudgetController := controller.NewMaintenanceBudgetController(maintenanceBudgetService, exchangeRateService)
+
+	// tenant portal
+	tenantPortalService := service.NewTenantPortalService(propRepo, maintenanceRepo, taskRepo, propAttachRepo, userRepo, notificationService, objectService, ioService)
+	tenantPortalController := controller.NewTenantPortalController(tenantPortalService)
+
+	// leases
+	leaseRepo := repository.NewLeaseRepository(client)
+	leaseService := service.NewLeaseService(leaseRepo, propRepo, unitRepo, contactRepo, transactionRepo, userRepo, notificationService)
+	leaseController := controller.NewLeaseController(leaseService)
+
+	// lease ledger
+	ledgerEntryRepo := repository.NewLedgerEntryRepository(client)
+	ledgerEntryService := service.NewLedgerEntryService(ledgerEntryRepo, leaseRepo, exchangeRateService, taxService)
+	ledgerEntryController := controller.NewLedgerEntryController(ledgerEntryService, exchangeRateService)
+
+	ownerStatementRepo := repository.NewOwnerStatementRepository(client)
+	ownerStatementService := service.NewOwnerStatementService(ownerStatementRepo, propRepo, exchangeRateService)
+	ownerStatementController := controller.NewOwnerStatementController(ownerStatementService, exchangeRateService)
+
+	// property units
+	unitService := service.NewUnitService(unitRepo, propRepo, leaseRepo, exchangeRateService)
+	unitController := controller.NewUnitController(unitService, exchangeRateService)
+
+	// Scheduled jobs
+	service.ScheduleJob(app.Ctx, "expired task snoozes", 5*time.Minute, taskService.ProcessExpiredSnoozes)
+	service.ScheduleJob(app.Ctx, "expiring vendor documents", 24*time.Hour, vendorDocumentService.ProcessExpiringDocuments)
+	service.ScheduleJob(app.Ctx, "maintenance budget alerts", time.Hour, maintenanceBudgetService.ProcessBudgetAlerts)
+	service.ScheduleJob(app.Ctx, "lease renewal reminders", 24*time.Hour, leaseService.ProcessRenewalReminders)
+	service.ScheduleJob(app.Ctx, "rent charges and late fees", time.Hour, ledgerEntryService.ProcessRentCharges)
+	service.ScheduleJob(app.Ctx, "owner statements", 24*time.Hour, ownerStatementService.ProcessMonthlyStatements)
 
 	// Build API using controllers
-	api := routes.NewApi(userController, propController, featController, propLogController, contactController, taskController, taskLogController, transactionController, maintenanceController, workTypeController, vendorController, propAttachController)
+	api := routes.NewApi(userController, propController, featController, propLogController, contactController, taskController, taskLogController, transactionController, maintenanceController, workTypeController, vendorController, propAttachController, taskCommentController, notificationController, taskChecklistItemController, taskDependencyController, timeEntryController, vendorQuoteController, workOrderController, vendorInvoiceController, vendorRatingController, vendorDocumentController, maintenanceBudgetController, tenantPortalController, leaseController, ledgerEntryController, ownerStatementController, commissionController, pipelineController, exchangeRateController, taxController, unitController)
 	return api
 }
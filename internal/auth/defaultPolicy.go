@@ -15,6 +15,37 @@ var DefaultPolicyList = []policySet{
 	{
 		subject: "user", object: "/api/me", action: "update",
 	},
+	// api/me/notifications
+	{
+		subject: "user", object: "/api/me/notifications", action: "read",
+	},
+	{
+		subject: "user", object: "/api/me/notifications", action: "update",
+	},
+	{
+		subject: "user", object: "/api/me/notifications", action: "delete",
+	},
+	// api/me/notification-preferences
+	{
+		subject: "user", object: "/api/me/notification-preferences", action: "read",
+	},
+	{
+		subject: "user", object: "/api/me/notification-preferences", action: "update",
+	},
+	// api/me/tasks
+	{
+		subject: "user", object: "/api/me/tasks", action: "read",
+	},
+	// api/me/timer
+	{
+		subject: "user", object: "/api/me/timer", action: "read",
+	},
+	{
+		subject: "user", object: "/api/me/timer/start", action: "create",
+	},
+	{
+		subject: "user", object: "/api/me/timer/stop", action: "create",
+	},
 	// Admin
 	// api/me
 	{
@@ -26,6 +57,37 @@ var DefaultPolicyList = []policySet{
 	{
 		subject: "admin", object: "/api/me", action: "update",
 	},
+	// api/me/notifications
+	{
+		subject: "admin", object: "/api/me/notifications", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/me/notifications", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/me/notifications", action: "delete",
+	},
+	// api/me/notification-preferences
+	{
+		subject: "admin", object: "/api/me/notification-preferences", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/me/notification-preferences", action: "update",
+	},
+	// api/me/tasks
+	{
+		subject: "admin", object: "/api/me/tasks", action: "read",
+	},
+	// api/me/timer
+	{
+		subject: "admin", object: "/api/me/timer", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/me/timer/start", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/me/timer/stop", action: "create",
+	},
 	// api/users
 	{
 		subject: "admin", object: "/api/users", action: "create",
@@ -53,12 +115,37 @@ var DefaultPolicyList = []policySet{
 	{
 		subject: "admin", object: "/api/properties", action: "delete",
 	},
+	{
+		subject: "admin", object: "/api/properties/portal-token", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/properties/occupancy", action: "read",
+	},
 	// user
 
 	{
 		subject: "user", object: "/api/properties", action: "read",
 	},
 
+	// api/units
+	// admin
+	{
+		subject: "admin", object: "/api/units", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/units", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/units", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/units", action: "delete",
+	},
+	// user
+	{
+		subject: "user", object: "/api/units", action: "read",
+	},
+
 	// api/features
 	// admin
 	{
@@ -123,6 +210,18 @@ var DefaultPolicyList = []policySet{
 	{
 		subject: "admin", object: "/api/tasks", action: "delete",
 	},
+	{
+		subject: "admin", object: "/api/tasks/board", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/tasks/board", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/tasks/with-transaction", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/tasks/with-maintenance", action: "create",
+	},
 
 	// api/task-logs
 	// admin
@@ -139,6 +238,75 @@ var DefaultPolicyList = []policySet{
 		subject: "admin", object: "/api/task-logs", action: "delete",
 	},
 
+	// api/task-checklist-items
+	// admin
+	{
+		subject: "admin", object: "/api/task-checklist-items", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/task-checklist-items", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/task-checklist-items", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/task-checklist-items", action: "delete",
+	},
+
+	// api/task-dependencies
+	// admin
+	{
+		subject: "admin", object: "/api/task-dependencies", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/task-dependencies", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/task-dependencies", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/task-dependencies/graph", action: "read",
+	},
+
+	// api/time-entries
+	// admin
+	{
+		subject: "admin", object: "/api/time-entries", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/time-entries", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/time-entries", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/time-entries", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/time-entries/rollup", action: "read",
+	},
+
+	// api/task-comments
+	// admin
+	{
+		subject: "admin", object: "/api/task-comments", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/task-comments", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/task-comments", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/task-comments", action: "delete",
+	},
+
+	// api/notification-dead-letters
+	// admin
+	{
+		subject: "admin", object: "/api/notification-dead-letters", action: "read",
+	},
+
 	// api/transactions
 	// admin
 	{
@@ -168,6 +336,9 @@ var DefaultPolicyList = []policySet{
 	{
 		subject: "admin", object: "/api/maintenance", action: "delete",
 	},
+	{
+		subject: "admin", object: "/api/maintenance/suggested-vendors", action: "read",
+	},
 
 	// api/work-types
 	// admin
@@ -199,6 +370,288 @@ var DefaultPolicyList = []policySet{
 		subject: "admin", object: "/api/vendors", action: "delete",
 	},
 
+	// api/vendor-quotes
+	// admin
+	{
+		subject: "admin", object: "/api/vendor-quotes", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/vendor-quotes", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/vendor-quotes", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/vendor-quotes", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/vendor-quotes/compare", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/vendor-quotes/accept", action: "create",
+	},
+
+	// api/work-orders
+	// admin
+	{
+		subject: "admin", object: "/api/work-orders", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/work-orders", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/work-orders", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/work-orders", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/work-orders/status", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/work-orders/document", action: "read",
+	},
+
+	// api/vendor-invoices
+	// admin
+	{
+		subject: "admin", object: "/api/vendor-invoices", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/vendor-invoices", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/vendor-invoices", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/vendor-invoices", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/vendor-invoices/payments", action: "create",
+	},
+
+	// api/payables
+	// admin
+	{
+		subject: "admin", object: "/api/payables", action: "read",
+	},
+
+	// api/vendor-ratings
+	// admin
+	{
+		subject: "admin", object: "/api/vendor-ratings", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/vendor-ratings", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/vendor-ratings", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/vendor-ratings", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/vendors/scorecard", action: "read",
+	},
+
+	// api/vendor-documents
+	// admin
+	{
+		subject: "admin", object: "/api/vendor-documents", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/vendor-documents", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/vendor-documents", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/vendor-documents", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/vendor-documents/file", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/vendor-documents/file", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/vendors/compliance", action: "read",
+	},
+
+	// api/maintenance-budgets
+	// admin
+	{
+		subject: "admin", object: "/api/maintenance-budgets", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/maintenance-budgets", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/maintenance-budgets", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/maintenance-budgets", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/maintenance-budgets/report", action: "read",
+	},
+
+	// api/leases
+	// admin
+	{
+		subject: "admin", object: "/api/leases", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/leases", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/leases", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/leases", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/leases/renew", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/leases/statement", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/leases/arrears", action: "read",
+	},
+
+	// api/ledger-entries
+	// admin
+	{
+		subject: "admin", object: "/api/ledger-entries", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/ledger-entries", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/ledger-entries", action: "delete",
+	},
+
+	// api/owner-statements
+	// admin
+	{
+		subject: "admin", object: "/api/owner-statements", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/owner-statements", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/owner-statements", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/owner-statements", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/owner-statements/finalise", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/owner-statements/pdf", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/owner-statements/csv", action: "read",
+	},
+
+	// api/commission-schemes
+	// admin
+	{
+		subject: "admin", object: "/api/commission-schemes", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/commission-schemes", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/commission-schemes", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/commission-schemes", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/transactions/commission-splits", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/commissions/report", action: "read",
+	},
+
+	// api/pipeline-stages
+	// admin
+	{
+		subject: "admin", object: "/api/pipeline-stages", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/pipeline-stages", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/pipeline-stages", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/pipeline-stages", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/transactions/stage", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/transactions/pipeline", action: "read",
+	},
+
+	// api/transaction-milestones
+	// admin
+	{
+		subject: "admin", object: "/api/transaction-milestones", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/transaction-milestones", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/transaction-milestones", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/transaction-milestones", action: "delete",
+	},
+
+	// api/exchange-rates
+	// admin
+	{
+		subject: "admin", object: "/api/exchange-rates", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/exchange-rates", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/exchange-rates", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/exchange-rates", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/exchange-rates/import", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/exchange-rates/convert", action: "read",
+	},
+
+	// api/tax-rules
+	// admin
+	{
+		subject: "admin", object: "/api/tax-rules", action: "create",
+	},
+	{
+		subject: "admin", object: "/api/tax-rules", action: "read",
+	},
+	{
+		subject: "admin", object: "/api/tax-rules", action: "update",
+	},
+	{
+		subject: "admin", object: "/api/tax-rules", action: "delete",
+	},
+	{
+		subject: "admin", object: "/api/tax/summary", action: "read",
+	},
+
 	// api/property-attachments
 	// admin
 	{
@@ -0,0 +1,359 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type CommissionController interface {
+	FindAllSchemes(w http.ResponseWriter, r *http.Request)
+	FindScheme(w http.ResponseWriter, r *http.Request)
+	CreateScheme(w http.ResponseWriter, r *http.Request)
+	UpdateScheme(w http.ResponseWriter, r *http.Request)
+	DeleteScheme(w http.ResponseWriter, r *http.Request)
+	SetSplits(w http.ResponseWriter, r *http.Request)
+	Report(w http.ResponseWriter, r *http.Request)
+}
+
+type commissionController struct {
+	service service.CommissionService
+	rates   service.ExchangeRateService
+}
+
+func NewCommissionController(service service.CommissionService, rates service.ExchangeRateService) CommissionController {
+	return &commissionController{service, rates}
+}
+
+// API/COMMISSION-SCHEMES
+// Find a list of commission schemes
+// @Summary      Find a list of commission schemes
+// @Description  Accepts limit, offset and order params and returns list of commission schemes (by name by default)
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Success      200 {object} []db.CommissionScheme
+// @Failure      400 {string} string "Can't find commission schemes"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /commission-schemes [get]
+// @Security BearerToken
+func (c commissionController) FindAllSchemes(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all commission schemes using query params
+	foundSchemes, err := c.service.FindAllSchemes(limit, offset, orderBy)
+	if err != nil {
+		http.Error(w, "Can't find commission schemes", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundSchemes)
+	if err != nil {
+		http.Error(w, "Can't find commission schemes", http.StatusBadRequest)
+		fmt.Println("error writing commission schemes to response: ", err)
+		return
+	}
+}
+
+// Find a created commission scheme
+// @Summary      Find commission scheme
+// @Description  Find a commission scheme with its tiers by ID
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Commission Scheme ID"
+// @Success      200 {object} db.CommissionScheme
+// @Failure      400 {string} string "Can't find commission scheme with ID: {id}"
+// @Router       /commission-schemes/{id} [get]
+// @Security BearerToken
+func (c commissionController) FindScheme(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundScheme, err := c.service.FindSchemeById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find commission scheme with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundScheme)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find commission scheme with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new commission scheme
+// @Summary      Create commission scheme
+// @Description  Creates a percentage, tiered or flat commission scheme. A scheme that is the default for a transaction type replaces the previous default
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        scheme body models.CreateCommissionScheme true "New Commission Scheme Json"
+// @Success      201 {object} db.CommissionScheme
+// @Failure      400 {string} string "Commission scheme creation failed."
+// @Router       /commission-schemes [post]
+// @Security BearerToken
+func (c commissionController) CreateScheme(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var scheme models.CreateCommissionScheme
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&scheme)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&scheme)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create commission scheme in db
+	createdScheme, createErr := c.service.CreateScheme(&scheme)
+	if createErr != nil {
+		http.Error(w, "Commission scheme creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created scheme to output
+	err = helpers.WriteAsJSON(w, createdScheme)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a commission scheme (using URL parameter id)
+// @Summary      Update commission scheme
+// @Description  Updates an existing commission scheme, replacing its tiers if provided. Completed transactions keep their calculated commission until they are next updated
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Commission Scheme ID"
+// @Param        scheme body models.UpdateCommissionScheme true "Update Commission Scheme Json"
+// @Success      200 {object} db.CommissionScheme
+// @Failure      400 {string} string "Failed commission scheme update"
+// @Router       /commission-schemes/{id} [put]
+// @Security BearerToken
+func (c commissionController) UpdateScheme(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var scheme models.UpdateCommissionScheme
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&scheme)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&scheme)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update commission scheme
+	updatedScheme, err := c.service.UpdateScheme(idParameter, &scheme)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed commission scheme update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated scheme to output
+	err = helpers.WriteAsJSON(w, updatedScheme)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed commission scheme update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete commission scheme (using URL parameter id)
+// @Summary      Delete commission scheme
+// @Description  Deletes a commission scheme that no transactions are calculated with
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Commission Scheme ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed commission scheme deletion"
+// @Failure      409 {string} string "Commission scheme is used by transactions"
+// @Router       /commission-schemes/{id} [delete]
+// @Security BearerToken
+func (c commissionController) DeleteScheme(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete commission scheme using id
+	err := c.service.DeleteScheme(idParameter)
+
+	// If error detected
+	if err != nil {
+		if errors.Is(err, service.ErrCommissionSchemeInUse) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed commission scheme deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/TRANSACTIONS/COMMISSION-SPLITS
+// Set the agents sharing a transaction's commission (using URL parameter id)
+// @Summary      Set commission splits
+// @Description  Replaces the agents sharing the agency's commission on a transaction. Shares must total 100 percent. Amounts are calculated once the transaction is completed
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Transaction ID"
+// @Param        splits body models.SetCommissionSplits true "Commission Splits Json"
+// @Success      200 {object} db.Transaction
+// @Failure      400 {string} string "Failed setting commission splits"
+// @Router       /transactions/commission-splits/{id} [put]
+// @Security BearerToken
+func (c commissionController) SetSplits(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var splits models.SetCommissionSplits
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&splits)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&splits)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	updatedTransaction, err := c.service.SetSplits(idParameter, &splits)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed setting commission splits: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated transaction to output
+	err = helpers.WriteAsJSON(w, updatedTransaction)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed setting commission splits: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// API/COMMISSIONS/REPORT
+// Commission earned per agent and month
+// @Summary      Commission report
+// @Description  Returns the commission of transactions completed between from and to (YYYY-MM-DD, both inclusive) per agent and month, with agency and co-agency totals. Defaults to the start of the year until today
+// @Tags         Commissions
+// @Accept       json
+// @Produce      json
+// @Param        from   path      string  false  "first completion date"
+// @Param        to   path      string  false  "last completion date"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.CommissionReport
+// @Failure      400 {string} string "Can't produce commission report"
+// @Router       /commissions/report [get]
+// @Security BearerToken
+func (c commissionController) Report(w http.ResponseWriter, r *http.Request) {
+	var err error
+	// Report period
+	now := time.Now()
+	from := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		from, err = time.ParseInLocation("2006-01-02", fromParam, time.Local)
+		if err != nil {
+			http.Error(w, "From must be a date formatted YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		to, err = time.ParseInLocation("2006-01-02", toParam, time.Local)
+		if err != nil {
+			http.Error(w, "To must be a date formatted YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	// Include transactions completed on the last day
+	to = to.AddDate(0, 0, 1)
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.Report(from, to, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce commission report: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, report)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce commission report: %v", err), http.StatusBadRequest)
+		return
+	}
+}
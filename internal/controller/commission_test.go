@@ -0,0 +1,124 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestCommissionController_SchemesSplitsAndReport(t *testing.T) {
+	// Test setup
+	property := db.Property{Property_Name: "commissionProperty1", Postcode: 80361, Suburb: "Seminyak", City: "Badung", Street_Address_1: "Jl. Kayu Aya", Bedrooms: 4, Bathrooms: 4, Description: "Villa"}
+	testConnection.dbClient.Create(&property)
+	task := db.Task{TaskName: "Sell the Seminyak villa", Type: "Sale"}
+	testConnection.dbClient.Create(&task)
+	tiers := []models.CommissionTier{{UpTo: db.NewMoney(1000000000, "IDR"), Rate: 3}, {UpTo: db.NewMoney(5000000000, "IDR"), Rate: 2.5}, {Rate: 2}}
+
+	var createTests = []struct {
+		data                   models.CreateCommissionScheme
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateCommissionScheme{Name: "Villa sales", Method: "Tiered", Tiers: tiers, CoAgencySplit: 50, DefaultFor: "Sale"}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateCommissionScheme{Name: "Villa sales", Method: "Tiered", CoAgencySplit: 50, DefaultFor: "Sale"}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin tiered without tiers fail test"},
+		{models.CreateCommissionScheme{Name: "Villa sales", Method: "Tiered", Tiers: tiers[:2], DefaultFor: "Sale"}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin tiered without top band fail test"},
+		{models.CreateCommissionScheme{Name: "Leases", Method: "Percentage", DefaultFor: "Lease"}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin percentage without rate fail test"},
+		{models.CreateCommissionScheme{Name: "Villa sales", Method: "Tiered", Tiers: tiers, CoAgencySplit: 50, DefaultFor: "Sale"}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+	}
+
+	var scheme db.CommissionScheme
+	for _, v := range createTests {
+		// Make new request with commission scheme creation in body
+		req, err := http.NewRequest("POST", "/api/commission-schemes", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Commission scheme create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &scheme)
+		}
+	}
+	if len(scheme.Tiers) != 3 {
+		t.Fatalf("Commission scheme create: expected 3 tiers, got %v", scheme)
+	}
+
+	// New sales use the default scheme. The sale is through a co-agency, who share the commission
+	transaction, err := testConnection.transactions.serv.Create(&models.CreateTransaction{Type: "Sale", Agency: "Other", AgencyName: "Ray White", TransactionValue: db.NewMoney(6000000000, "IDR"), Property: property, Task: task})
+	if err != nil {
+		t.Fatalf("Transaction create failed: %v", err)
+	}
+	if transaction.CommissionSchemeID == nil || *transaction.CommissionSchemeID != scheme.ID {
+		t.Errorf("Transaction create: expected default commission scheme %v, got %v", scheme.ID, transaction.CommissionSchemeID)
+	}
+
+	// Agents share the agency's commission
+	admin, user := testConnection.accounts.admin.details, testConnection.accounts.user.details
+	rr := serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/commission-splits/%v", transaction.ID), models.SetCommissionSplits{Splits: []models.CommissionSplitShare{{User: *admin, Share: 60}, {User: *user, Share: 30}}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Commission splits under 100 percent: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/commission-splits/%v", transaction.ID), models.SetCommissionSplits{Splits: []models.CommissionSplitShare{{User: *admin, Share: 60}, {User: *user, Share: 40}}})
+	if rr.Code != http.StatusOK {
+		t.Errorf("Commission splits: got %v want %v. %v", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	// Commission is calculated on completion: 3% of the first 1bn, 2.5% of the next 4bn and 2% of the last 1bn
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/%v", transaction.ID), models.UpdateTransaction{TransactionCompletion: time.Now()})
+	var completed db.Transaction
+	json.Unmarshal(rr.Body.Bytes(), &completed)
+	if rr.Code != http.StatusOK || completed.Fee.Float() != 150000000 || completed.CoAgencyFee.Float() != 75000000 || completed.OwnAgencyFee.Float() != 75000000 || len(completed.CommissionSplits) != 2 {
+		t.Fatalf("Transaction completion: expected 150000000 commission shared with the co-agency, got %v %v", rr.Code, rr.Body.String())
+	}
+	for _, split := range completed.CommissionSplits {
+		if split.UserID == admin.ID && split.Amount.Float() != 45000000 || split.UserID == user.ID && split.Amount.Float() != 30000000 {
+			t.Errorf("Commission split: expected 45000000 to the admin and 30000000 to the user, got %+v", split)
+		}
+	}
+
+	// Commission per agent and month
+	today := time.Now().Format("2006-01-02")
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/commissions/report?from=%s&to=%s", today, today), nil)
+	var report models.CommissionReport
+	json.Unmarshal(rr.Body.Bytes(), &report)
+	found := 0
+	for _, agent := range report.Agents {
+		if agent.UserID == admin.ID && agent.Amount.Float() == 45000000 || agent.UserID == user.ID && agent.Amount.Float() == 30000000 {
+			found++
+		}
+	}
+	if rr.Code != http.StatusOK || found != 2 || report.CoAgencyFees.Float() < 75000000 {
+		t.Errorf("Commission report: expected both agents' commission, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Schemes used by transactions can't be deleted
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/commission-schemes/%v", scheme.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Delete commission scheme in use: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	// Cleanup
+	testConnection.dbClient.Where("transaction_id = ?", transaction.ID).Delete(&db.CommissionSplit{})
+	testConnection.dbClient.Unscoped().Delete(transaction)
+	testConnection.dbClient.Where("commission_scheme_id = ?", scheme.ID).Delete(&db.CommissionTier{})
+	testConnection.dbClient.Unscoped().Delete(&scheme)
+	testConnection.dbClient.Unscoped().Delete(&task)
+	testConnection.dbClient.Unscoped().Delete(&property)
+}
@@ -45,6 +45,26 @@ type TestDbRepo struct {
 	workTypes           workTypeDB
 	vendors             vendorDB
 	propertyAttachments propertyAttachmentDB
+	taskComments        taskCommentDB
+	notifications       notificationDB
+	taskChecklistItems  taskChecklistItemDB
+	taskDependencies    taskDependencyDB
+	timeEntries         timeEntryDB
+	vendorQuotes        vendorQuoteDB
+	workOrders          workOrderDB
+	vendorInvoices      vendorInvoiceDB
+	vendorRatings       vendorRatingDB
+	vendorDocuments     vendorDocumentDB
+	maintenanceBudgets  maintenanceBudgetDB
+	tenantPortal        tenantPortalDB
+	leases              leaseDB
+	ledgerEntries       ledgerEntryDB
+	ownerStatements     ownerStatementDB
+	commissions         commissionDB
+	pipelines           pipelineDB
+	exchangeRates       exchangeRateDB
+	taxes               taxDB
+	units               unitDB
 	ioService           helpers.FileIO
 	router              http.Handler
 	// For authentication mocking
@@ -100,6 +120,18 @@ type taskLogDB struct {
 	cont controller.TaskLogController
 }
 
+type taskCommentDB struct {
+	repo repository.TaskCommentRepository
+	serv service.TaskCommentService
+	cont controller.TaskCommentController
+}
+
+type notificationDB struct {
+	repo repository.NotificationRepository
+	serv service.NotificationService
+	cont controller.NotificationController
+}
+
 // Types of tasks
 // Transactions
 type transactionDB struct {
@@ -127,6 +159,113 @@ type workTypeDB struct {
 	cont controller.WorkTypeController
 }
 
+type taskChecklistItemDB struct {
+	repo repository.TaskChecklistItemRepository
+	serv service.TaskChecklistItemService
+	cont controller.TaskChecklistItemController
+}
+
+type taskDependencyDB struct {
+	repo repository.TaskDependencyRepository
+	serv service.TaskDependencyService
+	cont controller.TaskDependencyController
+}
+
+type timeEntryDB struct {
+	repo repository.TimeEntryRepository
+	serv service.TimeEntryService
+	cont controller.TimeEntryController
+}
+
+type vendorQuoteDB struct {
+	repo repository.VendorQuoteRepository
+	serv service.VendorQuoteService
+	cont controller.VendorQuoteController
+}
+
+type workOrderDB struct {
+	repo repository.WorkOrderRepository
+	serv service.WorkOrderService
+	cont controller.WorkOrderController
+}
+
+type vendorInvoiceDB struct {
+	repo repository.VendorInvoiceRepository
+	serv service.VendorInvoiceService
+	cont controller.VendorInvoiceController
+}
+
+type vendorRatingDB struct {
+	repo repository.VendorRatingRepository
+	serv service.VendorRatingService
+	cont controller.VendorRatingController
+}
+
+type vendorDocumentDB struct {
+	repo repository.VendorDocumentRepository
+	serv service.VendorDocumentService
+	cont controller.VendorDocumentController
+}
+
+type maintenanceBudgetDB struct {
+	repo repository.MaintenanceBudgetRepository
+	serv service.MaintenanceBudgetService
+	cont controller.MaintenanceBudgetController
+}
+
+type tenantPortalDB struct {
+	serv service.TenantPortalService
+	cont controller.TenantPortalController
+}
+
+type leaseDB struct {
+	repo repository.LeaseRepository
+	serv service.LeaseService
+	cont controller.LeaseController
+}
+
+type ledgerEntryDB struct {
+	repo repository.LedgerEntryRepository
+	serv service.LedgerEntryService
+	cont controller.LedgerEntryController
+}
+
+type ownerStatementDB struct {
+	repo repository.OwnerStatementRepository
+	serv service.OwnerStatementService
+	cont controller.OwnerStatementController
+}
+
+type commissionDB struct {
+	repo repository.CommissionRepository
+	serv service.CommissionService
+	cont controller.CommissionController
+}
+
+type pipelineDB struct {
+	repo repository.PipelineRepository
+	serv service.PipelineService
+	cont controller.PipelineController
+}
+
+type exchangeRateDB struct {
+	repo repository.ExchangeRateRepository
+	serv service.ExchangeRateService
+	cont controller.ExchangeRateController
+}
+
+type taxDB struct {
+	repo repository.TaxRepository
+	serv service.TaxService
+	cont controller.TaxController
+}
+
+type unitDB struct {
+	repo repository.UnitRepository
+	serv service.UnitService
+	cont controller.UnitController
+}
+
 // Account structures
 type userAccounts struct {
 	admin dummyAccount
@@ -178,6 +317,26 @@ func (t TestDbRepo) buildAPI() http.Handler {
 		t.workTypes.cont,
 		t.vendors.cont,
 		t.propertyAttachments.cont,
+		t.taskComments.cont,
+		t.notifications.cont,
+		t.taskChecklistItems.cont,
+		t.taskDependencies.cont,
+		t.timeEntries.cont,
+		t.vendorQuotes.cont,
+		t.workOrders.cont,
+		t.vendorInvoices.cont,
+		t.vendorRatings.cont,
+		t.vendorDocuments.cont,
+		t.maintenanceBudgets.cont,
+		t.tenantPortal.cont,
+		t.leases.cont,
+		t.ledgerEntries.cont,
+		t.ownerStatements.cont,
+		t.commissions.cont,
+		t.pipelines.cont,
+		t.exchangeRates.cont,
+		t.taxes.cont,
+		t.units.cont,
 	)
 	// Extract handlers from api
 	handler := api.Routes()
@@ -215,6 +374,15 @@ func (t *TestDbRepo) setupDBAuthAppModels() {
 	t.users.repo = repository.NewUserRepository(t.dbClient)
 	t.users.serv = service.NewUserService(t.users.repo)
 	t.users.cont = controller.NewUserController(t.users.serv)
+	// Exchange rates
+	t.exchangeRates.repo = repository.NewExchangeRateRepository(t.dbClient)
+	t.exchangeRates.serv = service.NewExchangeRateService(t.exchangeRates.repo, t.users.repo)
+	t.exchangeRates.cont = controller.NewExchangeRateController(t.exchangeRates.serv)
+
+	// Tax
+	t.taxes.repo = repository.NewTaxRepository(t.dbClient)
+	t.taxes.serv = service.NewTaxService(t.taxes.repo, t.exchangeRates.serv)
+	t.taxes.cont = controller.NewTaxController(t.taxes.serv, t.exchangeRates.serv)
 	// Property Logs
 	t.propertyLogs.repo = repository.NewPropertyLogRepository(t.dbClient)
 	t.propertyLogs.serv = service.NewPropertyLogService(t.propertyLogs.repo)
@@ -223,6 +391,7 @@ func (t *TestDbRepo) setupDBAuthAppModels() {
 	t.properties.repo = repository.NewPropertyRepository(t.dbClient)
 	t.properties.serv = service.NewPropertyService(t.properties.repo)
 	t.properties.cont = controller.NewPropertyController(t.properties.serv, t.propertyLogs.serv)
+	t.units.repo = repository.NewUnitRepository(t.dbClient)
 	// Propety Attachments
 	t.propertyAttachments.repo = repository.NewPropertyAttachmentRepository(t.dbClient)
 	t.propertyAttachments.serv = service.NewPropertyAttachmentService(t.propertyAttachments.repo, mockObjectStorage{}, t.ioService)
@@ -241,19 +410,42 @@ func (t *TestDbRepo) setupDBAuthAppModels() {
 	t.taskLogs.serv = service.NewTaskLogService(t.taskLogs.repo)
 	t.taskLogs.cont = controller.NewTaskLogController(t.taskLogs.serv)
 
+	// Notifications (in app only for tests)
+	t.notifications.repo = repository.NewNotificationRepository(t.dbClient)
+	t.notifications.serv = service.NewNotificationService(t.notifications.repo, t.users.repo, repository.NewTaskRepository(t.dbClient), service.NewInAppNotifier(t.notifications.repo))
+	t.notifications.cont = controller.NewNotificationController(t.notifications.serv)
+
+	// Task comments
+	t.taskComments.repo = repository.NewTaskCommentRepository(t.dbClient)
+	t.taskComments.serv = service.NewTaskCommentService(t.taskComments.repo, t.users.serv, t.notifications.serv)
+	t.taskComments.cont = controller.NewTaskCommentController(t.taskComments.serv)
+
 	// Tasks
 	t.tasks.repo = repository.NewTaskRepository(t.dbClient)
-	t.tasks.serv = service.NewTaskService(t.tasks.repo)
+	t.tasks.serv = service.NewTaskService(t.tasks.repo, t.notifications.serv)
 	t.tasks.cont = controller.NewTaskController(t.tasks.serv, t.taskLogs.serv)
 
 	// Transactions
 	t.transactions.repo = repository.NewTransactionRepository(t.dbClient)
-	t.transactions.serv = service.NewTransactionService(t.transactions.repo)
+	// Commissions
+	t.commissions.repo = repository.NewCommissionRepository(t.dbClient)
+	t.commissions.serv = service.NewCommissionService(t.commissions.repo, t.transactions.repo, t.users.repo, t.exchangeRates.serv, t.taxes.serv)
+	t.commissions.cont = controller.NewCommissionController(t.commissions.serv, t.exchangeRates.serv)
+	t.pipelines.repo = repository.NewPipelineRepository(t.dbClient)
+	t.transactions.serv = service.NewTransactionService(t.transactions.repo, t.pipelines.repo, t.commissions.serv, t.notifications.serv, t.tasks.repo, t.units.repo)
 	t.transactions.cont = controller.NewTransactionController(t.transactions.serv)
+	// Pipelines
+	t.pipelines.serv = service.NewPipelineService(t.pipelines.repo, t.transactions.serv, t.exchangeRates.serv)
+	t.pipelines.cont = controller.NewPipelineController(t.pipelines.serv, t.exchangeRates.serv)
+
+	// Vendors
+	t.vendors.repo = repository.NewVendorRepository(t.dbClient)
+	t.vendors.serv = service.NewVendorService(t.vendors.repo)
+	t.vendors.cont = controller.NewVendorController(t.vendors.serv)
 
 	// Maintenance Requests
 	t.maintenanceRequests.repo = repository.NewMaintenanceRequestRepository(t.dbClient)
-	t.maintenanceRequests.serv = service.NewMaintenanceRequestService(t.maintenanceRequests.repo)
+	t.maintenanceRequests.serv = service.NewMaintenanceRequestService(t.maintenanceRequests.repo, t.notifications.serv, t.vendors.repo, t.tasks.repo, t.units.repo)
 	t.maintenanceRequests.cont = controller.NewMaintenanceRequestController(t.maintenanceRequests.serv)
 
 	// Work Types
@@ -261,10 +453,72 @@ func (t *TestDbRepo) setupDBAuthAppModels() {
 	t.workTypes.serv = service.NewWorkTypeService(t.workTypes.repo)
 	t.workTypes.cont = controller.NewWorkTypeController(t.workTypes.serv)
 
-	// Vendors
-	t.vendors.repo = repository.NewVendorRepository(t.dbClient)
-	t.vendors.serv = service.NewVendorService(t.vendors.repo)
-	t.vendors.cont = controller.NewVendorController(t.vendors.serv)
+	// Task checklist items
+	t.taskChecklistItems.repo = repository.NewTaskChecklistItemRepository(t.dbClient)
+	t.taskChecklistItems.serv = service.NewTaskChecklistItemService(t.taskChecklistItems.repo)
+	t.taskChecklistItems.cont = controller.NewTaskChecklistItemController(t.taskChecklistItems.serv)
+
+	// Task dependencies
+	t.taskDependencies.repo = repository.NewTaskDependencyRepository(t.dbClient)
+	t.taskDependencies.serv = service.NewTaskDependencyService(t.taskDependencies.repo, t.tasks.repo)
+	t.taskDependencies.cont = controller.NewTaskDependencyController(t.taskDependencies.serv)
+
+	// Time entries
+	t.timeEntries.repo = repository.NewTimeEntryRepository(t.dbClient)
+	t.timeEntries.serv = service.NewTimeEntryService(t.timeEntries.repo, t.tasks.repo)
+	t.timeEntries.cont = controller.NewTimeEntryController(t.timeEntries.serv)
+
+	// Vendor documents
+	t.vendorDocuments.repo = repository.NewVendorDocumentRepository(t.dbClient)
+	t.vendorDocuments.serv = service.NewVendorDocumentService(t.vendorDocuments.repo, t.vendors.repo, t.users.repo, t.notifications.serv, mockObjectStorage{}, t.ioService)
+	t.vendorDocuments.cont = controller.NewVendorDocumentController(t.vendorDocuments.serv, t.ioService)
+
+	// Vendor quotes
+	t.vendorQuotes.repo = repository.NewVendorQuoteRepository(t.dbClient)
+	t.vendorQuotes.serv = service.NewVendorQuoteService(t.vendorQuotes.repo, t.maintenanceRequests.repo, t.vendors.repo, t.propertyAttachments.repo, t.vendorDocuments.serv, t.exchangeRates.serv)
+	t.vendorQuotes.cont = controller.NewVendorQuoteController(t.vendorQuotes.serv, t.exchangeRates.serv)
+
+	// Work orders
+	t.workOrders.repo = repository.NewWorkOrderRepository(t.dbClient)
+	t.workOrders.serv = service.NewWorkOrderService(t.workOrders.repo, t.maintenanceRequests.repo, t.vendors.repo, t.taskLogs.serv, t.vendorDocuments.serv)
+	t.workOrders.cont = controller.NewWorkOrderController(t.workOrders.serv)
+
+	// Vendor invoices
+	t.vendorInvoices.repo = repository.NewVendorInvoiceRepository(t.dbClient)
+	t.vendorInvoices.serv = service.NewVendorInvoiceService(t.vendorInvoices.repo, t.vendors.repo, t.workOrders.repo, t.maintenanceRequests.repo, t.exchangeRates.serv, t.taxes.serv, t.workOrders.serv)
+	t.vendorInvoices.cont = controller.NewVendorInvoiceController(t.vendorInvoices.serv, t.exchangeRates.serv)
+
+	// Vendor ratings
+	t.vendorRatings.repo = repository.NewVendorRatingRepository(t.dbClient)
+	t.vendorRatings.serv = service.NewVendorRatingService(t.vendorRatings.repo, t.vendors.repo, t.maintenanceRequests.repo, t.workOrders.repo, t.vendorQuotes.repo, t.vendorInvoices.repo)
+	t.vendorRatings.cont = controller.NewVendorRatingController(t.vendorRatings.serv)
+
+	// Maintenance budgets
+	t.maintenanceBudgets.repo = repository.NewMaintenanceBudgetRepository(t.dbClient)
+	t.maintenanceBudgets.serv = service.NewMaintenanceBudgetService(t.maintenanceBudgets.repo, t.properties.repo, t.workTypes.repo, t.users.repo, t.notifications.serv, t.exchangeRates.serv)
+	t.maintenanceBudgets.cont = controller.NewMaintenanceBudgetController(t.maintenanceBudgets.serv, t.exchangeRates.serv)
+
+	// Tenant portal
+	t.tenantPortal.serv = service.NewTenantPortalService(t.properties.repo, t.maintenanceRequests.repo, t.tasks.repo, t.propertyAttachments.repo, t.users.repo, t.notifications.serv, mockObjectStorage{}, t.ioService)
+	t.tenantPortal.cont = controller.NewTenantPortalController(t.tenantPortal.serv)
+
+	// Leases
+	t.leases.repo = repository.NewLeaseRepository(t.dbClient)
+	t.leases.serv = service.NewLeaseService(t.leases.repo, t.properties.repo, t.units.repo, t.contacts.repo, t.transactions.repo, t.users.repo, t.notifications.serv)
+	t.leases.cont = controller.NewLeaseController(t.leases.serv)
+
+	// Lease ledger
+	t.ledgerEntries.repo = repository.NewLedgerEntryRepository(t.dbClient)
+	t.ledgerEntries.serv = service.NewLedgerEntryService(t.ledgerEntries.repo, t.leases.repo, t.exchangeRates.serv, t.taxes.serv)
+	t.ledgerEntries.cont = controller.NewLedgerEntryController(t.ledgerEntries.serv, t.exchangeRates.serv)
+
+	t.ownerStatements.repo = repository.NewOwnerStatementRepository(t.dbClient)
+	t.ownerStatements.serv = service.NewOwnerStatementService(t.ownerStatements.repo, t.properties.repo, t.exchangeRates.serv)
+	t.ownerStatements.cont = controller.NewOwnerStatementController(t.ownerStatements.serv, t.exchangeRates.serv)
+
+	// Units
+	t.units.serv = service.NewUnitService(t.units.repo, t.properties.repo, t.leases.repo, t.exchangeRates.serv)
+	t.units.cont = controller.NewUnitController(t.units.serv, t.exchangeRates.serv)
 
 	// Setup the enforcer for usage as middleware
 	setupTestEnforcer(t.dbClient)
@@ -279,7 +533,7 @@ func setupDatabase() *gorm.DB {
 	}
 
 	// Migrate the database schema
-	if err := dbClient.AutoMigrate(&db.User{}, &db.Property{}, &db.PropertyAttachment{}, &db.Feature{}, &db.PropertyLog{}, &db.Contact{}, &db.Task{}, &db.TaskLog{}, &db.Transaction{}, db.MaintenanceRequest{}, db.WorkType{}, db.Vendor{}); err != nil {
+	if err := dbClient.AutoMigrate(&db.User{}, &db.Property{}, &db.PropertyAttachment{}, &db.Feature{}, &db.Unit{}, &db.PropertyLog{}, &db.Contact{}, &db.Task{}, &db.TaskLog{}, &db.TaskChecklistItem{}, &db.Transaction{}, db.MaintenanceRequest{}, db.WorkType{}, db.Vendor{}, &db.TaskComment{}, &db.TaskCommentEdit{}, &db.Notification{}, &db.NotificationPreference{}, &db.NotificationDeadLetter{}, &db.TaskDependency{}, &db.TimeEntry{}, &db.VendorQuote{}, &db.WorkOrder{}, &db.VendorInvoice{}, &db.VendorInvoiceLine{}, &db.VendorPayment{}, &db.VendorRating{}, &db.VendorDocument{}, &db.MaintenanceBudget{}, &db.Lease{}, &db.RentScheduleItem{}, &db.LedgerEntry{}, &db.OwnerStatement{}, &db.OwnerStatementLine{}, &db.CommissionScheme{}, &db.CommissionTier{}, &db.CommissionSplit{}, &db.PipelineStage{}, &db.TransactionStageChange{}, &db.TransactionMilestone{}, &db.ExchangeRate{}, &db.TaxRule{}, &db.TaxLine{}); err != nil {
 		fmt.Errorf("failed to migrate database schema: %v", err)
 	}
 
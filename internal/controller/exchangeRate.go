@@ -0,0 +1,370 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type ExchangeRateController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Import(w http.ResponseWriter, r *http.Request)
+	Convert(w http.ResponseWriter, r *http.Request)
+}
+
+type exchangeRateController struct {
+	service service.ExchangeRateService
+}
+
+func NewExchangeRateController(service service.ExchangeRateService) ExchangeRateController {
+	return &exchangeRateController{service}
+}
+
+// API/EXCHANGE-RATES
+// Find a list of exchange rates
+// @Summary      Find a list of exchange rates
+// @Description  Accepts limit, offset, order, base and quote currency params and returns list of exchange rates (latest first by default)
+// @Tags         ExchangeRate
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        base   path      string  false  "base currency"
+// @Param        quote   path      string  false  "quote currency"
+// @Success      200 {object} []db.ExchangeRate
+// @Failure      400 {string} string "Can't find exchange rates"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /exchange-rates [get]
+// @Security BearerToken
+func (c exchangeRateController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	base := strings.ToUpper(r.URL.Query().Get("base"))
+	quote := strings.ToUpper(r.URL.Query().Get("quote"))
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all exchange rates using query params
+	foundRates, err := c.service.FindAll(limit, offset, orderBy, base, quote)
+	if err != nil {
+		http.Error(w, "Can't find exchange rates", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundRates)
+	if err != nil {
+		http.Error(w, "Can't find exchange rates", http.StatusBadRequest)
+		fmt.Println("error writing exchange rates to response: ", err)
+		return
+	}
+}
+
+// Find a created exchange rate
+// @Summary      Find exchange rate
+// @Description  Find an exchange rate by ID
+// @Tags         ExchangeRate
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Exchange Rate ID"
+// @Success      200 {object} db.ExchangeRate
+// @Failure      400 {string} string "Can't find exchange rate with ID: {id}"
+// @Router       /exchange-rates/{id} [get]
+// @Security BearerToken
+func (c exchangeRateController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundRate, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find exchange rate with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundRate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find exchange rate with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new exchange rate
+// @Summary      Create exchange rate
+// @Description  Records the rate to convert a base currency into a quote currency from an effective date. Each currency pair has one rate per date
+// @Tags         ExchangeRate
+// @Accept       json
+// @Produce      json
+// @Param        rate body models.CreateExchangeRate true "New Exchange Rate Json"
+// @Success      201 {object} db.ExchangeRate
+// @Failure      400 {string} string "Exchange rate creation failed."
+// @Failure      409 {string} string "Currency pair already has a rate for this date"
+// @Router       /exchange-rates [post]
+// @Security BearerToken
+func (c exchangeRateController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var rate models.CreateExchangeRate
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&rate)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&rate)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create exchange rate in db
+	createdRate, createErr := c.service.Create(&rate)
+	if createErr != nil {
+		if errors.Is(createErr, service.ErrExchangeRateExists) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Exchange rate creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created rate to output
+	err = helpers.WriteAsJSON(w, createdRate)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update an exchange rate (using URL parameter id)
+// @Summary      Update exchange rate
+// @Description  Updates the rate or effective date of an exchange rate. Reports use the updated rate from then on
+// @Tags         ExchangeRate
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Exchange Rate ID"
+// @Param        rate body models.UpdateExchangeRate true "Update Exchange Rate Json"
+// @Success      200 {object} db.ExchangeRate
+// @Failure      400 {string} string "Failed exchange rate update"
+// @Failure      409 {string} string "Currency pair already has a rate for this date"
+// @Router       /exchange-rates/{id} [put]
+// @Security BearerToken
+func (c exchangeRateController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var rate models.UpdateExchangeRate
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&rate)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&rate)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update exchange rate
+	updatedRate, err := c.service.Update(idParameter, &rate)
+	if err != nil {
+		if errors.Is(err, service.ErrExchangeRateExists) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed exchange rate update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated rate to output
+	err = helpers.WriteAsJSON(w, updatedRate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed exchange rate update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete exchange rate (using URL parameter id)
+// @Summary      Delete exchange rate
+// @Description  Deletes an exchange rate
+// @Tags         ExchangeRate
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Exchange Rate ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed exchange rate deletion"
+// @Router       /exchange-rates/{id} [delete]
+// @Security BearerToken
+func (c exchangeRateController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete exchange rate using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed exchange rate deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Import exchange rates from CSV
+// @Summary      Import exchange rates
+// @Description  Creates or replaces exchange rates from a CSV file (multipart field "file", or a text/csv body) with rows of effective date (YYYY-MM-DD), base currency, quote currency and rate. A header row naming the columns (effective_date, base_currency, quote_currency, rate) may set their order. Invalid rows are skipped and listed in the result
+// @Tags         ExchangeRate
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file formData file true "Exchange rates CSV"
+// @Success      200 {object} models.ExchangeRateImport
+// @Failure      400 {string} string "Exchange rate import failed"
+// @Router       /exchange-rates/import [post]
+// @Security BearerToken
+func (c exchangeRateController) Import(w http.ResponseWriter, r *http.Request) {
+	// Read the uploaded file, or the body itself if it's CSV
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		formFile, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Exchange rate import failed: missing CSV file", http.StatusBadRequest)
+			return
+		}
+		defer formFile.Close()
+		file = formFile
+	}
+
+	result, err := c.service.Import(file)
+	if err != nil {
+		http.Error(w, "Exchange rate import failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, result)
+	if err != nil {
+		http.Error(w, "Exchange rate import failed", http.StatusBadRequest)
+		return
+	}
+}
+
+// API/EXCHANGE-RATES/CONVERT
+// Convert an amount into another currency
+// @Summary      Convert amount
+// @Description  Converts an amount from one currency into another at the rate effective on a date. Currencies without a direct rate are converted through the default currency (IDR)
+// @Tags         ExchangeRate
+// @Accept       json
+// @Produce      json
+// @Param        amount   path      string  true  "amount (eg. 1500.50)"
+// @Param        from   path      string  true  "currency of the amount"
+// @Param        to   path      string  true  "currency to convert into"
+// @Param        date   path      string  false  "date of the rate (YYYY-MM-DD). Defaults to today"
+// @Success      200 {object} models.CurrencyConversion
+// @Failure      400 {string} string "Can't convert amount"
+// @Router       /exchange-rates/convert [get]
+// @Security BearerToken
+func (c exchangeRateController) Convert(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	from := strings.ToUpper(r.URL.Query().Get("from"))
+	to := strings.ToUpper(r.URL.Query().Get("to"))
+	if !db.IsCurrency(from) || !db.IsCurrency(to) {
+		http.Error(w, service.ErrUnsupportedCurrency.Error(), http.StatusBadRequest)
+		return
+	}
+	var amount db.Money
+	err := json.Unmarshal([]byte(fmt.Sprintf(`{"amount": %q, "currency": %q}`, r.URL.Query().Get("amount"), from)), &amount)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't convert amount: %v", err), http.StatusBadRequest)
+		return
+	}
+	date := time.Now()
+	if dateParam := r.URL.Query().Get("date"); dateParam != "" {
+		date, err = time.Parse("2006-01-02", dateParam)
+		if err != nil {
+			http.Error(w, "Invalid date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+	}
+
+	rate, err := c.service.Rate(from, to, date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't convert amount: %v", err), http.StatusBadRequest)
+		return
+	}
+	converted, err := c.service.Convert(amount, to, date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't convert amount: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, models.CurrencyConversion{Amount: amount, Converted: converted, Rate: rate, Date: date})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't convert amount: %v", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Currency a report is requested in: the currency parameter, else the user's reporting currency, else the default
+// currency. Writes an error response and returns false if the currency isn't supported
+func reportingCurrency(w http.ResponseWriter, r *http.Request, rates service.ExchangeRateService) (string, bool) {
+	tokenData, err := auth.ValidateAndParseToken(w, r)
+	if err != nil {
+		http.Error(w, "Error parsing authentication token", http.StatusForbidden)
+		return "", false
+	}
+	userId, _ := strconv.Atoi(tokenData.UserID)
+	currency, err := rates.ReportingCurrency(userId, r.URL.Query().Get("currency"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return currency, true
+}
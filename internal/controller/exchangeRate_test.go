@@ -0,0 +1,133 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestExchangeRateController_CreateImportAndConvert(t *testing.T) {
+	// Test setup
+	effective := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var createTests = []struct {
+		data                   models.CreateExchangeRate
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 15500, EffectiveDate: effective}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateExchangeRate{BaseCurrency: "USD", QuoteCurrency: "EUR", Rate: 0.9, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin unsupported currency fail test"},
+		{models.CreateExchangeRate{BaseCurrency: "USD", QuoteCurrency: "USD", Rate: 1, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin same currency fail test"},
+		{models.CreateExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 15500, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+		{models.CreateExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 15600, EffectiveDate: effective.Add(6 * time.Hour)}, testConnection.accounts.admin.token, http.StatusConflict, "admin duplicate date fail test"},
+	}
+
+	var created db.ExchangeRate
+	for _, v := range createTests {
+		// Make new request with exchange rate creation in body
+		req, err := http.NewRequest("POST", "/api/exchange-rates", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Exchange rate create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &created)
+		}
+	}
+	if created.Source != "Manual" {
+		t.Errorf("Exchange rate create: expected a manual rate, got %+v", created)
+	}
+
+	// Import rates from CSV. Rows with errors are skipped, rates already recorded for the date are replaced
+	fileBody := &bytes.Buffer{}
+	writer := multipart.NewWriter(fileBody)
+	fileField, err := writer.CreateFormFile("file", "rates.csv")
+	if err != nil {
+		t.Fatalf("Failed to create form file field: %v", err)
+	}
+	fileField.Write([]byte("date,from,to,rate\n2024-01-01,USD,IDR,16000\n2024-02-01,IDR,AUD,0.0001\n2024-02-01,USD,EUR,0.9\nyesterday,USD,IDR,16000\n2024-03-01,USD,IDR,NaN\n2024-03-01,USD,IDR,+Inf\n"))
+	writer.Close()
+	req, err := http.NewRequest("POST", "/api/exchange-rates/import", bytes.NewReader(fileBody.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	var imported models.ExchangeRateImport
+	json.Unmarshal(rr.Body.Bytes(), &imported)
+	if rr.Code != http.StatusOK || imported.Created != 1 || imported.Updated != 1 || len(imported.Errors) != 4 {
+		t.Errorf("Exchange rate import: expected 1 created, 1 updated and 4 errors, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	var convertTests = []struct {
+		query                  string
+		expectedResponseStatus int
+		expected               db.Money
+		testName               string
+	}{
+		// Imported rate replaced the manual rate
+		{"amount=100&from=USD&to=IDR&date=2024-01-15", http.StatusOK, db.NewMoney(1600000, "IDR"), "direct rate"},
+		// Inverse of the IDR to AUD rate
+		{"amount=100&from=AUD&to=IDR&date=2024-03-01", http.StatusOK, db.NewMoney(1000000, "IDR"), "inverse rate"},
+		// USD to IDR then IDR to AUD
+		{"amount=100&from=USD&to=AUD&date=2024-03-01", http.StatusOK, db.NewMoney(160, "AUD"), "cross rate"},
+		{"amount=100&from=USD&to=AUD&date=2024-01-15", http.StatusBadRequest, db.Money{}, "no rate yet"},
+		{"amount=100&from=USD&to=EUR&date=2024-01-15", http.StatusBadRequest, db.Money{}, "unsupported currency"},
+	}
+	for _, v := range convertTests {
+		rr = serveAsAdmin(t, "GET", "/api/exchange-rates/convert?"+v.query, nil)
+		var conversion models.CurrencyConversion
+		json.Unmarshal(rr.Body.Bytes(), &conversion)
+		if rr.Code != v.expectedResponseStatus || rr.Code == http.StatusOK && !conversion.Converted.Equal(v.expected) {
+			t.Errorf("Exchange rate convert (%v): expected %v %v, got %v %v", v.testName, v.expectedResponseStatus, v.expected.Format(), rr.Code, rr.Body.String())
+		}
+	}
+
+	// Reports are shown in the requested currency, else the user's reporting currency
+	rr = serveAsAdmin(t, "GET", "/api/transactions/pipeline?type=Sale&currency=usd", nil)
+	var summaries []models.PipelineSummary
+	json.Unmarshal(rr.Body.Bytes(), &summaries)
+	if rr.Code != http.StatusOK || len(summaries) != 1 || summaries[0].Currency != "USD" || summaries[0].Value.Code() != "USD" {
+		t.Errorf("Pipeline summary in USD: got %v %v", rr.Code, rr.Body.String())
+	}
+	rr = serveAsAdmin(t, "GET", "/api/transactions/pipeline?currency=EUR", nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Pipeline summary in unsupported currency: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+	rr = serveAsAdmin(t, "PUT", "/api/me", models.UpdateUser{ReportingCurrency: "AUD"})
+	if rr.Code != http.StatusOK {
+		t.Errorf("Set reporting currency: got %v %v", rr.Code, rr.Body.String())
+	}
+	rr = serveAsAdmin(t, "GET", "/api/payables", nil)
+	var aging models.PayablesAging
+	json.Unmarshal(rr.Body.Bytes(), &aging)
+	if rr.Code != http.StatusOK || aging.Currency != "AUD" || aging.Totals.Total.Code() != "AUD" {
+		t.Errorf("Payables aging in the user's reporting currency: got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Cleanup
+	testConnection.dbClient.Model(testConnection.accounts.admin.details).Update("reporting_currency", nil)
+	testConnection.dbClient.Where("1 = 1").Delete(&db.ExchangeRate{})
+}
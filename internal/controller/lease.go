@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type LeaseController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Renew(w http.ResponseWriter, r *http.Request)
+}
+
+type leaseController struct {
+	service service.LeaseService
+}
+
+func NewLeaseController(service service.LeaseService) LeaseController {
+	return &leaseController{service}
+}
+
+// API/LEASES
+// Find a list of leases
+// @Summary      Find a list of leases
+// @Description  Accepts limit, offset, order, property and tenant params and returns list of leases with their status (soonest ending first by default)
+// @Tags         Leases
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        property   path      int  false  "property id"
+// @Param        tenant   path      int  false  "tenant contact id"
+// @Success      200 {object} []db.Lease
+// @Failure      400 {string} string "Can't find leases"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /leases [get]
+// @Security BearerToken
+func (c leaseController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	propertyParam := r.URL.Query().Get("property")
+	tenantParam := r.URL.Query().Get("tenant")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	propertyId, _ := strconv.Atoi(propertyParam)
+	tenantId, _ := strconv.Atoi(tenantParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all leases using query params
+	foundLeases, err := c.service.FindAll(limit, offset, orderBy, propertyId, tenantId)
+	if err != nil {
+		http.Error(w, "Can't find leases", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundLeases)
+	if err != nil {
+		http.Error(w, "Can't find leases", http.StatusBadRequest)
+		fmt.Println("error writing leases to response: ", err)
+		return
+	}
+}
+
+// Find a created lease
+// @Summary      Find lease
+// @Description  Find a lease by ID, including its tenants and rent schedule
+// @Tags         Leases
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Lease ID"
+// @Success      200 {object} db.Lease
+// @Failure      400 {string} string "Can't find lease with ID: {id}"
+// @Router       /leases/{id} [get]
+// @Security BearerToken
+func (c leaseController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundLease, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find lease with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundLease)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find lease with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new lease
+// @Summary      Create lease
+// @Description  Leases a property to one or more tenant contacts and generates the rent schedule (Monthly, Quarterly or Yearly in advance)
+// @Tags         Leases
+// @Accept       json
+// @Produce      json
+// @Param        lease body models.CreateLease true "New Lease Json"
+// @Success      201 {object} db.Lease
+// @Failure      400 {string} string "Lease creation failed."
+// @Failure      409 {string} string "The property is already leased for part of this period"
+// @Router       /leases [post]
+// @Security BearerToken
+func (c leaseController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var lease models.CreateLease
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&lease)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&lease)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create lease in db
+	createdLease, createErr := c.service.Create(&lease)
+	if createErr != nil {
+		if errors.Is(createErr, service.ErrLeaseOverlap) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Lease creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created lease to output
+	err = helpers.WriteAsJSON(w, createdLease)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a lease (using URL parameter id)
+// @Summary      Update lease
+// @Description  Updates a lease. Changing the dates, rent or frequency regenerates the rent schedule and changing the end date or notice period re-arms the renewal reminder
+// @Tags         Leases
+// @Accept       json
+// @Produce      json
+// @Param        lease body models.UpdateLease true "Update Lease Json"
+// @Param        id   path      int  true  "Lease ID"
+// @Success      200 {object} db.Lease
+// @Failure      400 {string} string "Failed lease update"
+// @Failure      409 {string} string "The property is already leased for part of this period"
+// @Router       /leases/{id} [put]
+// @Security BearerToken
+func (c leaseController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var lease models.UpdateLease
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&lease)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&lease)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update lease
+	updatedLease, err := c.service.Update(idParameter, &lease)
+	if err != nil {
+		if errors.Is(err, service.ErrLeaseOverlap) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed lease update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated lease to output
+	err = helpers.WriteAsJSON(w, updatedLease)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed lease update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete lease (using URL parameter id)
+// @Summary      Delete lease
+// @Description  Deletes an existing lease
+// @Tags         Leases
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Lease ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed lease deletion"
+// @Router       /leases/{id} [delete]
+// @Security BearerToken
+func (c leaseController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete lease using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed lease deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/LEASES/RENEW
+// Renew a lease (using URL parameter id)
+// @Summary      Renew lease
+// @Description  Creates a lease for the following term starting when the lease ends, with the same property and tenants. Rent defaults to the current rent plus any percentage increase
+// @Tags         Leases
+// @Accept       json
+// @Produce      json
+// @Param        renewal body models.RenewLease true "Renew Lease Json"
+// @Param        id   path      int  true  "Lease ID"
+// @Success      201 {object} db.Lease
+// @Failure      400 {string} string "Failed lease renewal"
+// @Failure      409 {string} string "Lease has already been renewed"
+// @Router       /leases/renew/{id} [post]
+// @Security BearerToken
+func (c leaseController) Renew(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var renewal models.RenewLease
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&renewal)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&renewal)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	renewedLease, err := c.service.Renew(idParameter, &renewal)
+	if err != nil {
+		if errors.Is(err, service.ErrLeaseAlreadyRenewed) || errors.Is(err, service.ErrLeaseOverlap) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed lease renewal: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write renewed lease to output
+	err = helpers.WriteAsJSON(w, renewedLease)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
@@ -0,0 +1,171 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+// Property and tenants used by lease tests
+type leaseFixtures struct {
+	property db.Property
+	tenants  []db.Contact
+}
+
+// Creates a managed property and two tenant contacts
+func createLeaseFixtures(t *testing.T) *leaseFixtures {
+	f := &leaseFixtures{}
+	f.property = db.Property{Property_Name: "leaseProperty1", Postcode: 80361, Suburb: "Umalas", City: "Badung", Street_Address_1: "Jl. Bumbak", Bedrooms: 2, Bathrooms: 2, Description: "Villa", Managed: true}
+	if result := testConnection.dbClient.Create(&f.property); result.Error != nil {
+		t.Fatal("Failed to create property for lease test: ", result.Error)
+	}
+	f.tenants = []db.Contact{
+		{FirstName: "Made", LastName: "Sutama", ContactType: "Tenant", Email: "made@example.com"},
+		{FirstName: "Anna", LastName: "Berg", ContactType: "Tenant", Email: "anna@example.com"},
+	}
+	if result := testConnection.dbClient.Create(f.tenants); result.Error != nil {
+		t.Fatal("Failed to create tenants for lease test: ", result.Error)
+	}
+	return f
+}
+
+// Deletes the created fixtures along with any leases of the property
+func (f *leaseFixtures) delete() {
+	testConnection.dbClient.Exec("DELETE FROM lease_tenants WHERE lease_id IN (SELECT id FROM leases WHERE property_id = ?)", f.property.ID)
+	testConnection.dbClient.Exec("DELETE FROM ledger_entries WHERE lease_id IN (SELECT id FROM leases WHERE property_id = ?)", f.property.ID)
+	testConnection.dbClient.Exec("DELETE FROM rent_schedule_items WHERE lease_id IN (SELECT id FROM leases WHERE property_id = ?)", f.property.ID)
+	testConnection.dbClient.Unscoped().Where("property_id = ?", f.property.ID).Delete(&db.Lease{})
+	testConnection.dbClient.Unscoped().Delete(f.tenants)
+	testConnection.dbClient.Unscoped().Delete(&f.property)
+}
+
+func TestLeaseController_CreateRenewAndSchedule(t *testing.T) {
+	// Test setup
+	f := createLeaseFixtures(t)
+	start := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -10)
+	// Eighteen months paid yearly in advance
+	end := start.AddDate(0, 18, 0)
+
+	var createTests = []struct {
+		data                   models.CreateLease
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateLease{StartDate: start, EndDate: end, RentAmount: db.NewMoney(120000000, "IDR"), Frequency: "Yearly", Property: f.property, Tenants: f.tenants}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateLease{StartDate: start, EndDate: end, RentAmount: db.NewMoney(120000000, "IDR"), Frequency: "Weekly", Property: f.property, Tenants: f.tenants}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid frequency fail test"},
+		{models.CreateLease{StartDate: end, EndDate: start, RentAmount: db.NewMoney(120000000, "IDR"), Frequency: "Yearly", Property: f.property, Tenants: f.tenants}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin end before start fail test"},
+		{models.CreateLease{StartDate: start, EndDate: end, RentAmount: db.NewMoney(120000000, "IDR"), Frequency: "Yearly", Deposit: db.NewMoney(10000000, "IDR"), RenewalOption: "Option to Renew", Property: f.property, Tenants: f.tenants}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+		{models.CreateLease{StartDate: start.AddDate(0, 6, 0), EndDate: end.AddDate(0, 6, 0), RentAmount: db.NewMoney(10000000, "IDR"), Frequency: "Monthly", Property: f.property, Tenants: f.tenants[:1]}, testConnection.accounts.admin.token, http.StatusConflict, "admin overlapping lease fail test"},
+	}
+
+	var created db.Lease
+	for _, v := range createTests {
+		// Make new request with lease creation in body
+		req, err := http.NewRequest("POST", "/api/leases", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Lease create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &created)
+		}
+	}
+	if created.ID == 0 {
+		t.Fatal("Lease create: no lease created")
+	}
+	if created.Status != "Active" || created.RenewalNoticeDays != 60 || len(created.Tenants) != 2 {
+		t.Errorf("Lease create: expected an active lease with 2 tenants and a 60 day notice period, got %v", created)
+	}
+	// A full year then six months pro rata
+	if len(created.Schedule) != 2 || created.Schedule[0].Amount.Float() != 120000000 || !created.Schedule[0].DueDate.Equal(start) ||
+		created.Schedule[1].Amount.Float() < 59000000 || created.Schedule[1].Amount.Float() > 61000000 || !created.Schedule[1].PeriodEnd.Equal(end) {
+		t.Errorf("Lease create: expected a yearly then a pro rata schedule, got %v", created.Schedule)
+	}
+
+	// Switching to quarterly rent regenerates the schedule
+	rr := serveAsAdmin(t, "PUT", fmt.Sprintf("/api/leases/%v", created.ID), models.UpdateLease{RentAmount: db.NewMoney(30000000, "IDR"), Frequency: "Quarterly"})
+	var updated db.Lease
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if rr.Code != http.StatusOK || len(updated.Schedule) != 6 || updated.Schedule[5].Amount.Float() != 30000000 {
+		t.Errorf("Lease update: expected 6 quarterly periods, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Renewal starts when the lease ends
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/leases/renew/%v", created.ID), models.RenewLease{TermMonths: 12, RentIncreasePercent: 10, Frequency: "Yearly"})
+	var renewed db.Lease
+	json.Unmarshal(rr.Body.Bytes(), &renewed)
+	if rr.Code != http.StatusCreated || renewed.RentAmount.Float() != 33000000 || !renewed.StartDate.Equal(end) ||
+		renewed.RenewedFromID == nil || *renewed.RenewedFromID != created.ID || renewed.Status != "Upcoming" || len(renewed.Schedule) != 1 || len(renewed.Tenants) != 2 {
+		t.Errorf("Lease renew: expected an upcoming lease from the end date at 33000000, got %v %v", rr.Code, rr.Body.String())
+	}
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/leases/renew/%v", created.ID), models.RenewLease{TermMonths: 12})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Lease renew twice: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	// Leases are listed by tenant
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/leases?limit=10&tenant=%v", f.tenants[1].ID), nil)
+	var found []db.Lease
+	json.Unmarshal(rr.Body.Bytes(), &found)
+	if rr.Code != http.StatusOK || len(found) != 2 {
+		t.Errorf("Lease find all by tenant: expected 2 leases, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Clean up created fixtures
+	f.delete()
+}
+
+func TestLeaseService_ProcessRenewalReminders(t *testing.T) {
+	// Test setup
+	f := createLeaseFixtures(t)
+	now := time.Now()
+	// Monthly lease from the end of a month ending within its notice period
+	start := time.Date(now.Year()-1, time.January, 31, 0, 0, 0, 0, time.Local)
+	expiring, err := testConnection.leases.serv.Create(&models.CreateLease{StartDate: start, EndDate: now.AddDate(0, 0, 30), RentAmount: db.NewMoney(15000000, "IDR"), Frequency: "Monthly", Property: f.property, Tenants: f.tenants[:1]})
+	if err != nil {
+		t.Fatalf("Lease create failed: %v", err)
+	}
+	if expiring.Status != "Expiring" {
+		t.Errorf("Lease status: expected Expiring, got %v", expiring.Status)
+	}
+	// Periods keep to the end of shorter months
+	if len(expiring.Schedule) < 2 || expiring.Schedule[1].PeriodStart.Day() != 28 && expiring.Schedule[1].PeriodStart.Day() != 29 {
+		t.Errorf("Lease schedule: expected the second period to start at the end of February, got %v", expiring.Schedule)
+	}
+
+	// Reminders are only sent once
+	for i := 0; i < 2; i++ {
+		err := testConnection.leases.serv.ProcessRenewalReminders()
+		if err != nil {
+			t.Fatalf("Process renewal reminders failed: %v", err)
+		}
+	}
+	testConnection.notifications.serv.Wait()
+	var found []db.Notification
+	testConnection.dbClient.Where("user_id = ? AND type = ?", testConnection.accounts.admin.details.ID, "LeaseExpiring").Find(&found)
+	if len(found) != 1 {
+		t.Errorf("Lease renewal reminders: expected 1 admin notification, got %v", found)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(found)
+	f.delete()
+}
@@ -0,0 +1,280 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type LedgerEntryController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Statement(w http.ResponseWriter, r *http.Request)
+	Arrears(w http.ResponseWriter, r *http.Request)
+}
+
+type ledgerEntryController struct {
+	service service.LedgerEntryService
+	rates   service.ExchangeRateService
+}
+
+func NewLedgerEntryController(service service.LedgerEntryService, rates service.ExchangeRateService) LedgerEntryController {
+	return &ledgerEntryController{service, rates}
+}
+
+// API/LEDGER-ENTRIES
+// Find a list of ledger entries
+// @Summary      Find a list of ledger entries
+// @Description  Accepts limit, offset, order and lease params and returns list of ledger entries (latest first by default)
+// @Tags         Ledger
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        lease   path      int  false  "lease id"
+// @Success      200 {object} []db.LedgerEntry
+// @Failure      400 {string} string "Can't find ledger entries"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /ledger-entries [get]
+// @Security BearerToken
+func (c ledgerEntryController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	leaseParam := r.URL.Query().Get("lease")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	leaseId, _ := strconv.Atoi(leaseParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all ledger entries using query params
+	foundEntries, err := c.service.FindAll(limit, offset, orderBy, leaseId)
+	if err != nil {
+		http.Error(w, "Can't find ledger entries", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundEntries)
+	if err != nil {
+		http.Error(w, "Can't find ledger entries", http.StatusBadRequest)
+		fmt.Println("error writing ledger entries to response: ", err)
+		return
+	}
+}
+
+// Find a created ledger entry
+// @Summary      Find ledger entry
+// @Description  Find a ledger entry by ID
+// @Tags         Ledger
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Ledger Entry ID"
+// @Success      200 {object} db.LedgerEntry
+// @Failure      400 {string} string "Can't find ledger entry with ID: {id}"
+// @Router       /ledger-entries/{id} [get]
+// @Security BearerToken
+func (c ledgerEntryController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundEntry, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find ledger entry with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundEntry)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find ledger entry with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Record a new ledger entry
+// @Summary      Create ledger entry
+// @Description  Records a receipt, adjustment, late fee, deposit received or deposit refund against a lease. Rent charges are generated from the lease's rent schedule
+// @Tags         Ledger
+// @Accept       json
+// @Produce      json
+// @Param        entry body models.CreateLedgerEntry true "New Ledger Entry Json"
+// @Success      201 {object} db.LedgerEntry
+// @Failure      400 {string} string "Ledger entry creation failed."
+// @Router       /ledger-entries [post]
+// @Security BearerToken
+func (c ledgerEntryController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var entry models.CreateLedgerEntry
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&entry)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create ledger entry in db
+	createdEntry, createErr := c.service.Create(&entry)
+	if createErr != nil {
+		http.Error(w, "Ledger entry creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created entry to output
+	err = helpers.WriteAsJSON(w, createdEntry)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Void ledger entry (using URL parameter id)
+// @Summary      Delete ledger entry
+// @Description  Voids a ledger entry. Rent charges can't be voided and are corrected with adjustments. Voided late fees aren't charged again
+// @Tags         Ledger
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Ledger Entry ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed ledger entry deletion"
+// @Failure      409 {string} string "Rent charges can't be deleted, record an adjustment instead"
+// @Router       /ledger-entries/{id} [delete]
+// @Security BearerToken
+func (c ledgerEntryController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete ledger entry using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		if errors.Is(err, service.ErrRentChargeLocked) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed ledger entry deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/LEASES/{ID}/STATEMENT
+// Statement of a lease's ledger (using URL parameter id)
+// @Summary      Lease statement
+// @Description  Returns the lease's ledger entries between from and to (YYYY-MM-DD, both inclusive) with running rent and deposit balances and overdue charges. Defaults to the start of the lease until today
+// @Tags         Ledger
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Lease ID"
+// @Param        from   path      string  false  "first date of the statement"
+// @Param        to   path      string  false  "last date of the statement"
+// @Success      200 {object} models.LeaseStatement
+// @Failure      400 {string} string "Can't produce statement of lease with ID: {id}"
+// @Router       /leases/{id}/statement [get]
+// @Security BearerToken
+func (c ledgerEntryController) Statement(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	// Statement period
+	var from, to time.Time
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		from, err = time.ParseInLocation("2006-01-02", fromParam, time.Local)
+		if err != nil {
+			http.Error(w, "From must be a date formatted YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		to, err = time.ParseInLocation("2006-01-02", toParam, time.Local)
+		if err != nil {
+			http.Error(w, "To must be a date formatted YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		// Include entries on the last day
+		to = to.AddDate(0, 0, 1)
+	}
+
+	statement, err := c.service.Statement(idParameter, from, to)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce statement of lease with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, statement)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce statement of lease with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// API/LEASES/ARREARS
+// Arrears across managed properties
+// @Summary      Arrears report
+// @Description  Returns leases of managed properties with charges past their due date, aged 1-30, 31-60, 61-90 and over 90 days
+// @Tags         Ledger
+// @Accept       json
+// @Produce      json
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.ArrearsReport
+// @Failure      400 {string} string "Can't produce arrears report"
+// @Router       /leases/arrears [get]
+// @Security BearerToken
+func (c ledgerEntryController) Arrears(w http.ResponseWriter, r *http.Request) {
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.Arrears(currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce arrears report: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, report)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce arrears report: %v", err), http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,160 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestLedgerEntryController_ChargesReceiptsAndArrears(t *testing.T) {
+	// Test setup
+	f := createLeaseFixtures(t)
+	now := time.Now()
+	// Monthly lease that started a little over three months ago, so four months' rent has fallen due
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -95)
+	lease, err := testConnection.leases.serv.Create(&models.CreateLease{StartDate: start, EndDate: start.AddDate(1, 0, 0), RentAmount: db.NewMoney(10000000, "IDR"), Frequency: "Monthly", LateFee: db.NewMoney(500000, "IDR"), Property: f.property, Tenants: f.tenants})
+	if err != nil {
+		t.Fatalf("Lease create failed: %v", err)
+	}
+
+	var createTests = []struct {
+		data                   models.CreateLedgerEntry
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateLedgerEntry{Type: "Receipt", Amount: db.NewMoney(15000000, "IDR"), Lease: *lease}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateLedgerEntry{Type: "Rent Charge", Amount: db.NewMoney(15000000, "IDR"), Lease: *lease}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin rent charge fail test"},
+		{models.CreateLedgerEntry{Type: "Receipt", Amount: db.NewMoney(-15000000, "IDR"), Lease: *lease}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin negative receipt fail test"},
+		{models.CreateLedgerEntry{Type: "Receipt", Amount: db.NewMoney(15000000, "IDR"), Reference: "BCA-0001", EntryDate: now.Add(-time.Minute), Lease: *lease}, testConnection.accounts.admin.token, http.StatusCreated, "admin receipt create test"},
+		{models.CreateLedgerEntry{Type: "Deposit Received", Amount: db.NewMoney(20000000, "IDR"), EntryDate: now.Add(-time.Minute), Lease: *lease}, testConnection.accounts.admin.token, http.StatusCreated, "admin deposit create test"},
+		{models.CreateLedgerEntry{Type: "Deposit Refund", Amount: db.NewMoney(25000000, "IDR"), Lease: *lease}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin refund more than held fail test"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with ledger entry creation in body
+		req, err := http.NewRequest("POST", "/api/ledger-entries", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Ledger entry create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Statements include rent that has fallen due without charging it
+	rr := serveAsAdmin(t, "GET", fmt.Sprintf("/api/leases/%v/statement", lease.ID), nil)
+	var pendingStatement models.LeaseStatement
+	json.Unmarshal(rr.Body.Bytes(), &pendingStatement)
+	var charges int64
+	testConnection.dbClient.Model(&db.LedgerEntry{}).Where("lease_id = ? AND type = ?", lease.ID, "Rent Charge").Count(&charges)
+	if rr.Code != http.StatusOK || charges != 0 || pendingStatement.ClosingRentBalance.Float() != 25000000 {
+		t.Errorf("Lease statement before charging: expected 25000000 owing with no charges stored, got %v charges %v %v", charges, rr.Code, rr.Body.String())
+	}
+
+	// Rent is charged from the schedule. The receipt pays the first month and half the second,
+	// so late fees are charged on the second and third months, which are past the grace period
+	for i := 0; i < 2; i++ {
+		err = testConnection.ledgerEntries.serv.ProcessRentCharges()
+		if err != nil {
+			t.Fatalf("Process rent charges failed: %v", err)
+		}
+	}
+	var lateFees []db.LedgerEntry
+	testConnection.dbClient.Where("lease_id = ? AND type = ?", lease.ID, "Late Fee").Find(&lateFees)
+	if len(lateFees) != 2 {
+		t.Fatalf("Late fees: expected 2 late fees, got %v", lateFees)
+	}
+	// A rent period can only be charged once
+	created, err := testConnection.ledgerEntries.repo.CreateRentCharge(&db.LedgerEntry{EntryDate: start, Type: "Rent Charge", Account: "Rent", Debit: db.NewMoney(10000000, "IDR"), LeaseID: lease.ID, RentScheduleItemID: lateFees[0].RentScheduleItemID})
+	if created || err != nil {
+		t.Errorf("Duplicate rent charge: expected the charge to be skipped, got %v %v", created, err)
+	}
+
+	// Statements are restricted to admins
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/leases/%v/statement", lease.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.user.token))
+	rr = httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Lease statement as basic user: got %v want %v", rr.Code, http.StatusForbidden)
+	}
+
+	// Statement with running balances
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/leases/%v/statement", lease.ID), nil)
+	var statement models.LeaseStatement
+	json.Unmarshal(rr.Body.Bytes(), &statement)
+	if rr.Code != http.StatusOK || len(statement.Lines) != 8 || statement.ClosingRentBalance.Float() != 26000000 || statement.ClosingDepositBalance.Float() != 20000000 {
+		t.Errorf("Lease statement: expected 8 lines owing 26000000 with 20000000 deposit held, got %v %v", rr.Code, rr.Body.String())
+	}
+	// Late fees charged today aren't overdue yet
+	if statement.Arrears.Total.Float() != 25000000 || statement.Arrears.Days1To30.Float() != 10000000 || len(statement.Tenants) != 2 {
+		t.Errorf("Lease statement: expected arrears of 25000000 with 10000000 under 30 days, got %v", statement.Arrears)
+	}
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/leases/%v/statement?from=%s", lease.ID, now.Format("2006-01-02")), nil)
+	json.Unmarshal(rr.Body.Bytes(), &statement)
+	if rr.Code != http.StatusOK || statement.OpeningRentBalance.Add(statement.OpeningDepositBalance).IsZero() || statement.ClosingRentBalance.Float() != 26000000 {
+		t.Errorf("Lease statement from today: expected an opening balance and closing balance of 26000000, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Rent charges are locked, while waived late fees aren't charged again
+	var charge db.LedgerEntry
+	testConnection.dbClient.Where("lease_id = ? AND type = ?", lease.ID, "Rent Charge").First(&charge)
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/ledger-entries/%v", charge.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Ledger entry delete rent charge: got %v want %v", rr.Code, http.StatusConflict)
+	}
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/ledger-entries/%v", lateFees[0].ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Ledger entry delete late fee: got %v want %v", rr.Code, http.StatusOK)
+	}
+	testConnection.ledgerEntries.serv.ProcessRentCharges()
+	var count int64
+	testConnection.dbClient.Model(&db.LedgerEntry{}).Where("lease_id = ? AND type = ?", lease.ID, "Late Fee").Count(&count)
+	if count != 1 {
+		t.Errorf("Waived late fee: expected 1 late fee, got %v", count)
+	}
+
+	// Arrears across managed properties
+	rr = serveAsAdmin(t, "GET", "/api/leases/arrears", nil)
+	var report models.ArrearsReport
+	json.Unmarshal(rr.Body.Bytes(), &report)
+	var leaseArrears *models.LeaseArrears
+	for i := range report.Leases {
+		if report.Leases[i].LeaseID == lease.ID {
+			leaseArrears = &report.Leases[i]
+		}
+	}
+	if rr.Code != http.StatusOK || leaseArrears == nil || leaseArrears.Total.Float() != 25000000 || leaseArrears.RentBalance.Float() != 25500000 || leaseArrears.DaysOverdue < 60 {
+		t.Errorf("Arrears report: expected the lease with arrears of 25000000, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Changing the rent only regenerates periods that haven't been charged
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/leases/%v", lease.ID), models.UpdateLease{RentAmount: db.NewMoney(12000000, "IDR")})
+	var updated db.Lease
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if rr.Code != http.StatusOK || len(updated.Schedule) != 12 || updated.Schedule[0].Amount.Float() != 10000000 || updated.Schedule[11].Amount.Float() != 12000000 {
+		t.Errorf("Lease update after charges: expected charged periods kept at 10000000, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Clean up created fixtures
+	f.delete()
+}
@@ -0,0 +1,306 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type MaintenanceBudgetController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Report(w http.ResponseWriter, r *http.Request)
+}
+
+type maintenanceBudgetController struct {
+	service service.MaintenanceBudgetService
+	rates   service.ExchangeRateService
+}
+
+func NewMaintenanceBudgetController(service service.MaintenanceBudgetService, rates service.ExchangeRateService) MaintenanceBudgetController {
+	return &maintenanceBudgetController{service, rates}
+}
+
+// API/MAINTENANCE-BUDGETS
+// Find a list of maintenance budgets
+// @Summary      Find a list of maintenance budgets
+// @Description  Accepts limit, offset, order, property and year params and returns list of maintenance budgets (latest year first by default)
+// @Tags         Maintenance Budgets
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        property   path      int  false  "property id"
+// @Param        year   path      int  false  "budget year"
+// @Success      200 {object} []db.MaintenanceBudget
+// @Failure      400 {string} string "Can't find maintenance budgets"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /maintenance-budgets [get]
+// @Security BearerToken
+func (c maintenanceBudgetController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	propertyParam := r.URL.Query().Get("property")
+	yearParam := r.URL.Query().Get("year")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	propertyId, _ := strconv.Atoi(propertyParam)
+	year, _ := strconv.Atoi(yearParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all maintenance budgets using query params
+	foundBudgets, err := c.service.FindAll(limit, offset, orderBy, propertyId, year)
+	if err != nil {
+		http.Error(w, "Can't find maintenance budgets", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundBudgets)
+	if err != nil {
+		http.Error(w, "Can't find maintenance budgets", http.StatusBadRequest)
+		fmt.Println("error writing maintenance budgets to response: ", err)
+		return
+	}
+}
+
+// Find a created maintenance budget
+// @Summary      Find maintenance budget
+// @Description  Find a maintenance budget by ID
+// @Tags         Maintenance Budgets
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Maintenance Budget ID"
+// @Success      200 {object} db.MaintenanceBudget
+// @Failure      400 {string} string "Can't find maintenance budget with ID: {id}"
+// @Router       /maintenance-budgets/{id} [get]
+// @Security BearerToken
+func (c maintenanceBudgetController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundBudget, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find maintenance budget with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundBudget)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find maintenance budget with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new maintenance budget
+// @Summary      Create maintenance budget
+// @Description  Sets an annual (no month) or monthly maintenance budget for a property, optionally for a single work type
+// @Tags         Maintenance Budgets
+// @Accept       json
+// @Produce      json
+// @Param        budget body models.CreateMaintenanceBudget true "New Maintenance Budget Json"
+// @Success      201 {object} db.MaintenanceBudget
+// @Failure      400 {string} string "Maintenance budget creation failed."
+// @Failure      409 {string} string "A maintenance budget already exists for this property, period and work type"
+// @Router       /maintenance-budgets [post]
+// @Security BearerToken
+func (c maintenanceBudgetController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var budget models.CreateMaintenanceBudget
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&budget)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&budget)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create maintenance budget in db
+	createdBudget, createErr := c.service.Create(&budget)
+	if createErr != nil {
+		if errors.Is(createErr, service.ErrDuplicateBudget) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Maintenance budget creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created budget to output
+	err = helpers.WriteAsJSON(w, createdBudget)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a maintenance budget (using URL parameter id)
+// @Summary      Update maintenance budget
+// @Description  Updates a maintenance budget's amount, alert threshold and notes. Changing the amount or threshold re-arms the alert
+// @Tags         Maintenance Budgets
+// @Accept       json
+// @Produce      json
+// @Param        budget body models.UpdateMaintenanceBudget true "Update Maintenance Budget Json"
+// @Param        id   path      int  true  "Maintenance Budget ID"
+// @Success      200 {object} db.MaintenanceBudget
+// @Failure      400 {string} string "Failed maintenance budget update"
+// @Router       /maintenance-budgets/{id} [put]
+// @Security BearerToken
+func (c maintenanceBudgetController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var budget models.UpdateMaintenanceBudget
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&budget)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&budget)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update maintenance budget
+	updatedBudget, err := c.service.Update(idParameter, &budget)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed maintenance budget update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated budget to output
+	err = helpers.WriteAsJSON(w, updatedBudget)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed maintenance budget update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete maintenance budget (using URL parameter id)
+// @Summary      Delete maintenance budget
+// @Description  Deletes an existing maintenance budget
+// @Tags         Maintenance Budgets
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Maintenance Budget ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed maintenance budget deletion"
+// @Router       /maintenance-budgets/{id} [delete]
+// @Security BearerToken
+func (c maintenanceBudgetController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete maintenance budget using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed maintenance budget deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/MAINTENANCE-BUDGETS/REPORT
+// Actual vs budget report of a property (using URL parameter id)
+// @Summary      Maintenance budget report
+// @Description  Returns each of the property's budgets for the year with actual spend from vendor invoices and uninvoiced maintenance request costs, plus monthly spend
+// @Tags         Maintenance Budgets
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Property ID"
+// @Param        year   path      int  false  "report year. Defaults to the current year"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.MaintenanceBudgetReport
+// @Failure      400 {string} string "Can't report maintenance budgets of property with ID: {id}"
+// @Router       /maintenance-budgets/report/{id} [get]
+// @Security BearerToken
+func (c maintenanceBudgetController) Report(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	// Report year
+	year := time.Now().Year()
+	yearParam := r.URL.Query().Get("year")
+	if yearParam != "" {
+		year, err = strconv.Atoi(yearParam)
+		if err != nil || year < 2000 || year > 2100 {
+			http.Error(w, "Year must be between 2000 and 2100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.Report(idParameter, year, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't report maintenance budgets of property with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, report)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't report maintenance budgets of property with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,127 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestMaintenanceBudgetController_CreateAndReport(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	now := time.Now()
+	// Invoiced plumbing request. Cost recorded on the request isn't counted twice
+	testConnection.dbClient.Model(&f.request).Updates(db.MaintenanceRequest{TotalCost: db.NewMoney(999999, "IDR")})
+	createdInvoice := db.VendorInvoice{InvoiceNumber: "BUDGET-1", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: now, DueDate: now, Subtotal: db.NewMoney(1100000, "IDR"), Total: db.NewMoney(1221000, "IDR"), Status: "Unpaid", VendorID: f.vendors[0].ID, MaintenanceRequestID: &f.request.ID}
+	testConnection.dbClient.Create(&createdInvoice)
+	// Uninvoiced painting request
+	paintingTask := db.Task{TaskName: "Repaint the fence", Type: "Maintenance"}
+	testConnection.dbClient.Create(&paintingTask)
+	paintingRequest := db.MaintenanceRequest{Scale: "Low", WorkDefinition: "Repair", Type: "Painting", TotalCost: db.NewMoney(500000, "IDR"), Tax: db.NewMoney(55000, "IDR"), PropertyID: f.property.ID, TaskID: paintingTask.ID, WorkTypeID: f.workTypes[1].ID}
+	testConnection.dbClient.Create(&paintingRequest)
+
+	var createTests = []struct {
+		data                   models.CreateMaintenanceBudget
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateMaintenanceBudget{Year: now.Year(), Amount: db.NewMoney(10000000, "IDR"), Property: f.property}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateMaintenanceBudget{Year: now.Year(), Month: 13, Amount: db.NewMoney(10000000, "IDR"), Property: f.property}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid month fail test"},
+		// Annual budget across all work types
+		{models.CreateMaintenanceBudget{Year: now.Year(), Amount: db.NewMoney(10000000, "IDR"), AlertThreshold: 80, Property: f.property}, testConnection.accounts.admin.token, http.StatusCreated, "admin annual create test"},
+		// Monthly plumbing budget
+		{models.CreateMaintenanceBudget{Year: now.Year(), Month: int(now.Month()), Amount: db.NewMoney(1000000, "IDR"), Property: f.property, WorkType: f.workTypes[0]}, testConnection.accounts.admin.token, http.StatusCreated, "admin monthly create test"},
+		{models.CreateMaintenanceBudget{Year: now.Year(), Month: int(now.Month()), Amount: db.NewMoney(2000000, "IDR"), Property: f.property, WorkType: f.workTypes[0]}, testConnection.accounts.admin.token, http.StatusConflict, "admin duplicate period fail test"},
+	}
+
+	createdBudgets := []db.MaintenanceBudget{}
+	for _, v := range createTests {
+		// Make new request with budget creation in body
+		req, err := http.NewRequest("POST", "/api/maintenance-budgets", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Maintenance budget create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			var created db.MaintenanceBudget
+			json.Unmarshal(rr.Body.Bytes(), &created)
+			createdBudgets = append(createdBudgets, created)
+		}
+	}
+	if len(createdBudgets) != 2 {
+		t.Fatalf("Maintenance budget create: expected 2 budgets, got %d", len(createdBudgets))
+	}
+	if createdBudgets[1].AlertThreshold != 90 {
+		t.Errorf("Maintenance budget create: expected default alert threshold of 90, got %v", createdBudgets[1].AlertThreshold)
+	}
+
+	// Actual vs budget report
+	rr := serveAsAdmin(t, "GET", fmt.Sprintf("/api/maintenance-budgets/report/%v?year=%v", f.property.ID, now.Year()), nil)
+	var report models.MaintenanceBudgetReport
+	json.Unmarshal(rr.Body.Bytes(), &report)
+	if rr.Code != http.StatusOK || len(report.Budgets) != 2 || len(report.Months) != 12 {
+		t.Fatalf("Maintenance budget report: expected 2 budgets and 12 months, got %v %v", rr.Code, rr.Body.String())
+	}
+	annual, monthly := report.Budgets[0], report.Budgets[1]
+	if annual.Actual.Float() != 1776000 || annual.InvoicedCosts.Float() != 1221000 || annual.RequestCosts.Float() != 555000 || annual.PercentUsed != 17.8 || annual.OverThreshold {
+		t.Errorf("Maintenance budget report: expected annual spend of 1776000 (17.8%%), got %v", annual)
+	}
+	if monthly.Actual.Float() != 1221000 || monthly.Remaining.Float() != -221000 || monthly.PercentUsed != 122.1 || !monthly.OverThreshold {
+		t.Errorf("Maintenance budget report: expected monthly plumbing spend of 1221000 (122.1%%), got %v", monthly)
+	}
+	if report.Months[now.Month()-1].Actual.Float() != 1776000 || report.Total.Actual.Float() != 1776000 {
+		t.Errorf("Maintenance budget report: expected 1776000 spent this month and year, got %v and %v", report.Months[now.Month()-1].Actual, report.Total.Actual)
+	}
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/maintenance-budgets/report/%v?year=20", f.property.ID), nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Maintenance budget report with invalid year: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	// Alerts are only sent once for budgets over their threshold
+	for i := 0; i < 2; i++ {
+		err := testConnection.maintenanceBudgets.serv.ProcessBudgetAlerts()
+		if err != nil {
+			t.Fatalf("Process budget alerts failed: %v", err)
+		}
+	}
+	testConnection.notifications.serv.Wait()
+	var found []db.Notification
+	testConnection.dbClient.Where("user_id = ? AND type = ?", testConnection.accounts.admin.details.ID, "MaintenanceBudgetExceeded").Find(&found)
+	if len(found) != 1 {
+		t.Errorf("Maintenance budget alerts: expected 1 admin notification, got %v", found)
+	}
+
+	// Raising the budget re-arms the alert
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/maintenance-budgets/%v", createdBudgets[1].ID), models.UpdateMaintenanceBudget{Amount: db.NewMoney(2000000, "IDR")})
+	var updated db.MaintenanceBudget
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	if rr.Code != http.StatusOK || updated.Amount.Float() != 2000000 || updated.AlertSentAt != nil {
+		t.Errorf("Maintenance budget update: expected amount 2000000 with alert cleared, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(found)
+	testConnection.dbClient.Unscoped().Delete(createdBudgets)
+	testConnection.dbClient.Unscoped().Delete(&createdInvoice)
+	testConnection.dbClient.Delete(&paintingRequest)
+	testConnection.dbClient.Delete(&paintingTask)
+	f.delete()
+}
@@ -18,6 +18,8 @@ type MaintenanceRequestController interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	CreateWithTask(w http.ResponseWriter, r *http.Request)
+	SuggestedVendors(w http.ResponseWriter, r *http.Request)
 }
 
 type maintenanceRequestController struct {
@@ -153,6 +155,55 @@ func (c maintenanceRequestController) Create(w http.ResponseWriter, r *http.Requ
 	w.Write([]byte("Maintenance request creation successful!"))
 }
 
+// API/TASKS/WITH-MAINTENANCE
+// Create a new task together with its maintenance request
+// @Summary      Create task with maintenance request
+// @Description  Creates a task and its maintenance request in one database transaction, so neither is created without the other. The maintenance request's task is ignored
+// @Tags         Maintenance Requests
+// @Accept       json
+// @Produce      json
+// @Param        task body models.CreateTaskWithMaintenanceRequest true "New Task With Maintenance Request Json"
+// @Success      201 {object} db.Task
+// @Failure      400 {string} string "Task and maintenance request creation failed."
+// @Router       /tasks/with-maintenance [post]
+// @Security BearerToken
+func (c maintenanceRequestController) CreateWithTask(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var create models.CreateTaskWithMaintenanceRequest
+	// Decode request body from JSON and store
+	err := json.NewDecoder(r.Body).Decode(&create)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&create)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create task and maintenance request in db
+	createdTask, createErr := c.service.CreateWithTask(&create)
+	if createErr != nil {
+		http.Error(w, "Task and maintenance request creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created task to output
+	err = helpers.WriteAsJSON(w, createdTask)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
 // Update a maintenance request (using URL parameter id)
 // @Summary      Update maintenance request
 // @Description  Updates an existing maintenance request
@@ -243,3 +294,38 @@ func (c maintenanceRequestController) Delete(w http.ResponseWriter, r *http.Requ
 	// Else write success
 	w.Write([]byte("Deletion successful!"))
 }
+
+// Ranks vendors for dispatch to a maintenance request
+// @Summary      Suggest vendors for maintenance request
+// @Description  Ranks vendors offering the request's work type by proximity to the property, on time completion of past work orders, ratings and open workload
+// @Tags         Maintenance Requests
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Maintenance Request ID"
+// @Success      200 {object} []models.SuggestedVendor
+// @Failure      400 {string} string "Can't suggest vendors for maintenance request with ID:"
+// @Failure      400 {string} string "Invalid ID"
+// @Router       /maintenance/{id}/suggested-vendors [get]
+// @Security BearerToken
+func (c maintenanceRequestController) SuggestedVendors(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	// Rank vendors for maintenance request
+	suggestions, err := c.service.SuggestVendors(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't suggest vendors for maintenance request with ID: %v\n%v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	// Write suggestions to response
+	err = helpers.WriteAsJSON(w, suggestions)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't suggest vendors for maintenance request with ID: %v\n", idParameter), http.StatusBadRequest)
+		return
+	}
+}
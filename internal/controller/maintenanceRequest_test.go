@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/dmawardi/Go-Template/internal/db"
 	"github.com/dmawardi/Go-Template/internal/models"
@@ -690,10 +691,10 @@ func checkMaintenanceRequestDetails(actual *db.MaintenanceRequest, expected *db.
 	if actual.Scale != expected.Scale {
 		t.Errorf("found maintenance request has incorrect scale: expected %s, got %s", expected.Scale, actual.Scale)
 	}
-	if actual.Tax != expected.Tax {
+	if !actual.Tax.Equal(expected.Tax) {
 		t.Errorf("found maintenance request has incorrect tax: expected %v, got %v", expected.Tax, actual.Tax)
 	}
-	if actual.TotalCost != expected.TotalCost {
+	if !actual.TotalCost.Equal(expected.TotalCost) {
 		t.Errorf("found maintenance request has incorrect total cost: expected %v, got %v", expected.TotalCost, actual.TotalCost)
 	}
 	if actual.Type != expected.Type {
@@ -709,3 +710,93 @@ func checkMaintenanceRequestDetails(actual *db.MaintenanceRequest, expected *db.
 	}
 
 }
+
+func TestMaintenanceController_SuggestedVendors(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	// Second plumber is based in the property's suburb
+	testConnection.dbClient.Model(&f.vendors[1]).Update("suburb", f.property.Suburb)
+	// First plumber has an open work order and finished late in the past
+	scheduledEnd := time.Now().AddDate(0, 0, -10)
+	completedAt := scheduledEnd.AddDate(0, 0, 2)
+	createdOrders := []db.WorkOrder{
+		{Description: "Open work order", Status: "In Progress", MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, TaskID: f.task.ID},
+		{Description: "Late work order", Status: "Completed", ScheduledEnd: &scheduledEnd, CompletedAt: &completedAt, MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, TaskID: f.task.ID},
+	}
+	for i := range createdOrders {
+		testConnection.dbClient.Create(&createdOrders[i])
+	}
+	// First plumber is rated
+	rating := db.VendorRating{Quality: 5, Timeliness: 3, Price: 4, Communication: 4, MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, UserID: testConnection.accounts.admin.details.ID}
+	testConnection.dbClient.Create(&rating)
+
+	var suggestTests = []struct {
+		id                     uint
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{f.request.ID, testConnection.accounts.user.token, http.StatusForbidden, "basic user suggest test"},
+		{9999, testConnection.accounts.admin.token, http.StatusBadRequest, "admin missing request fail test"},
+		{f.request.ID, testConnection.accounts.admin.token, http.StatusOK, "admin suggest test"},
+	}
+
+	var suggestions []models.SuggestedVendor
+	for _, v := range suggestTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", fmt.Sprintf("/api/maintenance/%v/suggested-vendors", v.id), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+
+		// Use handler with recorder and created request
+		testConnection.router.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Suggested vendors (%v): got %v want %v", v.testName, status, v.expectedResponseStatus)
+		}
+		if v.expectedResponseStatus == http.StatusOK {
+			json.Unmarshal(rr.Body.Bytes(), &suggestions)
+		}
+	}
+
+	// Check ranking of fixture vendors: local plumber then busy late plumber. The painter doesn't offer plumbing
+	expectedOrder := []uint{f.vendors[1].ID, f.vendors[0].ID}
+	found := map[uint]models.SuggestedVendor{}
+	ranked := []uint{}
+	for _, suggestion := range suggestions {
+		for _, id := range expectedOrder {
+			if suggestion.VendorID == id {
+				found[id] = suggestion
+				ranked = append(ranked, id)
+			}
+		}
+	}
+	if fmt.Sprint(ranked) != fmt.Sprint(expectedOrder) {
+		t.Errorf("Suggested vendors: expected fixture vendors ranked %v, got %v", expectedOrder, ranked)
+	}
+	if local := found[f.vendors[1].ID]; !local.WorkTypeMatch || local.Proximity != "Suburb" {
+		t.Errorf("Suggested vendors: expected local plumber to match work type and suburb, got %v", local)
+	}
+	if busy := found[f.vendors[0].ID]; busy.OpenWorkOrders != 1 || busy.CompletedWorkOrders != 1 || busy.OnTimeRate != 0 || busy.Proximity != "Province" {
+		t.Errorf("Suggested vendors: expected 1 open and 1 late completed work order in the property's province, got %v", busy)
+	}
+	if busy := found[f.vendors[0].ID]; busy.Ratings != 1 || busy.AverageRating != 4 || busy.Score != 66 {
+		t.Errorf("Suggested vendors: expected 1 rating averaging 4 and a score of 66, got %v", busy)
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.VendorID == f.vendors[2].ID {
+			t.Errorf("Suggested vendors: expected painter not to be suggested for plumbing")
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Unscoped().Delete(&rating)
+	testConnection.dbClient.Delete(createdOrders)
+	f.delete()
+}
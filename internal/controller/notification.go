@@ -0,0 +1,354 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type NotificationController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	// Delivery preferences
+	FindPreferences(w http.ResponseWriter, r *http.Request)
+	UpdatePreference(w http.ResponseWriter, r *http.Request)
+	// Failed deliveries (admin)
+	FindAllDeadLetters(w http.ResponseWriter, r *http.Request)
+}
+
+type notificationController struct {
+	service service.NotificationService
+}
+
+func NewNotificationController(service service.NotificationService) NotificationController {
+	return &notificationController{service}
+}
+
+// API/ME/NOTIFICATIONS
+// Find a list of my notifications
+// @Summary      Find a list of my notifications
+// @Description  Accepts limit, offset, order and unread params and returns list of the current user's notifications
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        unread   path      bool  false  "only return unread"
+// @Success      200 {object} []db.Notification
+// @Failure      400 {string} string "Can't find notifications"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /me/notifications [get]
+// @Security BearerToken
+func (c notificationController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	unreadParam := r.URL.Query().Get("unread")
+
+	// Convert to int/bool
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	unreadOnly, _ := strconv.ParseBool(unreadParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Query database for user's notifications using query params
+	foundNotifications, err := c.service.FindAllByUser(userID, limit, offset, orderBy, unreadOnly)
+	if err != nil {
+		http.Error(w, "Can't find notifications", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundNotifications)
+	if err != nil {
+		http.Error(w, "Can't find notifications", http.StatusBadRequest)
+		fmt.Println("error writing notifications to response: ", err)
+		return
+	}
+}
+
+// Find one of my notifications
+// @Summary      Find notification
+// @Description  Find one of the current user's notifications by ID
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Notification ID"
+// @Success      200 {object} db.Notification
+// @Failure      400 {string} string "Can't find notification with ID: {id}"
+// @Router       /me/notifications/{id} [get]
+// @Security BearerToken
+func (c notificationController) Find(w http.ResponseWriter, r *http.Request) {
+	// Find notification belonging to user
+	foundNotification, ok := c.findMyNotification(w, r)
+	if !ok {
+		return
+	}
+
+	err := helpers.WriteAsJSON(w, foundNotification)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find notification with ID: %v.\n %v", foundNotification.ID, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Update read state of one of my notifications (using URL parameter id)
+// @Summary      Update notification read state
+// @Description  Marks one of the current user's notifications as read or unread
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        notification body models.UpdateNotification true "Update Notification Json"
+// @Param        id   path      int  true  "Notification ID"
+// @Success      200 {object} db.Notification
+// @Failure      400 {string} string "Failed notification update"
+// @Router       /me/notifications/{id} [put]
+// @Security BearerToken
+func (c notificationController) Update(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var notification models.UpdateNotification
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&notification)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&notification)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Find notification belonging to user
+	foundNotification, ok := c.findMyNotification(w, r)
+	if !ok {
+		return
+	}
+
+	// Update read state
+	updatedNotification, updateErr := c.service.SetReadState(int(foundNotification.ID), notification.Read)
+	if updateErr != nil {
+		http.Error(w, fmt.Sprintf("Failed notification update: %s", updateErr), http.StatusBadRequest)
+		return
+	}
+
+	// Write notification to output
+	err = helpers.WriteAsJSON(w, updatedNotification)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Delete one of my notifications (using URL parameter id)
+// @Summary      Delete notification
+// @Description  Deletes one of the current user's notifications
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Notification ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed notification deletion"
+// @Router       /me/notifications/{id} [delete]
+// @Security BearerToken
+func (c notificationController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Find notification belonging to user
+	foundNotification, ok := c.findMyNotification(w, r)
+	if !ok {
+		return
+	}
+
+	// Attempt to delete notification using id
+	err := c.service.Delete(int(foundNotification.ID))
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed notification deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/ME/NOTIFICATION-PREFERENCES
+// Find my notification preferences
+// @Summary      Find my notification preferences
+// @Description  Returns the current user's delivery channels for each notification event type
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} []db.NotificationPreference
+// @Failure      400 {string} string "Can't find notification preferences"
+// @Router       /me/notification-preferences [get]
+// @Security BearerToken
+func (c notificationController) FindPreferences(w http.ResponseWriter, r *http.Request) {
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	foundPreferences, err := c.service.FindPreferences(userID)
+	if err != nil {
+		http.Error(w, "Can't find notification preferences", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundPreferences)
+	if err != nil {
+		http.Error(w, "Can't find notification preferences", http.StatusBadRequest)
+		return
+	}
+}
+
+// Update my notification preference for an event type
+// @Summary      Update my notification preference
+// @Description  Sets the delivery channels (in app, email, webhook) used for a notification event type
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        preference body models.UpdateNotificationPreference true "Update Notification Preference Json"
+// @Success      200 {object} db.NotificationPreference
+// @Failure      400 {string} string "Failed notification preference update"
+// @Router       /me/notification-preferences [put]
+// @Security BearerToken
+func (c notificationController) UpdatePreference(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var preference models.UpdateNotificationPreference
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&preference)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&preference)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Update preference
+	updatedPreference, updateErr := c.service.UpdatePreference(userID, &preference)
+	if updateErr != nil {
+		http.Error(w, fmt.Sprintf("Failed notification preference update: %s", updateErr), http.StatusBadRequest)
+		return
+	}
+
+	// Write preference to output
+	err = helpers.WriteAsJSON(w, updatedPreference)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// API/NOTIFICATION-DEAD-LETTERS
+// Find a list of notifications that failed delivery
+// @Summary      Find a list of failed notification deliveries
+// @Description  Accepts limit, offset, and order params and returns notifications that failed all delivery attempts
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Success      200 {object} []db.NotificationDeadLetter
+// @Failure      400 {string} string "Can't find notification dead letters"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /notification-dead-letters [get]
+// @Security BearerToken
+func (c notificationController) FindAllDeadLetters(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	foundDeadLetters, err := c.service.FindAllDeadLetters(limit, offset, orderBy)
+	if err != nil {
+		http.Error(w, "Can't find notification dead letters", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundDeadLetters)
+	if err != nil {
+		http.Error(w, "Can't find notification dead letters", http.StatusBadRequest)
+		fmt.Println("error writing notification dead letters to response: ", err)
+		return
+	}
+}
+
+// Finds notification using URL parameter id and ensures it belongs to the user from token.
+// Writes error to response and returns false if not found
+func (c notificationController) findMyNotification(w http.ResponseWriter, r *http.Request) (*db.Notification, bool) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return nil, false
+	}
+
+	// Find notification and check ownership (others' notifications are treated as not found)
+	foundNotification, err := c.service.FindById(idParameter)
+	if err != nil || foundNotification.UserID != uint(userID) {
+		http.Error(w, fmt.Sprintf("Can't find notification with ID: %v", idParameter), http.StatusBadRequest)
+		return nil, false
+	}
+	return foundNotification, true
+}
@@ -0,0 +1,272 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+)
+
+func TestNotificationController_FindAll(t *testing.T) {
+	// Test setup
+	// Two notifications for basic user (one read) and one for admin
+	createdNotifications := []db.Notification{
+		{UserID: testConnection.accounts.user.details.ID, Type: "Mention", Message: "You were mentioned"},
+		{UserID: testConnection.accounts.user.details.ID, Type: "Mention", Message: "You were mentioned again", Read: true},
+		{UserID: testConnection.accounts.admin.details.ID, Type: "Mention", Message: "Admin was mentioned"},
+	}
+	createResult := testConnection.dbClient.Create(createdNotifications)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create notifications for find all test: ", createResult.Error)
+	}
+
+	var findAllTests = []struct {
+		request                string
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedLength         int
+	}{
+		// Only the user's own notifications are returned
+		{"/api/me/notifications?limit=10", testConnection.accounts.user.token, http.StatusOK, 2},
+		{"/api/me/notifications?limit=10&unread=true", testConnection.accounts.user.token, http.StatusOK, 1},
+		{"/api/me/notifications?limit=10", testConnection.accounts.admin.token, http.StatusOK, 1},
+		// No limit should result in bad request
+		{"/api/me/notifications", testConnection.accounts.user.token, http.StatusBadRequest, 0},
+	}
+
+	for _, v := range findAllTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", v.request, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+
+		// Use handler with recorder and created request
+		testConnection.router.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Notification find all (%v): got %v want %v", v.request, status, v.expectedResponseStatus)
+		}
+
+		// Check length of notifications array if successful
+		if v.expectedResponseStatus == http.StatusOK {
+			var body []db.Notification
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if len(body) != v.expectedLength {
+				t.Errorf("Notifications array in findAll (%v) failed: expected %d, got %d", v.request, v.expectedLength, len(body))
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(createdNotifications)
+}
+
+func TestNotificationController_Update(t *testing.T) {
+	// Test setup
+	createdNotifications := []db.Notification{
+		{UserID: testConnection.accounts.user.details.ID, Type: "Mention", Message: "You were mentioned"},
+	}
+	createResult := testConnection.dbClient.Create(createdNotifications)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create notifications for update test: ", createResult.Error)
+	}
+
+	var updateTests = []struct {
+		data                   models.UpdateNotification
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedRead           bool
+		testName               string
+	}{
+		// Admin can't update another user's notification
+		{models.UpdateNotification{Read: true}, testConnection.accounts.admin.token, http.StatusBadRequest, false, "admin other user's notification test"},
+		{models.UpdateNotification{Read: true}, testConnection.accounts.user.token, http.StatusOK, true, "user mark read test"},
+		{models.UpdateNotification{Read: false}, testConnection.accounts.user.token, http.StatusOK, false, "user mark unread test"},
+	}
+
+	requestUrl := fmt.Sprintf("/api/me/notifications/%v", createdNotifications[0].ID)
+	for _, v := range updateTests {
+		// Make new request with notification update in body
+		req, err := http.NewRequest("PUT", requestUrl, buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send update request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Notification update test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+
+		// Check read state in database
+		var found db.Notification
+		testConnection.dbClient.First(&found, createdNotifications[0].ID)
+		if found.Read != v.expectedRead || (found.ReadAt != nil) != v.expectedRead {
+			t.Errorf("Notification update test (%v): expected read %v, got %v at %v", v.testName, v.expectedRead, found.Read, found.ReadAt)
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(createdNotifications)
+}
+
+func TestNotificationController_Preferences(t *testing.T) {
+	// Check defaults are returned for every event type
+	req, err := http.NewRequest("GET", "/api/me/notification-preferences", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.user.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Notification preferences find: got %v want %v", status, http.StatusOK)
+	}
+	var preferences []db.NotificationPreference
+	json.Unmarshal(rr.Body.Bytes(), &preferences)
+	if len(preferences) != len(models.NotificationEventTypes) {
+		t.Errorf("Notification preferences find: expected %d preferences, got %d", len(models.NotificationEventTypes), len(preferences))
+	}
+	for _, preference := range preferences {
+		if !preference.InApp || preference.Email || preference.Webhook {
+			t.Errorf("Notification preferences find: expected in app only default for %s, got %v", preference.EventType, preference)
+		}
+	}
+
+	var updateTests = []struct {
+		data                   models.UpdateNotificationPreference
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.UpdateNotificationPreference{EventType: "TaskAssigned", InApp: false, Email: true}, http.StatusOK, "disable in app test"},
+		{models.UpdateNotificationPreference{EventType: "TaskAssigned", InApp: true, Email: true}, http.StatusOK, "update existing test"},
+		{models.UpdateNotificationPreference{EventType: "Birthday", InApp: true}, http.StatusBadRequest, "invalid event type test"},
+		{models.UpdateNotificationPreference{EventType: "Mention", Webhook: true, WebhookURL: "not a url"}, http.StatusBadRequest, "invalid webhook url test"},
+		{models.UpdateNotificationPreference{EventType: "Mention", Webhook: true, WebhookURL: "http://hooks.example.com/notify"}, http.StatusBadRequest, "plain http webhook url test"},
+		{models.UpdateNotificationPreference{EventType: "Mention", Webhook: true, WebhookURL: "https://169.254.169.254/latest/meta-data"}, http.StatusBadRequest, "metadata address webhook url test"},
+		{models.UpdateNotificationPreference{EventType: "Mention", Webhook: true, WebhookURL: "https://localhost:8080/admin"}, http.StatusBadRequest, "localhost webhook url test"},
+		{models.UpdateNotificationPreference{EventType: "Mention", Webhook: true, WebhookURL: "https://hooks.example.com/notify"}, http.StatusOK, "public webhook url test"},
+	}
+
+	for _, v := range updateTests {
+		req, err := http.NewRequest("PUT", "/api/me/notification-preferences", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.user.token))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Notification preference update (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+		// Check stored preference matches update
+		if v.expectedResponseStatus == http.StatusOK {
+			var body db.NotificationPreference
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if body.InApp != v.data.InApp || body.Email != v.data.Email || body.Webhook != v.data.Webhook {
+				t.Errorf("Notification preference update (%v): expected %v, got %v", v.testName, v.data, body)
+			}
+		}
+	}
+
+	// Only one preference should be stored for the event type
+	var count int64
+	testConnection.dbClient.Model(&db.NotificationPreference{}).Where("user_id = ? AND event_type = ?", testConnection.accounts.user.details.ID, "TaskAssigned").Count(&count)
+	if count != 1 {
+		t.Errorf("Notification preference update: expected 1 stored preference, got %d", count)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("user_id = ?", testConnection.accounts.user.details.ID).Delete(&db.NotificationPreference{})
+}
+
+func TestNotificationController_TaskAssignedDelivery(t *testing.T) {
+	// Assign basic user to a new task through the task service
+	createdTask, err := testConnection.tasks.serv.Create(&models.CreateTask{
+		TaskName:   "Inspect the pool pump",
+		Type:       "Inspection",
+		Assignment: []db.User{*testConnection.accounts.user.details},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create task for task assigned delivery test: %v", err)
+	}
+	// Wait for delivery
+	testConnection.notifications.serv.Wait()
+
+	// Check in app notification was delivered
+	var found []db.Notification
+	testConnection.dbClient.Where("user_id = ? AND task_id = ? AND type = ?", testConnection.accounts.user.details.ID, createdTask.ID, "TaskAssigned").Find(&found)
+	if len(found) != 1 {
+		t.Errorf("Task assigned delivery: expected 1 notification, got %d", len(found))
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(found)
+	testConnection.dbClient.Model(createdTask).Association("Assignment").Clear()
+	testConnection.dbClient.Delete(createdTask)
+}
+
+// Notifier that takes a while to deliver, counting deliveries
+type slowNotifier struct {
+	delivered int32
+}
+
+func (n *slowNotifier) Channel() string {
+	return "Webhook"
+}
+
+func (n *slowNotifier) Enabled(preference *db.NotificationPreference) bool {
+	return true
+}
+
+func (n *slowNotifier) Notify(recipient *db.User, preference *db.NotificationPreference, event *models.NotificationEvent) error {
+	time.Sleep(50 * time.Millisecond)
+	atomic.AddInt32(&n.delivered, 1)
+	return nil
+}
+
+func TestNotificationController_SlowChannelDoesNotBlockDispatch(t *testing.T) {
+	notifier := &slowNotifier{}
+	notifications := service.NewNotificationService(testConnection.notifications.repo, testConnection.users.repo, testConnection.tasks.repo, notifier)
+
+	// More events than the queue holds are dispatched without waiting on delivery
+	started := time.Now()
+	for i := 0; i < 150; i++ {
+		notifications.Dispatch(&models.NotificationEvent{Type: "TaskAssigned", Message: "Slow delivery", UserIDs: []uint{testConnection.accounts.user.details.ID}})
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Notification dispatch: expected dispatch not to wait on delivery, took %v", elapsed)
+	}
+	notifications.Wait()
+
+	// Events are either delivered or dead lettered
+	var deadLetters int64
+	testConnection.dbClient.Model(&db.NotificationDeadLetter{}).Where("message = ?", "Slow delivery").Count(&deadLetters)
+	if delivered := atomic.LoadInt32(&notifier.delivered); int64(delivered)+deadLetters != 150 {
+		t.Errorf("Notification dispatch: expected 150 deliveries or dead letters, got %v delivered and %v dead lettered", delivered, deadLetters)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("message = ?", "Slow delivery").Delete(&db.NotificationDeadLetter{})
+}
@@ -0,0 +1,378 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type OwnerStatementController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Generate(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Finalise(w http.ResponseWriter, r *http.Request)
+	PDF(w http.ResponseWriter, r *http.Request)
+	CSV(w http.ResponseWriter, r *http.Request)
+}
+
+type ownerStatementController struct {
+	service service.OwnerStatementService
+	rates   service.ExchangeRateService
+}
+
+func NewOwnerStatementController(service service.OwnerStatementService, rates service.ExchangeRateService) OwnerStatementController {
+	return &ownerStatementController{service, rates}
+}
+
+// API/OWNER-STATEMENTS
+// Find a list of owner statements
+// @Summary      Find a list of owner statements
+// @Description  Accepts limit, offset, order, property and year params and returns list of owner statements (latest month first by default)
+// @Tags         Owner Statements
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        property   path      int  false  "property id"
+// @Param        year   path      int  false  "year"
+// @Success      200 {object} []db.OwnerStatement
+// @Failure      400 {string} string "Can't find owner statements"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /owner-statements [get]
+// @Security BearerToken
+func (c ownerStatementController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	propertyParam := r.URL.Query().Get("property")
+	yearParam := r.URL.Query().Get("year")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	propertyId, _ := strconv.Atoi(propertyParam)
+	year, _ := strconv.Atoi(yearParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all owner statements using query params
+	foundStatements, err := c.service.FindAll(limit, offset, orderBy, propertyId, year)
+	if err != nil {
+		http.Error(w, "Can't find owner statements", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundStatements)
+	if err != nil {
+		http.Error(w, "Can't find owner statements", http.StatusBadRequest)
+		fmt.Println("error writing owner statements to response: ", err)
+		return
+	}
+}
+
+// Find a created owner statement
+// @Summary      Find owner statement
+// @Description  Find an owner statement with its lines by ID
+// @Tags         Owner Statements
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Owner Statement ID"
+// @Success      200 {object} db.OwnerStatement
+// @Failure      400 {string} string "Can't find owner statement with ID: {id}"
+// @Router       /owner-statements/{id} [get]
+// @Security BearerToken
+func (c ownerStatementController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundStatement, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find owner statement with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundStatement)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find owner statement with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Generate an owner statement
+// @Summary      Generate owner statement
+// @Description  Generates a managed property's statement for a month from rent received, management fees, maintenance costs and vendor invoices. A draft statement for the month is regenerated, keeping its payment details
+// @Tags         Owner Statements
+// @Accept       json
+// @Produce      json
+// @Param        statement body models.GenerateOwnerStatement true "Owner Statement Period Json"
+// @Success      201 {object} db.OwnerStatement
+// @Failure      400 {string} string "Owner statement generation failed."
+// @Failure      409 {string} string "Owner statement has been finalised"
+// @Router       /owner-statements [post]
+// @Security BearerToken
+func (c ownerStatementController) Generate(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var statement models.GenerateOwnerStatement
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&statement)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&statement)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Generate owner statement in db
+	generatedStatement, generateErr := c.service.Generate(&statement)
+	if generateErr != nil {
+		if errors.Is(generateErr, service.ErrStatementFinalised) {
+			http.Error(w, generateErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Owner statement generation failed: "+generateErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write generated statement to output
+	err = helpers.WriteAsJSON(w, generatedStatement)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update an owner statement (using URL parameter id)
+// @Summary      Update owner statement
+// @Description  Records the payment to the owner of a draft statement and updates its closing balance
+// @Tags         Owner Statements
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Owner Statement ID"
+// @Param        statement body models.UpdateOwnerStatement true "Update Owner Statement Json"
+// @Success      200 {object} db.OwnerStatement
+// @Failure      400 {string} string "Failed owner statement update"
+// @Failure      409 {string} string "Owner statement has been finalised"
+// @Router       /owner-statements/{id} [put]
+// @Security BearerToken
+func (c ownerStatementController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var statement models.UpdateOwnerStatement
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&statement)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&statement)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update owner statement
+	updatedStatement, err := c.service.Update(idParameter, &statement)
+	if err != nil {
+		if errors.Is(err, service.ErrStatementFinalised) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed owner statement update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated statement to output
+	err = helpers.WriteAsJSON(w, updatedStatement)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed owner statement update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete owner statement (using URL parameter id)
+// @Summary      Delete owner statement
+// @Description  Deletes a draft owner statement
+// @Tags         Owner Statements
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Owner Statement ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed owner statement deletion"
+// @Failure      409 {string} string "Owner statement has been finalised"
+// @Router       /owner-statements/{id} [delete]
+// @Security BearerToken
+func (c ownerStatementController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete owner statement using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		if errors.Is(err, service.ErrStatementFinalised) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed owner statement deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Finalise owner statement (using URL parameter id)
+// @Summary      Finalise owner statement
+// @Description  Regenerates a draft statement with the latest figures and locks it. The previous month's statement, if any, must be final as its closing balance is carried forward
+// @Tags         Owner Statements
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Owner Statement ID"
+// @Success      200 {object} db.OwnerStatement
+// @Failure      400 {string} string "Failed owner statement finalisation"
+// @Failure      409 {string} string "Owner statement has been finalised"
+// @Failure      409 {string} string "The previous month's owner statement must be finalised first"
+// @Router       /owner-statements/finalise/{id} [post]
+// @Security BearerToken
+func (c ownerStatementController) Finalise(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	finalisedStatement, err := c.service.Finalise(idParameter)
+	if err != nil {
+		if errors.Is(err, service.ErrStatementFinalised) || errors.Is(err, service.ErrPreviousStatementDraft) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed owner statement finalisation: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write finalised statement to output
+	err = helpers.WriteAsJSON(w, finalisedStatement)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed owner statement finalisation: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Owner statement as PDF (using URL parameter id)
+// @Summary      Owner statement PDF
+// @Description  Exports an owner statement as a PDF document
+// @Tags         Owner Statements
+// @Produce      application/pdf
+// @Param        id   path      int  true  "Owner Statement ID"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {file} file "Owner statement PDF"
+// @Failure      400 {string} string "Can't export owner statement"
+// @Router       /owner-statements/pdf/{id} [get]
+// @Security BearerToken
+func (c ownerStatementController) PDF(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	document, fileName, err := c.service.PDF(idParameter, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't export owner statement: %s", err), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Write(document)
+}
+
+// Owner statement as CSV (using URL parameter id)
+// @Summary      Owner statement CSV
+// @Description  Exports an owner statement's lines and summary as CSV
+// @Tags         Owner Statements
+// @Produce      text/csv
+// @Param        id   path      int  true  "Owner Statement ID"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {file} file "Owner statement CSV"
+// @Failure      400 {string} string "Can't export owner statement"
+// @Router       /owner-statements/csv/{id} [get]
+// @Security BearerToken
+func (c ownerStatementController) CSV(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	document, fileName, err := c.service.CSV(idParameter, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't export owner statement: %s", err), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Write(document)
+}
@@ -0,0 +1,194 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestOwnerStatementController_GenerateFinaliseAndExport(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	midMonth := monthStart.Add(12 * time.Hour)
+	lastMonth := monthStart.AddDate(0, -1, 0)
+
+	// Management agreement charging 20% of rent received
+	management := db.Transaction{Type: "Management", Agency: "Own", ManagementFeeRate: 20, PropertyID: f.property.ID}
+	testConnection.dbClient.Create(&management)
+	lease := db.Lease{StartDate: lastMonth, EndDate: lastMonth.AddDate(1, 0, 0), RentAmount: db.NewMoney(5000000, "IDR"), Frequency: "Monthly", PropertyID: f.property.ID}
+	testConnection.dbClient.Create(&lease)
+	receipts := []db.LedgerEntry{
+		{EntryDate: lastMonth.AddDate(0, 0, 2), Type: "Receipt", Account: "Rent", Credit: db.NewMoney(5000000, "IDR"), Reference: "BCA-1", LeaseID: lease.ID},
+		{EntryDate: midMonth, Type: "Receipt", Account: "Rent", Credit: db.NewMoney(10000000, "IDR"), Reference: "BCA-2", LeaseID: lease.ID},
+	}
+	testConnection.dbClient.Create(receipts)
+	// Uninvoiced plumbing repair and an invoiced painting job this month
+	testConnection.dbClient.Model(&f.request).UpdateColumns(map[string]interface{}{"total_cost_amount": 50000000, "tax_amount": 5500000, "created_at": midMonth})
+	paintingTask := db.Task{TaskName: "Repaint the gate", Type: "Maintenance"}
+	testConnection.dbClient.Create(&paintingTask)
+	paintingRequest := db.MaintenanceRequest{Scale: "Low", WorkDefinition: "Repair", Type: "Painting", PropertyID: f.property.ID, TaskID: paintingTask.ID, WorkTypeID: f.workTypes[1].ID}
+	testConnection.dbClient.Create(&paintingRequest)
+	invoice := db.VendorInvoice{InvoiceNumber: "WP-77", VendorNPWP: f.vendors[2].NPWP, InvoiceDate: midMonth, DueDate: midMonth, Subtotal: db.NewMoney(1100000, "IDR"), Total: db.NewMoney(1221000, "IDR"), Status: "Unpaid", VendorID: f.vendors[2].ID, MaintenanceRequestID: &paintingRequest.ID}
+	testConnection.dbClient.Create(&invoice)
+
+	var generateTests = []struct {
+		data                   models.GenerateOwnerStatement
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.GenerateOwnerStatement{Year: lastMonth.Year(), Month: int(lastMonth.Month()), Property: f.property}, testConnection.accounts.user.token, http.StatusForbidden, "basic user generate test"},
+		{models.GenerateOwnerStatement{Year: lastMonth.Year(), Month: 13, Property: f.property}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid month fail test"},
+		{models.GenerateOwnerStatement{Year: lastMonth.Year(), Month: int(lastMonth.Month()), Property: f.property}, testConnection.accounts.admin.token, http.StatusCreated, "admin generate last month test"},
+		{models.GenerateOwnerStatement{Year: now.Year(), Month: int(now.Month()), Property: f.property}, testConnection.accounts.admin.token, http.StatusCreated, "admin generate this month test"},
+	}
+
+	statements := []db.OwnerStatement{}
+	for _, v := range generateTests {
+		req, err := http.NewRequest("POST", "/api/owner-statements", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send generate request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Owner statement generate test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			var statement db.OwnerStatement
+			json.Unmarshal(rr.Body.Bytes(), &statement)
+			statements = append(statements, statement)
+		}
+	}
+	if len(statements) != 2 {
+		t.Fatalf("Owner statements: expected 2 generated statements, got %v", statements)
+	}
+	previous, current := statements[0], statements[1]
+	if previous.RentReceived.Float() != 5000000 || previous.ManagementFees.Float() != 1000000 || previous.NetPayable.Float() != 4000000 || len(previous.Lines) != 2 {
+		t.Errorf("Last month's statement: expected 5000000 rent less 1000000 fees, got %+v", previous)
+	}
+
+	// Record the payment to the owner, leaving 1000000 to carry forward
+	rr := serveAsAdmin(t, "PUT", fmt.Sprintf("/api/owner-statements/%v", previous.ID), models.UpdateOwnerStatement{PaidToOwner: db.NewMoney(3000000, "IDR"), PaymentReference: "TRF-OWNER-1"})
+	json.Unmarshal(rr.Body.Bytes(), &previous)
+	if rr.Code != http.StatusOK || previous.ClosingBalance.Float() != 1000000 {
+		t.Errorf("Owner statement payment: expected closing balance of 1000000, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// This month's statement can't be finalised before last month's
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/owner-statements/finalise/%v", current.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Owner statement finalise before previous: got %v want %v", rr.Code, http.StatusConflict)
+	}
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/owner-statements/finalise/%v", previous.ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Owner statement finalise: got %v want %v. %v", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/owner-statements/finalise/%v", current.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &current)
+	// 10000000 rent - 2000000 fees - 555000 maintenance - 1221000 invoices, plus 1000000 carried forward
+	if rr.Code != http.StatusOK || current.Status != "Final" || current.OpeningBalance.Float() != 1000000 || current.ManagementFees.Float() != 2000000 ||
+		current.MaintenanceCosts.Float() != 555000 || current.VendorInvoices.Float() != 1221000 || current.NetPayable.Float() != 6224000 || current.ClosingBalance.Float() != 7224000 || len(current.Lines) != 4 {
+		t.Errorf("This month's statement: expected net payable of 6224000 closing at 7224000, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Final statements are locked
+	rr = serveAsAdmin(t, "POST", "/api/owner-statements", models.GenerateOwnerStatement{Year: now.Year(), Month: int(now.Month()), Property: f.property})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Regenerate final owner statement: got %v want %v", rr.Code, http.StatusConflict)
+	}
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/owner-statements/%v", current.ID), models.UpdateOwnerStatement{PaidToOwner: db.NewMoney(7224000, "IDR")})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Update final owner statement: got %v want %v", rr.Code, http.StatusConflict)
+	}
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/owner-statements/%v", current.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Delete final owner statement: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	// Exports
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/owner-statements/pdf/%v", current.ID), nil)
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "application/pdf" || !strings.HasPrefix(rr.Body.String(), "%PDF-") || !strings.Contains(rr.Body.String(), "IDR 7,224,000.00") {
+		t.Errorf("Owner statement PDF: expected PDF document with the closing balance, got %v %v", rr.Code, rr.Header())
+	}
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/owner-statements/csv/%v", current.ID), nil)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "WP-77") || !strings.Contains(rr.Body.String(), "Closing balance,7224000.00") {
+		t.Errorf("Owner statement CSV: expected invoice line and closing balance, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// List filtered by property
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/owner-statements?limit=10&property=%v", f.property.ID), nil)
+	var found []db.OwnerStatement
+	json.Unmarshal(rr.Body.Bytes(), &found)
+	if rr.Code != http.StatusOK || len(found) != 2 || found[0].ID != current.ID {
+		t.Errorf("Owner statement list: expected this month's statement first of 2, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Cleanup
+	testConnection.dbClient.Exec("DELETE FROM owner_statement_lines WHERE owner_statement_id IN (SELECT id FROM owner_statements WHERE property_id = ?)", f.property.ID)
+	testConnection.dbClient.Unscoped().Where("property_id = ?", f.property.ID).Delete(&db.OwnerStatement{})
+	testConnection.dbClient.Unscoped().Where("lease_id = ?", lease.ID).Delete(&db.LedgerEntry{})
+	testConnection.dbClient.Unscoped().Delete(&lease)
+	testConnection.dbClient.Unscoped().Delete(&invoice)
+	testConnection.dbClient.Unscoped().Delete(&paintingRequest)
+	testConnection.dbClient.Unscoped().Delete(&paintingTask)
+	testConnection.dbClient.Unscoped().Delete(&management)
+	f.delete()
+}
+
+func TestOwnerStatementController_FlatSchemeManagementFee(t *testing.T) {
+	// Test setup
+	f := createLeaseFixtures(t)
+	lastMonth := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+	scheme := db.CommissionScheme{Name: "Flat management", Method: "Flat", FlatFee: db.NewMoney(2000000, "IDR")}
+	testConnection.dbClient.Create(&scheme)
+	// The flat commission is an amount, so it isn't charged as a percentage of rent
+	management := db.Transaction{Type: "Management", Agency: "Own", Fee: db.NewMoney(2000000, "IDR"), CommissionSchemeID: &scheme.ID, PropertyID: f.property.ID}
+	testConnection.dbClient.Create(&management)
+	lease := db.Lease{StartDate: lastMonth, EndDate: lastMonth.AddDate(1, 0, 0), RentAmount: db.NewMoney(5000000, "IDR"), Frequency: "Monthly", PropertyID: f.property.ID}
+	testConnection.dbClient.Create(&lease)
+	receipt := db.LedgerEntry{EntryDate: lastMonth.AddDate(0, 0, 2), Type: "Receipt", Account: "Rent", Credit: db.NewMoney(5000000, "IDR"), LeaseID: lease.ID}
+	testConnection.dbClient.Create(&receipt)
+
+	var feeTests = []struct {
+		rate            float64
+		expectedFees    float64
+		expectedPayable float64
+		testName        string
+	}{
+		{0, 0, 5000000, "flat scheme without a management rate"},
+		{10, 500000, 4500000, "flat scheme with a management rate"},
+	}
+	for _, v := range feeTests {
+		testConnection.dbClient.Model(&management).Update("management_fee_rate", v.rate)
+		rr := serveAsAdmin(t, "POST", "/api/owner-statements", models.GenerateOwnerStatement{Year: lastMonth.Year(), Month: int(lastMonth.Month()), Property: f.property})
+		var statement db.OwnerStatement
+		json.Unmarshal(rr.Body.Bytes(), &statement)
+		if rr.Code != http.StatusCreated || statement.ManagementFees.Float() != v.expectedFees || statement.NetPayable.Float() != v.expectedPayable || statement.ClosingBalance.Float() != v.expectedPayable {
+			t.Errorf("Owner statement (%v): expected %v fees and %v payable, got %v %v", v.testName, v.expectedFees, v.expectedPayable, rr.Code, rr.Body.String())
+		}
+	}
+
+	// Cleanup
+	testConnection.dbClient.Exec("DELETE FROM owner_statement_lines WHERE owner_statement_id IN (SELECT id FROM owner_statements WHERE property_id = ?)", f.property.ID)
+	testConnection.dbClient.Unscoped().Where("property_id = ?", f.property.ID).Delete(&db.OwnerStatement{})
+	testConnection.dbClient.Unscoped().Delete(&management)
+	testConnection.dbClient.Unscoped().Delete(&scheme)
+	f.delete()
+}
@@ -0,0 +1,554 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type PipelineController interface {
+	FindAllStages(w http.ResponseWriter, r *http.Request)
+	FindStage(w http.ResponseWriter, r *http.Request)
+	CreateStage(w http.ResponseWriter, r *http.Request)
+	UpdateStage(w http.ResponseWriter, r *http.Request)
+	DeleteStage(w http.ResponseWriter, r *http.Request)
+	Move(w http.ResponseWriter, r *http.Request)
+	FindAllMilestones(w http.ResponseWriter, r *http.Request)
+	CreateMilestone(w http.ResponseWriter, r *http.Request)
+	UpdateMilestone(w http.ResponseWriter, r *http.Request)
+	DeleteMilestone(w http.ResponseWriter, r *http.Request)
+	Summary(w http.ResponseWriter, r *http.Request)
+}
+
+type pipelineController struct {
+	service service.PipelineService
+	rates   service.ExchangeRateService
+}
+
+func NewPipelineController(service service.PipelineService, rates service.ExchangeRateService) PipelineController {
+	return &pipelineController{service, rates}
+}
+
+// API/PIPELINE-STAGES
+// Find a list of pipeline stages
+// @Summary      Find a list of pipeline stages
+// @Description  Accepts limit, offset, order and transaction type params and returns list of pipeline stages (by type and position by default)
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        type   path      string  false  "transaction type"
+// @Success      200 {object} []db.PipelineStage
+// @Failure      400 {string} string "Can't find pipeline stages"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /pipeline-stages [get]
+// @Security BearerToken
+func (c pipelineController) FindAllStages(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	transactionType := r.URL.Query().Get("type")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all pipeline stages using query params
+	foundStages, err := c.service.FindAllStages(limit, offset, orderBy, transactionType)
+	if err != nil {
+		http.Error(w, "Can't find pipeline stages", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundStages)
+	if err != nil {
+		http.Error(w, "Can't find pipeline stages", http.StatusBadRequest)
+		fmt.Println("error writing pipeline stages to response: ", err)
+		return
+	}
+}
+
+// Find a created pipeline stage
+// @Summary      Find pipeline stage
+// @Description  Find a pipeline stage by ID
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Pipeline Stage ID"
+// @Success      200 {object} db.PipelineStage
+// @Failure      400 {string} string "Can't find pipeline stage with ID: {id}"
+// @Router       /pipeline-stages/{id} [get]
+// @Security BearerToken
+func (c pipelineController) FindStage(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundStage, err := c.service.FindStageById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find pipeline stage with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundStage)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find pipeline stage with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new pipeline stage
+// @Summary      Create pipeline stage
+// @Description  Adds a stage to the pipeline of a transaction type, at the end if no position is provided. Stages with due days set a milestone when a transaction enters them
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        stage body models.CreatePipelineStage true "New Pipeline Stage Json"
+// @Success      201 {object} db.PipelineStage
+// @Failure      400 {string} string "Pipeline stage creation failed."
+// @Router       /pipeline-stages [post]
+// @Security BearerToken
+func (c pipelineController) CreateStage(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var stage models.CreatePipelineStage
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&stage)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&stage)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create pipeline stage in db
+	createdStage, createErr := c.service.CreateStage(&stage)
+	if createErr != nil {
+		http.Error(w, "Pipeline stage creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created stage to output
+	err = helpers.WriteAsJSON(w, createdStage)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a pipeline stage (using URL parameter id)
+// @Summary      Update pipeline stage
+// @Description  Updates an existing pipeline stage. Changed due days apply to transactions entering the stage afterwards
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Pipeline Stage ID"
+// @Param        stage body models.UpdatePipelineStage true "Update Pipeline Stage Json"
+// @Success      200 {object} db.PipelineStage
+// @Failure      400 {string} string "Failed pipeline stage update"
+// @Router       /pipeline-stages/{id} [put]
+// @Security BearerToken
+func (c pipelineController) UpdateStage(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var stage models.UpdatePipelineStage
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&stage)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&stage)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update pipeline stage
+	updatedStage, err := c.service.UpdateStage(idParameter, &stage)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed pipeline stage update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated stage to output
+	err = helpers.WriteAsJSON(w, updatedStage)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed pipeline stage update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete pipeline stage (using URL parameter id)
+// @Summary      Delete pipeline stage
+// @Description  Deletes a pipeline stage that no transactions have been through
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Pipeline Stage ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed pipeline stage deletion"
+// @Failure      409 {string} string "Pipeline stage has transactions"
+// @Router       /pipeline-stages/{id} [delete]
+// @Security BearerToken
+func (c pipelineController) DeleteStage(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete pipeline stage using id
+	err := c.service.DeleteStage(idParameter)
+
+	// If error detected
+	if err != nil {
+		if errors.Is(err, service.ErrStageInUse) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed pipeline stage deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/TRANSACTIONS/STAGE
+// Move a transaction to a stage of its pipeline (using URL parameter id)
+// @Summary      Move transaction stage
+// @Description  Moves a transaction to a stage of its type's pipeline, recording the change with the user making it. Milestones of the previous stage are completed. Entering a completing stage completes the transaction
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Transaction ID"
+// @Param        move body models.MoveTransactionStage true "Move Transaction Stage Json"
+// @Success      200 {object} db.Transaction
+// @Failure      400 {string} string "Failed moving transaction"
+// @Failure      403 {string} string "Error parsing authentication token"
+// @Failure      409 {string} string "Transaction is already at this stage"
+// @Router       /transactions/stage/{id} [put]
+// @Security BearerToken
+func (c pipelineController) Move(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var move models.MoveTransactionStage
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&move)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&move)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Validate the token
+	tokenData, err := auth.ValidateAndParseToken(w, r)
+	// If error detected
+	if err != nil {
+		http.Error(w, "Error parsing authentication token", http.StatusForbidden)
+		return
+	}
+	// Convert user id from token to int and store
+	userIdFromToken, err := strconv.Atoi(tokenData.UserID)
+	if err != nil {
+		http.Error(w, "Issue with user id from token", http.StatusBadRequest)
+		return
+	}
+
+	movedTransaction, err := c.service.Move(idParameter, &move, uint(userIdFromToken))
+	if err != nil {
+		if errors.Is(err, service.ErrAlreadyAtStage) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed moving transaction: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write moved transaction to output
+	err = helpers.WriteAsJSON(w, movedTransaction)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed moving transaction: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// API/TRANSACTION-MILESTONES
+// Find a list of transaction milestones
+// @Summary      Find a list of transaction milestones
+// @Description  Accepts limit, offset, order, transaction and overdue params and returns list of milestones (by due date by default)
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        transaction   path      int  false  "transaction ID"
+// @Param        overdue   path      bool  false  "only milestones past their due date"
+// @Success      200 {object} []db.TransactionMilestone
+// @Failure      400 {string} string "Can't find transaction milestones"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /transaction-milestones [get]
+// @Security BearerToken
+func (c pipelineController) FindAllMilestones(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	transactionParam := r.URL.Query().Get("transaction")
+	overdue := r.URL.Query().Get("overdue") == "true"
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	transactionId, _ := strconv.Atoi(transactionParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all milestones using query params
+	foundMilestones, err := c.service.FindAllMilestones(limit, offset, orderBy, transactionId, overdue)
+	if err != nil {
+		http.Error(w, "Can't find transaction milestones", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundMilestones)
+	if err != nil {
+		http.Error(w, "Can't find transaction milestones", http.StatusBadRequest)
+		fmt.Println("error writing transaction milestones to response: ", err)
+		return
+	}
+}
+
+// Create a new transaction milestone
+// @Summary      Create transaction milestone
+// @Description  Adds a milestone with a due date to a transaction
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        milestone body models.CreateTransactionMilestone true "New Transaction Milestone Json"
+// @Success      201 {object} db.TransactionMilestone
+// @Failure      400 {string} string "Transaction milestone creation failed."
+// @Router       /transaction-milestones [post]
+// @Security BearerToken
+func (c pipelineController) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var milestone models.CreateTransactionMilestone
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&milestone)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&milestone)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create milestone in db
+	createdMilestone, createErr := c.service.CreateMilestone(&milestone)
+	if createErr != nil {
+		http.Error(w, "Transaction milestone creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created milestone to output
+	err = helpers.WriteAsJSON(w, createdMilestone)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a transaction milestone (using URL parameter id)
+// @Summary      Update transaction milestone
+// @Description  Updates an existing milestone. Providing a completion time marks it as reached
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Transaction Milestone ID"
+// @Param        milestone body models.UpdateTransactionMilestone true "Update Transaction Milestone Json"
+// @Success      200 {object} db.TransactionMilestone
+// @Failure      400 {string} string "Failed transaction milestone update"
+// @Router       /transaction-milestones/{id} [put]
+// @Security BearerToken
+func (c pipelineController) UpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var milestone models.UpdateTransactionMilestone
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&milestone)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&milestone)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update milestone
+	updatedMilestone, err := c.service.UpdateMilestone(idParameter, &milestone)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed transaction milestone update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated milestone to output
+	err = helpers.WriteAsJSON(w, updatedMilestone)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed transaction milestone update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete transaction milestone (using URL parameter id)
+// @Summary      Delete transaction milestone
+// @Description  Deletes a transaction milestone
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Transaction Milestone ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed transaction milestone deletion"
+// @Router       /transaction-milestones/{id} [delete]
+// @Security BearerToken
+func (c pipelineController) DeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete milestone using id
+	err := c.service.DeleteMilestone(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed transaction milestone deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/TRANSACTIONS/PIPELINE
+// Count and value of transactions per pipeline stage
+// @Summary      Pipeline summary
+// @Description  Returns the count and value of transactions at each stage of a transaction type's pipeline, or of every type's pipeline if no type is provided
+// @Tags         Pipeline
+// @Accept       json
+// @Produce      json
+// @Param        type   path      string  false  "transaction type"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} []models.PipelineSummary
+// @Failure      400 {string} string "Can't produce pipeline summary"
+// @Router       /transactions/pipeline [get]
+// @Security BearerToken
+func (c pipelineController) Summary(w http.ResponseWriter, r *http.Request) {
+	transactionType := r.URL.Query().Get("type")
+	switch transactionType {
+	case "", "Sale", "Lease", "Management", "Other":
+	default:
+		http.Error(w, "Type must be one of Sale, Lease, Management or Other", http.StatusBadRequest)
+		return
+	}
+
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	summary, err := c.service.Summary(transactionType, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce pipeline summary: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, summary)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't produce pipeline summary: %v", err), http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,161 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestPipelineController_MoveMilestonesAndSummary(t *testing.T) {
+	// Test setup
+	property := db.Property{Property_Name: "pipelineProperty1", Postcode: 80351, Suburb: "Canggu", City: "Badung", Street_Address_1: "Jl. Batu Bolong", Bedrooms: 3, Bathrooms: 2, Description: "Villa"}
+	testConnection.dbClient.Create(&property)
+	task := db.Task{TaskName: "Lease the Canggu villa", Type: "Lease"}
+	testConnection.dbClient.Create(&task)
+
+	var createTests = []struct {
+		data                   models.CreatePipelineStage
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreatePipelineStage{Name: "Enquiry", TransactionType: "Lease", DueDays: 7}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreatePipelineStage{Name: "Enquiry", TransactionType: "Rental", DueDays: 7}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid type fail test"},
+		{models.CreatePipelineStage{Name: "Enquiry", TransactionType: "Lease", DueDays: 7}, testConnection.accounts.admin.token, http.StatusCreated, "admin create enquiry test"},
+		{models.CreatePipelineStage{Name: "Offer", TransactionType: "Lease", DueDays: 3}, testConnection.accounts.admin.token, http.StatusCreated, "admin create offer test"},
+		{models.CreatePipelineStage{Name: "Settlement", TransactionType: "Lease", Completes: true}, testConnection.accounts.admin.token, http.StatusCreated, "admin create settlement test"},
+		{models.CreatePipelineStage{Name: "Offer", TransactionType: "Sale"}, testConnection.accounts.admin.token, http.StatusCreated, "admin create sale offer test"},
+	}
+
+	stages := []db.PipelineStage{}
+	for _, v := range createTests {
+		// Make new request with pipeline stage creation in body
+		req, err := http.NewRequest("POST", "/api/pipeline-stages", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Pipeline stage create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			var stage db.PipelineStage
+			json.Unmarshal(rr.Body.Bytes(), &stage)
+			stages = append(stages, stage)
+		}
+	}
+	if len(stages) != 4 {
+		t.Fatalf("Pipeline stage create: expected 4 stages, got %v", stages)
+	}
+	enquiry, offer, settlement, saleOffer := stages[0], stages[1], stages[2], stages[3]
+	// Stages are added to the end of the pipeline
+	if enquiry.Position != 1 || offer.Position != 2 || settlement.Position != 3 {
+		t.Errorf("Pipeline stage create: expected positions 1, 2 and 3, got %v, %v and %v", enquiry.Position, offer.Position, settlement.Position)
+	}
+
+	// New leases enter the first stage of the lease pipeline with its milestone
+	transaction, err := testConnection.transactions.serv.Create(&models.CreateTransaction{Type: "Lease", Agency: "Own", TransactionValue: db.NewMoney(240000000, "IDR"), Property: property, Task: task})
+	if err != nil {
+		t.Fatalf("Transaction create failed: %v", err)
+	}
+	found, _ := testConnection.transactions.serv.FindById(int(transaction.ID))
+	if found.StageID == nil || *found.StageID != enquiry.ID || len(found.StageHistory) != 1 || len(found.Milestones) != 1 {
+		t.Fatalf("Transaction create: expected to enter the enquiry stage with a milestone, got %+v", found)
+	}
+
+	// Stages of another type's pipeline are rejected
+	rr := serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/stage/%v", transaction.ID), models.MoveTransactionStage{Stage: saleOffer})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Move to sale stage: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	// Moves are recorded with the user making them
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/stage/%v", transaction.ID), models.MoveTransactionStage{Stage: offer, Notes: "Tenant offered two years upfront"})
+	var moved db.Transaction
+	json.Unmarshal(rr.Body.Bytes(), &moved)
+	if rr.Code != http.StatusOK || moved.StageID == nil || *moved.StageID != offer.ID || len(moved.StageHistory) != 2 {
+		t.Fatalf("Move to offer: expected the offer stage with 2 changes, got %v %v", rr.Code, rr.Body.String())
+	}
+	change := moved.StageHistory[1]
+	if change.UserID == nil || *change.UserID != testConnection.accounts.admin.details.ID || change.FromStageID == nil || *change.FromStageID != enquiry.ID {
+		t.Errorf("Move to offer: expected change from enquiry by the admin, got %+v", change)
+	}
+	// The enquiry milestone is reached and the offer milestone is set
+	for _, milestone := range moved.Milestones {
+		if milestone.PipelineStageID != nil && *milestone.PipelineStageID == enquiry.ID && milestone.CompletedAt == nil {
+			t.Errorf("Move to offer: expected the enquiry milestone to be completed, got %+v", milestone)
+		}
+	}
+	if len(moved.Milestones) != 2 {
+		t.Errorf("Move to offer: expected 2 milestones, got %v", len(moved.Milestones))
+	}
+
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/stage/%v", transaction.ID), models.MoveTransactionStage{Stage: offer})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Move to current stage: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	// Milestones past their due date are overdue
+	rr = serveAsAdmin(t, "POST", "/api/transaction-milestones", models.CreateTransactionMilestone{Name: "Bond received", DueDate: time.Now().AddDate(0, 0, -1), Transaction: *transaction})
+	var milestone db.TransactionMilestone
+	json.Unmarshal(rr.Body.Bytes(), &milestone)
+	if rr.Code != http.StatusCreated || !milestone.Overdue {
+		t.Errorf("Create milestone: expected an overdue milestone, got %v %v", rr.Code, rr.Body.String())
+	}
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/transaction-milestones?limit=10&transaction=%v&overdue=true", transaction.ID), nil)
+	var overdue []db.TransactionMilestone
+	json.Unmarshal(rr.Body.Bytes(), &overdue)
+	if rr.Code != http.StatusOK || len(overdue) != 1 || overdue[0].ID != milestone.ID {
+		t.Errorf("Find overdue milestones: expected the bond milestone, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Count and value per stage
+	rr = serveAsAdmin(t, "GET", "/api/transactions/pipeline?type=Lease", nil)
+	var summary []models.PipelineSummary
+	json.Unmarshal(rr.Body.Bytes(), &summary)
+	if rr.Code != http.StatusOK || len(summary) != 1 || len(summary[0].Stages) != 3 {
+		t.Fatalf("Pipeline summary: expected the 3 lease stages, got %v %v", rr.Code, rr.Body.String())
+	}
+	offerSummary := summary[0].Stages[1]
+	if offerSummary.StageID != offer.ID || offerSummary.Transactions != 1 || offerSummary.Value.Float() != 240000000 || offerSummary.OverdueMilestones != 1 {
+		t.Errorf("Pipeline summary: expected 1 transaction worth 240000000 at the offer stage, got %+v", offerSummary)
+	}
+
+	// Stages transactions have been through can't be deleted
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/pipeline-stages/%v", enquiry.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Delete pipeline stage in use: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	// Entering the settlement stage completes the transaction
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/stage/%v", transaction.ID), models.MoveTransactionStage{Stage: settlement})
+	var settled db.Transaction
+	json.Unmarshal(rr.Body.Bytes(), &settled)
+	if rr.Code != http.StatusOK || settled.TransactionCompletion.IsZero() {
+		t.Errorf("Move to settlement: expected a completed transaction, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Cleanup
+	testConnection.dbClient.Where("transaction_id = ?", transaction.ID).Delete(&db.TransactionMilestone{})
+	testConnection.dbClient.Where("transaction_id = ?", transaction.ID).Delete(&db.TransactionStageChange{})
+	testConnection.dbClient.Unscoped().Delete(transaction)
+	for _, stage := range stages {
+		testConnection.dbClient.Delete(&stage)
+	}
+	testConnection.dbClient.Unscoped().Delete(&task)
+	testConnection.dbClient.Unscoped().Delete(&property)
+}
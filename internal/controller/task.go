@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -22,6 +23,11 @@ type TaskController interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	// Board
+	Board(w http.ResponseWriter, r *http.Request)
+	Move(w http.ResponseWriter, r *http.Request)
+	// My tasks
+	FindMyTasks(w http.ResponseWriter, r *http.Request)
 }
 
 type taskController struct {
@@ -80,7 +86,7 @@ func (c taskController) FindAll(w http.ResponseWriter, r *http.Request) {
 
 // Find a created task
 // @Summary      Find task
-// @Description  Find a task by ID
+// @Description  Find a task by ID, including its ordered checklist, subtasks and progress percentage
 // @Tags         Tasks
 // @Accept       json
 // @Produce      json
@@ -166,6 +172,8 @@ func (c taskController) Create(w http.ResponseWriter, r *http.Request) {
 // @Success      200 {object} db.Task
 // @Failure      400 {string} string "Failed task update"
 // @Failure      403 {string} string "Authentication Token not detected"
+// @Failure      409 {string} string "All subtasks must be completed before completing this task"
+// @Failure      409 {string} string "Task is blocked by an incomplete task and can't be made active"
 // @Router       /tasks/{id} [put]
 // @Security BearerToken
 func (c taskController) Update(w http.ResponseWriter, r *http.Request) {
@@ -206,6 +214,11 @@ func (c taskController) Update(w http.ResponseWriter, r *http.Request) {
 	// Update task
 	updatedTask, createErr := c.service.Update(idParameter, &task)
 	if createErr != nil {
+		// If subtasks or blocking tasks must be completed first
+		if errors.Is(createErr, service.ErrIncompleteSubtasks) || errors.Is(createErr, service.ErrTaskBlocked) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Failed task update: %s", createErr), http.StatusBadRequest)
 		return
 	}
@@ -231,13 +244,14 @@ func (c taskController) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete task (using URL parameter id)
 // @Summary      Delete task
-// @Description  Deletes an existing task
+// @Description  Deletes an existing task along with its transaction or maintenance request and its dependencies. Tasks with a completed transaction or an invoiced maintenance request can only be archived
 // @Tags         Tasks
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Task ID"
 // @Success      200 {string} string "Deletion successful!"
 // @Failure      400 {string} string "Failed task deletion"
+// @Failure      409 {string} string "Task's transaction is completed or its maintenance request invoiced, archive the task instead"
 // @Router       /tasks/{id} [delete]
 // @Security BearerToken
 func (c taskController) Delete(w http.ResponseWriter, r *http.Request) {
@@ -250,6 +264,10 @@ func (c taskController) Delete(w http.ResponseWriter, r *http.Request) {
 	err := c.service.Delete(idParameter)
 
 	// If error detected
+	if errors.Is(err, service.ErrTaskHasCompletedRecords) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed task deletion", http.StatusBadRequest)
 		return
@@ -258,6 +276,137 @@ func (c taskController) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Deletion successful!"))
 }
 
+// Find tasks grouped by status
+// @Summary      Task board
+// @Description  Returns tasks grouped into status columns with per column counts. Tasks are ordered by their board rank. Accepts limit param for tasks per column (default 20)
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  false  "tasks per column"
+// @Success      200 {object} models.TaskBoard
+// @Failure      400 {string} string "Can't build task board"
+// @Failure      400 {string} string "Limit parameter has a max value of 50"
+// @Router       /tasks/board [get]
+// @Security BearerToken
+func (c taskController) Board(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+
+	// Check limit
+	if limit >= 50 {
+		http.Error(w, "Limit parameter has a max value of 50", http.StatusBadRequest)
+		return
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	board, err := c.service.Board(limit)
+	if err != nil {
+		http.Error(w, "Can't build task board", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, board)
+	if err != nil {
+		http.Error(w, "Can't build task board", http.StatusBadRequest)
+		fmt.Println("error writing task board to response: ", err)
+		return
+	}
+}
+
+// Move a task on the board (using URL parameter id)
+// @Summary      Move task on board
+// @Description  Moves a task to a status column and position (rank) within the column. Status changes follow the same rules as task updates
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Param        move body models.MoveTask true "Move Task Json"
+// @Param        id   path      int  true  "Task ID"
+// @Success      200 {object} db.Task
+// @Failure      400 {string} string "Failed task move"
+// @Failure      409 {string} string "Task is blocked by an incomplete task and can't be made active"
+// @Router       /tasks/board/{id} [put]
+// @Security BearerToken
+func (c taskController) Move(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var move models.MoveTask
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&move)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&move)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Move task
+	movedTask, moveErr := c.service.Move(idParameter, &move)
+	if moveErr != nil {
+		// If subtasks or blocking tasks must be completed first
+		if errors.Is(moveErr, service.ErrIncompleteSubtasks) || errors.Is(moveErr, service.ErrTaskBlocked) {
+			http.Error(w, moveErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed task move: %s", moveErr), http.StatusBadRequest)
+		return
+	}
+
+	// Write task to output
+	err = helpers.WriteAsJSON(w, movedTask)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// API/ME/TASKS
+// Find tasks for the current user
+// @Summary      Find my tasks
+// @Description  Returns the current user's assigned, overdue, snoozed and recently completed tasks
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} models.MyTasks
+// @Failure      400 {string} string "Can't find tasks"
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /me/tasks [get]
+// @Security BearerToken
+func (c taskController) FindMyTasks(w http.ResponseWriter, r *http.Request) {
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	myTasks, err := c.service.FindMyTasks(userID)
+	if err != nil {
+		http.Error(w, "Can't find tasks", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, myTasks)
+	if err != nil {
+		http.Error(w, "Can't find tasks", http.StatusBadRequest)
+		fmt.Println("error writing tasks to response: ", err)
+		return
+	}
+}
+
 // Build a log string for struct updates
 func buildTaskLogUpdate(updateStruct interface{}) string {
 	// Log update
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type TaskChecklistItemController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+type taskChecklistItemController struct {
+	service service.TaskChecklistItemService
+}
+
+func NewTaskChecklistItemController(service service.TaskChecklistItemService) TaskChecklistItemController {
+	return &taskChecklistItemController{service}
+}
+
+// API/TASK-CHECKLIST-ITEMS
+// Find a list of task checklist items
+// @Summary      Find a list of task checklist items
+// @Description  Accepts limit, offset, order and task params and returns list of checklist items (ordered by position)
+// @Tags         Task Checklist Items
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        task   path      int  false  "task id"
+// @Success      200 {object} []db.TaskChecklistItem
+// @Failure      400 {string} string "Can't find task checklist items"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /task-checklist-items [get]
+// @Security BearerToken
+func (c taskChecklistItemController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	taskParam := r.URL.Query().Get("task")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	taskId, _ := strconv.Atoi(taskParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all checklist items using query params
+	foundItems, err := c.service.FindAll(limit, offset, orderBy, taskId)
+	if err != nil {
+		http.Error(w, "Can't find task checklist items", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundItems)
+	if err != nil {
+		http.Error(w, "Can't find task checklist items", http.StatusBadRequest)
+		fmt.Println("error writing task checklist items to response: ", err)
+		return
+	}
+}
+
+// Find a created task checklist item
+// @Summary      Find task checklist item
+// @Description  Find a task checklist item by ID
+// @Tags         Task Checklist Items
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Task Checklist Item ID"
+// @Success      200 {object} db.TaskChecklistItem
+// @Failure      400 {string} string "Can't find task checklist item with ID: {id}"
+// @Router       /task-checklist-items/{id} [get]
+// @Security BearerToken
+func (c taskChecklistItemController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundItem, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find task checklist item with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundItem)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find task checklist item with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new task checklist item
+// @Summary      Create a task checklist item
+// @Description  Creates a new task checklist item. Added to the end of the checklist if no position is provided
+// @Tags         Task Checklist Items
+// @Accept       json
+// @Produce      json
+// @Param        item body models.CreateTaskChecklistItem true "New Task Checklist Item Json"
+// @Success      201 {object} db.TaskChecklistItem
+// @Failure      400 {string} string "Task checklist item creation failed."
+// @Router       /task-checklist-items [post]
+// @Security BearerToken
+func (c taskChecklistItemController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var item models.CreateTaskChecklistItem
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&item)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&item)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create task checklist item in db
+	createdItem, createErr := c.service.Create(&item)
+	if createErr != nil {
+		http.Error(w, "Task checklist item creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created item to output
+	err = helpers.WriteAsJSON(w, createdItem)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a task checklist item (using URL parameter id)
+// @Summary      Update task checklist item
+// @Description  Updates an existing task checklist item. Marking an item done records who completed it and when
+// @Tags         Task Checklist Items
+// @Accept       json
+// @Produce      json
+// @Param        item body models.UpdateTaskChecklistItem true "Update Task Checklist Item Json"
+// @Param        id   path      int  true  "Task Checklist Item ID"
+// @Success      200 {object} db.TaskChecklistItem
+// @Failure      400 {string} string "Failed task checklist item update"
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /task-checklist-items/{id} [put]
+// @Security BearerToken
+func (c taskChecklistItemController) Update(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var item models.UpdateTaskChecklistItem
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&item)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&item)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Update task checklist item
+	updatedItem, updateErr := c.service.Update(idParameter, userID, &item)
+	if updateErr != nil {
+		http.Error(w, fmt.Sprintf("Failed task checklist item update: %s", updateErr), http.StatusBadRequest)
+		return
+	}
+
+	// Write task checklist item to output
+	err = helpers.WriteAsJSON(w, updatedItem)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Delete task checklist item (using URL parameter id)
+// @Summary      Delete task checklist item
+// @Description  Deletes an existing task checklist item
+// @Tags         Task Checklist Items
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Task Checklist Item ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed task checklist item deletion"
+// @Router       /task-checklist-items/{id} [delete]
+// @Security BearerToken
+func (c taskChecklistItemController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete task checklist item using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed task checklist item deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
@@ -0,0 +1,138 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestTaskChecklistItemController_Create(t *testing.T) {
+	// Test setup
+	createdTasks := []db.Task{{TaskName: "Quarterly inspection", Type: "Inspection"}}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task for checklist item create test: ", createResult.Error)
+	}
+
+	var createTests = []struct {
+		data                   models.CreateTaskChecklistItem
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedPosition       int
+		testName               string
+	}{
+		{models.CreateTaskChecklistItem{Description: "Check smoke alarms", Task: createdTasks[0]}, testConnection.accounts.user.token, http.StatusForbidden, 0, "basic user create test"},
+		// Items without a position are added to the end of the checklist
+		{models.CreateTaskChecklistItem{Description: "Check smoke alarms", Task: createdTasks[0]}, testConnection.accounts.admin.token, http.StatusCreated, 1, "admin first item test"},
+		{models.CreateTaskChecklistItem{Description: "Check water pressure", Task: createdTasks[0]}, testConnection.accounts.admin.token, http.StatusCreated, 2, "admin second item test"},
+		{models.CreateTaskChecklistItem{Description: "Check garden", Position: 10, Task: createdTasks[0]}, testConnection.accounts.admin.token, http.StatusCreated, 10, "admin positioned item test"},
+		{models.CreateTaskChecklistItem{Description: "", Task: createdTasks[0]}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin empty description fail test"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with checklist item creation in body
+		req, err := http.NewRequest("POST", "/api/task-checklist-items", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task checklist item create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+
+		// Check position if created
+		if v.expectedResponseStatus == http.StatusCreated {
+			var body db.TaskChecklistItem
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if body.Position != v.expectedPosition {
+				t.Errorf("Task checklist item create test (%v): expected position %d, got %d", v.testName, v.expectedPosition, body.Position)
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id = ?", createdTasks[0].ID).Delete(&db.TaskChecklistItem{})
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTaskChecklistItemController_Update(t *testing.T) {
+	// Test setup
+	createdTasks := []db.Task{{TaskName: "Move out inspection", Type: "Inspection"}}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task for checklist item update test: ", createResult.Error)
+	}
+	createdItems := []db.TaskChecklistItem{{Description: "Collect keys", Position: 1, TaskID: createdTasks[0].ID}}
+	createResult = testConnection.dbClient.Create(createdItems)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create checklist item for update test: ", createResult.Error)
+	}
+
+	done := true
+	notDone := false
+	var updateTests = []struct {
+		data                   models.UpdateTaskChecklistItem
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedDone           bool
+		testName               string
+	}{
+		{models.UpdateTaskChecklistItem{Done: &done}, testConnection.accounts.user.token, http.StatusForbidden, false, "basic user update test"},
+		{models.UpdateTaskChecklistItem{Done: &done}, testConnection.accounts.admin.token, http.StatusOK, true, "admin mark done test"},
+		// Done state is unchanged when not provided
+		{models.UpdateTaskChecklistItem{Description: "Collect all keys"}, testConnection.accounts.admin.token, http.StatusOK, true, "admin description test"},
+		{models.UpdateTaskChecklistItem{Done: &notDone}, testConnection.accounts.admin.token, http.StatusOK, false, "admin mark not done test"},
+	}
+
+	requestUrl := fmt.Sprintf("/api/task-checklist-items/%v", createdItems[0].ID)
+	for _, v := range updateTests {
+		// Make new request with checklist item update in body
+		req, err := http.NewRequest("PUT", requestUrl, buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send update request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task checklist item update test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+
+		// Check done details in database
+		var found db.TaskChecklistItem
+		testConnection.dbClient.First(&found, createdItems[0].ID)
+		if found.Done != v.expectedDone {
+			t.Errorf("Task checklist item update test (%v): expected done %v, got %v", v.testName, v.expectedDone, found.Done)
+		}
+		// Done by and done at are recorded only while done
+		if v.expectedDone && (found.DoneByID == nil || *found.DoneByID != testConnection.accounts.admin.details.ID || found.DoneAt == nil) {
+			t.Errorf("Task checklist item update test (%v): expected done by admin with time, got %v at %v", v.testName, found.DoneByID, found.DoneAt)
+		}
+		if !v.expectedDone && (found.DoneByID != nil || found.DoneAt != nil) {
+			t.Errorf("Task checklist item update test (%v): expected done by and done at to be cleared", v.testName)
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(createdItems)
+	testConnection.dbClient.Delete(createdTasks)
+}
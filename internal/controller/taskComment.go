@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type TaskCommentController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+type taskCommentController struct {
+	service service.TaskCommentService
+}
+
+func NewTaskCommentController(service service.TaskCommentService) TaskCommentController {
+	return &taskCommentController{service}
+}
+
+// API/TASK-COMMENTS
+// Find a list of task comments
+// @Summary      Find a list of task comments
+// @Description  Accepts limit, offset, order and task params and returns list of task comments
+// @Tags         Task Comments
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        task   path      int  false  "task id"
+// @Success      200 {object} []db.TaskComment
+// @Failure      400 {string} string "Can't find task comments"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /task-comments [get]
+// @Security BearerToken
+func (c taskCommentController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	taskParam := r.URL.Query().Get("task")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	taskId, _ := strconv.Atoi(taskParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all task comments using query params
+	foundComments, err := c.service.FindAll(limit, offset, orderBy, taskId)
+	if err != nil {
+		http.Error(w, "Can't find task comments", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundComments)
+	if err != nil {
+		http.Error(w, "Can't find task comments", http.StatusBadRequest)
+		fmt.Println("error writing task comments to response: ", err)
+		return
+	}
+}
+
+// Find a created task comment
+// @Summary      Find task comment
+// @Description  Find a task comment (with replies and edit history) by ID
+// @Tags         Task Comments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Task Comment ID"
+// @Success      200 {object} db.TaskComment
+// @Failure      400 {string} string "Can't find task comment with ID: {id}"
+// @Router       /task-comments/{id} [get]
+// @Security BearerToken
+func (c taskCommentController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundComment, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find task comment with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundComment)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find task comment with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new task comment
+// @Summary      Create a task comment
+// @Description  Creates a new task comment. Users mentioned with @username are notified
+// @Tags         Task Comments
+// @Accept       json
+// @Produce      json
+// @Param        comment body models.RecvTaskComment true "New Task Comment Json"
+// @Success      201 {object} db.TaskComment
+// @Failure      400 {string} string "Task comment creation failed."
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /task-comments [post]
+// @Security BearerToken
+func (c taskCommentController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var recvComment models.RecvTaskComment
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&recvComment)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&recvComment)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Convert DTO to service required input model
+	var comment = models.CreateTaskComment{
+		Comment:  recvComment.Comment,
+		User:     db.User{ID: uint(userID)},
+		Task:     recvComment.Task,
+		ParentID: recvComment.ParentID,
+	}
+
+	// Create task comment in db
+	createdComment, createErr := c.service.Create(&comment)
+	if createErr != nil {
+		http.Error(w, "Task comment creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created comment to output
+	err = helpers.WriteAsJSON(w, createdComment)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a task comment (using URL parameter id)
+// @Summary      Update task comment
+// @Description  Updates an existing task comment. Only the author can edit and the previous version is kept in history
+// @Tags         Task Comments
+// @Accept       json
+// @Produce      json
+// @Param        comment body models.UpdateTaskComment true "Update Task Comment Json"
+// @Param        id   path      int  true  "Task Comment ID"
+// @Success      200 {object} db.TaskComment
+// @Failure      400 {string} string "Failed task comment update"
+// @Failure      403 {string} string "Only the author can edit a task comment"
+// @Router       /task-comments/{id} [put]
+// @Security BearerToken
+func (c taskCommentController) Update(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var comment models.UpdateTaskComment
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&comment)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&comment)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Update task comment
+	updatedComment, updateErr := c.service.Update(idParameter, userID, &comment)
+	if updateErr != nil {
+		// If user is not the author
+		if errors.Is(updateErr, service.ErrNotCommentAuthor) {
+			http.Error(w, "Only the author can edit a task comment", http.StatusForbidden)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed task comment update: %s", updateErr), http.StatusBadRequest)
+		return
+	}
+
+	// Write task comment to output
+	err = helpers.WriteAsJSON(w, updatedComment)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Delete task comment (using URL parameter id)
+// @Summary      Delete task comment
+// @Description  Deletes an existing task comment
+// @Tags         Task Comments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Task Comment ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed task comment deletion"
+// @Router       /task-comments/{id} [delete]
+// @Security BearerToken
+func (c taskCommentController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete task comment using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed task comment deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
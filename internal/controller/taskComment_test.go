@@ -0,0 +1,255 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestTaskCommentController_FindAll(t *testing.T) {
+	// Test setup
+	// Create tasks
+	createdTasks := []db.Task{
+		{TaskName: "Test Task", Type: "Maintenance"},
+		{TaskName: "Other Task", Type: "Inspection"},
+	}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create tasks for task comment find all test: ", createResult.Error)
+	}
+	// Create task comments (two on first task, one on second)
+	createdComments := []db.TaskComment{
+		{TaskID: createdTasks[0].ID, UserID: testConnection.accounts.admin.details.ID, Comment: "First comment"},
+		{TaskID: createdTasks[0].ID, UserID: testConnection.accounts.admin.details.ID, Comment: "Second comment"},
+		{TaskID: createdTasks[1].ID, UserID: testConnection.accounts.admin.details.ID, Comment: "Comment on other task"},
+	}
+	createResult = testConnection.dbClient.Create(createdComments)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task comments for find all test: ", createResult.Error)
+	}
+
+	var findAllTests = []struct {
+		request                string
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedLength         int
+	}{
+		{"/api/task-comments?limit=10&offset=0&order=", testConnection.accounts.admin.token, http.StatusOK, 3},
+		// Filter by task
+		{fmt.Sprintf("/api/task-comments?limit=10&task=%v", createdTasks[0].ID), testConnection.accounts.admin.token, http.StatusOK, 2},
+		// No limit should result in bad request
+		{"/api/task-comments?limit=&offset=&order=", testConnection.accounts.admin.token, http.StatusBadRequest, 0},
+		// Basic users are forbidden
+		{"/api/task-comments?limit=10", testConnection.accounts.user.token, http.StatusForbidden, 0},
+	}
+
+	for _, v := range findAllTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", v.request, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+
+		// Use handler with recorder and created request
+		testConnection.router.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task comment find all (%v): got %v want %v", v.request, status, v.expectedResponseStatus)
+		}
+
+		// Check length of comments array if successful
+		if v.expectedResponseStatus == http.StatusOK {
+			var body []db.TaskComment
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if len(body) != v.expectedLength {
+				t.Errorf("Task comments array in findAll (%v) failed: expected %d, got %d", v.request, v.expectedLength, len(body))
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(createdComments)
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTaskCommentController_Create(t *testing.T) {
+	// Test setup
+	// Create task
+	createdTasks := []db.Task{{TaskName: "Repaint the walls", Type: "Maintenance"}}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task for task comment create test: ", createResult.Error)
+	}
+	// Create user to be mentioned
+	mentionedUser := &db.User{Username: "wayan.putu", Email: "wayan@ymail.com", Name: "Wayan Putu", Password: "password"}
+	createResult = testConnection.dbClient.Create(mentionedUser)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create user for task comment create test: ", createResult.Error)
+	}
+
+	var createTests = []struct {
+		data                   models.RecvTaskComment
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedNotifications  int64
+		testName               string
+	}{
+		{models.RecvTaskComment{
+			Comment: "Painter is booked for Monday @wayan.putu",
+			Task:    createdTasks[0],
+		}, testConnection.accounts.user.token, http.StatusForbidden, 0, "basic user create test"},
+		{models.RecvTaskComment{
+			Comment: "Painter is booked for Monday @wayan.putu, and @nobody",
+			Task:    createdTasks[0],
+		}, testConnection.accounts.admin.token, http.StatusCreated, 1, "admin create with mention test"},
+		{models.RecvTaskComment{
+			Comment: "",
+			Task:    createdTasks[0],
+		}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin empty comment fail test"},
+		// Parent comment does not exist
+		{models.RecvTaskComment{
+			Comment:  "Reply to nothing",
+			Task:     createdTasks[0],
+			ParentID: 9999,
+		}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin missing parent fail test"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with comment creation in body
+		req, err := http.NewRequest("POST", "/api/task-comments", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task comment create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+
+		// Check mentions and notifications if created
+		if v.expectedResponseStatus == http.StatusCreated {
+			var body db.TaskComment
+			json.Unmarshal(rr.Body.Bytes(), &body)
+
+			// Check mentioned user was resolved
+			if len(body.Mentions) != 1 || body.Mentions[0].ID != mentionedUser.ID {
+				t.Errorf("Task comment create test (%v): expected mention of user %d, got %v", v.testName, mentionedUser.ID, body.Mentions)
+			}
+
+			// Check notification was created for mentioned user (once delivered)
+			testConnection.notifications.serv.Wait()
+			var count int64
+			testConnection.dbClient.Model(&db.Notification{}).Where("user_id = ? AND task_comment_id = ?", mentionedUser.ID, body.ID).Count(&count)
+			if count != v.expectedNotifications {
+				t.Errorf("Task comment create test (%v): expected %d notifications, got %d", v.testName, v.expectedNotifications, count)
+			}
+
+			// Check reply can be created on comment
+			replyReq, _ := http.NewRequest("POST", "/api/task-comments", buildReqBody(models.RecvTaskComment{
+				Comment:  "Thanks, confirmed",
+				Task:     createdTasks[0],
+				ParentID: body.ID,
+			}))
+			replyReq.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+			replyRR := httptest.NewRecorder()
+			testConnection.router.ServeHTTP(replyRR, replyReq)
+			if status := replyRR.Code; status != http.StatusCreated {
+				t.Errorf("Task comment reply create test (%v): got %v want %v", v.testName, status, http.StatusCreated)
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id = ?", createdTasks[0].ID).Delete(&db.TaskComment{})
+	testConnection.dbClient.Where("user_id = ?", mentionedUser.ID).Delete(&db.Notification{})
+	testConnection.dbClient.Unscoped().Delete(mentionedUser)
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTaskCommentController_Update(t *testing.T) {
+	// Test setup
+	// Create task
+	createdTasks := []db.Task{{TaskName: "Replace the locks", Type: "Maintenance"}}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task for task comment update test: ", createResult.Error)
+	}
+	// Create comment authored by basic user mentioning admin
+	createdComments := []db.TaskComment{
+		{TaskID: createdTasks[0].ID, UserID: testConnection.accounts.user.details.ID, Comment: "Original comment", Mentions: []db.User{{ID: testConnection.accounts.admin.details.ID}}},
+	}
+	createResult = testConnection.dbClient.Create(createdComments)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task comment for update test: ", createResult.Error)
+	}
+
+	var updateTests = []struct {
+		data                   models.UpdateTaskComment
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		// Admin is not the author of the comment
+		{models.UpdateTaskComment{Comment: "Edited comment"}, testConnection.accounts.admin.token, http.StatusForbidden, "admin not author test"},
+		{models.UpdateTaskComment{Comment: ""}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin empty comment fail test"},
+	}
+
+	requestUrl := fmt.Sprintf("/api/task-comments/%v", createdComments[0].ID)
+	for _, v := range updateTests {
+		// Make new request with comment update in body
+		req, err := http.NewRequest("PUT", requestUrl, buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send update request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task comment update test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Update as author through service and check history is kept
+	updatedComment, err := testConnection.taskComments.serv.Update(int(createdComments[0].ID), int(testConnection.accounts.user.details.ID), &models.UpdateTaskComment{Comment: "Edited comment"})
+	if err != nil {
+		t.Fatalf("Task comment update by author failed: %v", err)
+	}
+	if updatedComment.Comment != "Edited comment" || !updatedComment.Edited {
+		t.Errorf("Task comment update by author: expected edited comment, got %v (edited: %v)", updatedComment.Comment, updatedComment.Edited)
+	}
+	if len(updatedComment.History) != 1 || updatedComment.History[0].PreviousComment != "Original comment" {
+		t.Errorf("Task comment update by author: expected history with original comment, got %v", updatedComment.History)
+	}
+	// Mentions removed from the comment are cleared
+	if len(updatedComment.Mentions) != 0 {
+		t.Errorf("Task comment update by author: expected mentions to be cleared, got %v", updatedComment.Mentions)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_comment_id = ?", createdComments[0].ID).Delete(&db.TaskCommentEdit{})
+	testConnection.dbClient.Delete(createdComments)
+	testConnection.dbClient.Delete(createdTasks)
+}
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type TaskDependencyController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Graph(w http.ResponseWriter, r *http.Request)
+}
+
+type taskDependencyController struct {
+	service service.TaskDependencyService
+}
+
+func NewTaskDependencyController(service service.TaskDependencyService) TaskDependencyController {
+	return &taskDependencyController{service}
+}
+
+// API/TASK-DEPENDENCIES
+// Find a list of task dependencies
+// @Summary      Find a list of task dependencies
+// @Description  Accepts limit, offset, order and task params and returns list of task dependencies
+// @Tags         Task Dependencies
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        task   path      int  false  "task id (blocked or blocking)"
+// @Success      200 {object} []db.TaskDependency
+// @Failure      400 {string} string "Can't find task dependencies"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /task-dependencies [get]
+// @Security BearerToken
+func (c taskDependencyController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	taskParam := r.URL.Query().Get("task")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	taskId, _ := strconv.Atoi(taskParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all task dependencies using query params
+	foundDependencies, err := c.service.FindAll(limit, offset, orderBy, taskId)
+	if err != nil {
+		http.Error(w, "Can't find task dependencies", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundDependencies)
+	if err != nil {
+		http.Error(w, "Can't find task dependencies", http.StatusBadRequest)
+		fmt.Println("error writing task dependencies to response: ", err)
+		return
+	}
+}
+
+// Find a created task dependency
+// @Summary      Find task dependency
+// @Description  Find a task dependency by ID
+// @Tags         Task Dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Task Dependency ID"
+// @Success      200 {object} db.TaskDependency
+// @Failure      400 {string} string "Can't find task dependency with ID: {id}"
+// @Router       /task-dependencies/{id} [get]
+// @Security BearerToken
+func (c taskDependencyController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundDependency, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find task dependency with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundDependency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find task dependency with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new task dependency
+// @Summary      Create a task dependency
+// @Description  Marks a task as blocked by another task. Dependencies that would create a cycle are refused
+// @Tags         Task Dependencies
+// @Accept       json
+// @Produce      json
+// @Param        dependency body models.CreateTaskDependency true "New Task Dependency Json"
+// @Success      201 {object} db.TaskDependency
+// @Failure      400 {string} string "Task dependency creation failed."
+// @Failure      409 {string} string "Task dependency would create a cycle"
+// @Router       /task-dependencies [post]
+// @Security BearerToken
+func (c taskDependencyController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var dependency models.CreateTaskDependency
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&dependency)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&dependency)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create task dependency in db
+	createdDependency, createErr := c.service.Create(&dependency)
+	if createErr != nil {
+		// If dependency would create a cycle
+		if errors.Is(createErr, service.ErrDependencyCycle) {
+			http.Error(w, "Task dependency would create a cycle", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Task dependency creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created dependency to output
+	err = helpers.WriteAsJSON(w, createdDependency)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Delete task dependency (using URL parameter id)
+// @Summary      Delete task dependency
+// @Description  Deletes an existing task dependency
+// @Tags         Task Dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Task Dependency ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed task dependency deletion"
+// @Router       /task-dependencies/{id} [delete]
+// @Security BearerToken
+func (c taskDependencyController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete task dependency using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed task dependency deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Find the dependency graph of a property's open tasks
+// @Summary      Find property task dependency graph
+// @Description  Returns the open tasks of a property (through transactions and maintenance requests) as nodes, with edges from blocking tasks to the tasks they block
+// @Tags         Task Dependencies
+// @Accept       json
+// @Produce      json
+// @Param        property   path      int  true  "property id"
+// @Success      200 {object} models.TaskDependencyGraph
+// @Failure      400 {string} string "Must include property parameter"
+// @Failure      400 {string} string "Can't build task dependency graph"
+// @Router       /task-dependencies/graph [get]
+// @Security BearerToken
+func (c taskDependencyController) Graph(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	propertyParam := r.URL.Query().Get("property")
+	// Convert to int
+	propertyId, _ := strconv.Atoi(propertyParam)
+
+	// Check that property is present as requirement
+	if propertyId == 0 {
+		http.Error(w, "Must include property parameter", http.StatusBadRequest)
+		return
+	}
+
+	// Build graph
+	graph, err := c.service.Graph(propertyId)
+	if err != nil {
+		http.Error(w, "Can't build task dependency graph", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, graph)
+	if err != nil {
+		http.Error(w, "Can't build task dependency graph", http.StatusBadRequest)
+		fmt.Println("error writing task dependency graph to response: ", err)
+		return
+	}
+}
@@ -0,0 +1,204 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestTaskDependencyController_Create(t *testing.T) {
+	// Test setup
+	// Plumbing must finish before painting, painting before cleaning
+	createdTasks := []db.Task{
+		{TaskName: "Fix leaking pipe", Type: "Maintenance", Status: "Open"},
+		{TaskName: "Repaint bathroom", Type: "Maintenance", Status: "Open"},
+		{TaskName: "Final clean", Type: "Other", Status: "Open"},
+	}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create tasks for task dependency create test: ", createResult.Error)
+	}
+	plumbing, painting, cleaning := createdTasks[0], createdTasks[1], createdTasks[2]
+
+	var createTests = []struct {
+		data                   models.CreateTaskDependency
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateTaskDependency{Task: painting, BlockedBy: plumbing}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateTaskDependency{Task: painting, BlockedBy: plumbing}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+		{models.CreateTaskDependency{Task: cleaning, BlockedBy: painting}, testConnection.accounts.admin.token, http.StatusCreated, "admin chained create test"},
+		// Cycles are refused (directly and indirectly)
+		{models.CreateTaskDependency{Task: plumbing, BlockedBy: plumbing}, testConnection.accounts.admin.token, http.StatusConflict, "admin self dependency fail test"},
+		{models.CreateTaskDependency{Task: plumbing, BlockedBy: painting}, testConnection.accounts.admin.token, http.StatusConflict, "admin direct cycle fail test"},
+		{models.CreateTaskDependency{Task: plumbing, BlockedBy: cleaning}, testConnection.accounts.admin.token, http.StatusConflict, "admin indirect cycle fail test"},
+		// Missing blocking task
+		{models.CreateTaskDependency{Task: plumbing, BlockedBy: db.Task{ID: 9999}}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin missing task fail test"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with dependency creation in body
+		req, err := http.NewRequest("POST", "/api/task-dependencies", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task dependency create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Painting can't be made active while plumbing is incomplete
+	var activateTests = []struct {
+		completePlumbing       bool
+		expectedResponseStatus int
+		testName               string
+	}{
+		{false, http.StatusConflict, "blocked activate fail test"},
+		{true, http.StatusOK, "unblocked activate test"},
+	}
+	for _, v := range activateTests {
+		if v.completePlumbing {
+			testConnection.dbClient.Model(&plumbing).Update("status", "Completed")
+		}
+		req, err := http.NewRequest("PUT", fmt.Sprintf("/api/tasks/%v", painting.ID), buildReqBody(models.UpdateTask{Status: "Active"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task dependency activate test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Deleting painting removes its dependencies, so cleaning is no longer blocked
+	rr := serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/tasks/%v", painting.ID), nil)
+	var dependencies int64
+	testConnection.dbClient.Model(&db.TaskDependency{}).Where("task_id = ? OR blocked_by_id = ?", painting.ID, painting.ID).Count(&dependencies)
+	if rr.Code != http.StatusOK || dependencies != 0 {
+		t.Errorf("Task dependency blocking task delete: expected dependencies to be removed, got %v remaining. %v %v", dependencies, rr.Code, rr.Body.String())
+	}
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/tasks/%v", cleaning.ID), models.UpdateTask{Status: "Active"})
+	if rr.Code != http.StatusOK {
+		t.Errorf("Task dependency activate after blocking task delete: got %v want %v. %v", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id IN ?", []uint{plumbing.ID, painting.ID, cleaning.ID}).Delete(&db.TaskDependency{})
+	testConnection.dbClient.Where("task_id IN ?", []uint{plumbing.ID, painting.ID, cleaning.ID}).Delete(&db.TaskLog{})
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTaskDependencyController_Graph(t *testing.T) {
+	// Test setup
+	// Create property
+	createdProperties := []db.Property{{
+		Property_Name:    "dependencyProperty1",
+		Postcode:         80361,
+		Suburb:           "Test Suburb",
+		City:             "Test City",
+		Street_Address_1: "Test Street Address 1",
+		Bedrooms:         3,
+		Bathrooms:        2,
+		Description:      "Test Description",
+		Managed:          true,
+	}}
+	createResult := testConnection.dbClient.Create(createdProperties)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create property for task dependency graph test: ", createResult.Error)
+	}
+	// Two open tasks and one completed task
+	createdTasks := []db.Task{
+		{TaskName: "Fix leaking pipe", Type: "Maintenance", Status: "Open"},
+		{TaskName: "Repaint bathroom", Type: "Maintenance", Status: "Open"},
+		{TaskName: "Replace tiles", Type: "Maintenance", Status: "Completed", Completed: true},
+	}
+	createResult = testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create tasks for task dependency graph test: ", createResult.Error)
+	}
+	// Link tasks to property through maintenance requests
+	createdRequests := []db.MaintenanceRequest{}
+	for _, task := range createdTasks {
+		createdRequests = append(createdRequests, db.MaintenanceRequest{Scale: "Low", WorkDefinition: "Repair", Type: "Plumbing", PropertyID: createdProperties[0].ID, TaskID: task.ID})
+	}
+	createResult = testConnection.dbClient.Create(createdRequests)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create maintenance requests for task dependency graph test: ", createResult.Error)
+	}
+	// Painting is blocked by plumbing
+	createdDependencies := []db.TaskDependency{{TaskID: createdTasks[1].ID, BlockedByID: createdTasks[0].ID}}
+	createResult = testConnection.dbClient.Create(createdDependencies)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create dependencies for task dependency graph test: ", createResult.Error)
+	}
+
+	var graphTests = []struct {
+		request                string
+		tokenToUse             string
+		expectedResponseStatus int
+	}{
+		{fmt.Sprintf("/api/task-dependencies/graph?property=%v", createdProperties[0].ID), testConnection.accounts.user.token, http.StatusForbidden},
+		{fmt.Sprintf("/api/task-dependencies/graph?property=%v", createdProperties[0].ID), testConnection.accounts.admin.token, http.StatusOK},
+		// No property should result in bad request
+		{"/api/task-dependencies/graph", testConnection.accounts.admin.token, http.StatusBadRequest},
+	}
+
+	for _, v := range graphTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", v.request, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task dependency graph (%v): got %v want %v", v.request, status, v.expectedResponseStatus)
+		}
+
+		// Check graph contents if successful
+		if v.expectedResponseStatus == http.StatusOK {
+			var body models.TaskDependencyGraph
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			// Completed task is excluded from open work
+			if len(body.Nodes) != 2 {
+				t.Errorf("Task dependency graph: expected 2 nodes, got %v", body.Nodes)
+			}
+			if len(body.Edges) != 1 || body.Edges[0].From != createdTasks[0].ID || body.Edges[0].To != createdTasks[1].ID {
+				t.Errorf("Task dependency graph: expected edge from %d to %d, got %v", createdTasks[0].ID, createdTasks[1].ID, body.Edges)
+			}
+			for _, node := range body.Nodes {
+				if node.Blocked != (node.ID == createdTasks[1].ID) {
+					t.Errorf("Task dependency graph: unexpected blocked state for node %v", node)
+				}
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(createdDependencies)
+	testConnection.dbClient.Delete(createdRequests)
+	testConnection.dbClient.Delete(createdTasks)
+	testConnection.dbClient.Delete(createdProperties)
+}
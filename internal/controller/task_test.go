@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/dmawardi/Go-Template/internal/db"
 	"github.com/dmawardi/Go-Template/internal/models"
@@ -472,3 +473,321 @@ func checkTaskDetails(actual *db.Task, expected *db.Task, t *testing.T, checkId
 		t.Errorf("Task has incorrect Snoozed value: expected %v, got %v", expected.Snoozed, actual.Snoozed)
 	}
 }
+
+func TestTaskController_Subtasks(t *testing.T) {
+	// Test setup
+	// Create parent task that requires its subtasks to be completed first
+	parentTask := db.Task{TaskName: "Villa move out", Type: "Inspection", Status: "Open", RequireSubtasksComplete: true}
+	seedErr := testConnection.dbClient.Create(&parentTask)
+	if seedErr.Error != nil {
+		t.Fatalf("Error seeding database: %v", seedErr.Error)
+	}
+	subtask := db.Task{TaskName: "Return keys", Type: "Other", Status: "Open", ParentID: &parentTask.ID}
+	seedErr = testConnection.dbClient.Create(&subtask)
+	if seedErr.Error != nil {
+		t.Fatalf("Error seeding database: %v", seedErr.Error)
+	}
+	// Checklist with one of two items done
+	checklist := []db.TaskChecklistItem{
+		{Description: "Photograph each room", Position: 2, TaskID: parentTask.ID},
+		{Description: "Check meter readings", Position: 1, TaskID: parentTask.ID, Done: true},
+	}
+	seedErr = testConnection.dbClient.Create(checklist)
+	if seedErr.Error != nil {
+		t.Fatalf("Error seeding database: %v", seedErr.Error)
+	}
+
+	// Check parent includes ordered checklist, subtasks and progress
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/tasks/%v", parentTask.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	var body db.Task
+	json.Unmarshal(rr.Body.Bytes(), &body)
+	if len(body.Checklist) != 2 || body.Checklist[0].Description != "Check meter readings" {
+		t.Errorf("Task subtasks find: expected checklist ordered by position, got %v", body.Checklist)
+	}
+	if len(body.Subtasks) != 1 || body.Subtasks[0].ID != subtask.ID {
+		t.Errorf("Task subtasks find: expected subtask %d, got %v", subtask.ID, body.Subtasks)
+	}
+	// One of two checklist items and zero of one subtasks complete
+	if body.Progress != 33 {
+		t.Errorf("Task subtasks find: expected progress 33, got %d", body.Progress)
+	}
+
+	var updateTests = []struct {
+		data                   models.UpdateTask
+		completeSubtask        bool
+		expectedResponseStatus int
+		testName               string
+	}{
+		// Parent can't be made a subtask of its own subtask
+		{models.UpdateTask{ParentID: subtask.ID}, false, http.StatusBadRequest, "subtask cycle fail test"},
+		// Parent can't be completed before its subtask
+		{models.UpdateTask{Status: "Completed"}, false, http.StatusConflict, "incomplete subtask fail test"},
+		{models.UpdateTask{Completed: true}, false, http.StatusConflict, "incomplete subtask completed flag fail test"},
+		{models.UpdateTask{Status: "Completed"}, true, http.StatusOK, "complete subtask test"},
+	}
+
+	requestUrl := fmt.Sprintf("/api/tasks/%v", parentTask.ID)
+	for _, v := range updateTests {
+		// Complete subtask if required
+		if v.completeSubtask {
+			testConnection.dbClient.Model(&subtask).Update("status", "Completed")
+		}
+		req, err := http.NewRequest("PUT", requestUrl, buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task subtasks update (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Delete the created fixtures
+	testConnection.dbClient.Delete(checklist)
+	testConnection.dbClient.Where("task_id = ?", parentTask.ID).Delete(&db.TaskLog{})
+	testConnection.dbClient.Delete(&subtask)
+	testConnection.dbClient.Delete(&parentTask)
+}
+
+func TestTaskController_Board(t *testing.T) {
+	// Test setup
+	createdTasks := []db.Task{
+		{TaskName: "Board task A", Type: "Other", Status: "Processing"},
+		{TaskName: "Board task B", Type: "Other", Status: "Processing"},
+		{TaskName: "Board task C", Type: "Other", Status: "Processing"},
+	}
+	seedErr := testConnection.dbClient.Create(createdTasks)
+	if seedErr.Error != nil {
+		t.Fatalf("Error seeding database: %v", seedErr.Error)
+	}
+	// Block task C from becoming active
+	blocker := db.Task{TaskName: "Board blocker", Type: "Other", Status: "Open"}
+	testConnection.dbClient.Create(&blocker)
+	dependency := db.TaskDependency{TaskID: createdTasks[2].ID, BlockedByID: blocker.ID}
+	testConnection.dbClient.Create(&dependency)
+
+	// Finds the processing column of the board
+	findProcessingColumn := func() models.TaskBoardColumn {
+		req, _ := http.NewRequest("GET", "/api/tasks/board?limit=49", nil)
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("Task board: got %v want %v", status, http.StatusOK)
+		}
+		var board models.TaskBoard
+		json.Unmarshal(rr.Body.Bytes(), &board)
+		if len(board.Columns) != len(models.TaskBoardStatuses) {
+			t.Errorf("Task board: expected %d columns, got %d", len(models.TaskBoardStatuses), len(board.Columns))
+		}
+		for _, column := range board.Columns {
+			if column.Status == "Processing" {
+				return column
+			}
+		}
+		return models.TaskBoardColumn{}
+	}
+	column := findProcessingColumn()
+	if column.Count < 3 || int(column.Count) < len(column.Tasks) {
+		t.Errorf("Task board: expected processing count of at least 3 and no less than listed tasks, got %d for %d tasks", column.Count, len(column.Tasks))
+	}
+
+	var moveTests = []struct {
+		data                   models.MoveTask
+		taskId                 uint
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.MoveTask{Rank: 1}, createdTasks[2].ID, testConnection.accounts.user.token, http.StatusForbidden, "basic user move test"},
+		// Rank within column
+		{models.MoveTask{Rank: 1}, createdTasks[2].ID, testConnection.accounts.admin.token, http.StatusOK, "admin rank test"},
+		// Moving column follows task update rules
+		{models.MoveTask{Status: "Active", Rank: 1}, createdTasks[2].ID, testConnection.accounts.admin.token, http.StatusConflict, "admin blocked move fail test"},
+		{models.MoveTask{Status: "Unknown"}, createdTasks[2].ID, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid status fail test"},
+	}
+	for _, v := range moveTests {
+		req, err := http.NewRequest("PUT", fmt.Sprintf("/api/tasks/board/%v", v.taskId), buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task board move (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Ranked task is listed first in its column
+	column = findProcessingColumn()
+	if len(column.Tasks) == 0 || column.Tasks[0].ID != createdTasks[2].ID || column.Tasks[0].BoardRank != 1 {
+		t.Errorf("Task board: expected task %d ranked first, got %v", createdTasks[2].ID, column.Tasks)
+	}
+
+	// Delete the created fixtures
+	testConnection.dbClient.Delete(&dependency)
+	testConnection.dbClient.Delete(&blocker)
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTaskController_FindMyTasks(t *testing.T) {
+	// Test setup
+	basicUser := *testConnection.accounts.user.details
+	createdTasks := []db.Task{
+		{TaskName: "My open task", Type: "Other", Status: "Open", DueDate: time.Now().AddDate(0, 0, 7)},
+		{TaskName: "My overdue task", Type: "Other", Status: "Open", DueDate: time.Now().AddDate(0, 0, -1)},
+		{TaskName: "My snoozed task", Type: "Other", Status: "Pending", Snoozed: true, SnoozedTill: time.Now().AddDate(0, 0, 3)},
+		{TaskName: "My completed task", Type: "Other", Status: "Completed", Completed: true},
+		{TaskName: "Someone else's task", Type: "Other", Status: "Open"},
+	}
+	// Created individually as only some tasks have a due date
+	for i := range createdTasks {
+		seedErr := testConnection.dbClient.Create(&createdTasks[i])
+		if seedErr.Error != nil {
+			t.Fatalf("Error seeding database: %v", seedErr.Error)
+		}
+	}
+	for _, task := range createdTasks[:4] {
+		testConnection.dbClient.Model(&task).Association("Assignment").Append(&basicUser)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/me/tasks", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.user.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Find my tasks: got %v want %v. %v", status, http.StatusOK, rr.Body.String())
+	}
+	var body models.MyTasks
+	json.Unmarshal(rr.Body.Bytes(), &body)
+
+	// Returns ids of tasks in list
+	ids := func(tasks []db.Task) map[uint]bool {
+		found := make(map[uint]bool)
+		for _, task := range tasks {
+			found[task.ID] = true
+		}
+		return found
+	}
+	assigned, overdue, snoozed, completed := ids(body.Assigned), ids(body.Overdue), ids(body.Snoozed), ids(body.RecentlyCompleted)
+	if !assigned[createdTasks[0].ID] || !assigned[createdTasks[1].ID] || !assigned[createdTasks[2].ID] || assigned[createdTasks[3].ID] || assigned[createdTasks[4].ID] {
+		t.Errorf("Find my tasks: unexpected assigned tasks %v", body.Assigned)
+	}
+	if !overdue[createdTasks[1].ID] || overdue[createdTasks[0].ID] {
+		t.Errorf("Find my tasks: unexpected overdue tasks %v", body.Overdue)
+	}
+	if !snoozed[createdTasks[2].ID] || len(body.Snoozed) != len(snoozed) {
+		t.Errorf("Find my tasks: unexpected snoozed tasks %v", body.Snoozed)
+	}
+	if !completed[createdTasks[3].ID] {
+		t.Errorf("Find my tasks: unexpected recently completed tasks %v", body.RecentlyCompleted)
+	}
+
+	// Delete the created fixtures
+	for _, task := range createdTasks {
+		testConnection.dbClient.Model(&task).Association("Assignment").Clear()
+	}
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTaskController_CreateWithRecordsAndDelete(t *testing.T) {
+	// Test setup
+	property := db.Property{Property_Name: "taskRecordsProperty1", Postcode: 80361, Suburb: "Ubud", City: "Gianyar", Street_Address_1: "Jl. Raya Ubud", Bedrooms: 2, Bathrooms: 2, Description: "Villa"}
+	testConnection.dbClient.Create(&property)
+	transaction := models.CreateTransaction{Type: "Sale", Agency: "Own", TransactionValue: db.NewMoney(2000000000, "IDR"), Property: property}
+	missingScheme := transaction
+	missingScheme.CommissionScheme = db.CommissionScheme{ID: 9999}
+
+	var createTests = []struct {
+		data                   models.CreateTaskWithTransaction
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateTaskWithTransaction{Task: models.CreateTask{TaskName: "Sell the Ubud villa", Type: "Transaction"}, Transaction: transaction}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateTaskWithTransaction{Task: models.CreateTask{TaskName: "Sell the Ubud villa", Type: "Transaction"}}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin missing transaction fail test"},
+		// Nothing is created when the transaction can't be
+		{models.CreateTaskWithTransaction{Task: models.CreateTask{TaskName: "Sell the Ubud villa", Type: "Transaction"}, Transaction: missingScheme}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin missing commission scheme fail test"},
+		{models.CreateTaskWithTransaction{Task: models.CreateTask{TaskName: "Sell the Ubud villa", Type: "Transaction"}, Transaction: transaction}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+	}
+
+	var saleTask db.Task
+	for _, v := range createTests {
+		// Make new request with task and transaction in body
+		req, err := http.NewRequest("POST", "/api/tasks/with-transaction", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Task with transaction create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &saleTask)
+		}
+	}
+	var created int64
+	testConnection.dbClient.Model(&db.Task{}).Where("task_name = ?", "Sell the Ubud villa").Count(&created)
+	if created != 1 || saleTask.Transaction.ID == 0 || saleTask.Transaction.TaskID != saleTask.ID {
+		t.Fatalf("Task with transaction create: expected one task with its transaction, got %v tasks and %+v", created, saleTask)
+	}
+
+	// Transactions and maintenance requests must belong to an existing task
+	rr := serveAsAdmin(t, "POST", "/api/transactions", transaction)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Transaction create without task: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+	rr = serveAsAdmin(t, "POST", "/api/tasks/with-maintenance", models.CreateTaskWithMaintenanceRequest{Task: models.CreateTask{TaskName: "Fix the Ubud villa pump", Type: "Maintenance"},
+		MaintenanceRequest: models.CreateMaintenanceRequest{WorkDefinition: "Repair", Type: "Plumbing", Scale: "High", Property: property}})
+	var maintenanceTask db.Task
+	json.Unmarshal(rr.Body.Bytes(), &maintenanceTask)
+	if rr.Code != http.StatusCreated || maintenanceTask.MaintenanceRequest.ID == 0 || maintenanceTask.MaintenanceRequest.TaskID != maintenanceTask.ID {
+		t.Fatalf("Task with maintenance request create: expected the task with its request, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Deleting a maintenance request archives its task
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/maintenance/%v", maintenanceTask.MaintenanceRequest.ID), nil)
+	var found db.Task
+	testConnection.dbClient.First(&found, maintenanceTask.ID)
+	if rr.Code != http.StatusOK || found.Status != "Archived" {
+		t.Errorf("Maintenance request delete: expected its task archived, got %v with status %v", rr.Code, found.Status)
+	}
+
+	// Completed transactions are kept, so their task can only be archived
+	testConnection.dbClient.Model(&db.Transaction{}).Where("id = ?", saleTask.Transaction.ID).Update("transaction_completion", time.Now())
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/tasks/%v", saleTask.ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Delete task with completed transaction: got %v want %v", rr.Code, http.StatusConflict)
+	}
+	// Open transactions are deleted with their task
+	testConnection.dbClient.Model(&db.Transaction{}).Where("id = ?", saleTask.Transaction.ID).Update("transaction_completion", nil)
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/tasks/%v", saleTask.ID), nil)
+	var remaining int64
+	testConnection.dbClient.Model(&db.Transaction{}).Where("task_id = ?", saleTask.ID).Count(&remaining)
+	if rr.Code != http.StatusOK || remaining != 0 {
+		t.Errorf("Delete task with open transaction: expected the transaction deleted, got %v with %v remaining", rr.Code, remaining)
+	}
+
+	// Cleanup
+	testConnection.dbClient.Unscoped().Where("transaction_id = ?", saleTask.Transaction.ID).Delete(&db.TransactionStageChange{})
+	testConnection.dbClient.Unscoped().Where("transaction_id = ?", saleTask.Transaction.ID).Delete(&db.TransactionMilestone{})
+	testConnection.dbClient.Unscoped().Delete(&db.Transaction{}, saleTask.Transaction.ID)
+	testConnection.dbClient.Unscoped().Delete(&db.MaintenanceRequest{}, maintenanceTask.MaintenanceRequest.ID)
+	testConnection.dbClient.Unscoped().Delete(&db.Task{}, []uint{saleTask.ID, maintenanceTask.ID})
+	testConnection.dbClient.Unscoped().Delete(&property)
+}
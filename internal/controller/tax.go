@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type TaxController interface {
+	FindAllRules(w http.ResponseWriter, r *http.Request)
+	FindRule(w http.ResponseWriter, r *http.Request)
+	CreateRule(w http.ResponseWriter, r *http.Request)
+	UpdateRule(w http.ResponseWriter, r *http.Request)
+	DeleteRule(w http.ResponseWriter, r *http.Request)
+	Summary(w http.ResponseWriter, r *http.Request)
+}
+
+type taxController struct {
+	service service.TaxService
+	rates   service.ExchangeRateService
+}
+
+func NewTaxController(service service.TaxService, rates service.ExchangeRateService) TaxController {
+	return &taxController{service, rates}
+}
+
+// API/TAX-RULES
+// Find a list of tax rules
+// @Summary      Find a list of tax rules
+// @Description  Accepts limit, offset, order, tax and applies to params and returns list of tax rules (latest first within each tax by default)
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        tax   path      string  false  "tax (PPN, PPh 4(2) or PPh 23)"
+// @Param        applies_to   path      string  false  "applies to (Rent, Commission or Vendor Invoice)"
+// @Success      200 {object} []db.TaxRule
+// @Failure      400 {string} string "Can't find tax rules"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /tax-rules [get]
+// @Security BearerToken
+func (c taxController) FindAllRules(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	tax := r.URL.Query().Get("tax")
+	appliesTo := r.URL.Query().Get("applies_to")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all tax rules using query params
+	foundRules, err := c.service.FindAllRules(limit, offset, orderBy, tax, appliesTo)
+	if err != nil {
+		http.Error(w, "Can't find tax rules", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundRules)
+	if err != nil {
+		http.Error(w, "Can't find tax rules", http.StatusBadRequest)
+		fmt.Println("error writing tax rules to response: ", err)
+		return
+	}
+}
+
+// Find a created tax rule
+// @Summary      Find tax rule
+// @Description  Find a tax rule by ID
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Tax Rule ID"
+// @Success      200 {object} db.TaxRule
+// @Failure      400 {string} string "Can't find tax rule with ID: {id}"
+// @Router       /tax-rules/{id} [get]
+// @Security BearerToken
+func (c taxController) FindRule(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundRule, err := c.service.FindRuleById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find tax rule with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundRule)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find tax rule with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new tax rule
+// @Summary      Create tax rule
+// @Description  Sets the rate of a tax on rent, commission or vendor invoices from an effective date until a later rule of the same tax. PPN applies to commission and vendor invoices, PPh 4(2) to rent and PPh 23 (withheld) to vendor invoices
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        rule body models.CreateTaxRule true "New Tax Rule Json"
+// @Success      201 {object} db.TaxRule
+// @Failure      400 {string} string "Tax rule creation failed."
+// @Failure      409 {string} string "Tax already has a rule for this date"
+// @Router       /tax-rules [post]
+// @Security BearerToken
+func (c taxController) CreateRule(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var rule models.CreateTaxRule
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&rule)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&rule)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create tax rule in db
+	createdRule, createErr := c.service.CreateRule(&rule)
+	if createErr != nil {
+		if errors.Is(createErr, service.ErrTaxRuleExists) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Tax rule creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created rule to output
+	err = helpers.WriteAsJSON(w, createdRule)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a tax rule (using URL parameter id)
+// @Summary      Update tax rule
+// @Description  Updates the rate, effective date or notes of a tax rule. Tax already calculated with the rule is kept
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Tax Rule ID"
+// @Param        rule body models.UpdateTaxRule true "Update Tax Rule Json"
+// @Success      200 {object} db.TaxRule
+// @Failure      400 {string} string "Failed tax rule update"
+// @Failure      409 {string} string "Tax already has a rule for this date"
+// @Router       /tax-rules/{id} [put]
+// @Security BearerToken
+func (c taxController) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var rule models.UpdateTaxRule
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&rule)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&rule)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update tax rule
+	updatedRule, err := c.service.UpdateRule(idParameter, &rule)
+	if err != nil {
+		if errors.Is(err, service.ErrTaxRuleExists) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed tax rule update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated rule to output
+	err = helpers.WriteAsJSON(w, updatedRule)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed tax rule update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete tax rule (using URL parameter id)
+// @Summary      Delete tax rule
+// @Description  Deletes a tax rule. Tax already calculated with the rule is kept
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Tax Rule ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed tax rule deletion"
+// @Router       /tax-rules/{id} [delete]
+// @Security BearerToken
+func (c taxController) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete tax rule using id
+	err := c.service.DeleteRule(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed tax rule deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/TAX/SUMMARY
+// Monthly tax summary
+// @Summary      Monthly tax summary
+// @Description  Returns tax computed on rent, commission and vendor invoices over a month, with PPN output less input tax, PPh 4(2) on rent and income tax withheld from vendor payments
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        year   path      int  false  "year. Defaults to the current year"
+// @Param        month   path      int  false  "month (1-12). Defaults to the current month"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.TaxSummary
+// @Failure      400 {string} string "Can't summarise tax"
+// @Router       /tax/summary [get]
+// @Security BearerToken
+func (c taxController) Summary(w http.ResponseWriter, r *http.Request) {
+	var err error
+	// Summary month
+	now := time.Now()
+	year, month := now.Year(), int(now.Month())
+	if yearParam := r.URL.Query().Get("year"); yearParam != "" {
+		year, err = strconv.Atoi(yearParam)
+		if err != nil || year < 2000 || year > 2100 {
+			http.Error(w, "Year must be between 2000 and 2100", http.StatusBadRequest)
+			return
+		}
+	}
+	if monthParam := r.URL.Query().Get("month"); monthParam != "" {
+		month, err = strconv.Atoi(monthParam)
+		if err != nil || month < 1 || month > 12 {
+			http.Error(w, "Month must be between 1 and 12", http.StatusBadRequest)
+			return
+		}
+	}
+
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	summary, err := c.service.Summary(year, month, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't summarise tax: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, summary)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't summarise tax: %v", err), http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,129 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestTaxController_RulesTaxLinesAndSummary(t *testing.T) {
+	// Test setup
+	effective := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var createTests = []struct {
+		data                   models.CreateTaxRule
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateTaxRule{Tax: "PPN", AppliesTo: "Commission", Rate: 11, EffectiveDate: effective}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateTaxRule{Tax: "VAT", AppliesTo: "Commission", Rate: 11, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin unknown tax fail test"},
+		// PPh 23 isn't charged on rent
+		{models.CreateTaxRule{Tax: "PPh 23", AppliesTo: "Rent", Rate: 2, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid applies to fail test"},
+		{models.CreateTaxRule{Tax: "PPN", AppliesTo: "Commission", Rate: 11, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusCreated, "admin ppn on commission test"},
+		{models.CreateTaxRule{Tax: "PPN", AppliesTo: "Vendor Invoice", Rate: 11, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusCreated, "admin ppn on vendor invoices test"},
+		{models.CreateTaxRule{Tax: "PPh 23", AppliesTo: "Vendor Invoice", Rate: 2, Withheld: true, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusCreated, "admin pph 23 withheld test"},
+		{models.CreateTaxRule{Tax: "PPh 4(2)", AppliesTo: "Rent", Rate: 10, EffectiveDate: effective}, testConnection.accounts.admin.token, http.StatusCreated, "admin pph 4(2) on rent test"},
+		{models.CreateTaxRule{Tax: "PPN", AppliesTo: "Commission", Rate: 12, EffectiveDate: effective.Add(6 * time.Hour)}, testConnection.accounts.admin.token, http.StatusConflict, "admin duplicate date fail test"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with tax rule creation in body
+		req, err := http.NewRequest("POST", "/api/tax-rules", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Tax rule create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Vendor invoices are charged PPN on taxable lines, with PPh 23 withheld from the payment
+	f := createVendorQuoteFixtures(t)
+	createdOrder := db.WorkOrder{Description: "Service air conditioners", Status: "Completed", MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, TaskID: f.task.ID}
+	testConnection.dbClient.Create(&createdOrder)
+	invoiceDate := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.Local)
+	rr := serveAsAdmin(t, "POST", "/api/vendor-invoices", models.CreateVendorInvoice{InvoiceNumber: "TAX-001", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), WorkOrder: createdOrder, Lines: []models.VendorInvoiceLine{
+		{Description: "Air conditioner service", Quantity: 2, UnitPrice: db.NewMoney(500000, "IDR")},
+		{Description: "Call out fee", Quantity: 1, UnitPrice: db.NewMoney(100000, "IDR"), PPNExempt: true},
+	}})
+	var invoice db.VendorInvoice
+	json.Unmarshal(rr.Body.Bytes(), &invoice)
+	if rr.Code != http.StatusCreated || invoice.PPN.Float() != 110000 || invoice.Total.Float() != 1210000 || invoice.Withholding.Float() != 22000 || len(invoice.TaxLines) != 2 {
+		t.Fatalf("Vendor invoice create: expected PPN 110000, total 1210000 and 22000 withheld, got %v %v", rr.Code, rr.Body.String())
+	}
+	// Only the total less withholding is paid to the vendor
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/vendor-invoices/payments/%v", invoice.ID), models.RecordVendorPayment{Amount: db.NewMoney(1200000, "IDR")})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Vendor invoice payment of withheld tax: got %v want %v", rr.Code, http.StatusConflict)
+	}
+	rr = serveAsAdmin(t, "POST", fmt.Sprintf("/api/vendor-invoices/payments/%v", invoice.ID), models.RecordVendorPayment{Amount: db.NewMoney(1188000, "IDR")})
+	json.Unmarshal(rr.Body.Bytes(), &invoice)
+	if rr.Code != http.StatusCreated || invoice.Status != "Paid" {
+		t.Errorf("Vendor invoice payment less withholding: expected paid, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Commission is charged PPN on the agency's share when the transaction completes
+	property := db.Property{Property_Name: "taxProperty1", Postcode: 80361, Suburb: "Canggu", City: "Badung", Street_Address_1: "Jl. Batu Bolong", Bedrooms: 3, Bathrooms: 3, Description: "Villa"}
+	testConnection.dbClient.Create(&property)
+	task := db.Task{TaskName: "Sell the Canggu villa", Type: "Sale"}
+	testConnection.dbClient.Create(&task)
+	transaction, err := testConnection.transactions.serv.Create(&models.CreateTransaction{Type: "Sale", Agency: "Own", TransactionValue: db.NewMoney(4000000000, "IDR"), Fee: db.NewMoney(100000000, "IDR"), Property: property, Task: task})
+	if err != nil {
+		t.Fatalf("Transaction create failed: %v", err)
+	}
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/%v", transaction.ID), models.UpdateTransaction{TransactionCompletion: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.Local)})
+	var completed db.Transaction
+	json.Unmarshal(rr.Body.Bytes(), &completed)
+	if rr.Code != http.StatusOK || len(completed.TaxLines) != 1 || completed.TaxLines[0].Amount.Float() != 11000000 {
+		t.Errorf("Transaction completion: expected 11000000 PPN on commission, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Monthly summary credits PPN on vendor invoices against PPN on commission
+	var summaryTests = []struct {
+		query                  string
+		expectedResponseStatus int
+		expectedPPNPayable     float64
+		expectedWithheld       float64
+		expectedTaxes          int
+	}{
+		{"year=2024&month=3", http.StatusOK, 10890000, 22000, 3},
+		{"year=2024&month=4", http.StatusOK, 0, 0, 0},
+		{"year=2024&month=13", http.StatusBadRequest, 0, 0, 0},
+	}
+	for _, v := range summaryTests {
+		rr = serveAsAdmin(t, "GET", "/api/tax/summary?"+v.query, nil)
+		var summary models.TaxSummary
+		json.Unmarshal(rr.Body.Bytes(), &summary)
+		if rr.Code != v.expectedResponseStatus || rr.Code == http.StatusOK && (summary.PPNPayable.Float() != v.expectedPPNPayable || summary.WithheldTax.Float() != v.expectedWithheld || len(summary.Taxes) != v.expectedTaxes) {
+			t.Errorf("Tax summary (%v): expected PPN payable %v and %v withheld over %v taxes, got %v %v", v.query, v.expectedPPNPayable, v.expectedWithheld, v.expectedTaxes, rr.Code, rr.Body.String())
+		}
+	}
+
+	// Cleanup
+	testConnection.dbClient.Where("1 = 1").Delete(&db.TaxLine{})
+	testConnection.dbClient.Unscoped().Where("1 = 1").Delete(&db.TaxRule{})
+	testConnection.dbClient.Unscoped().Delete(transaction)
+	testConnection.dbClient.Unscoped().Delete(&task)
+	testConnection.dbClient.Unscoped().Delete(&property)
+	testConnection.dbClient.Where("vendor_invoice_id = ?", invoice.ID).Delete(&db.VendorPayment{})
+	testConnection.dbClient.Where("vendor_invoice_id = ?", invoice.ID).Delete(&db.VendorInvoiceLine{})
+	testConnection.dbClient.Unscoped().Delete(&invoice)
+	testConnection.dbClient.Delete(&createdOrder)
+	f.delete()
+}
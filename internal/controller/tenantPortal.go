@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type TenantPortalController interface {
+	SubmitMaintenance(w http.ResponseWriter, r *http.Request)
+	MaintenanceStatus(w http.ResponseWriter, r *http.Request)
+	RotatePortalToken(w http.ResponseWriter, r *http.Request)
+}
+
+type tenantPortalController struct {
+	service service.TenantPortalService
+}
+
+func NewTenantPortalController(service service.TenantPortalService) TenantPortalController {
+	return &tenantPortalController{service}
+}
+
+// PORTAL/{PROPERTYTOKEN}/MAINTENANCE
+// Submit a maintenance request as a tenant
+// @Summary      Submit tenant maintenance request
+// @Description  Accepts form-data with description, category (Electrical, Plumbing, Painting, HVAC, Civil or Other), name, contact and up to 5 "photos" of up to 5 MB each. Creates a maintenance request with a task awaiting triage and returns a tracking code
+// @Tags         Tenant Portal
+// @Accept       mpfd
+// @Produce      json
+// @Param        propertyToken   path      string  true  "Property portal token"
+// @Success      201 {object} models.TenantMaintenanceReceipt
+// @Failure      400 {string} string "Maintenance request submission failed"
+// @Failure      404 {string} string "Portal not found"
+// @Failure      413 {string} string "Maintenance request submission is too large"
+// @Router       /portal/{propertyToken}/maintenance [post]
+func (c tenantPortalController) SubmitMaintenance(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	token := chi.URLParam(r, "propertyToken")
+
+	// Cap the submission before reading it, as the portal is public
+	r.Body = http.MaxBytesReader(w, r.Body, models.TenantPortalMaxSubmissionSize)
+	// Parse the multipart form data. Parts beyond 10 MB are held in temporary files
+	err := r.ParseMultipartForm(10 << 20)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Maintenance request submission is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Maintenance request must be submitted as form-data", http.StatusBadRequest)
+		return
+	}
+	request := models.TenantMaintenanceRequest{
+		Description: r.FormValue("description"),
+		Category:    r.FormValue("category"),
+		Name:        r.FormValue("name"),
+		Contact:     r.FormValue("contact"),
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&request)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	var photos []*multipart.FileHeader
+	if r.MultipartForm != nil {
+		photos = r.MultipartForm.File["photos"]
+	}
+	receipt, err := c.service.SubmitMaintenance(token, &request, photos)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPortalToken) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Maintenance request submission failed: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write receipt to output
+	err = helpers.WriteAsJSON(w, receipt)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Check progress of a tenant's maintenance request
+// @Summary      Tenant maintenance request status
+// @Description  Returns the progress of a maintenance request submitted through the property's portal (Received, In Progress, Scheduled, Completed or Closed)
+// @Tags         Tenant Portal
+// @Accept       json
+// @Produce      json
+// @Param        propertyToken   path      string  true  "Property portal token"
+// @Param        code   path      string  true  "Tracking code"
+// @Success      200 {object} models.TenantMaintenanceStatus
+// @Failure      404 {string} string "Maintenance request not found"
+// @Router       /portal/{propertyToken}/maintenance/{code} [get]
+func (c tenantPortalController) MaintenanceStatus(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameters
+	token := chi.URLParam(r, "propertyToken")
+	code := chi.URLParam(r, "code")
+
+	status, err := c.service.MaintenanceStatus(token, code)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPortalToken) || errors.Is(err, service.ErrTrackingCodeNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Can't find maintenance request", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, status)
+	if err != nil {
+		http.Error(w, "Can't find maintenance request", http.StatusBadRequest)
+		return
+	}
+}
+
+// API/PROPERTIES/PORTAL-TOKEN
+// Generate a property's tenant portal token (using URL parameter id)
+// @Summary      Generate property portal token
+// @Description  Generates a new tenant portal token for the property. Links using the previous token stop working
+// @Tags         Tenant Portal
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Property ID"
+// @Success      201 {object} models.PropertyPortalToken
+// @Failure      400 {string} string "Failed generating portal token"
+// @Router       /properties/portal-token/{id} [post]
+// @Security BearerToken
+func (c tenantPortalController) RotatePortalToken(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	portalToken, err := c.service.RotatePortalToken(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed generating portal token: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write token to output
+	err = helpers.WriteAsJSON(w, portalToken)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
@@ -0,0 +1,196 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+// Builds a tenant maintenance form with the named photo files of the given size
+func buildTenantMaintenanceForm(t *testing.T, fields map[string]string, photos []string, photoSize int) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	for _, photo := range photos {
+		fileField, err := writer.CreateFormFile("photos", photo)
+		if err != nil {
+			t.Fatalf("Failed to create form file field: %v", err)
+		}
+		fileField.Write(bytes.Repeat([]byte("p"), photoSize))
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestTenantPortalController_SubmitAndStatus(t *testing.T) {
+	// Test setup
+	createdProperty := db.Property{Property_Name: "portalProperty1", Suburb: "Ubud", City: "Gianyar"}
+	testConnection.dbClient.Create(&createdProperty)
+
+	// Generate the property's portal token
+	var tokenTests = []struct {
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{testConnection.accounts.user.token, http.StatusForbidden, "basic user portal token test"},
+		{testConnection.accounts.admin.token, http.StatusCreated, "admin portal token test"},
+	}
+	var portal models.PropertyPortalToken
+	for _, v := range tokenTests {
+		req, err := http.NewRequest("POST", fmt.Sprintf("/api/properties/portal-token/%v", createdProperty.ID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Portal token (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &portal)
+		}
+	}
+	if portal.PortalToken == "" || portal.MaintenancePath != fmt.Sprintf("/portal/%s/maintenance", portal.PortalToken) {
+		t.Fatalf("Portal token: expected token and maintenance path, got %v", portal)
+	}
+
+	validFields := map[string]string{"description": "Water is leaking under the kitchen sink", "category": "Plumbing", "name": "Ayu Lestari", "contact": "+62 812 3456 7890"}
+	var submitTests = []struct {
+		token                  string
+		fields                 map[string]string
+		photos                 []string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{"not-a-token", validFields, nil, http.StatusNotFound, "invalid token test"},
+		{portal.PortalToken, map[string]string{"category": "Plumbing", "name": "Ayu Lestari", "contact": "+62 812 3456 7890"}, nil, http.StatusBadRequest, "missing description test"},
+		{portal.PortalToken, map[string]string{"description": "Water is leaking under the kitchen sink", "category": "Roof", "name": "Ayu Lestari", "contact": "+62 812 3456 7890"}, nil, http.StatusBadRequest, "invalid category test"},
+		{portal.PortalToken, validFields, []string{"leak.jpg", "notes.txt"}, http.StatusBadRequest, "non photo upload test"},
+		{portal.PortalToken, validFields, []string{"1.jpg", "2.jpg", "3.jpg", "4.jpg", "5.jpg", "6.jpg"}, http.StatusBadRequest, "too many photos test"},
+		{portal.PortalToken, validFields, []string{"leak.jpg", "cabinet.PNG"}, http.StatusCreated, "tenant submit test"},
+	}
+	var receipt models.TenantMaintenanceReceipt
+	for _, v := range submitTests {
+		body, contentType := buildTenantMaintenanceForm(t, v.fields, v.photos, 1024)
+		req, err := http.NewRequest("POST", fmt.Sprintf("/portal/%s/maintenance", v.token), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// No authorization header. Portal is public
+		req.Header.Set("Content-Type", contentType)
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Tenant maintenance submit (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &receipt)
+		}
+	}
+	if receipt.TrackingCode == "" || receipt.Status != "Received" || receipt.Photos != 2 {
+		t.Fatalf("Tenant maintenance submit: expected received request with 2 photos, got %v", receipt)
+	}
+
+	// Each photo is capped, and submissions over the overall cap are rejected before they are read
+	var sizeTests = []struct {
+		photoSize              int
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.TenantPortalMaxPhotoSize + 1, http.StatusBadRequest, "oversized photo test"},
+		{models.TenantPortalMaxSubmissionSize, http.StatusRequestEntityTooLarge, "oversized submission test"},
+	}
+	for _, v := range sizeTests {
+		body, contentType := buildTenantMaintenanceForm(t, validFields, []string{"leak.jpg"}, v.photoSize)
+		req, err := http.NewRequest("POST", fmt.Sprintf("/portal/%s/maintenance", portal.PortalToken), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Tenant maintenance submit (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Request and triage task are created
+	var createdRequest db.MaintenanceRequest
+	testConnection.dbClient.Preload("Photos").Where("tracking_code = ?", receipt.TrackingCode).First(&createdRequest)
+	var createdTask db.Task
+	testConnection.dbClient.First(&createdTask, createdRequest.TaskID)
+	if createdRequest.PropertyID != createdProperty.ID || createdRequest.Type != "Plumbing" || createdRequest.ReporterName != "Ayu Lestari" || len(createdRequest.Photos) != 2 {
+		t.Errorf("Tenant maintenance submit: expected plumbing request with 2 photos from Ayu Lestari, got %v", createdRequest)
+	} else if createdRequest.Photos[0].FileName != "leak.jpg" || createdRequest.Photos[1].FileName != "cabinet.PNG" {
+		t.Errorf("Tenant maintenance submit: expected photos to keep their file names, got %v and %v", createdRequest.Photos[0].FileName, createdRequest.Photos[1].FileName)
+	}
+	if createdTask.Status != "Triage" || createdTask.Type != "Maintenance" {
+		t.Errorf("Tenant maintenance submit: expected maintenance task in triage, got %v %v", createdTask.Type, createdTask.Status)
+	}
+	testConnection.notifications.serv.Wait()
+	var found []db.Notification
+	testConnection.dbClient.Where("user_id = ? AND type = ? AND task_id = ?", testConnection.accounts.admin.details.ID, "TenantRequestSubmitted", createdTask.ID).Find(&found)
+	if len(found) != 1 {
+		t.Errorf("Tenant maintenance submit: expected admin notification, got %d", len(found))
+	}
+
+	// Checks the tenant's view of the request
+	checkStatus := func(token string, code string, expectedResponseStatus int, expectedStatus string) *models.TenantMaintenanceStatus {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/portal/%s/maintenance/%s", token, code), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		var status models.TenantMaintenanceStatus
+		json.Unmarshal(rr.Body.Bytes(), &status)
+		if rr.Code != expectedResponseStatus || status.Status != expectedStatus {
+			t.Errorf("Tenant maintenance status (%v): expected %v %v, got %v %v", code, expectedResponseStatus, expectedStatus, rr.Code, rr.Body.String())
+		}
+		return &status
+	}
+	checkStatus(portal.PortalToken, receipt.TrackingCode, http.StatusOK, "Received")
+	checkStatus(portal.PortalToken, "MR-UNKNOWN1", http.StatusNotFound, "")
+
+	// Scheduled vendor visit
+	testConnection.dbClient.Model(&createdTask).Update("status", "Active")
+	scheduledStart := time.Now().AddDate(0, 0, 2).Truncate(time.Second)
+	createdVendor := db.Vendor{CompanyName: "Portal Plumbing", NPWP: "012345674012000"}
+	testConnection.dbClient.Create(&createdVendor)
+	createdOrder := db.WorkOrder{Description: "Fix leak", Status: "Scheduled", ScheduledStart: &scheduledStart, MaintenanceRequestID: createdRequest.ID, VendorID: createdVendor.ID, TaskID: createdTask.ID}
+	testConnection.dbClient.Create(&createdOrder)
+	status := checkStatus(portal.PortalToken, receipt.TrackingCode, http.StatusOK, "Scheduled")
+	if status.ScheduledFor == nil || !status.ScheduledFor.Equal(scheduledStart) {
+		t.Errorf("Tenant maintenance status: expected visit scheduled for %v, got %v", scheduledStart, status.ScheduledFor)
+	}
+
+	// Rotating the token disables the previous portal link
+	rr := serveAsAdmin(t, "POST", fmt.Sprintf("/api/properties/portal-token/%v", createdProperty.ID), nil)
+	var rotated models.PropertyPortalToken
+	json.Unmarshal(rr.Body.Bytes(), &rotated)
+	checkStatus(portal.PortalToken, receipt.TrackingCode, http.StatusNotFound, "")
+	checkStatus(rotated.PortalToken, receipt.TrackingCode, http.StatusOK, "Scheduled")
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(found)
+	testConnection.dbClient.Where("task_id = ?", createdTask.ID).Delete(&db.TaskLog{})
+	testConnection.dbClient.Delete(&createdOrder)
+	testConnection.dbClient.Unscoped().Delete(&createdVendor)
+	testConnection.dbClient.Model(&createdRequest).Association("Photos").Clear()
+	testConnection.dbClient.Delete(createdRequest.Photos)
+	testConnection.dbClient.Unscoped().Delete(&createdRequest)
+	testConnection.dbClient.Delete(&createdTask)
+	testConnection.dbClient.Unscoped().Delete(&createdProperty)
+}
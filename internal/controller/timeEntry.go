@@ -0,0 +1,442 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type TimeEntryController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Rollup(w http.ResponseWriter, r *http.Request)
+	// Timers for the current user
+	FindRunningTimer(w http.ResponseWriter, r *http.Request)
+	StartTimer(w http.ResponseWriter, r *http.Request)
+	StopTimer(w http.ResponseWriter, r *http.Request)
+}
+
+type timeEntryController struct {
+	service service.TimeEntryService
+}
+
+func NewTimeEntryController(service service.TimeEntryService) TimeEntryController {
+	return &timeEntryController{service}
+}
+
+// API/TIME-ENTRIES
+// Find a list of time entries
+// @Summary      Find a list of time entries
+// @Description  Accepts limit, offset, order, task and user params and returns list of time entries
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        task   path      int  false  "task id"
+// @Param        user   path      int  false  "user id"
+// @Success      200 {object} []db.TimeEntry
+// @Failure      400 {string} string "Can't find time entries"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /time-entries [get]
+// @Security BearerToken
+func (c timeEntryController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	taskParam := r.URL.Query().Get("task")
+	userParam := r.URL.Query().Get("user")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	taskId, _ := strconv.Atoi(taskParam)
+	userId, _ := strconv.Atoi(userParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all time entries using query params
+	foundEntries, err := c.service.FindAll(limit, offset, orderBy, taskId, userId)
+	if err != nil {
+		http.Error(w, "Can't find time entries", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundEntries)
+	if err != nil {
+		http.Error(w, "Can't find time entries", http.StatusBadRequest)
+		fmt.Println("error writing time entries to response: ", err)
+		return
+	}
+}
+
+// Find a created time entry
+// @Summary      Find time entry
+// @Description  Find a time entry by ID
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Time Entry ID"
+// @Success      200 {object} db.TimeEntry
+// @Failure      400 {string} string "Can't find time entry with ID: {id}"
+// @Router       /time-entries/{id} [get]
+// @Security BearerToken
+func (c timeEntryController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundEntry, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find time entry with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundEntry)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find time entry with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new time entry
+// @Summary      Create a time entry
+// @Description  Records time spent on a task by the current user. Requires an end time or duration
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        entry body models.RecvTimeEntry true "New Time Entry Json"
+// @Success      201 {object} db.TimeEntry
+// @Failure      400 {string} string "Time entry creation failed."
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /time-entries [post]
+// @Security BearerToken
+func (c timeEntryController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var recvEntry models.RecvTimeEntry
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&recvEntry)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&recvEntry)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Convert DTO to service required input model
+	var entry = models.CreateTimeEntry{
+		User:            db.User{ID: uint(userID)},
+		Task:            recvEntry.Task,
+		StartedAt:       recvEntry.StartedAt,
+		EndedAt:         recvEntry.EndedAt,
+		DurationMinutes: recvEntry.DurationMinutes,
+		Billable:        recvEntry.Billable,
+		Note:            recvEntry.Note,
+	}
+
+	// Create time entry in db
+	createdEntry, createErr := c.service.Create(&entry)
+	if createErr != nil {
+		http.Error(w, "Time entry creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created entry to output
+	err = helpers.WriteAsJSON(w, createdEntry)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a time entry (using URL parameter id)
+// @Summary      Update time entry
+// @Description  Updates an existing time entry. Duration is recalculated when times change. Times of running timers can't be changed
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        entry body models.UpdateTimeEntry true "Update Time Entry Json"
+// @Param        id   path      int  true  "Time Entry ID"
+// @Success      200 {object} db.TimeEntry
+// @Failure      400 {string} string "Failed time entry update"
+// @Failure      409 {string} string "Times can't be changed while the timer is running"
+// @Router       /time-entries/{id} [put]
+// @Security BearerToken
+func (c timeEntryController) Update(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var entry models.UpdateTimeEntry
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&entry)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Update time entry
+	updatedEntry, updateErr := c.service.Update(idParameter, &entry)
+	if updateErr != nil {
+		// If entry is of a running timer
+		if errors.Is(updateErr, service.ErrTimerNotStopped) {
+			http.Error(w, updateErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed time entry update: %s", updateErr), http.StatusBadRequest)
+		return
+	}
+
+	// Write time entry to output
+	err = helpers.WriteAsJSON(w, updatedEntry)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Delete time entry (using URL parameter id)
+// @Summary      Delete time entry
+// @Description  Deletes an existing time entry
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Time Entry ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed time entry deletion"
+// @Router       /time-entries/{id} [delete]
+// @Security BearerToken
+func (c timeEntryController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete time entry using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed time entry deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Total time recorded over a date range
+// @Summary      Time entry rollup
+// @Description  Totals completed time entries (all and billable minutes) started within a date range, grouped by task, property or user
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        group   path      string  true  "task, property or user"
+// @Param        from   path      string  true  "start date (YYYY-MM-DD)"
+// @Param        to   path      string  true  "end date inclusive (YYYY-MM-DD)"
+// @Success      200 {object} []models.TimeRollup
+// @Failure      400 {string} string "Must include from and to dates (YYYY-MM-DD)"
+// @Failure      400 {string} string "Can't total time entries"
+// @Router       /time-entries/rollup [get]
+// @Security BearerToken
+func (c timeEntryController) Rollup(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	groupBy := r.URL.Query().Get("group")
+	fromParam := r.URL.Query().Get("from")
+	toParam := r.URL.Query().Get("to")
+
+	// Convert to dates
+	from, fromErr := time.Parse("2006-01-02", fromParam)
+	to, toErr := time.Parse("2006-01-02", toParam)
+	if fromErr != nil || toErr != nil {
+		http.Error(w, "Must include from and to dates (YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+
+	// Total time entries (to date is inclusive)
+	rollups, err := c.service.Rollup(groupBy, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		http.Error(w, "Can't total time entries: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, rollups)
+	if err != nil {
+		http.Error(w, "Can't total time entries", http.StatusBadRequest)
+		fmt.Println("error writing time entry rollup to response: ", err)
+		return
+	}
+}
+
+// API/ME/TIMER
+// Find the current user's running timer
+// @Summary      Find running timer
+// @Description  Returns the current user's running timer
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} db.TimeEntry
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Failure      404 {string} string "No timer is running for this user"
+// @Router       /me/timer [get]
+// @Security BearerToken
+func (c timeEntryController) FindRunningTimer(w http.ResponseWriter, r *http.Request) {
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	runningEntry, err := c.service.FindRunningTimer(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	err = helpers.WriteAsJSON(w, runningEntry)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Start a timer on a task for the current user
+// @Summary      Start timer
+// @Description  Starts a timer on a task for the current user. Only one timer can run per user
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Param        timer body models.StartTimer true "Start Timer Json"
+// @Success      201 {object} db.TimeEntry
+// @Failure      400 {string} string "Failed to start timer"
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Failure      409 {string} string "A timer is already running for this user"
+// @Router       /me/timer/start [post]
+// @Security BearerToken
+func (c timeEntryController) StartTimer(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var timer models.StartTimer
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&timer)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&timer)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Start timer
+	startedEntry, startErr := c.service.StartTimer(userID, &timer)
+	if startErr != nil {
+		// If timer already running
+		if errors.Is(startErr, service.ErrTimerRunning) {
+			http.Error(w, startErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to start timer: "+startErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write started entry to output
+	err = helpers.WriteAsJSON(w, startedEntry)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Stop the current user's running timer
+// @Summary      Stop timer
+// @Description  Stops the current user's running timer and records its duration
+// @Tags         Time Entries
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} db.TimeEntry
+// @Failure      400 {string} string "No timer is running for this user"
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /me/timer/stop [post]
+// @Security BearerToken
+func (c timeEntryController) StopTimer(w http.ResponseWriter, r *http.Request) {
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Stop timer
+	stoppedEntry, stopErr := c.service.StopTimer(userID)
+	if stopErr != nil {
+		http.Error(w, stopErr.Error(), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, stoppedEntry)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
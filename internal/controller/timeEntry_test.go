@@ -0,0 +1,269 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestTimeEntryController_Create(t *testing.T) {
+	// Test setup
+	createdTasks := []db.Task{{TaskName: "Monthly management visit", Type: "Other"}}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task for time entry create test: ", createResult.Error)
+	}
+	startedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var createTests = []struct {
+		data                   models.RecvTimeEntry
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedMinutes        int
+		testName               string
+	}{
+		{models.RecvTimeEntry{Task: createdTasks[0], StartedAt: startedAt, DurationMinutes: 30}, testConnection.accounts.user.token, http.StatusForbidden, 0, "basic user create test"},
+		// Duration calculated from end time
+		{models.RecvTimeEntry{Task: createdTasks[0], StartedAt: startedAt, EndedAt: startedAt.Add(90 * time.Minute), Billable: true}, testConnection.accounts.admin.token, http.StatusCreated, 90, "admin end time test"},
+		// End time calculated from duration
+		{models.RecvTimeEntry{Task: createdTasks[0], StartedAt: startedAt, DurationMinutes: 45, Note: "Garden check"}, testConnection.accounts.admin.token, http.StatusCreated, 45, "admin duration test"},
+		{models.RecvTimeEntry{Task: createdTasks[0], StartedAt: startedAt}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin missing end and duration fail test"},
+		{models.RecvTimeEntry{Task: createdTasks[0], StartedAt: startedAt, EndedAt: startedAt.Add(-time.Hour)}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin end before start fail test"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with time entry creation in body
+		req, err := http.NewRequest("POST", "/api/time-entries", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Time entry create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+
+		// Check duration and end time if created
+		if v.expectedResponseStatus == http.StatusCreated {
+			var body db.TimeEntry
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if body.DurationMinutes != v.expectedMinutes {
+				t.Errorf("Time entry create test (%v): expected %d minutes, got %d", v.testName, v.expectedMinutes, body.DurationMinutes)
+			}
+			if body.EndedAt == nil || !body.EndedAt.Equal(startedAt.Add(time.Duration(v.expectedMinutes)*time.Minute)) {
+				t.Errorf("Time entry create test (%v): unexpected end time %v", v.testName, body.EndedAt)
+			}
+			if body.UserID != testConnection.accounts.admin.details.ID {
+				t.Errorf("Time entry create test (%v): expected entry for admin, got user %d", v.testName, body.UserID)
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id = ?", createdTasks[0].ID).Delete(&db.TimeEntry{})
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTimeEntryController_Timer(t *testing.T) {
+	// Test setup
+	createdTasks := []db.Task{{TaskName: "Tenant handover", Type: "Other"}}
+	createResult := testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create task for timer test: ", createResult.Error)
+	}
+
+	var timerTests = []struct {
+		method                 string
+		request                string
+		data                   interface{}
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		// Basic users run their own timers
+		{"POST", "/api/me/timer/start", models.StartTimer{Task: createdTasks[0]}, testConnection.accounts.user.token, http.StatusCreated, "basic user start test"},
+		{"GET", "/api/me/timer", nil, testConnection.accounts.user.token, http.StatusOK, "basic user running timer test"},
+		{"POST", "/api/me/timer/stop", nil, testConnection.accounts.user.token, http.StatusOK, "basic user stop test"},
+		{"GET", "/api/me/timer", nil, testConnection.accounts.admin.token, http.StatusNotFound, "no running timer test"},
+		{"POST", "/api/me/timer/start", models.StartTimer{Task: createdTasks[0], Billable: true}, testConnection.accounts.admin.token, http.StatusCreated, "admin start test"},
+		{"GET", "/api/me/timer", nil, testConnection.accounts.admin.token, http.StatusOK, "running timer test"},
+		// Only one running timer per user
+		{"POST", "/api/me/timer/start", models.StartTimer{Task: createdTasks[0]}, testConnection.accounts.admin.token, http.StatusConflict, "admin second start fail test"},
+		{"POST", "/api/me/timer/stop", nil, testConnection.accounts.admin.token, http.StatusOK, "admin stop test"},
+		{"POST", "/api/me/timer/stop", nil, testConnection.accounts.admin.token, http.StatusBadRequest, "admin stop without timer fail test"},
+	}
+
+	for _, v := range timerTests {
+		req, err := http.NewRequest(v.method, v.request, buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Timer test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Check stopped timers were recorded
+	var entries []db.TimeEntry
+	testConnection.dbClient.Where("task_id = ?", createdTasks[0].ID).Order("id").Find(&entries)
+	if len(entries) != 2 || entries[0].UserID != testConnection.accounts.user.details.ID || entries[0].EndedAt == nil || entries[1].EndedAt == nil || !entries[1].Billable {
+		t.Errorf("Timer test: expected a stopped entry for each user with the admin's billable, got %v", entries)
+	}
+
+	// Only the note and billing of a running timer can be changed
+	rr := serveAsAdmin(t, "POST", "/api/me/timer/start", models.StartTimer{Task: createdTasks[0]})
+	var running db.TimeEntry
+	json.Unmarshal(rr.Body.Bytes(), &running)
+	billable := true
+	var runningTests = []struct {
+		data                   models.UpdateTimeEntry
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.UpdateTimeEntry{DurationMinutes: 30}, http.StatusConflict, "running duration fail test"},
+		{models.UpdateTimeEntry{StartedAt: running.StartedAt.Add(-time.Hour)}, http.StatusConflict, "running start fail test"},
+		{models.UpdateTimeEntry{EndedAt: running.StartedAt.Add(time.Hour)}, http.StatusConflict, "running end fail test"},
+		{models.UpdateTimeEntry{Note: "Handover keys", Billable: &billable}, http.StatusOK, "running note test"},
+	}
+	for _, v := range runningTests {
+		rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/time-entries/%v", running.ID), v.data)
+		if rr.Code != v.expectedResponseStatus {
+			t.Errorf("Timer update test (%v): got %v want %v. %v", v.testName, rr.Code, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+	rr = serveAsAdmin(t, "POST", "/api/me/timer/stop", nil)
+	json.Unmarshal(rr.Body.Bytes(), &running)
+	if rr.Code != http.StatusOK || running.Note != "Handover keys" || !running.Billable || running.EndedAt == nil {
+		t.Errorf("Timer update test: expected the stopped timer to keep its note, got %v %v", rr.Code, rr.Body.String())
+	}
+	testConnection.dbClient.Where("task_id = ?", createdTasks[0].ID).Find(&entries)
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(entries)
+	testConnection.dbClient.Delete(createdTasks)
+}
+
+func TestTimeEntryController_Rollup(t *testing.T) {
+	// Test setup
+	// Create property with maintenance task
+	createdProperties := []db.Property{{
+		Property_Name:    "timeEntryProperty1",
+		Postcode:         80361,
+		Suburb:           "Test Suburb",
+		City:             "Test City",
+		Street_Address_1: "Test Street Address 1",
+		Bedrooms:         3,
+		Bathrooms:        2,
+		Description:      "Test Description",
+		Managed:          true,
+	}}
+	createResult := testConnection.dbClient.Create(createdProperties)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create property for time entry rollup test: ", createResult.Error)
+	}
+	createdTasks := []db.Task{{TaskName: "Fix gate", Type: "Maintenance"}, {TaskName: "Fix roof", Type: "Maintenance"}}
+	createResult = testConnection.dbClient.Create(createdTasks)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create tasks for time entry rollup test: ", createResult.Error)
+	}
+	createdRequests := []db.MaintenanceRequest{
+		{Scale: "Low", WorkDefinition: "Repair", Type: "Civil", PropertyID: createdProperties[0].ID, TaskID: createdTasks[0].ID},
+		{Scale: "Low", WorkDefinition: "Repair", Type: "Civil", PropertyID: createdProperties[0].ID, TaskID: createdTasks[1].ID},
+	}
+	createResult = testConnection.dbClient.Create(createdRequests)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create maintenance requests for time entry rollup test: ", createResult.Error)
+	}
+	// Two entries in March (one billable) and one in April
+	march := time.Date(2023, 3, 10, 9, 0, 0, 0, time.UTC)
+	april := time.Date(2023, 4, 10, 9, 0, 0, 0, time.UTC)
+	end := func(start time.Time, minutes int) *time.Time {
+		ended := start.Add(time.Duration(minutes) * time.Minute)
+		return &ended
+	}
+	createdEntries := []db.TimeEntry{
+		{UserID: testConnection.accounts.admin.details.ID, TaskID: createdTasks[0].ID, StartedAt: march, EndedAt: end(march, 60), DurationMinutes: 60, Billable: true},
+		{UserID: testConnection.accounts.admin.details.ID, TaskID: createdTasks[1].ID, StartedAt: march, EndedAt: end(march, 30), DurationMinutes: 30},
+		{UserID: testConnection.accounts.admin.details.ID, TaskID: createdTasks[0].ID, StartedAt: april, EndedAt: end(april, 15), DurationMinutes: 15, Billable: true},
+	}
+	createResult = testConnection.dbClient.Create(createdEntries)
+	if createResult.Error != nil {
+		t.Fatal("Failed to create time entries for rollup test: ", createResult.Error)
+	}
+
+	var rollupTests = []struct {
+		request                string
+		expectedResponseStatus int
+		expectedID             uint
+		expected               models.TimeRollup
+	}{
+		{"/api/time-entries/rollup?group=task&from=2023-03-01&to=2023-04-30", http.StatusOK, createdTasks[0].ID, models.TimeRollup{Entries: 2, Minutes: 75, BillableMinutes: 75}},
+		{"/api/time-entries/rollup?group=property&from=2023-03-01&to=2023-03-31", http.StatusOK, createdProperties[0].ID, models.TimeRollup{Entries: 2, Minutes: 90, BillableMinutes: 60}},
+		{"/api/time-entries/rollup?group=user&from=2023-03-01&to=2023-04-30", http.StatusOK, testConnection.accounts.admin.details.ID, models.TimeRollup{Entries: 3, Minutes: 105, BillableMinutes: 75}},
+		{"/api/time-entries/rollup?group=vendor&from=2023-03-01&to=2023-04-30", http.StatusBadRequest, 0, models.TimeRollup{}},
+		{"/api/time-entries/rollup?group=task", http.StatusBadRequest, 0, models.TimeRollup{}},
+	}
+
+	for _, v := range rollupTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", v.request, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Time entry rollup (%v): got %v want %v. %v", v.request, status, v.expectedResponseStatus, rr.Body.String())
+		}
+
+		// Check totals of expected group if successful
+		if v.expectedResponseStatus == http.StatusOK {
+			var body []models.TimeRollup
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			found := false
+			for _, rollup := range body {
+				if rollup.ID == v.expectedID {
+					found = true
+					v.expected.ID = v.expectedID
+					if rollup != v.expected {
+						t.Errorf("Time entry rollup (%v): expected %v, got %v", v.request, v.expected, rollup)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("Time entry rollup (%v): expected rollup for %d in %v", v.request, v.expectedID, body)
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(createdEntries)
+	testConnection.dbClient.Delete(createdRequests)
+	testConnection.dbClient.Delete(createdTasks)
+	testConnection.dbClient.Delete(createdProperties)
+}
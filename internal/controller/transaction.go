@@ -18,6 +18,7 @@ type TransactionController interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	CreateWithTask(w http.ResponseWriter, r *http.Request)
 }
 
 type transactionController struct {
@@ -154,6 +155,55 @@ func (c transactionController) Create(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Transaction creation successful!"))
 }
 
+// API/TASKS/WITH-TRANSACTION
+// Create a new task together with its transaction
+// @Summary      Create task with transaction
+// @Description  Creates a task and its transaction in one database transaction, so neither is created without the other. The transaction's task is ignored
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        task body models.CreateTaskWithTransaction true "New Task With Transaction Json"
+// @Success      201 {object} db.Task
+// @Failure      400 {string} string "Task and transaction creation failed."
+// @Router       /tasks/with-transaction [post]
+// @Security BearerToken
+func (c transactionController) CreateWithTask(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var create models.CreateTaskWithTransaction
+	// Decode request body from JSON and store
+	err := json.NewDecoder(r.Body).Decode(&create)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&create)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create task and transaction in db
+	createdTask, createErr := c.service.CreateWithTask(&create)
+	if createErr != nil {
+		http.Error(w, "Task and transaction creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created task to output
+	err = helpers.WriteAsJSON(w, createdTask)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
 // Update a transaction (using URL parameter id)
 // @Summary      Update transaction
 // @Description  Updates an existing transaction
@@ -213,7 +263,7 @@ func (c transactionController) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete transaction (using URL parameter id)
 // @Summary      Delete transaction
-// @Description  Deletes an existing transaction
+// @Description  Deletes an existing transaction and archives its task
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
@@ -53,7 +53,7 @@ func TestTransactionController_FindAll(t *testing.T) {
 		Agency:           "Own",
 		AgencyName:       "Test Agency Name",
 		IsLease:          true,
-		Fee:              3.5,
+		Fee:              db.NewMoney(3.5, "IDR"),
 		TransactionNotes: "This is a note",
 		TenancyType:      "Monthly",
 		Property:         db.Property{ID: createdProperties[0].ID},
@@ -64,7 +64,7 @@ func TestTransactionController_FindAll(t *testing.T) {
 		Agency:           "Own",
 		AgencyName:       "Test Agency Name",
 		IsLease:          true,
-		Fee:              3.5,
+		Fee:              db.NewMoney(3.5, "IDR"),
 		TransactionNotes: "This is a note",
 		TenancyType:      "Monthly",
 		Property:         *propertyToCreate,
@@ -206,7 +206,7 @@ func TestTransactionController_Find(t *testing.T) {
 		Agency:           "Own",
 		AgencyName:       "Test Agency Name",
 		IsLease:          true,
-		Fee:              3.5,
+		Fee:              db.NewMoney(3.5, "IDR"),
 		TransactionNotes: "This is a note",
 		TenancyType:      "Monthly",
 		Property:         db.Property{ID: createdProperties[0].ID},
@@ -297,7 +297,7 @@ func TestTransactionController_Delete(t *testing.T) {
 		Agency:           "Own",
 		AgencyName:       "Test Agency Name",
 		IsLease:          true,
-		Fee:              3.5,
+		Fee:              db.NewMoney(3.5, "IDR"),
 		TransactionNotes: "This is a note",
 		TenancyType:      "Monthly",
 		Property:         db.Property{ID: createdProperties[0].ID},
@@ -396,7 +396,7 @@ func TestTransactionController_Update(t *testing.T) {
 		Agency:           "Own",
 		AgencyName:       "Test Agency Name",
 		IsLease:          true,
-		Fee:              3.5,
+		Fee:              db.NewMoney(3.5, "IDR"),
 		TransactionNotes: "This is a note",
 		TenancyType:      "Monthly",
 		Property:         db.Property{ID: createdProperties[0].ID},
@@ -416,25 +416,25 @@ func TestTransactionController_Update(t *testing.T) {
 	}{
 		// Test of update failure: basic user
 		{models.UpdateTransaction{
-			Fee:              4.5,
-			TransactionValue: 35000000,
+			Fee:              db.NewMoney(4.5, "IDR"),
+			TransactionValue: db.NewMoney(35000000, "IDR"),
 		}, testConnection.accounts.user.token, http.StatusForbidden, false},
 		// Update should be allowed: admin
 		{models.UpdateTransaction{
-			Fee:              4.5,
-			TransactionValue: 35000000,
+			Fee:              db.NewMoney(4.5, "IDR"),
+			TransactionValue: db.NewMoney(35000000, "IDR"),
 		}, testConnection.accounts.admin.token, http.StatusOK, true},
 		// Update should be disallowed due to being invalid value for agency
 		{models.UpdateTransaction{
 			Agency:           "Insane",
-			Fee:              4.5,
-			TransactionValue: 35000000,
+			Fee:              db.NewMoney(4.5, "IDR"),
+			TransactionValue: db.NewMoney(35000000, "IDR"),
 		}, testConnection.accounts.admin.token, http.StatusBadRequest, false},
 		// Update should be disallowed due to being invalid value for type
 		{models.UpdateTransaction{
 			Type:             "Insane",
-			Fee:              4.5,
-			TransactionValue: 35000000,
+			Fee:              db.NewMoney(4.5, "IDR"),
+			TransactionValue: db.NewMoney(35000000, "IDR"),
 		}, testConnection.accounts.admin.token, http.StatusBadRequest, false},
 		// User should be forbidden before validating rather than Bad Request
 		{models.UpdateTransaction{
@@ -563,7 +563,7 @@ func TestTransactionController_Create(t *testing.T) {
 			Agency:           "Own",
 			AgencyName:       "Test Agency Name",
 			IsLease:          false,
-			Fee:              3.5,
+			Fee:              db.NewMoney(3.5, "IDR"),
 			TransactionNotes: "This is a note",
 			TenancyType:      "Monthly",
 			Property:         db.Property{ID: createdProperties[0].ID},
@@ -574,7 +574,7 @@ func TestTransactionController_Create(t *testing.T) {
 			Agency:           "Own",
 			AgencyName:       "Test Agency Name",
 			IsLease:          false,
-			Fee:              3.5,
+			Fee:              db.NewMoney(3.5, "IDR"),
 			TransactionNotes: "This is a note",
 			TenancyType:      "Monthly",
 			Property:         db.Property{ID: createdProperties[0].ID},
@@ -585,7 +585,7 @@ func TestTransactionController_Create(t *testing.T) {
 			Agency:           "Sakra",
 			AgencyName:       "Test Agency Name",
 			IsLease:          false,
-			Fee:              3.5,
+			Fee:              db.NewMoney(3.5, "IDR"),
 			TransactionNotes: "This is a note",
 			TenancyType:      "Monthly",
 			Property:         db.Property{ID: createdProperties[0].ID},
@@ -596,7 +596,7 @@ func TestTransactionController_Create(t *testing.T) {
 			Agency:           "Own",
 			AgencyName:       "Test Agency Name",
 			IsLease:          false,
-			Fee:              3.5,
+			Fee:              db.NewMoney(3.5, "IDR"),
 			TransactionNotes: "This is a note",
 			TenancyType:      "Monthly",
 			Property:         db.Property{ID: createdProperties[0].ID},
@@ -607,7 +607,7 @@ func TestTransactionController_Create(t *testing.T) {
 			Agency:           "Own",
 			AgencyName:       "Test Agency Name",
 			IsLease:          false,
-			Fee:              3.5,
+			Fee:              db.NewMoney(3.5, "IDR"),
 			TransactionNotes: "This is a note",
 			TenancyType:      "Iglesias",
 			Property:         db.Property{ID: createdProperties[0].ID},
@@ -618,7 +618,7 @@ func TestTransactionController_Create(t *testing.T) {
 			Agency:           "Own",
 			AgencyName:       "Test Agency Name",
 			IsLease:          false,
-			Fee:              3.5,
+			Fee:              db.NewMoney(3.5, "IDR"),
 			TransactionNotes: "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Suspendisse vulputate, nunc sit amet efficitur bibendum, sapien odio auctor nisi, a interdum magna nisl ac purus. Fusce condimentum malesuada mi at eleifend. Sed laoreet varius risus, id mattis libero tristique nec. Sed eget malesuada magna. Morbi feugiat sapien euismod neque commodo suscipit. Vivamus vehicula euismod dui, id imperdiet elit lacinia non. Integer hendrerit, enim ac gravida malesuada, dolor leo dictum purus, nec bibendum velit est vel nulla. Nulla sagittis nulla non elit imperdiet convallis. Sed bibendum sollicitudin nunc, vel facilisis nulla convallis a. Nunc id ex feugiat, finibus magna sit amet, ultricies lacus.",
 			TenancyType:      "Iglesias",
 			Property:         db.Property{ID: createdProperties[0].ID},
@@ -720,8 +720,8 @@ func checkTransactionDetails(actual *db.Transaction, expected *db.Transaction, t
 	if actual.TenancyType != expected.TenancyType {
 		t.Errorf("found transaction has incorrect tenancy type: expected %s, got %s", expected.TenancyType, actual.TenancyType)
 	}
-	if actual.Fee != expected.Fee {
-		t.Errorf("found transaction has incorrect fee: expected %f, got %f", expected.Fee, actual.Fee)
+	if !actual.Fee.Equal(expected.Fee) {
+		t.Errorf("found transaction has incorrect fee: expected %s, got %s", expected.Fee, actual.Fee)
 	}
 	if actual.TransactionNotes != expected.TransactionNotes {
 		t.Errorf("found transaction has incorrect transaction notes: expected %s, got %s", expected.TransactionNotes, actual.TransactionNotes)
@@ -737,8 +737,8 @@ func checkTransactionDetails(actual *db.Transaction, expected *db.Transaction, t
 	if actual.TransactionCompletion != expected.TransactionCompletion {
 		t.Errorf("found transaction has incorrect transaction completion: expected %s, got %s", expected.TransactionCompletion, actual.TransactionCompletion)
 	}
-	if actual.TransactionValue != expected.TransactionValue {
-		t.Errorf("found transaction has incorrect transaction value: expected %f, got %f", expected.TransactionValue, actual.TransactionValue)
+	if !actual.TransactionValue.Equal(expected.TransactionValue) {
+		t.Errorf("found transaction has incorrect transaction value: expected %s, got %s", expected.TransactionValue, actual.TransactionValue)
 	}
 }
 
@@ -0,0 +1,328 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type UnitController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Occupancy(w http.ResponseWriter, r *http.Request)
+}
+
+type unitController struct {
+	service service.UnitService
+	rates   service.ExchangeRateService
+}
+
+func NewUnitController(service service.UnitService, rates service.ExchangeRateService) UnitController {
+	return &unitController{service, rates}
+}
+
+// API/UNITS
+// Find a list of units
+// @Summary      Find a list of units
+// @Description  Accepts limit, offset, order and property params and returns list of units of multi-unit properties
+// @Tags         Unit
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        property   path      int  false  "property id"
+// @Success      200 {object} []db.Unit
+// @Failure      400 {string} string "Can't find units"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /units [get]
+// @Security BearerToken
+func (c unitController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	propertyParam := r.URL.Query().Get("property")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	propertyId, _ := strconv.Atoi(propertyParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all units using query params
+	foundUnits, err := c.service.FindAll(limit, offset, orderBy, propertyId)
+	if err != nil {
+		http.Error(w, "Can't find units", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundUnits)
+	if err != nil {
+		http.Error(w, "Can't find units", http.StatusBadRequest)
+		fmt.Println("error writing units to response: ", err)
+		return
+	}
+}
+
+// Find a created unit
+// @Summary      Find unit
+// @Description  Find a unit by ID
+// @Tags         Unit
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Unit ID"
+// @Success      200 {object} db.Unit
+// @Failure      400 {string} string "Can't find unit with ID: {id}"
+// @Router       /units/{id} [get]
+// @Security BearerToken
+func (c unitController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundUnit, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find unit with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundUnit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find unit with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new unit
+// @Summary      Create unit
+// @Description  Creates a rentable unit of a property (eg. a villa in a complex or a shophouse floor) that leases, maintenance requests and transactions can target
+// @Tags         Unit
+// @Accept       json
+// @Produce      json
+// @Param        unit body models.CreateUnit true "New Unit Json"
+// @Success      201 {object} db.Unit
+// @Failure      400 {string} string "Unit creation failed."
+// @Failure      409 {string} string "Property already has a unit with this name"
+// @Router       /units [post]
+// @Security BearerToken
+func (c unitController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var unit models.CreateUnit
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&unit)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&unit)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create unit in db
+	createdUnit, createErr := c.service.Create(&unit)
+	if createErr != nil {
+		if errors.Is(createErr, service.ErrUnitExists) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Unit creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created unit to output
+	err = helpers.WriteAsJSON(w, createdUnit)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a unit (using URL parameter id)
+// @Summary      Update unit
+// @Description  Updates the details of a unit. Features are replaced if provided
+// @Tags         Unit
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Unit ID"
+// @Param        unit body models.UpdateUnit true "Update Unit Json"
+// @Success      200 {object} db.Unit
+// @Failure      400 {string} string "Failed unit update"
+// @Failure      409 {string} string "Property already has a unit with this name"
+// @Router       /units/{id} [put]
+// @Security BearerToken
+func (c unitController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var unit models.UpdateUnit
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&unit)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&unit)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update unit
+	updatedUnit, err := c.service.Update(idParameter, &unit)
+	if err != nil {
+		if errors.Is(err, service.ErrUnitExists) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed unit update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated unit to output
+	err = helpers.WriteAsJSON(w, updatedUnit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed unit update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete unit (using URL parameter id)
+// @Summary      Delete unit
+// @Description  Deletes a unit. Units targeted by leases, maintenance requests or transactions can't be deleted
+// @Tags         Unit
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Unit ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed unit deletion"
+// @Failure      409 {string} string "Unit has leases, maintenance requests or transactions"
+// @Router       /units/{id} [delete]
+// @Security BearerToken
+func (c unitController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete unit using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		if errors.Is(err, service.ErrUnitInUse) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed unit deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/PROPERTIES/OCCUPANCY
+// Occupancy and rent received of a property's units
+// @Summary      Property occupancy
+// @Description  Returns the occupancy and rent received of each unit of a property between from and to (YYYY-MM-DD, both inclusive), rolled up to the property. A lease of the whole property occupies all of its units. Defaults to the start of the month until today
+// @Tags         Unit
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Property ID"
+// @Param        from   path      string  false  "first day"
+// @Param        to   path      string  false  "last day"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.PropertyOccupancy
+// @Failure      400 {string} string "Can't find property occupancy"
+// @Router       /properties/occupancy/{id} [get]
+// @Security BearerToken
+func (c unitController) Occupancy(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Occupancy period
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		from, err = time.ParseInLocation("2006-01-02", fromParam, time.Local)
+		if err != nil {
+			http.Error(w, "From must be a date formatted YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		to, err = time.ParseInLocation("2006-01-02", toParam, time.Local)
+		if err != nil {
+			http.Error(w, "To must be a date formatted YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	// Include the last day
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		http.Error(w, "To must not be before from", http.StatusBadRequest)
+		return
+	}
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	occupancy, err := c.service.Occupancy(idParameter, from, to, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find property occupancy: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, occupancy)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find property occupancy: %v", err), http.StatusBadRequest)
+		return
+	}
+}
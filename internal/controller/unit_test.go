@@ -0,0 +1,177 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestUnitController_UnitLeasesAndOccupancy(t *testing.T) {
+	// Test setup
+	f := createLeaseFixtures(t)
+	feature := db.Feature{Feature_Name: "Plunge pool"}
+	testConnection.dbClient.Create(&feature)
+	otherProperty := db.Property{Property_Name: "unitProperty2", Postcode: 80361, Suburb: "Seminyak", City: "Badung", Street_Address_1: "Jl. Kayu Aya", Description: "Shophouse"}
+	testConnection.dbClient.Create(&otherProperty)
+	otherUnit := db.Unit{Name: "Ground Floor", PropertyID: otherProperty.ID}
+	testConnection.dbClient.Create(&otherUnit)
+
+	var createTests = []struct {
+		data                   models.CreateUnit
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateUnit{Name: "Villa 1", Bedrooms: 2, Bathrooms: 2, Property: f.property}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateUnit{Bedrooms: 2, Bathrooms: 2, Property: f.property}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin missing name fail test"},
+		{models.CreateUnit{Name: "Villa 1", Bedrooms: 2, Bathrooms: 2, Area: 150, Area_Metric: "m2", Property: f.property, Features: []db.Feature{{ID: feature.ID}}}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+		{models.CreateUnit{Name: "Villa 2", Bedrooms: 1, Bathrooms: 1, Area: 90, Area_Metric: "m2", Property: f.property}, testConnection.accounts.admin.token, http.StatusCreated, "admin second unit create test"},
+		{models.CreateUnit{Name: "Villa 1", Bedrooms: 3, Bathrooms: 3, Property: f.property}, testConnection.accounts.admin.token, http.StatusConflict, "admin duplicate name fail test"},
+	}
+
+	units := []db.Unit{}
+	for _, v := range createTests {
+		// Make new request with unit creation in body
+		req, err := http.NewRequest("POST", "/api/units", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Unit create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			var created db.Unit
+			json.Unmarshal(rr.Body.Bytes(), &created)
+			units = append(units, created)
+		}
+	}
+	if len(units) != 2 || len(units[0].Features) != 1 {
+		t.Fatalf("Unit create: expected two units with the first having a feature, got %+v", units)
+	}
+
+	// Units of a property are leased separately, while a lease of the whole property overlaps them all
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+	var leaseTests = []struct {
+		data                   models.CreateLease
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateLease{StartDate: start, EndDate: start.AddDate(1, 0, 0), RentAmount: db.NewMoney(10000000, "IDR"), Frequency: "Monthly", Property: f.property, Unit: units[0], Tenants: f.tenants[:1]}, http.StatusCreated, "unit lease test"},
+		{models.CreateLease{StartDate: start.AddDate(0, 2, 15), EndDate: start.AddDate(1, 2, 15), RentAmount: db.NewMoney(8000000, "IDR"), Frequency: "Monthly", Property: f.property, Unit: units[1], Tenants: f.tenants[1:]}, http.StatusCreated, "other unit overlapping lease test"},
+		{models.CreateLease{StartDate: start.AddDate(0, 6, 0), EndDate: start.AddDate(1, 6, 0), RentAmount: db.NewMoney(10000000, "IDR"), Frequency: "Monthly", Property: f.property, Unit: units[0], Tenants: f.tenants[1:]}, http.StatusConflict, "same unit overlapping lease fail test"},
+		{models.CreateLease{StartDate: start.AddDate(0, 6, 0), EndDate: start.AddDate(1, 6, 0), RentAmount: db.NewMoney(20000000, "IDR"), Frequency: "Monthly", Property: f.property, Tenants: f.tenants[1:]}, http.StatusConflict, "whole property overlapping lease fail test"},
+		{models.CreateLease{StartDate: start, EndDate: start.AddDate(1, 0, 0), RentAmount: db.NewMoney(10000000, "IDR"), Frequency: "Monthly", Property: f.property, Unit: otherUnit, Tenants: f.tenants[1:]}, http.StatusBadRequest, "unit of another property fail test"},
+	}
+	leases := []db.Lease{}
+	for _, v := range leaseTests {
+		rr := serveAsAdmin(t, "POST", "/api/leases", v.data)
+		if rr.Code != v.expectedResponseStatus {
+			t.Errorf("Unit lease create test (%v): got %v want %v. %v", v.testName, rr.Code, v.expectedResponseStatus, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated {
+			var created db.Lease
+			json.Unmarshal(rr.Body.Bytes(), &created)
+			leases = append(leases, created)
+		}
+	}
+	if len(leases) != 2 || leases[0].UnitID == nil || *leases[0].UnitID != units[0].ID {
+		t.Fatalf("Unit lease create: expected the first lease to be of %v, got %+v", units[0].Name, leases)
+	}
+
+	// Rent received in March
+	receipts := []db.LedgerEntry{
+		{EntryDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local), Type: "Receipt", Account: "Rent", Credit: db.NewMoney(10000000, "IDR"), LeaseID: leases[0].ID},
+		{EntryDate: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.Local), Type: "Receipt", Account: "Rent", Credit: db.NewMoney(8000000, "IDR"), LeaseID: leases[1].ID},
+		{EntryDate: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.Local), Type: "Receipt", Account: "Rent", Credit: db.NewMoney(10000000, "IDR"), LeaseID: leases[0].ID},
+	}
+	testConnection.dbClient.Create(&receipts)
+
+	// Villa 1 is leased for all 31 days of March and Villa 2 for the last 16
+	rr := serveAsAdmin(t, "GET", fmt.Sprintf("/api/properties/occupancy/%v?from=2024-03-01&to=2024-03-31&currency=IDR", f.property.ID), nil)
+	var occupancy models.PropertyOccupancy
+	json.Unmarshal(rr.Body.Bytes(), &occupancy)
+	if rr.Code != http.StatusOK || occupancy.Units != 2 || occupancy.OccupiedUnits != 2 || occupancy.OccupancyRate != 75.8 || occupancy.Income.Float() != 18000000 {
+		t.Errorf("Property occupancy: expected 2 occupied units, 75.8%% occupancy and 18000000 received, got %v %v", rr.Code, rr.Body.String())
+	} else if occupancy.UnitOccupancy[1].LeasedDays != 16 || occupancy.UnitOccupancy[1].Income.Float() != 8000000 {
+		t.Errorf("Property occupancy: expected %v leased for 16 days with 8000000 received, got %+v", units[1].Name, occupancy.UnitOccupancy[1])
+	}
+	// Before Villa 2 is leased
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/properties/occupancy/%v?from=2024-02-01&to=2024-02-29&currency=IDR", f.property.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &occupancy)
+	if rr.Code != http.StatusOK || occupancy.OccupiedUnits != 1 || occupancy.VacantUnits != 1 || occupancy.OccupancyRate != 50 {
+		t.Errorf("Property occupancy: expected 1 vacant unit and 50%% occupancy in February, got %v %v", rr.Code, rr.Body.String())
+	}
+
+	// Units with leases can't be deleted
+	rr = serveAsAdmin(t, "DELETE", fmt.Sprintf("/api/units/%v", units[0].ID), nil)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Unit delete with leases: got %v want %v", rr.Code, http.StatusConflict)
+	}
+
+	// A maintenance request keeps its unit only while the unit belongs to its property
+	pumpTask := db.Task{TaskName: "Fix the pool pump", Type: "Maintenance"}
+	testConnection.dbClient.Create(&pumpTask)
+	pumpRequest := db.MaintenanceRequest{Scale: "Low", WorkDefinition: "Repair", Type: "Plumbing", TotalCost: db.NewMoney(500000, "IDR"), Tax: db.NewMoney(55000, "IDR"), PropertyID: f.property.ID, UnitID: &units[0].ID, TaskID: pumpTask.ID}
+	testConnection.dbClient.Create(&pumpRequest)
+	totalCost, tax := db.NewMoney(500000, "IDR"), db.NewMoney(55000, "IDR")
+	var maintenanceTests = []struct {
+		data                   models.UpdateMaintenanceRequest
+		expectedResponseStatus int
+		expectedUnit           uint
+		testName               string
+	}{
+		{models.UpdateMaintenanceRequest{TotalCost: totalCost, Tax: tax, Property: otherProperty}, http.StatusBadRequest, units[0].ID, "move with unit of old property fail test"},
+		{models.UpdateMaintenanceRequest{TotalCost: totalCost, Tax: tax, Unit: otherUnit}, http.StatusBadRequest, units[0].ID, "unit of another property fail test"},
+		{models.UpdateMaintenanceRequest{TotalCost: totalCost, Tax: tax, Property: otherProperty, Unit: otherUnit}, http.StatusOK, otherUnit.ID, "move with unit of new property test"},
+	}
+	for _, v := range maintenanceTests {
+		rr := serveAsAdmin(t, "PUT", fmt.Sprintf("/api/maintenance/%v", pumpRequest.ID), v.data)
+		if rr.Code != v.expectedResponseStatus {
+			t.Errorf("Unit maintenance update test (%v): got %v want %v. %v", v.testName, rr.Code, v.expectedResponseStatus, rr.Body.String())
+		}
+		var found db.MaintenanceRequest
+		testConnection.dbClient.First(&found, pumpRequest.ID)
+		if found.UnitID == nil || *found.UnitID != v.expectedUnit {
+			t.Errorf("Unit maintenance update test (%v): expected unit %v, got %v", v.testName, v.expectedUnit, found.UnitID)
+		}
+	}
+
+	// Transactions can only target units of their property
+	sale := db.Transaction{Type: "Sale", Agency: "Own", PropertyID: f.property.ID}
+	testConnection.dbClient.Create(&sale)
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/%v", sale.ID), models.UpdateTransaction{Unit: otherUnit})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Unit transaction update with unit of another property: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/transactions/%v", sale.ID), models.UpdateTransaction{Unit: units[1]})
+	var updatedSale db.Transaction
+	json.Unmarshal(rr.Body.Bytes(), &updatedSale)
+	if rr.Code != http.StatusOK || updatedSale.UnitID == nil || *updatedSale.UnitID != units[1].ID {
+		t.Errorf("Unit transaction update: expected %v, got %v %v", units[1].Name, rr.Code, rr.Body.String())
+	}
+
+	// Cleanup
+	testConnection.dbClient.Unscoped().Delete(&sale)
+	testConnection.dbClient.Unscoped().Delete(&pumpRequest)
+	testConnection.dbClient.Unscoped().Delete(&pumpTask)
+	f.delete()
+	testConnection.dbClient.Exec("DELETE FROM unit_features WHERE feature_id = ?", feature.ID)
+	testConnection.dbClient.Unscoped().Where("property_id IN ?", []uint{f.property.ID, otherProperty.ID}).Delete(&db.Unit{})
+	testConnection.dbClient.Unscoped().Delete(&feature)
+	testConnection.dbClient.Unscoped().Delete(&otherProperty)
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -111,13 +112,14 @@ func (c vendorController) Find(w http.ResponseWriter, r *http.Request) {
 
 // Create a new vendor
 // @Summary      Create vendor
-// @Description  Creates a new vendor
+// @Description  Creates a new vendor. NPWP and NIB formatting is removed before saving
 // @Tags         Vendors
 // @Accept       json
 // @Produce      json
 // @Param        vendor body models.CreateVendor true "New Vendor Json"
 // @Success      201 {string} string "Vendor creation successful!"
 // @Failure      400 {string} string "Vendor creation failed."
+// @Failure      409 {string} string "A vendor with this NPWP already exists"
 // @Router       /vendors [post]
 func (c vendorController) Create(w http.ResponseWriter, r *http.Request) {
 	// Init
@@ -143,6 +145,10 @@ func (c vendorController) Create(w http.ResponseWriter, r *http.Request) {
 	// Create work type in db
 	_, createErr := c.service.Create(&vendor)
 	if createErr != nil {
+		if errors.Is(createErr, service.ErrDuplicateNPWP) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Vendor creation failed:."+createErr.Error(), http.StatusBadRequest)
 		return
 	}
@@ -163,6 +169,7 @@ func (c vendorController) Create(w http.ResponseWriter, r *http.Request) {
 // @Param        id   path      int  true  "Vendor ID"
 // @Success      200 {object} db.MaintenanceRequest
 // @Failure      400 {string} string "Failed vendor update"
+// @Failure      409 {string} string "A vendor with this NPWP already exists"
 // @Failure      400 {string} string "Invalid ID"
 // @Failure      403 {string} string "Authentication Token not detected"
 // @Router       /vendorss/{id} [put]
@@ -200,6 +207,10 @@ func (c vendorController) Update(w http.ResponseWriter, r *http.Request) {
 	// Update vendor in db
 	updatedVendor, createErr := c.service.Update(idParameter, &vendor)
 	if createErr != nil {
+		if errors.Is(createErr, service.ErrDuplicateNPWP) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Failed vendor update: %s", createErr), http.StatusBadRequest)
 		return
 	}
@@ -0,0 +1,375 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type VendorDocumentController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Upload(w http.ResponseWriter, r *http.Request)
+	Download(w http.ResponseWriter, r *http.Request)
+	Compliance(w http.ResponseWriter, r *http.Request)
+}
+
+type vendorDocumentController struct {
+	service service.VendorDocumentService
+	// Local storage service
+	ioService helpers.FileIO
+}
+
+func NewVendorDocumentController(service service.VendorDocumentService, ioServ helpers.FileIO) VendorDocumentController {
+	return &vendorDocumentController{service, ioServ}
+}
+
+// API/VENDOR-DOCUMENTS
+// Find a list of vendor documents
+// @Summary      Find a list of vendor documents
+// @Description  Accepts limit, offset, order, vendor and type params and returns list of vendor documents (soonest expiry first by default)
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        vendor   path      int  false  "vendor id"
+// @Param        type   path      string  false  "document type (Business Licence, Insurance, Tax Registration)"
+// @Success      200 {object} []db.VendorDocument
+// @Failure      400 {string} string "Can't find vendor documents"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /vendor-documents [get]
+// @Security BearerToken
+func (c vendorDocumentController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	vendorParam := r.URL.Query().Get("vendor")
+	documentType := r.URL.Query().Get("type")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	vendorId, _ := strconv.Atoi(vendorParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all vendor documents using query params
+	foundDocuments, err := c.service.FindAll(limit, offset, orderBy, vendorId, documentType)
+	if err != nil {
+		http.Error(w, "Can't find vendor documents", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundDocuments)
+	if err != nil {
+		http.Error(w, "Can't find vendor documents", http.StatusBadRequest)
+		fmt.Println("error writing vendor documents to response: ", err)
+		return
+	}
+}
+
+// Find a created vendor document
+// @Summary      Find vendor document
+// @Description  Find a vendor document by ID
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Document ID"
+// @Success      200 {object} db.VendorDocument
+// @Failure      400 {string} string "Can't find vendor document with ID: {id}"
+// @Router       /vendor-documents/{id} [get]
+// @Security BearerToken
+func (c vendorDocumentController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundDocument, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor document with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundDocument)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor document with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new vendor document
+// @Summary      Create vendor document
+// @Description  Records a vendor's business licence, insurance or tax registration. The file is uploaded separately
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        document body models.CreateVendorDocument true "New Vendor Document Json"
+// @Success      201 {object} db.VendorDocument
+// @Failure      400 {string} string "Vendor document creation failed."
+// @Router       /vendor-documents [post]
+// @Security BearerToken
+func (c vendorDocumentController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var document models.CreateVendorDocument
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&document)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&document)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create vendor document in db
+	createdDocument, createErr := c.service.Create(&document)
+	if createErr != nil {
+		http.Error(w, "Vendor document creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created document to output
+	err = helpers.WriteAsJSON(w, createdDocument)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a vendor document (using URL parameter id)
+// @Summary      Update vendor document
+// @Description  Updates a vendor document's number and dates. A new expiry date is flagged again before it expires
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        document body models.UpdateVendorDocument true "Update Vendor Document Json"
+// @Param        id   path      int  true  "Vendor Document ID"
+// @Success      200 {object} db.VendorDocument
+// @Failure      400 {string} string "Failed vendor document update"
+// @Router       /vendor-documents/{id} [put]
+// @Security BearerToken
+func (c vendorDocumentController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var document models.UpdateVendorDocument
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&document)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&document)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update vendor document
+	updatedDocument, err := c.service.Update(idParameter, &document)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor document update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated document to output
+	err = helpers.WriteAsJSON(w, updatedDocument)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor document update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete vendor document (using URL parameter id)
+// @Summary      Delete vendor document
+// @Description  Deletes an existing vendor document
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Document ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed vendor document deletion"
+// @Router       /vendor-documents/{id} [delete]
+// @Security BearerToken
+func (c vendorDocumentController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete vendor document using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed vendor document deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Upload a vendor document's file (using URL parameter id)
+// @Summary      Upload vendor document file
+// @Description  Accepts a file delivered by form-data with a key of "file" and stores it in object storage against the vendor document
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Document ID"
+// @Success      201 {object} db.VendorDocument
+// @Failure      400 {string} string "Vendor document upload failed"
+// @Router       /vendor-documents/file/{id} [post]
+// @Security BearerToken
+func (c vendorDocumentController) Upload(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Upload file and store against document
+	uploadedDocument, err := c.service.Upload(idParameter, r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Vendor document upload failed: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write document to output
+	err = helpers.WriteAsJSON(w, uploadedDocument)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Downloads a vendor document's file (using URL parameter id)
+// @Summary      Download vendor document file
+// @Description  Downloads the file uploaded for a vendor document
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Document ID"
+// @Success      200 {file} file "Vendor document file"
+// @Failure      400 {string} string "Can't find vendor document with ID: {id}"
+// @Failure      404 {string} string "Vendor document has no uploaded file"
+// @Router       /vendor-documents/file/{id} [get]
+// @Security BearerToken
+func (c vendorDocumentController) Download(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	// Download document file if found
+	downloadedFilePath, err := c.service.Download(idParameter)
+	if err != nil {
+		if errors.Is(err, service.ErrNoDocumentFile) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Can't find vendor document with ID: %v\n", idParameter), http.StatusBadRequest)
+		return
+	}
+	// read downloaded file
+	file, err := c.ioService.ReadFile(downloadedFilePath)
+	if err != nil {
+		http.Error(w, fmt.Sprint("Failed to download file: ", err), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+	// Copy the file contents to the response writer
+	_, err = c.ioService.Copy(w, file)
+	if err != nil {
+		http.Error(w, fmt.Sprint("Failed to copy download file: ", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Delete the file from the server
+	err = c.ioService.DeleteFile(downloadedFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Error deleting temporary file: %v\n", err)
+	}
+}
+
+// API/VENDORS/COMPLIANCE
+// Vendor compliance (using URL parameter id)
+// @Summary      Vendor compliance
+// @Description  Returns Compliant, Expiring Soon or Non-compliant with missing required documents and documents expiring within 30 days
+// @Tags         Vendor Documents
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor ID"
+// @Success      200 {object} models.VendorCompliance
+// @Failure      400 {string} string "Can't check compliance of vendor with ID: {id}"
+// @Router       /vendors/compliance/{id} [get]
+// @Security BearerToken
+func (c vendorDocumentController) Compliance(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	compliance, err := c.service.Compliance(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't check compliance of vendor with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, compliance)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't check compliance of vendor with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,225 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+// Sends a request as admin to the mock server
+func serveAsAdmin(t *testing.T, method string, url string, data interface{}) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, url, buildReqBody(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestVendorDocumentController_CreateAndCompliance(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	now := time.Now()
+
+	var createTests = []struct {
+		data                   models.CreateVendorDocument
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateVendorDocument{Type: "Business Licence", Number: "NIB-0001", IssueDate: now.AddDate(-1, 0, 0), Vendor: f.vendors[0]}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateVendorDocument{Type: "Permit", Number: "NIB-0001", IssueDate: now.AddDate(-1, 0, 0), Vendor: f.vendors[0]}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid type fail test"},
+		{models.CreateVendorDocument{Type: "Insurance", Number: "POL-1", IssueDate: now, ExpiryDate: now.AddDate(0, 0, -1), Vendor: f.vendors[0]}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin expiry before issue fail test"},
+		// Licence doesn't expire
+		{models.CreateVendorDocument{Type: "Business Licence", Number: "NIB-0001", IssueDate: now.AddDate(-1, 0, 0), Vendor: f.vendors[0]}, testConnection.accounts.admin.token, http.StatusCreated, "admin licence create test"},
+		// Insurance expires within the warning period
+		{models.CreateVendorDocument{Type: "Insurance", Number: "POL-1", IssueDate: now.AddDate(-1, 0, 0), ExpiryDate: now.AddDate(0, 0, 10), Vendor: f.vendors[0]}, testConnection.accounts.admin.token, http.StatusCreated, "admin insurance create test"},
+		// Tax registration has expired
+		{models.CreateVendorDocument{Type: "Tax Registration", Number: "SKT-1", IssueDate: now.AddDate(-2, 0, 0), ExpiryDate: now.AddDate(0, 0, -5), Vendor: f.vendors[0]}, testConnection.accounts.admin.token, http.StatusCreated, "admin expired tax registration create test"},
+	}
+
+	createdDocuments := []db.VendorDocument{}
+	for _, v := range createTests {
+		// Make new request with document creation in body
+		req, err := http.NewRequest("POST", "/api/vendor-documents", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor document create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			var created db.VendorDocument
+			json.Unmarshal(rr.Body.Bytes(), &created)
+			createdDocuments = append(createdDocuments, created)
+		}
+	}
+
+	// Checks the compliance status of the first vendor
+	checkCompliance := func(expectedStatus string, expectedMissing int, expectedExpiring int) {
+		rr := serveAsAdmin(t, "GET", fmt.Sprintf("/api/vendors/compliance/%v", f.vendors[0].ID), nil)
+		var compliance models.VendorCompliance
+		json.Unmarshal(rr.Body.Bytes(), &compliance)
+		if rr.Code != http.StatusOK || compliance.Status != expectedStatus || len(compliance.Missing) != expectedMissing || len(compliance.Expiring) != expectedExpiring {
+			t.Errorf("Vendor compliance: expected %v with %d missing and %d expiring, got %v %v", expectedStatus, expectedMissing, expectedExpiring, rr.Code, compliance)
+		}
+	}
+	checkCompliance("Non-compliant", 1, 1)
+
+	// A current tax registration leaves only the insurance expiring
+	rr := serveAsAdmin(t, "POST", "/api/vendor-documents", models.CreateVendorDocument{Type: "Tax Registration", Number: "SKT-2", IssueDate: now.AddDate(0, 0, -4), ExpiryDate: now.AddDate(2, 0, 0), Vendor: f.vendors[0]})
+	var renewedTax db.VendorDocument
+	json.Unmarshal(rr.Body.Bytes(), &renewedTax)
+	createdDocuments = append(createdDocuments, renewedTax)
+	checkCompliance("Expiring Soon", 0, 1)
+
+	// Renewed insurance
+	rr = serveAsAdmin(t, "PUT", fmt.Sprintf("/api/vendor-documents/%v", createdDocuments[1].ID), models.UpdateVendorDocument{ExpiryDate: now.AddDate(1, 0, 0)})
+	if rr.Code != http.StatusOK {
+		t.Errorf("Vendor document update: got %v want %v. %v", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	checkCompliance("Compliant", 0, 0)
+
+	// Work orders issued to vendors without documents carry warnings
+	var issuedOrder db.WorkOrder
+	rr = serveAsAdmin(t, "POST", "/api/work-orders", models.CreateWorkOrder{MaintenanceRequest: f.request, Vendor: f.vendors[1], Description: "Replace water heater"})
+	json.Unmarshal(rr.Body.Bytes(), &issuedOrder)
+	if rr.Code != http.StatusCreated || len(issuedOrder.ComplianceWarnings) != len(models.RequiredVendorDocumentTypes) {
+		t.Errorf("Work order for non-compliant vendor: expected %d warnings, got %v %v", len(models.RequiredVendorDocumentTypes), rr.Code, issuedOrder.ComplianceWarnings)
+	}
+	var compliantOrder db.WorkOrder
+	rr = serveAsAdmin(t, "POST", "/api/work-orders", models.CreateWorkOrder{MaintenanceRequest: f.request, Vendor: f.vendors[0], Description: "Replace water heater"})
+	json.Unmarshal(rr.Body.Bytes(), &compliantOrder)
+	if rr.Code != http.StatusCreated || len(compliantOrder.ComplianceWarnings) != 0 {
+		t.Errorf("Work order for compliant vendor: expected no warnings, got %v %v", rr.Code, compliantOrder.ComplianceWarnings)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id = ?", f.task.ID).Delete(&db.TaskLog{})
+	testConnection.dbClient.Delete([]db.WorkOrder{issuedOrder, compliantOrder})
+	testConnection.dbClient.Unscoped().Delete(createdDocuments)
+	f.delete()
+}
+
+func TestVendorDocumentController_UploadAndDownload(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	createdDocument := db.VendorDocument{Type: "Insurance", Number: "POL-2", IssueDate: time.Now(), VendorID: f.vendors[0].ID}
+	testConnection.dbClient.Create(&createdDocument)
+
+	// No file uploaded yet
+	rr := serveAsAdmin(t, "GET", fmt.Sprintf("/api/vendor-documents/file/%v", createdDocument.ID), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Vendor document download without file: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+
+	// Build multipart form with file
+	fileBody := &bytes.Buffer{}
+	writer := multipart.NewWriter(fileBody)
+	fileField, err := writer.CreateFormFile("file", "policy.pdf")
+	if err != nil {
+		t.Fatalf("Failed to create form file field: %v", err)
+	}
+	fileField.Write([]byte("%PDF-1.4"))
+	writer.Close()
+
+	var uploadTests = []struct {
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{testConnection.accounts.user.token, http.StatusForbidden, "basic user upload test"},
+		{testConnection.accounts.admin.token, http.StatusCreated, "admin upload test"},
+	}
+	for _, v := range uploadTests {
+		req, err := http.NewRequest("POST", fmt.Sprintf("/api/vendor-documents/file/%v", createdDocument.ID), bytes.NewReader(fileBody.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Set content type
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor document upload (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			var uploaded db.VendorDocument
+			json.Unmarshal(rr.Body.Bytes(), &uploaded)
+			if uploaded.FileName != "policy.pdf" || uploaded.FileType != "pdf" || uploaded.ObjectKey == "" {
+				t.Errorf("Vendor document upload: expected policy.pdf stored in object storage, got %v", uploaded)
+			}
+		}
+	}
+
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/vendor-documents/file/%v", createdDocument.ID), nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Vendor document download: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Unscoped().Delete(&createdDocument)
+	f.delete()
+}
+
+func TestVendorDocumentService_ProcessExpiringDocuments(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	now := time.Now()
+	expiring := now.AddDate(0, 0, 5)
+	later := now.AddDate(0, 6, 0)
+	createdDocuments := []db.VendorDocument{
+		{Type: "Insurance", Number: "POL-EXP", IssueDate: now.AddDate(-1, 0, 0), ExpiryDate: &expiring, VendorID: f.vendors[1].ID},
+		{Type: "Business Licence", Number: "NIB-LATER", IssueDate: now.AddDate(-1, 0, 0), ExpiryDate: &later, VendorID: f.vendors[1].ID},
+	}
+	for i := range createdDocuments {
+		testConnection.dbClient.Create(&createdDocuments[i])
+	}
+
+	// Run job twice. Documents are only flagged once
+	for i := 0; i < 2; i++ {
+		err := testConnection.vendorDocuments.serv.ProcessExpiringDocuments()
+		if err != nil {
+			t.Fatalf("Process expiring documents failed: %v", err)
+		}
+	}
+	// Wait for delivery
+	testConnection.notifications.serv.Wait()
+
+	var found []db.Notification
+	testConnection.dbClient.Where("user_id = ? AND type = ?", testConnection.accounts.admin.details.ID, "VendorDocumentExpiring").Find(&found)
+	if len(found) != 1 || !strings.Contains(found[0].Message, "POL-EXP") {
+		t.Errorf("Expiring vendor documents: expected 1 admin notification for POL-EXP, got %v", found)
+	}
+	var flagged []db.VendorDocument
+	testConnection.dbClient.Where("vendor_id = ? AND expiry_notified_at IS NOT NULL", f.vendors[1].ID).Find(&flagged)
+	if len(flagged) != 1 || flagged[0].ID != createdDocuments[0].ID {
+		t.Errorf("Expiring vendor documents: expected only the expiring document to be flagged, got %d", len(flagged))
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Delete(found)
+	testConnection.dbClient.Unscoped().Delete(createdDocuments)
+	f.delete()
+}
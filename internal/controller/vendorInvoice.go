@@ -0,0 +1,389 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type VendorInvoiceController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	RecordPayment(w http.ResponseWriter, r *http.Request)
+	Payables(w http.ResponseWriter, r *http.Request)
+}
+
+type vendorInvoiceController struct {
+	service service.VendorInvoiceService
+	rates   service.ExchangeRateService
+}
+
+func NewVendorInvoiceController(service service.VendorInvoiceService, rates service.ExchangeRateService) VendorInvoiceController {
+	return &vendorInvoiceController{service, rates}
+}
+
+// API/VENDOR-INVOICES
+// Find a list of vendor invoices
+// @Summary      Find a list of vendor invoices
+// @Description  Accepts limit, offset, order, vendor and status params and returns list of vendor invoices (earliest due first by default)
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        vendor   path      int  false  "vendor id"
+// @Param        status   path      string  false  "payment status (Unpaid, Partially Paid, Paid)"
+// @Success      200 {object} []db.VendorInvoice
+// @Failure      400 {string} string "Can't find vendor invoices"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /vendor-invoices [get]
+// @Security BearerToken
+func (c vendorInvoiceController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	vendorParam := r.URL.Query().Get("vendor")
+	status := r.URL.Query().Get("status")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	vendorId, _ := strconv.Atoi(vendorParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all vendor invoices using query params
+	foundInvoices, err := c.service.FindAll(limit, offset, orderBy, vendorId, status)
+	if err != nil {
+		http.Error(w, "Can't find vendor invoices", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundInvoices)
+	if err != nil {
+		http.Error(w, "Can't find vendor invoices", http.StatusBadRequest)
+		fmt.Println("error writing vendor invoices to response: ", err)
+		return
+	}
+}
+
+// Find a created vendor invoice
+// @Summary      Find vendor invoice
+// @Description  Find a vendor invoice with its line items and payments by ID
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Invoice ID"
+// @Success      200 {object} db.VendorInvoice
+// @Failure      400 {string} string "Can't find vendor invoice with ID: {id}"
+// @Router       /vendor-invoices/{id} [get]
+// @Security BearerToken
+func (c vendorInvoiceController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundInvoice, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor invoice with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundInvoice)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor invoice with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Create a new vendor invoice
+// @Summary      Create vendor invoice
+// @Description  Records a vendor invoice for a work order or maintenance request. Totals and PPN are calculated from the line items and the invoice NPWP must match the vendor's. A completed work order moves to Invoiced
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        invoice body models.CreateVendorInvoice true "New Vendor Invoice Json"
+// @Success      201 {object} db.VendorInvoice
+// @Failure      400 {string} string "Vendor invoice creation failed."
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /vendor-invoices [post]
+// @Security BearerToken
+func (c vendorInvoiceController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var invoice models.CreateVendorInvoice
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&invoice)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&invoice)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Create vendor invoice in db
+	createdInvoice, createErr := c.service.Create(userID, &invoice)
+	if createErr != nil {
+		http.Error(w, "Vendor invoice creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created invoice to output
+	err = helpers.WriteAsJSON(w, createdInvoice)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a vendor invoice (using URL parameter id)
+// @Summary      Update vendor invoice
+// @Description  Updates a vendor invoice. Line items can only be replaced before payments are recorded
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        invoice body models.UpdateVendorInvoice true "Update Vendor Invoice Json"
+// @Param        id   path      int  true  "Vendor Invoice ID"
+// @Success      200 {object} db.VendorInvoice
+// @Failure      400 {string} string "Failed vendor invoice update"
+// @Failure      409 {string} string "Line items can't be changed once payments are recorded"
+// @Router       /vendor-invoices/{id} [put]
+// @Security BearerToken
+func (c vendorInvoiceController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var invoice models.UpdateVendorInvoice
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&invoice)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&invoice)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update vendor invoice
+	updatedInvoice, err := c.service.Update(idParameter, &invoice)
+	if err != nil {
+		// If payments have already been recorded
+		if errors.Is(err, service.ErrInvoiceHasPayments) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed vendor invoice update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated invoice to output
+	err = helpers.WriteAsJSON(w, updatedInvoice)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor invoice update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete vendor invoice (using URL parameter id)
+// @Summary      Delete vendor invoice
+// @Description  Deletes an existing vendor invoice
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Invoice ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed vendor invoice deletion"
+// @Router       /vendor-invoices/{id} [delete]
+// @Security BearerToken
+func (c vendorInvoiceController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete vendor invoice using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed vendor invoice deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Record a payment against a vendor invoice (using URL parameter id)
+// @Summary      Record vendor payment
+// @Description  Records a full or partial payment against a vendor invoice and updates its payment status. An invoiced work order moves to Paid once its invoice is paid
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        payment body models.RecordVendorPayment true "Vendor Payment Json"
+// @Param        id   path      int  true  "Vendor Invoice ID"
+// @Success      201 {object} db.VendorInvoice
+// @Failure      400 {string} string "Failed recording vendor payment"
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Failure      409 {string} string "Payment exceeds the invoice's outstanding balance"
+// @Router       /vendor-invoices/payments/{id} [post]
+// @Security BearerToken
+func (c vendorInvoiceController) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var payment models.RecordVendorPayment
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&payment)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&payment)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Record payment
+	paidInvoice, err := c.service.RecordPayment(idParameter, userID, &payment)
+	if err != nil {
+		// If payment is more than what is owed
+		if errors.Is(err, service.ErrOverpayment) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed recording vendor payment: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write paid invoice to output
+	err = helpers.WriteAsJSON(w, paidInvoice)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// API/PAYABLES
+// Payables aging report
+// @Summary      Payables aging report
+// @Description  Groups outstanding vendor invoice balances per vendor into current, 1-30, 31-60, 61-90 and over 90 days past due
+// @Tags         Vendor Invoices
+// @Accept       json
+// @Produce      json
+// @Param        as_of   path      string  false  "report date (YYYY-MM-DD). Defaults to today"
+// @Param        vendor   path      int  false  "vendor id"
+// @Param        currency   path      string  false  "reporting currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.PayablesAging
+// @Failure      400 {string} string "Invalid as_of date (YYYY-MM-DD)"
+// @Failure      400 {string} string "Can't build payables aging report"
+// @Router       /payables [get]
+// @Security BearerToken
+func (c vendorInvoiceController) Payables(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	asOfParam := r.URL.Query().Get("as_of")
+	vendorParam := r.URL.Query().Get("vendor")
+	// Convert to int
+	vendorId, _ := strconv.Atoi(vendorParam)
+
+	// Report as of end of day
+	asOf := time.Now()
+	if asOfParam != "" {
+		asOfDate, err := time.Parse("2006-01-02", asOfParam)
+		if err != nil {
+			http.Error(w, "Invalid as_of date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		asOf = asOfDate.Add(24*time.Hour - time.Second)
+	}
+
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	// Build report
+	report, err := c.service.Aging(asOf, vendorId, currency)
+	if err != nil {
+		http.Error(w, "Can't build payables aging report", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, report)
+	if err != nil {
+		http.Error(w, "Can't build payables aging report", http.StatusBadRequest)
+		fmt.Println("error writing payables aging report to response: ", err)
+		return
+	}
+}
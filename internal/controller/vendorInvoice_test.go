@@ -0,0 +1,215 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestVendorInvoiceController_CreateAndPay(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	createdOrder := db.WorkOrder{Description: "Replace water heater element", Status: "Completed", MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, TaskID: f.task.ID}
+	testConnection.dbClient.Create(&createdOrder)
+
+	// Two taxable units and one exempt line
+	lines := []models.VendorInvoiceLine{
+		{Description: "Heater element", Quantity: 2, UnitPrice: db.NewMoney(500000, "IDR")},
+		{Description: "Call out fee", Quantity: 1, UnitPrice: db.NewMoney(100000, "IDR"), PPNExempt: true},
+	}
+	invoiceDate := time.Now()
+	var createTests = []struct {
+		data                   models.CreateVendorInvoice
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateVendorInvoice{InvoiceNumber: "INV-001", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), WorkOrder: createdOrder, Lines: lines}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		// NPWP belongs to another vendor
+		{models.CreateVendorInvoice{InvoiceNumber: "INV-001", VendorNPWP: f.vendors[1].NPWP, InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), WorkOrder: createdOrder, Lines: lines}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin npwp mismatch fail test"},
+		// Work order was issued to another vendor
+		{models.CreateVendorInvoice{InvoiceNumber: "INV-001", VendorNPWP: f.vendors[1].NPWP, InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), WorkOrder: createdOrder, Vendor: f.vendors[1], Lines: lines}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin work order vendor mismatch fail test"},
+		{models.CreateVendorInvoice{InvoiceNumber: "INV-001", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), Lines: lines}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin nothing invoiced fail test"},
+		// Formatted NPWP matches
+		{models.CreateVendorInvoice{InvoiceNumber: "INV-001", VendorNPWP: "12.345.678.9-012.345", InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), WorkOrder: createdOrder, Lines: lines}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+	}
+
+	var createdInvoice db.VendorInvoice
+	for _, v := range createTests {
+		// Make new request with invoice creation in body
+		req, err := http.NewRequest("POST", "/api/vendor-invoices", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor invoice create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &createdInvoice)
+		}
+	}
+
+	// Check totals. PPN only applies to taxable lines
+	if createdInvoice.Subtotal.Float() != 1100000 || createdInvoice.PPN.Float() != 110000 || createdInvoice.Total.Float() != 1210000 || createdInvoice.PPNRate != 11 {
+		t.Errorf("Vendor invoice create: expected subtotal 1100000, PPN 110000 and total 1210000, got %v, %v and %v", createdInvoice.Subtotal, createdInvoice.PPN, createdInvoice.Total)
+	}
+	if createdInvoice.MaintenanceRequestID == nil || *createdInvoice.MaintenanceRequestID != f.request.ID || len(createdInvoice.Lines) != 2 {
+		t.Errorf("Vendor invoice create: expected 2 lines for maintenance request %d, got %v", f.request.ID, createdInvoice)
+	}
+	// Vendors that aren't PPN registered invoice at 0%
+	zeroRate := 0.0
+	rr := serveAsAdmin(t, "POST", "/api/vendor-invoices", models.CreateVendorInvoice{InvoiceNumber: "INV-002", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: invoiceDate, DueDate: invoiceDate.AddDate(0, 0, 30), PPNRate: &zeroRate, MaintenanceRequest: f.request, Vendor: f.vendors[0], Lines: lines})
+	var exemptInvoice db.VendorInvoice
+	json.Unmarshal(rr.Body.Bytes(), &exemptInvoice)
+	if rr.Code != http.StatusCreated || exemptInvoice.PPNRate != 0 || !exemptInvoice.PPN.IsZero() || exemptInvoice.Total.Float() != 1100000 {
+		t.Errorf("Vendor invoice create at 0%% PPN: expected no PPN and total 1100000, got %v %v", rr.Code, rr.Body.String())
+	}
+	testConnection.dbClient.Where("vendor_invoice_id = ?", exemptInvoice.ID).Delete(&db.VendorInvoiceLine{})
+	testConnection.dbClient.Unscoped().Delete(&exemptInvoice)
+
+	// Completed work order is invoiced
+	var foundOrder db.WorkOrder
+	testConnection.dbClient.First(&foundOrder, createdOrder.ID)
+	if foundOrder.Status != "Invoiced" {
+		t.Errorf("Vendor invoice create: expected work order to be Invoiced, got %v", foundOrder.Status)
+	}
+
+	// Record payments
+	var paymentTests = []struct {
+		data                   models.RecordVendorPayment
+		expectedResponseStatus int
+		expectedStatus         string
+		expectedOrderStatus    string
+		testName               string
+	}{
+		{models.RecordVendorPayment{Amount: db.NewMoney(500000, "IDR"), Method: "Bank Transfer"}, http.StatusCreated, "Partially Paid", "Invoiced", "admin partial payment test"},
+		{models.RecordVendorPayment{Amount: db.NewMoney(800000, "IDR")}, http.StatusConflict, "Partially Paid", "Invoiced", "admin overpayment fail test"},
+		{models.RecordVendorPayment{Amount: db.NewMoney(710000, "IDR"), Method: "Bank Transfer"}, http.StatusCreated, "Paid", "Paid", "admin final payment test"},
+	}
+	for _, v := range paymentTests {
+		req, err := http.NewRequest("POST", fmt.Sprintf("/api/vendor-invoices/payments/%v", createdInvoice.ID), buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor invoice payment test (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+		var found db.VendorInvoice
+		testConnection.dbClient.First(&found, createdInvoice.ID)
+		if found.Status != v.expectedStatus {
+			t.Errorf("Vendor invoice payment test (%v): expected status %v, got %v", v.testName, v.expectedStatus, found.Status)
+		}
+		testConnection.dbClient.First(&foundOrder, createdOrder.ID)
+		if foundOrder.Status != v.expectedOrderStatus {
+			t.Errorf("Vendor invoice payment test (%v): expected work order status %v, got %v", v.testName, v.expectedOrderStatus, foundOrder.Status)
+		}
+	}
+	// Status changes are logged on the work order's task
+	var orderLogs int64
+	testConnection.dbClient.Model(&db.TaskLog{}).Where("task_id = ? AND log_message LIKE ?", f.task.ID, fmt.Sprintf("Work order #%d status changed%%", createdOrder.ID)).Count(&orderLogs)
+	if orderLogs != 2 {
+		t.Errorf("Vendor invoice work order logs: expected 2 status changes logged, got %v", orderLogs)
+	}
+
+	// Lines can't be changed once paid
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/api/vendor-invoices/%v", createdInvoice.ID), buildReqBody(models.UpdateVendorInvoice{Lines: lines[:1]}))
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr = httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("Vendor invoice update after payment: got %v want %v", status, http.StatusConflict)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("vendor_invoice_id = ?", createdInvoice.ID).Delete(&db.VendorPayment{})
+	testConnection.dbClient.Where("vendor_invoice_id = ?", createdInvoice.ID).Delete(&db.VendorInvoiceLine{})
+	testConnection.dbClient.Unscoped().Delete(&createdInvoice)
+	testConnection.dbClient.Where("task_id = ?", f.task.ID).Delete(&db.TaskLog{})
+	testConnection.dbClient.Delete(&createdOrder)
+	f.delete()
+}
+
+func TestVendorInvoiceController_Payables(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	now := time.Now()
+	createdInvoices := []db.VendorInvoice{
+		// Not yet due
+		{InvoiceNumber: "AGE-1", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: now, DueDate: now.AddDate(0, 0, 5), Total: db.NewMoney(100000, "IDR"), Status: "Unpaid", VendorID: f.vendors[0].ID},
+		// 10 days past due, partially paid
+		{InvoiceNumber: "AGE-2", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: now, DueDate: now.AddDate(0, 0, -10), Total: db.NewMoney(200000, "IDR"), AmountPaid: db.NewMoney(50000, "IDR"), Status: "Partially Paid", VendorID: f.vendors[0].ID},
+		// 45 and 120 days past due
+		{InvoiceNumber: "AGE-3", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: now, DueDate: now.AddDate(0, 0, -45), Total: db.NewMoney(300000, "IDR"), Status: "Unpaid", VendorID: f.vendors[0].ID},
+		{InvoiceNumber: "AGE-4", VendorNPWP: f.vendors[1].NPWP, InvoiceDate: now, DueDate: now.AddDate(0, 0, -120), Total: db.NewMoney(400000, "IDR"), Status: "Unpaid", VendorID: f.vendors[1].ID},
+		// Paid invoices aren't included
+		{InvoiceNumber: "AGE-5", VendorNPWP: f.vendors[1].NPWP, InvoiceDate: now, DueDate: now.AddDate(0, 0, -75), Total: db.NewMoney(500000, "IDR"), AmountPaid: db.NewMoney(500000, "IDR"), Status: "Paid", VendorID: f.vendors[1].ID},
+	}
+	if result := testConnection.dbClient.Create(createdInvoices); result.Error != nil {
+		t.Fatal("Failed to create vendor invoices for payables test: ", result.Error)
+	}
+
+	var payablesTests = []struct {
+		request                string
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedVendors        int
+		expectedTotals         models.PayablesAgingBucket
+	}{
+		{"/api/payables", testConnection.accounts.user.token, http.StatusForbidden, 0, models.PayablesAgingBucket{}},
+		{"/api/payables", testConnection.accounts.admin.token, http.StatusOK, 2, models.PayablesAgingBucket{Current: db.NewMoney(100000, "IDR"), Days30: db.NewMoney(150000, "IDR"), Days60: db.NewMoney(300000, "IDR"), Over90: db.NewMoney(400000, "IDR"), Total: db.NewMoney(950000, "IDR")}},
+		{fmt.Sprintf("/api/payables?vendor=%v", f.vendors[1].ID), testConnection.accounts.admin.token, http.StatusOK, 1, models.PayablesAgingBucket{Over90: db.NewMoney(400000, "IDR"), Total: db.NewMoney(400000, "IDR")}},
+		// Everything is current before it was due
+		{fmt.Sprintf("/api/payables?vendor=%v&as_of=%v", f.vendors[1].ID, now.AddDate(0, 0, -200).Format("2006-01-02")), testConnection.accounts.admin.token, http.StatusOK, 1, models.PayablesAgingBucket{Current: db.NewMoney(400000, "IDR"), Total: db.NewMoney(400000, "IDR")}},
+		{"/api/payables?as_of=yesterday", testConnection.accounts.admin.token, http.StatusBadRequest, 0, models.PayablesAgingBucket{}},
+	}
+
+	for _, v := range payablesTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", v.request, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+
+		// Use handler with recorder and created request
+		testConnection.router.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Payables aging (%v): got %v want %v", v.request, status, v.expectedResponseStatus)
+		}
+		// Check buckets
+		if v.expectedResponseStatus == http.StatusOK {
+			var body models.PayablesAging
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if len(body.Vendors) != v.expectedVendors || fmt.Sprint(body.Totals) != fmt.Sprint(v.expectedTotals) {
+				t.Errorf("Payables aging (%v): expected %d vendors with totals %v, got %d with %v", v.request, v.expectedVendors, v.expectedTotals, len(body.Vendors), body.Totals)
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Unscoped().Delete(createdInvoices)
+	f.delete()
+}
@@ -0,0 +1,340 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type VendorQuoteController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Invite(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Compare(w http.ResponseWriter, r *http.Request)
+	Accept(w http.ResponseWriter, r *http.Request)
+}
+
+type vendorQuoteController struct {
+	service service.VendorQuoteService
+	rates   service.ExchangeRateService
+}
+
+func NewVendorQuoteController(service service.VendorQuoteService, rates service.ExchangeRateService) VendorQuoteController {
+	return &vendorQuoteController{service, rates}
+}
+
+// API/VENDOR-QUOTES
+// Find a list of vendor quotes
+// @Summary      Find a list of vendor quotes
+// @Description  Accepts limit, offset, order, maintenance and vendor params and returns list of vendor quotes
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        maintenance   path      int  false  "maintenance request id"
+// @Param        vendor   path      int  false  "vendor id"
+// @Success      200 {object} []db.VendorQuote
+// @Failure      400 {string} string "Can't find vendor quotes"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /vendor-quotes [get]
+// @Security BearerToken
+func (c vendorQuoteController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	maintenanceParam := r.URL.Query().Get("maintenance")
+	vendorParam := r.URL.Query().Get("vendor")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	maintenanceId, _ := strconv.Atoi(maintenanceParam)
+	vendorId, _ := strconv.Atoi(vendorParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all vendor quotes using query params
+	foundQuotes, err := c.service.FindAll(limit, offset, orderBy, maintenanceId, vendorId)
+	if err != nil {
+		http.Error(w, "Can't find vendor quotes", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundQuotes)
+	if err != nil {
+		http.Error(w, "Can't find vendor quotes", http.StatusBadRequest)
+		fmt.Println("error writing vendor quotes to response: ", err)
+		return
+	}
+}
+
+// Find a created vendor quote
+// @Summary      Find vendor quote
+// @Description  Find a vendor quote by ID
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Quote ID"
+// @Success      200 {object} db.VendorQuote
+// @Failure      400 {string} string "Can't find vendor quote with ID: {id}"
+// @Router       /vendor-quotes/{id} [get]
+// @Security BearerToken
+func (c vendorQuoteController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundQuote, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor quote with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundQuote)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor quote with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Invite vendors to quote on a maintenance request
+// @Summary      Invite vendors to quote
+// @Description  Invites vendors offering the maintenance request's work type to quote. All matching vendors are invited if none are selected
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        invite body models.InviteVendorQuotes true "Vendor Quote Invitation Json"
+// @Success      201 {object} []db.VendorQuote
+// @Failure      400 {string} string "Vendor quote invitation failed."
+// @Router       /vendor-quotes [post]
+// @Security BearerToken
+func (c vendorQuoteController) Invite(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var invite models.InviteVendorQuotes
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&invite)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&invite)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Create invited quotes in db
+	createdQuotes, createErr := c.service.Invite(&invite)
+	if createErr != nil {
+		http.Error(w, "Vendor quote invitation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created quotes to output
+	err = helpers.WriteAsJSON(w, createdQuotes)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Record a vendor's quote (using URL parameter id)
+// @Summary      Update vendor quote
+// @Description  Records the vendor's quote amount, tax, validity and attachments. Invited quotes are marked submitted once an amount is recorded
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        quote body models.UpdateVendorQuote true "Update Vendor Quote Json"
+// @Param        id   path      int  true  "Vendor Quote ID"
+// @Success      200 {object} db.VendorQuote
+// @Failure      400 {string} string "Failed vendor quote update"
+// @Failure      409 {string} string "Vendor quote has already been accepted or rejected"
+// @Router       /vendor-quotes/{id} [put]
+// @Security BearerToken
+func (c vendorQuoteController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var quote models.UpdateVendorQuote
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&quote)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&quote)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update vendor quote
+	updatedQuote, err := c.service.Update(idParameter, &quote)
+	if err != nil {
+		// If quote is no longer open
+		if errors.Is(err, service.ErrQuoteClosed) {
+			http.Error(w, "Vendor quote has already been accepted or rejected", http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed vendor quote update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated quote to output
+	err = helpers.WriteAsJSON(w, updatedQuote)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor quote update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete vendor quote (using URL parameter id)
+// @Summary      Delete vendor quote
+// @Description  Deletes an existing vendor quote
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Quote ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed vendor quote deletion"
+// @Router       /vendor-quotes/{id} [delete]
+// @Security BearerToken
+func (c vendorQuoteController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete vendor quote using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed vendor quote deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Compare a maintenance request's quotes side by side
+// @Summary      Compare vendor quotes
+// @Description  Returns a maintenance request's quotes cheapest first, flagging expired quotes and the cheapest quote that can be accepted. Quotes in other currencies are ranked by their total converted into the comparison currency at today's rate
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        maintenance   path      int  true  "maintenance request id"
+// @Param        currency   path      string  false  "comparison currency (IDR, USD or AUD). Defaults to the user's reporting currency"
+// @Success      200 {object} models.VendorQuoteComparison
+// @Failure      400 {string} string "Must include maintenance parameter"
+// @Failure      400 {string} string "Can't compare vendor quotes"
+// @Router       /vendor-quotes/compare [get]
+// @Security BearerToken
+func (c vendorQuoteController) Compare(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	maintenanceParam := r.URL.Query().Get("maintenance")
+	// Convert to int
+	maintenanceId, _ := strconv.Atoi(maintenanceParam)
+
+	// Check that maintenance request is present as requirement
+	if maintenanceId == 0 {
+		http.Error(w, "Must include maintenance parameter", http.StatusBadRequest)
+		return
+	}
+	currency, ok := reportingCurrency(w, r, c.rates)
+	if !ok {
+		return
+	}
+
+	// Build comparison
+	comparison, err := c.service.Compare(maintenanceId, currency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't compare vendor quotes: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, comparison)
+	if err != nil {
+		http.Error(w, "Can't compare vendor quotes", http.StatusBadRequest)
+		fmt.Println("error writing vendor quote comparison to response: ", err)
+		return
+	}
+}
+
+// Accept a vendor quote (using URL parameter id)
+// @Summary      Accept vendor quote
+// @Description  Accepts a submitted quote, rejecting the request's other open quotes and assigning the vendor and cost to the maintenance request
+// @Tags         Vendor Quotes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Quote ID"
+// @Success      200 {object} db.VendorQuote
+// @Failure      400 {string} string "Failed vendor quote acceptance"
+// @Failure      409 {string} string "Only submitted quotes that haven't expired can be accepted"
+// @Router       /vendor-quotes/accept/{id} [post]
+// @Security BearerToken
+func (c vendorQuoteController) Accept(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Accept vendor quote
+	acceptedQuote, err := c.service.Accept(idParameter)
+	if err != nil {
+		// If quote can't be accepted in its current state
+		if errors.Is(err, service.ErrQuoteClosed) || errors.Is(err, service.ErrQuoteNotAcceptable) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed vendor quote acceptance: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write accepted quote to output
+	err = helpers.WriteAsJSON(w, acceptedQuote)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor quote acceptance: %s", err), http.StatusBadRequest)
+		return
+	}
+}
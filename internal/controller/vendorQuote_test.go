@@ -0,0 +1,237 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+// Fixtures shared by vendor quote tests
+type vendorQuoteFixtures struct {
+	property    db.Property
+	task        db.Task
+	workTypes   []db.WorkType
+	vendors     []db.Vendor
+	request     db.MaintenanceRequest
+	attachments []db.PropertyAttachment
+}
+
+// Creates a plumbing maintenance request with two plumbing vendors and one painting vendor
+func createVendorQuoteFixtures(t *testing.T) *vendorQuoteFixtures {
+	f := &vendorQuoteFixtures{}
+	f.property = db.Property{Property_Name: "quoteProperty1", Postcode: 80361, Suburb: "Canggu", City: "Badung", Street_Address_1: "Jl. Pantai Batu Bolong", Bedrooms: 3, Bathrooms: 2, Description: "Villa", Managed: true}
+	if result := testConnection.dbClient.Create(&f.property); result.Error != nil {
+		t.Fatal("Failed to create property for vendor quote test: ", result.Error)
+	}
+	f.task = db.Task{TaskName: "Fix the water heater", Type: "Maintenance"}
+	testConnection.dbClient.Create(&f.task)
+	f.workTypes = []db.WorkType{{Name: "Quote Plumbing"}, {Name: "Quote Painting"}}
+	testConnection.dbClient.Create(f.workTypes)
+	f.vendors = []db.Vendor{
+		{CompanyName: "Tirta Plumbing", NPWP: "123456789012345", WorkTypes: []db.WorkType{f.workTypes[0]}},
+		{CompanyName: "Bali Pipe Works", NPWP: "223456789012345", WorkTypes: []db.WorkType{f.workTypes[0]}},
+		{CompanyName: "Warna Painters", NPWP: "323456789012345", WorkTypes: []db.WorkType{f.workTypes[1]}},
+	}
+	if result := testConnection.dbClient.Create(f.vendors); result.Error != nil {
+		t.Fatal("Failed to create vendors for vendor quote test: ", result.Error)
+	}
+	f.request = db.MaintenanceRequest{Scale: "High", WorkDefinition: "Repair", Type: "Plumbing", PropertyID: f.property.ID, TaskID: f.task.ID, WorkTypeID: f.workTypes[0].ID}
+	if result := testConnection.dbClient.Create(&f.request); result.Error != nil {
+		t.Fatal("Failed to create maintenance request for vendor quote test: ", result.Error)
+	}
+	f.attachments = []db.PropertyAttachment{{Label: "Quote PDF", FileName: "quote.pdf", PropertyID: f.property.ID}}
+	testConnection.dbClient.Create(f.attachments)
+	return f
+}
+
+// Deletes the created fixtures
+func (f *vendorQuoteFixtures) delete() {
+	testConnection.dbClient.Exec("DELETE FROM vendor_quote_attachments WHERE vendor_quote_id IN (SELECT id FROM vendor_quotes WHERE maintenance_request_id = ?)", f.request.ID)
+	testConnection.dbClient.Unscoped().Where("maintenance_request_id = ?", f.request.ID).Delete(&db.VendorQuote{})
+	testConnection.dbClient.Delete(&f.request)
+	for _, vendor := range f.vendors {
+		testConnection.dbClient.Model(&vendor).Association("WorkTypes").Clear()
+	}
+	testConnection.dbClient.Unscoped().Delete(f.vendors)
+	testConnection.dbClient.Unscoped().Delete(f.workTypes)
+	testConnection.dbClient.Delete(f.attachments)
+	testConnection.dbClient.Delete(&f.task)
+	testConnection.dbClient.Unscoped().Delete(&f.property)
+}
+
+func TestVendorQuoteController_Invite(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+
+	var inviteTests = []struct {
+		data                   models.InviteVendorQuotes
+		tokenToUse             string
+		expectedResponseStatus int
+		expectedInvites        int
+		testName               string
+	}{
+		{models.InviteVendorQuotes{MaintenanceRequest: f.request}, testConnection.accounts.user.token, http.StatusForbidden, 0, "basic user invite test"},
+		// Vendor doesn't offer plumbing
+		{models.InviteVendorQuotes{MaintenanceRequest: f.request, Vendors: []db.Vendor{f.vendors[2]}}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin mismatched work type fail test"},
+		{models.InviteVendorQuotes{MaintenanceRequest: f.request, Vendors: []db.Vendor{f.vendors[0]}}, testConnection.accounts.admin.token, http.StatusCreated, 1, "admin selected vendor invite test"},
+		// Only the vendor not yet invited is added
+		{models.InviteVendorQuotes{MaintenanceRequest: f.request}, testConnection.accounts.admin.token, http.StatusCreated, 1, "admin all matching vendors invite test"},
+		{models.InviteVendorQuotes{MaintenanceRequest: db.MaintenanceRequest{ID: 9999}}, testConnection.accounts.admin.token, http.StatusBadRequest, 0, "admin missing request fail test"},
+	}
+
+	for _, v := range inviteTests {
+		// Make new request with invitation in body
+		req, err := http.NewRequest("POST", "/api/vendor-quotes", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send invite request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor quote invite test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		// Check number of quotes created
+		if v.expectedResponseStatus == http.StatusCreated {
+			var body []db.VendorQuote
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if len(body) != v.expectedInvites {
+				t.Errorf("Vendor quote invite test (%v): expected %d invites, got %d", v.testName, v.expectedInvites, len(body))
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	f.delete()
+}
+
+func TestVendorQuoteController_CompareAndAccept(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	createdQuotes := []db.VendorQuote{
+		{Status: "Invited", MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID},
+		{Status: "Invited", MaintenanceRequestID: f.request.ID, VendorID: f.vendors[1].ID},
+	}
+	if result := testConnection.dbClient.Create(createdQuotes); result.Error != nil {
+		t.Fatal("Failed to create vendor quotes for compare test: ", result.Error)
+	}
+
+	// Record quotes
+	var updateTests = []struct {
+		quoteId                uint
+		data                   models.UpdateVendorQuote
+		expectedResponseStatus int
+		testName               string
+	}{
+		{createdQuotes[0].ID, models.UpdateVendorQuote{Amount: db.NewMoney(2500000, "IDR"), Tax: db.NewMoney(275000, "IDR"), ValidUntil: time.Now().AddDate(0, 1, 0), Attachments: f.attachments}, http.StatusOK, "admin record quote test"},
+		{createdQuotes[1].ID, models.UpdateVendorQuote{Amount: db.NewMoney(1800000, "IDR"), Tax: db.NewMoney(198000, "IDR")}, http.StatusOK, "admin record cheaper quote test"},
+		// Attachment must belong to the request's property
+		{createdQuotes[1].ID, models.UpdateVendorQuote{Attachments: []db.PropertyAttachment{{ID: 9999}}}, http.StatusBadRequest, "admin missing attachment fail test"},
+	}
+	for _, v := range updateTests {
+		req, err := http.NewRequest("PUT", fmt.Sprintf("/api/vendor-quotes/%v", v.quoteId), buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor quote update test (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusOK {
+			var body db.VendorQuote
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if body.Status != "Submitted" || len(body.Attachments) != len(v.data.Attachments) {
+				t.Errorf("Vendor quote update test (%v): expected submitted quote with %d attachments, got %v with %d", v.testName, len(v.data.Attachments), body.Status, len(body.Attachments))
+			}
+		}
+	}
+
+	// Compare quotes side by side
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/vendor-quotes/compare?maintenance=%v", f.request.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Vendor quote compare: got %v want %v", status, http.StatusOK)
+	}
+	var comparison models.VendorQuoteComparison
+	json.Unmarshal(rr.Body.Bytes(), &comparison)
+	if len(comparison.Quotes) != 2 || comparison.Quotes[0].ID != createdQuotes[1].ID || !comparison.Quotes[0].Lowest || comparison.Quotes[0].Total.Float() != 1998000 {
+		t.Errorf("Vendor quote compare: expected cheapest quote %d first, got %v", createdQuotes[1].ID, comparison.Quotes)
+	}
+
+	// Quotes in other currencies are ranked by their converted total
+	rate := db.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: 16000, EffectiveDate: time.Now().AddDate(0, 0, -1)}
+	testConnection.dbClient.Create(&rate)
+	testConnection.dbClient.Model(&createdQuotes[0]).Updates(db.VendorQuote{Amount: db.NewMoney(150, "USD"), Tax: db.NewMoney(16.5, "USD")})
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/vendor-quotes/compare?maintenance=%v&currency=IDR", f.request.ID), nil)
+	json.Unmarshal(rr.Body.Bytes(), &comparison)
+	if rr.Code != http.StatusOK || len(comparison.Quotes) != 2 || comparison.Quotes[0].ID != createdQuotes[1].ID || comparison.Quotes[1].ComparableTotal.Float() != 2664000 {
+		t.Errorf("Vendor quote compare in IDR: expected quote %d first and the USD quote totalling 2664000, got %v %v", createdQuotes[1].ID, rr.Code, rr.Body.String())
+	}
+	// Quotes can't be compared without a rate
+	rr = serveAsAdmin(t, "GET", fmt.Sprintf("/api/vendor-quotes/compare?maintenance=%v&currency=AUD", f.request.ID), nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Vendor quote compare without a rate: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+	testConnection.dbClient.Unscoped().Delete(&rate)
+	testConnection.dbClient.Model(&createdQuotes[0]).Updates(db.VendorQuote{Amount: db.NewMoney(2500000, "IDR"), Tax: db.NewMoney(275000, "IDR")})
+
+	// Accept quotes
+	var acceptTests = []struct {
+		quoteId                uint
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{createdQuotes[0].ID, testConnection.accounts.user.token, http.StatusForbidden, "basic user accept test"},
+		{createdQuotes[0].ID, testConnection.accounts.admin.token, http.StatusOK, "admin accept test"},
+		// Other quote was rejected on acceptance
+		{createdQuotes[1].ID, testConnection.accounts.admin.token, http.StatusConflict, "admin accept rejected fail test"},
+	}
+	for _, v := range acceptTests {
+		req, err := http.NewRequest("POST", fmt.Sprintf("/api/vendor-quotes/accept/%v", v.quoteId), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor quote accept test (%v): got %v want %v. %v", v.testName, status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Check vendor and cost were assigned to maintenance request
+	var foundRequest db.MaintenanceRequest
+	testConnection.dbClient.First(&foundRequest, f.request.ID)
+	if foundRequest.VendorID == nil || *foundRequest.VendorID != f.vendors[0].ID || foundRequest.TotalCost.Float() != 2500000 || foundRequest.Tax.Float() != 275000 {
+		t.Errorf("Vendor quote accept: expected vendor %d with cost 2500000, got %v with cost %v", f.vendors[0].ID, foundRequest.VendorID, foundRequest.TotalCost)
+	}
+
+	// Accepted quote can no longer be changed
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/api/vendor-quotes/%v", createdQuotes[0].ID), buildReqBody(models.UpdateVendorQuote{Amount: db.NewMoney(1, "IDR")}))
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr = httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("Vendor quote update after accept: got %v want %v", status, http.StatusConflict)
+	}
+
+	// Clean up created fixtures
+	f.delete()
+}
@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type VendorRatingController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Scorecard(w http.ResponseWriter, r *http.Request)
+}
+
+type vendorRatingController struct {
+	service service.VendorRatingService
+}
+
+func NewVendorRatingController(service service.VendorRatingService) VendorRatingController {
+	return &vendorRatingController{service}
+}
+
+// API/VENDOR-RATINGS
+// Find a list of vendor ratings
+// @Summary      Find a list of vendor ratings
+// @Description  Accepts limit, offset, order, vendor and maintenance params and returns list of vendor ratings (newest first by default)
+// @Tags         Vendor Ratings
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        vendor   path      int  false  "vendor id"
+// @Param        maintenance   path      int  false  "maintenance request id"
+// @Success      200 {object} []db.VendorRating
+// @Failure      400 {string} string "Can't find vendor ratings"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /vendor-ratings [get]
+// @Security BearerToken
+func (c vendorRatingController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	vendorParam := r.URL.Query().Get("vendor")
+	maintenanceParam := r.URL.Query().Get("maintenance")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	vendorId, _ := strconv.Atoi(vendorParam)
+	maintenanceId, _ := strconv.Atoi(maintenanceParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all vendor ratings using query params
+	foundRatings, err := c.service.FindAll(limit, offset, orderBy, vendorId, maintenanceId)
+	if err != nil {
+		http.Error(w, "Can't find vendor ratings", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundRatings)
+	if err != nil {
+		http.Error(w, "Can't find vendor ratings", http.StatusBadRequest)
+		fmt.Println("error writing vendor ratings to response: ", err)
+		return
+	}
+}
+
+// Find a created vendor rating
+// @Summary      Find vendor rating
+// @Description  Find a vendor rating by ID
+// @Tags         Vendor Ratings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Rating ID"
+// @Success      200 {object} db.VendorRating
+// @Failure      400 {string} string "Can't find vendor rating with ID: {id}"
+// @Router       /vendor-ratings/{id} [get]
+// @Security BearerToken
+func (c vendorRatingController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundRating, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor rating with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundRating)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find vendor rating with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Rate a vendor's work on a maintenance request
+// @Summary      Create vendor rating
+// @Description  Rates quality, timeliness, price and communication (1 to 5) of a vendor that has completed a work order for the maintenance request
+// @Tags         Vendor Ratings
+// @Accept       json
+// @Produce      json
+// @Param        rating body models.CreateVendorRating true "New Vendor Rating Json"
+// @Success      201 {object} db.VendorRating
+// @Failure      400 {string} string "Vendor rating creation failed."
+// @Failure      409 {string} string "Vendor has no completed work order for the maintenance request"
+// @Failure      409 {string} string "Vendor has already been rated for the maintenance request"
+// @Router       /vendor-ratings [post]
+// @Security BearerToken
+func (c vendorRatingController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var rating models.CreateVendorRating
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&rating)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&rating)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Create vendor rating in db
+	createdRating, createErr := c.service.Create(userID, &rating)
+	if createErr != nil {
+		// If work isn't complete or already rated
+		if errors.Is(createErr, service.ErrRequestNotCompleted) || errors.Is(createErr, service.ErrAlreadyRated) {
+			http.Error(w, createErr.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Vendor rating creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created rating to output
+	err = helpers.WriteAsJSON(w, createdRating)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a vendor rating (using URL parameter id)
+// @Summary      Update vendor rating
+// @Description  Updates a vendor rating's scores and comment
+// @Tags         Vendor Ratings
+// @Accept       json
+// @Produce      json
+// @Param        rating body models.UpdateVendorRating true "Update Vendor Rating Json"
+// @Param        id   path      int  true  "Vendor Rating ID"
+// @Success      200 {object} db.VendorRating
+// @Failure      400 {string} string "Failed vendor rating update"
+// @Router       /vendor-ratings/{id} [put]
+// @Security BearerToken
+func (c vendorRatingController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var rating models.UpdateVendorRating
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&rating)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&rating)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update vendor rating
+	updatedRating, err := c.service.Update(idParameter, &rating)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor rating update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated rating to output
+	err = helpers.WriteAsJSON(w, updatedRating)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed vendor rating update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete vendor rating (using URL parameter id)
+// @Summary      Delete vendor rating
+// @Description  Deletes an existing vendor rating
+// @Tags         Vendor Ratings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor Rating ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed vendor rating deletion"
+// @Router       /vendor-ratings/{id} [delete]
+// @Security BearerToken
+func (c vendorRatingController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete vendor rating using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed vendor rating deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// API/VENDORS/{ID}/SCORECARD
+// Vendor scorecard (using URL parameter id)
+// @Summary      Vendor scorecard
+// @Description  Summarizes a vendor's average ratings overall and per work type, work order response and completion times, and invoiced cost variance against accepted quotes over a rolling period
+// @Tags         Vendor Ratings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Vendor ID"
+// @Param        months   path      int  false  "rolling period in months (1 to 60). Defaults to 12"
+// @Success      200 {object} models.VendorScorecard
+// @Failure      400 {string} string "Months must be between 1 and 60"
+// @Failure      400 {string} string "Can't build scorecard for vendor with ID: {id}"
+// @Router       /vendors/{id}/scorecard [get]
+// @Security BearerToken
+func (c vendorRatingController) Scorecard(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Rolling period defaults to a year
+	months := 12
+	monthsParam := r.URL.Query().Get("months")
+	if monthsParam != "" {
+		months, err = strconv.Atoi(monthsParam)
+		if err != nil || months < 1 || months > 60 {
+			http.Error(w, "Months must be between 1 and 60", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Build scorecard
+	scorecard, err := c.service.Scorecard(idParameter, time.Now().AddDate(0, -months, 0))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't build scorecard for vendor with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, scorecard)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't build scorecard for vendor with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
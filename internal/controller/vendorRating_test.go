@@ -0,0 +1,126 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestVendorRatingController_CreateAndScorecard(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	testConnection.dbClient.Model(&f.request).Update("vendor_id", f.vendors[0].ID)
+	// Accepted 2 hours and completed 24 hours after being issued
+	issuedAt := time.Now().Add(-48 * time.Hour)
+	acceptedAt := issuedAt.Add(2 * time.Hour)
+	completedAt := issuedAt.Add(24 * time.Hour)
+	createdOrder := db.WorkOrder{CreatedAt: issuedAt, Description: "Replace water heater", Status: "Completed", AcceptedAt: &acceptedAt, CompletedAt: &completedAt, MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, TaskID: f.task.ID}
+	testConnection.dbClient.Create(&createdOrder)
+	// Invoiced 10% above the accepted quote
+	createdQuote := db.VendorQuote{Status: "Accepted", Amount: db.NewMoney(1000000, "IDR"), MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID}
+	testConnection.dbClient.Create(&createdQuote)
+	createdInvoice := db.VendorInvoice{InvoiceNumber: "RATE-1", VendorNPWP: f.vendors[0].NPWP, InvoiceDate: time.Now(), DueDate: time.Now(), Subtotal: db.NewMoney(1100000, "IDR"), Total: db.NewMoney(1221000, "IDR"), Status: "Unpaid", VendorID: f.vendors[0].ID, MaintenanceRequestID: &f.request.ID}
+	testConnection.dbClient.Create(&createdInvoice)
+
+	var createTests = []struct {
+		data                   models.CreateVendorRating
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateVendorRating{Quality: 5, Timeliness: 3, Price: 2, Communication: 4, MaintenanceRequest: f.request}, testConnection.accounts.user.token, http.StatusForbidden, "basic user create test"},
+		{models.CreateVendorRating{Quality: 6, Timeliness: 3, Price: 2, Communication: 4, MaintenanceRequest: f.request}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin score out of range fail test"},
+		// Vendor hasn't completed any work on the request
+		{models.CreateVendorRating{Quality: 5, Timeliness: 3, Price: 2, Communication: 4, MaintenanceRequest: f.request, Vendor: f.vendors[1]}, testConnection.accounts.admin.token, http.StatusConflict, "admin incomplete work fail test"},
+		// Vendor defaults to the one assigned to the request
+		{models.CreateVendorRating{Quality: 5, Timeliness: 3, Price: 2, Communication: 4, Comment: "Good work but late", MaintenanceRequest: f.request}, testConnection.accounts.admin.token, http.StatusCreated, "admin create test"},
+		{models.CreateVendorRating{Quality: 5, Timeliness: 3, Price: 2, Communication: 4, MaintenanceRequest: f.request}, testConnection.accounts.admin.token, http.StatusConflict, "admin duplicate rating fail test"},
+	}
+
+	var createdRating db.VendorRating
+	for _, v := range createTests {
+		// Make new request with rating creation in body
+		req, err := http.NewRequest("POST", "/api/vendor-ratings", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor rating create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		if v.expectedResponseStatus == http.StatusCreated {
+			json.Unmarshal(rr.Body.Bytes(), &createdRating)
+			if createdRating.VendorID != f.vendors[0].ID || createdRating.UserID != testConnection.accounts.admin.details.ID {
+				t.Errorf("Vendor rating create test (%v): expected vendor %d rated by admin, got %v", v.testName, f.vendors[0].ID, createdRating)
+			}
+		}
+	}
+
+	var scorecardTests = []struct {
+		request                string
+		tokenToUse             string
+		expectedResponseStatus int
+	}{
+		{fmt.Sprintf("/api/vendors/%v/scorecard", f.vendors[0].ID), testConnection.accounts.user.token, http.StatusForbidden},
+		{fmt.Sprintf("/api/vendors/%v/scorecard?months=0", f.vendors[0].ID), testConnection.accounts.admin.token, http.StatusBadRequest},
+		{"/api/vendors/9999/scorecard", testConnection.accounts.admin.token, http.StatusBadRequest},
+		{fmt.Sprintf("/api/vendors/%v/scorecard?months=6", f.vendors[0].ID), testConnection.accounts.admin.token, http.StatusOK},
+	}
+
+	for _, v := range scorecardTests {
+		// Create a new request
+		req, err := http.NewRequest("GET", v.request, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+
+		// Use handler with recorder and created request
+		testConnection.router.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Vendor scorecard (%v): got %v want %v", v.request, status, v.expectedResponseStatus)
+		}
+		// Check scorecard
+		if v.expectedResponseStatus == http.StatusOK {
+			var scorecard models.VendorScorecard
+			json.Unmarshal(rr.Body.Bytes(), &scorecard)
+			if scorecard.Ratings != 1 || scorecard.Quality != 5 || scorecard.Price != 2 || scorecard.Overall != 3.5 {
+				t.Errorf("Vendor scorecard: expected 1 rating with overall 3.5, got %v", scorecard.VendorRatingAverages)
+			}
+			if scorecard.AverageResponseHours != 2 || scorecard.AverageCompletionHours != 24 {
+				t.Errorf("Vendor scorecard: expected response in 2 hours and completion in 24 hours, got %v and %v", scorecard.AverageResponseHours, scorecard.AverageCompletionHours)
+			}
+			if scorecard.QuotesCompared != 1 || scorecard.CostVariancePercent != 10 {
+				t.Errorf("Vendor scorecard: expected 10%% cost variance over 1 quote, got %v over %d", scorecard.CostVariancePercent, scorecard.QuotesCompared)
+			}
+			if len(scorecard.WorkTypes) != 1 || scorecard.WorkTypes[0].Name != f.workTypes[0].Name || scorecard.WorkTypes[0].Ratings != 1 {
+				t.Errorf("Vendor scorecard: expected 1 rating for %v, got %v", f.workTypes[0].Name, scorecard.WorkTypes)
+			}
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Unscoped().Delete(&createdRating)
+	testConnection.dbClient.Unscoped().Delete(&createdInvoice)
+	testConnection.dbClient.Delete(&createdOrder)
+	f.delete()
+}
@@ -381,23 +381,23 @@ func TestVendorController_Create(t *testing.T) {
 		// Should fail due to user role status of basic
 		{models.CreateVendor{
 			CompanyName: "PT widodo Jokowow",
-			NPWP:        "09098.2314.234.234",
+			NPWP:        "01.234.567.4-012.000",
 		}, http.StatusForbidden, testConnection.accounts.user.token, "basic user create"},
 		// Should pass as user is admin
 		{models.CreateVendor{
 			CompanyName: "PT widodo Grw",
-			NPWP:        "09098.2314.234.234",
+			NPWP:        "01.234.567.4-012.000",
 		}, http.StatusCreated, testConnection.accounts.admin.token, "admin create"},
 		// Should fail due to incorrect province value
 		{models.CreateVendor{
 			CompanyName: "PT widodo Grlkjfaww",
-			NPWP:        "09098.2314.234.234",
+			NPWP:        "01.234.567.4-012.000",
 			Province:    "Pantat",
 		}, http.StatusBadRequest, testConnection.accounts.admin.token, "admin create"},
 		// Create should be disallowed due to note being too long
 		{models.CreateVendor{
 			CompanyName: "PT widodo Slow",
-			NPWP:        "09098.2314.234.234",
+			NPWP:        "01.234.567.4-012.000",
 			Notes:       "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nislLorem ipsum dolor sit amet, consectetur adipiscing elit. Donec euismod, nisl eget ultricies ultricies, nisl nisl luctus nisl, vitae aliquam nisl",
 		}, http.StatusBadRequest, testConnection.accounts.admin.token, "invalid notes length create"},
 	}
@@ -451,6 +451,71 @@ func TestVendorController_Create(t *testing.T) {
 	}
 }
 
+func TestVendorController_NPWPValidation(t *testing.T) {
+	// Test setup
+	existingVendor := db.Vendor{CompanyName: "PT Sudah Terdaftar", NPWP: "012345674012000"}
+	testConnection.dbClient.Create(&existingVendor)
+
+	var createTests = []struct {
+		data                   models.CreateVendor
+		expectedResponseStatus int
+		testName               string
+	}{
+		// Check digit (9th digit) is incorrect
+		{models.CreateVendor{CompanyName: "PT Salah Cek", NPWP: "01.234.567.5-012.000"}, http.StatusBadRequest, "invalid check digit"},
+		{models.CreateVendor{CompanyName: "PT Terlalu Pendek", NPWP: "01.234.567.4-012"}, http.StatusBadRequest, "invalid length"},
+		{models.CreateVendor{CompanyName: "PT Huruf", NPWP: "01.234.567.4-012.00A"}, http.StatusBadRequest, "invalid characters"},
+		{models.CreateVendor{CompanyName: "PT Salah NIB", NPWP: "02.234.567.2-012.000", NIB: "12345"}, http.StatusBadRequest, "invalid NIB"},
+		// Same taxpayer in the 16 digit format
+		{models.CreateVendor{CompanyName: "PT Duplikat", NPWP: "0012345674012000"}, http.StatusConflict, "duplicate 16 digit NPWP"},
+		{models.CreateVendor{CompanyName: "PT Duplikat", NPWP: "01.234.567.4-012.000"}, http.StatusConflict, "duplicate formatted NPWP"},
+		// NIK used as NPWP by an individual
+		{models.CreateVendor{CompanyName: "Budi Tukang", NPWP: "5171011205800003", NIB: "9120 0012 3456 7"}, http.StatusCreated, "NIK as NPWP"},
+		{models.CreateVendor{CompanyName: "PT Baru", NPWP: "02.234.567.2-012.000", NIB: "1234567890123"}, http.StatusCreated, "formatted NPWP"},
+	}
+
+	for _, v := range createTests {
+		// Make new request with vendor creation in body
+		req, err := http.NewRequest("POST", "/api/vendors", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("NPWP validation test (%v): got %v want %v. \nBody: %v\n", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Formatting is removed before saving
+	var created []db.Vendor
+	testConnection.dbClient.Where("company_name IN ?", []string{"Budi Tukang", "PT Baru"}).Order("company_name ASC").Find(&created)
+	if len(created) != 2 || created[0].NIB != "9120001234567" || created[1].NPWP != "022345672012000" {
+		t.Errorf("NPWP normalisation: expected NPWP and NIB stored as digits, got %v", created)
+	}
+
+	// Updating another vendor to an existing NPWP conflicts
+	if len(created) == 2 {
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("/api/vendors/%v", created[1].ID), buildReqBody(models.UpdateVendor{NPWP: "01.234.567.4-012.000"}))
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+		rr := httptest.NewRecorder()
+		testConnection.router.ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("Duplicate NPWP update: got %v want %v", status, http.StatusConflict)
+		}
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Unscoped().Delete(append(created, existingVendor))
+}
+
 // Check the vendor details
 func checkVendorDetails(actual *db.Vendor, expected *db.Vendor, t *testing.T, checkId bool) {
 	// Only check ID if parameter checkId is true
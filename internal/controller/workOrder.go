@@ -0,0 +1,359 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dmawardi/Go-Template/internal/auth"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/service"
+	"github.com/go-chi/chi"
+)
+
+type WorkOrderController interface {
+	FindAll(w http.ResponseWriter, r *http.Request)
+	Find(w http.ResponseWriter, r *http.Request)
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	UpdateStatus(w http.ResponseWriter, r *http.Request)
+	Document(w http.ResponseWriter, r *http.Request)
+}
+
+type workOrderController struct {
+	service service.WorkOrderService
+}
+
+func NewWorkOrderController(service service.WorkOrderService) WorkOrderController {
+	return &workOrderController{service}
+}
+
+// API/WORK-ORDERS
+// Find a list of work orders
+// @Summary      Find a list of work orders
+// @Description  Accepts limit, offset, order, maintenance, vendor and status params and returns list of work orders
+// @Tags         Work Orders
+// @Accept       json
+// @Produce      json
+// @Param        limit   path      int  true  "limit"
+// @Param        offset   path      int  true  "offset"
+// @Param        order   path      int  true  "order by"
+// @Param        maintenance   path      int  false  "maintenance request id"
+// @Param        vendor   path      int  false  "vendor id"
+// @Param        status   path      string  false  "status"
+// @Success      200 {object} []db.WorkOrder
+// @Failure      400 {string} string "Can't find work orders"
+// @Failure      400 {string} string "Must include limit parameter with a max value of 50"
+// @Router       /work-orders [get]
+// @Security BearerToken
+func (c workOrderController) FindAll(w http.ResponseWriter, r *http.Request) {
+	// Grab URL query parameters
+	limitParam := r.URL.Query().Get("limit")
+	offsetParam := r.URL.Query().Get("offset")
+	orderBy := r.URL.Query().Get("order")
+	maintenanceParam := r.URL.Query().Get("maintenance")
+	vendorParam := r.URL.Query().Get("vendor")
+	status := r.URL.Query().Get("status")
+
+	// Convert to int
+	limit, _ := strconv.Atoi(limitParam)
+	offset, _ := strconv.Atoi(offsetParam)
+	maintenanceId, _ := strconv.Atoi(maintenanceParam)
+	vendorId, _ := strconv.Atoi(vendorParam)
+
+	// Check that limit is present as requirement
+	if (limit == 0) || (limit >= 50) {
+		http.Error(w, "Must include limit parameter with a max value of 50", http.StatusBadRequest)
+		return
+	}
+
+	// Query database for all work orders using query params
+	foundOrders, err := c.service.FindAll(limit, offset, orderBy, maintenanceId, vendorId, status)
+	if err != nil {
+		http.Error(w, "Can't find work orders", http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundOrders)
+	if err != nil {
+		http.Error(w, "Can't find work orders", http.StatusBadRequest)
+		fmt.Println("error writing work orders to response: ", err)
+		return
+	}
+}
+
+// Find a created work order
+// @Summary      Find work order
+// @Description  Find a work order by ID
+// @Tags         Work Orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Work Order ID"
+// @Success      200 {object} db.WorkOrder
+// @Failure      400 {string} string "Can't find work order with ID: {id}"
+// @Router       /work-orders/{id} [get]
+// @Security BearerToken
+func (c workOrderController) Find(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	foundOrder, err := c.service.FindById(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find work order with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+	err = helpers.WriteAsJSON(w, foundOrder)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't find work order with ID: %v.\n %v", idParameter, err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Issue a new work order
+// @Summary      Issue a work order
+// @Description  Issues a work order to a vendor for a maintenance request. Vendor and cost default to those of the maintenance request
+// @Tags         Work Orders
+// @Accept       json
+// @Produce      json
+// @Param        order body models.CreateWorkOrder true "New Work Order Json"
+// @Success      201 {object} db.WorkOrder
+// @Failure      400 {string} string "Work order creation failed."
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Router       /work-orders [post]
+// @Security BearerToken
+func (c workOrderController) Create(w http.ResponseWriter, r *http.Request) {
+	// Init
+	var order models.CreateWorkOrder
+	// Decode request body as JSON and store
+	err := json.NewDecoder(r.Body).Decode(&order)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&order)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Create work order in db
+	createdOrder, createErr := c.service.Create(userID, &order)
+	if createErr != nil {
+		http.Error(w, "Work order creation failed: "+createErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Set status to created
+	w.WriteHeader(http.StatusCreated)
+	// Write created work order to output
+	err = helpers.WriteAsJSON(w, createdOrder)
+	if err != nil {
+		fmt.Printf("Error encountered when writing to JSON. Err: %s", err)
+	}
+}
+
+// Update a work order (using URL parameter id)
+// @Summary      Update work order
+// @Description  Updates a work order's details. Status is changed through the status endpoint
+// @Tags         Work Orders
+// @Accept       json
+// @Produce      json
+// @Param        order body models.UpdateWorkOrder true "Update Work Order Json"
+// @Param        id   path      int  true  "Work Order ID"
+// @Success      200 {object} db.WorkOrder
+// @Failure      400 {string} string "Failed work order update"
+// @Router       /work-orders/{id} [put]
+// @Security BearerToken
+func (c workOrderController) Update(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var order models.UpdateWorkOrder
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&order)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&order)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Update work order
+	updatedOrder, err := c.service.Update(idParameter, &order)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed work order update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated work order to output
+	err = helpers.WriteAsJSON(w, updatedOrder)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed work order update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Delete work order (using URL parameter id)
+// @Summary      Delete work order
+// @Description  Deletes an existing work order
+// @Tags         Work Orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Work Order ID"
+// @Success      200 {string} string "Deletion successful!"
+// @Failure      400 {string} string "Failed work order deletion"
+// @Router       /work-orders/{id} [delete]
+// @Security BearerToken
+func (c workOrderController) Delete(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, _ := strconv.Atoi(stringParameter)
+
+	// Attempt to delete work order using id
+	err := c.service.Delete(idParameter)
+
+	// If error detected
+	if err != nil {
+		http.Error(w, "Failed work order deletion", http.StatusBadRequest)
+		return
+	}
+	// Else write success
+	w.Write([]byte("Deletion successful!"))
+}
+
+// Change a work order's status (using URL parameter id)
+// @Summary      Update work order status
+// @Description  Moves a work order to the next status in its lifecycle (Issued, Accepted, Scheduled, In Progress, Completed, Invoiced, Paid) and logs the change on the parent task. Scheduling requires a visit window
+// @Tags         Work Orders
+// @Accept       json
+// @Produce      json
+// @Param        status body models.UpdateWorkOrderStatus true "Work Order Status Json"
+// @Param        id   path      int  true  "Work Order ID"
+// @Success      200 {object} db.WorkOrder
+// @Failure      400 {string} string "Failed work order status update"
+// @Failure      403 {string} string "Authentication Token not detected"
+// @Failure      409 {string} string "Work orders can only move to the next status in their lifecycle"
+// @Router       /work-orders/status/{id} [put]
+// @Security BearerToken
+func (c workOrderController) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	// Init
+	var status models.UpdateWorkOrderStatus
+	// Decode request body as JSON and store
+	err = json.NewDecoder(r.Body).Decode(&status)
+	if err != nil {
+		fmt.Println("Decoding error: ", err)
+	}
+
+	// Validate the incoming DTO
+	pass, valErrors := helpers.GoValidateStruct(&status)
+	// If failure detected
+	if !pass {
+		// Write bad request header
+		w.WriteHeader(http.StatusBadRequest)
+		// Write validation errors to JSON
+		helpers.WriteAsJSON(w, valErrors)
+		return
+	}
+	// else, validation passes and allow through
+
+	// Grab user id from token
+	userID, err := auth.GetUserIDFromToken(w, r)
+	if err != nil {
+		http.Error(w, "Authentication Token not detected", http.StatusForbidden)
+		return
+	}
+
+	// Update work order status
+	updatedOrder, err := c.service.UpdateStatus(idParameter, userID, &status)
+	if err != nil {
+		// If status change skips or reverses lifecycle
+		if errors.Is(err, service.ErrInvalidWorkOrderTransition) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed work order status update: %s", err), http.StatusBadRequest)
+		return
+	}
+	// Write updated work order to output
+	err = helpers.WriteAsJSON(w, updatedOrder)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed work order status update: %s", err), http.StatusBadRequest)
+		return
+	}
+}
+
+// Printable work order document (using URL parameter id)
+// @Summary      Work order document
+// @Description  Renders a printable HTML work order for the vendor
+// @Tags         Work Orders
+// @Produce      html
+// @Param        id   path      int  true  "Work Order ID"
+// @Success      200 {string} string "Work order document"
+// @Failure      400 {string} string "Can't build work order document"
+// @Router       /work-orders/document/{id} [get]
+// @Security BearerToken
+func (c workOrderController) Document(w http.ResponseWriter, r *http.Request) {
+	// Grab URL parameter
+	stringParameter := chi.URLParam(r, "id")
+	// Convert to int
+	idParameter, err := strconv.Atoi(stringParameter)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	document, err := c.service.Document(idParameter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't build work order document: %s", err), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(document)
+}
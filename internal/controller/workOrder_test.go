@@ -0,0 +1,160 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+)
+
+func TestWorkOrderController_Create(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+
+	var createTests = []struct {
+		data                   models.CreateWorkOrder
+		tokenToUse             string
+		assignVendor           bool
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.CreateWorkOrder{MaintenanceRequest: f.request, Description: "Replace water heater element"}, testConnection.accounts.user.token, false, http.StatusForbidden, "basic user create test"},
+		// Request has no vendor assigned yet
+		{models.CreateWorkOrder{MaintenanceRequest: f.request, Description: "Replace water heater element"}, testConnection.accounts.admin.token, false, http.StatusBadRequest, "admin no vendor fail test"},
+		{models.CreateWorkOrder{MaintenanceRequest: f.request, Description: "Replace water heater element"}, testConnection.accounts.admin.token, true, http.StatusCreated, "admin assigned vendor create test"},
+		{models.CreateWorkOrder{MaintenanceRequest: f.request, Vendor: f.vendors[1], Description: "Flush the pipes"}, testConnection.accounts.admin.token, false, http.StatusCreated, "admin selected vendor create test"},
+		{models.CreateWorkOrder{MaintenanceRequest: f.request, Description: "Bad window", ScheduledStart: time.Now().Add(2 * time.Hour), ScheduledEnd: time.Now()}, testConnection.accounts.admin.token, false, http.StatusBadRequest, "admin invalid window fail test"},
+		{models.CreateWorkOrder{MaintenanceRequest: f.request, Description: ""}, testConnection.accounts.admin.token, false, http.StatusBadRequest, "admin missing description fail test"},
+	}
+
+	for _, v := range createTests {
+		if v.assignVendor {
+			testConnection.dbClient.Model(&f.request).Updates(map[string]interface{}{"vendor_id": f.vendors[0].ID, "total_cost_amount": 250000000, "total_cost_currency": "IDR"})
+		}
+		// Make new request with work order creation in body
+		req, err := http.NewRequest("POST", "/api/work-orders", buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send create request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Work order create test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+		// Check defaults and task link
+		if v.expectedResponseStatus == http.StatusCreated {
+			var body db.WorkOrder
+			json.Unmarshal(rr.Body.Bytes(), &body)
+			if body.Status != "Issued" || body.TaskID != f.task.ID || body.Cost.Float() != 2500000 {
+				t.Errorf("Work order create test (%v): expected issued work order on task %d costing 2500000, got %v", v.testName, f.task.ID, body)
+			}
+		}
+	}
+
+	// Issuing is logged on the parent task
+	var logCount int64
+	testConnection.dbClient.Model(&db.TaskLog{}).Where("task_id = ?", f.task.ID).Count(&logCount)
+	if logCount != 2 {
+		t.Errorf("Work order create: expected 2 task logs, got %d", logCount)
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id = ?", f.task.ID).Delete(&db.TaskLog{})
+	testConnection.dbClient.Where("maintenance_request_id = ?", f.request.ID).Delete(&db.WorkOrder{})
+	f.delete()
+}
+
+func TestWorkOrderController_UpdateStatus(t *testing.T) {
+	// Test setup
+	f := createVendorQuoteFixtures(t)
+	createdOrder := db.WorkOrder{Description: "Replace water heater element", Status: "Issued", MaintenanceRequestID: f.request.ID, VendorID: f.vendors[0].ID, TaskID: f.task.ID, Cost: db.NewMoney(2500000, "IDR")}
+	if result := testConnection.dbClient.Create(&createdOrder); result.Error != nil {
+		t.Fatal("Failed to create work order for status test: ", result.Error)
+	}
+
+	visitStart := time.Now().AddDate(0, 0, 2)
+	var statusTests = []struct {
+		data                   models.UpdateWorkOrderStatus
+		tokenToUse             string
+		expectedResponseStatus int
+		testName               string
+	}{
+		{models.UpdateWorkOrderStatus{Status: "Accepted"}, testConnection.accounts.user.token, http.StatusForbidden, "basic user status test"},
+		// Statuses can't be skipped
+		{models.UpdateWorkOrderStatus{Status: "In Progress"}, testConnection.accounts.admin.token, http.StatusConflict, "admin skip status fail test"},
+		{models.UpdateWorkOrderStatus{Status: "Accepted"}, testConnection.accounts.admin.token, http.StatusOK, "admin accepted test"},
+		// Visit window required to schedule
+		{models.UpdateWorkOrderStatus{Status: "Scheduled"}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin schedule without window fail test"},
+		{models.UpdateWorkOrderStatus{Status: "Scheduled", ScheduledStart: visitStart, ScheduledEnd: visitStart.Add(3 * time.Hour)}, testConnection.accounts.admin.token, http.StatusOK, "admin scheduled test"},
+		{models.UpdateWorkOrderStatus{Status: "Scheduled", ScheduledStart: visitStart.AddDate(0, 0, 1), ScheduledEnd: visitStart.AddDate(0, 0, 1).Add(3 * time.Hour)}, testConnection.accounts.admin.token, http.StatusOK, "admin rescheduled test"},
+		{models.UpdateWorkOrderStatus{Status: "In Progress"}, testConnection.accounts.admin.token, http.StatusOK, "admin in progress test"},
+		{models.UpdateWorkOrderStatus{Status: "Completed"}, testConnection.accounts.admin.token, http.StatusOK, "admin completed test"},
+		// Statuses can't be reversed
+		{models.UpdateWorkOrderStatus{Status: "Issued"}, testConnection.accounts.admin.token, http.StatusConflict, "admin reverse status fail test"},
+		{models.UpdateWorkOrderStatus{Status: "Done"}, testConnection.accounts.admin.token, http.StatusBadRequest, "admin invalid status fail test"},
+	}
+
+	requestUrl := fmt.Sprintf("/api/work-orders/status/%v", createdOrder.ID)
+	for _, v := range statusTests {
+		// Make new request with status in body
+		req, err := http.NewRequest("PUT", requestUrl, buildReqBody(v.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Create a response recorder
+		rr := httptest.NewRecorder()
+		// Add auth token to header
+		req.Header.Set("Authorization", fmt.Sprintf("bearer %v", v.tokenToUse))
+
+		// Send status request to mock server
+		testConnection.router.ServeHTTP(rr, req)
+		// Check response is as expected
+		if status := rr.Code; status != v.expectedResponseStatus {
+			t.Errorf("Work order status test (%v): got %v want %v. %v", v.testName,
+				status, v.expectedResponseStatus, rr.Body.String())
+		}
+	}
+
+	// Check completion was recorded
+	var foundOrder db.WorkOrder
+	testConnection.dbClient.First(&foundOrder, createdOrder.ID)
+	if foundOrder.Status != "Completed" || foundOrder.CompletedAt == nil || foundOrder.ScheduledStart == nil {
+		t.Errorf("Work order status: expected completed work order with visit window, got %v", foundOrder)
+	}
+	// Each status change is logged on the parent task
+	var logCount int64
+	testConnection.dbClient.Model(&db.TaskLog{}).Where("task_id = ? AND type = ?", f.task.ID, "GEN").Count(&logCount)
+	if logCount != 5 {
+		t.Errorf("Work order status: expected 5 task logs, got %d", logCount)
+	}
+
+	// Check printable document
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/work-orders/document/%v", createdOrder.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %v", testConnection.accounts.admin.token))
+	rr := httptest.NewRecorder()
+	testConnection.router.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Work order document: got %v want %v", status, http.StatusOK)
+	}
+	if !strings.Contains(rr.Header().Get("Content-Type"), "text/html") || !strings.Contains(rr.Body.String(), f.vendors[0].CompanyName) {
+		t.Errorf("Work order document: expected HTML document for %s, got %v", f.vendors[0].CompanyName, rr.Header().Get("Content-Type"))
+	}
+
+	// Clean up created fixtures
+	testConnection.dbClient.Where("task_id = ?", f.task.ID).Delete(&db.TaskLog{})
+	testConnection.dbClient.Delete(&createdOrder)
+	f.delete()
+}
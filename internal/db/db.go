@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -27,18 +28,59 @@ func DbConnect() *gorm.DB {
 	db.AutoMigrate(&User{})
 	db.AutoMigrate(&Property{})
 	db.AutoMigrate(&Feature{})
+	db.AutoMigrate(&Unit{})
 	db.AutoMigrate(&PropertyLog{})
 	db.AutoMigrate(&Contact{})
 	db.AutoMigrate(&Task{})
 	db.AutoMigrate(&TaskLog{})
+	db.AutoMigrate(&TaskChecklistItem{})
 	db.AutoMigrate(&Transaction{})
 	db.AutoMigrate(&WorkType{})
 	db.AutoMigrate(&MaintenanceRequest{})
 	db.AutoMigrate(&Vendor{})
 	db.AutoMigrate(&PropertyAttachment{})
+	db.AutoMigrate(&TaskComment{})
+	db.AutoMigrate(&TaskCommentEdit{})
+	db.AutoMigrate(&Notification{})
+	db.AutoMigrate(&NotificationPreference{})
+	db.AutoMigrate(&NotificationDeadLetter{})
+	db.AutoMigrate(&TaskDependency{})
+	db.AutoMigrate(&TimeEntry{})
+	db.AutoMigrate(&VendorQuote{})
+	db.AutoMigrate(&WorkOrder{})
+	db.AutoMigrate(&VendorInvoice{})
+	db.AutoMigrate(&VendorInvoiceLine{})
+	db.AutoMigrate(&VendorPayment{})
+	db.AutoMigrate(&VendorRating{})
+	db.AutoMigrate(&VendorDocument{})
+	db.AutoMigrate(&MaintenanceBudget{})
+	db.AutoMigrate(&Lease{})
+	db.AutoMigrate(&RentScheduleItem{})
+	db.AutoMigrate(&LedgerEntry{})
+	db.AutoMigrate(&OwnerStatement{})
+	db.AutoMigrate(&OwnerStatementLine{})
+	db.AutoMigrate(&CommissionScheme{})
+	db.AutoMigrate(&CommissionTier{})
+	db.AutoMigrate(&CommissionSplit{})
+	db.AutoMigrate(&PipelineStage{})
+	db.AutoMigrate(&TransactionStageChange{})
+	db.AutoMigrate(&TransactionMilestone{})
+	db.AutoMigrate(&ExchangeRate{})
+	db.AutoMigrate(&TaxRule{})
+	db.AutoMigrate(&TaxLine{})
+	// Move float amounts into money columns
+	migrateMoneyColumns(db)
+	// Strip formatting from vendor NPWPs stored before they were normalised
+	normalizeVendorNPWPs(db)
+	// Clear the zero read times stored on unread notifications
+	clearUnreadNotificationTimes(db)
 
 	// Build basic work types
 	buildBasicWorkTypes(db)
+	// Build basic sale and lease pipelines
+	buildBasicPipelineStages(db)
+	// Build standard Indonesian tax rules
+	buildBasicTaxRules(db)
 
 	return db
 }
@@ -62,3 +104,34 @@ func buildBasicWorkTypes(db *gorm.DB) {
 	}
 	createWorkOrderIfNotExist(workTypes, db)
 }
+
+func buildBasicPipelineStages(db *gorm.DB) {
+	// Build basic pipeline stages of sales and leases
+	for _, transactionType := range []string{"Sale", "Lease"} {
+		stages := []PipelineStage{
+			{Name: "Enquiry", Position: 1, DueDays: 7},
+			{Name: "Viewing", Position: 2, DueDays: 14},
+			{Name: "Offer", Position: 3, DueDays: 7},
+			{Name: "Deposit", Position: 4, DueDays: 7},
+			{Name: "Contract", Position: 5, DueDays: 30},
+			{Name: "Settlement", Position: 6, Completes: true},
+		}
+		for i := range stages {
+			stages[i].TransactionType = transactionType
+		}
+		createPipelineIfNotExist(transactionType, stages, db)
+	}
+}
+
+func buildBasicTaxRules(db *gorm.DB) {
+	// PPN at 11% since April 2022, PPh 4(2) final tax of 10% on land and building rent and PPh 23 of 2% withheld from services
+	ppnDate := time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)
+	pphDate := time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rules := []TaxRule{
+		{Tax: "PPN", AppliesTo: "Commission", Rate: 11, EffectiveDate: ppnDate},
+		{Tax: "PPN", AppliesTo: "Vendor Invoice", Rate: 11, EffectiveDate: ppnDate},
+		{Tax: "PPh 4(2)", AppliesTo: "Rent", Rate: 10, EffectiveDate: pphDate},
+		{Tax: "PPh 23", AppliesTo: "Vendor Invoice", Rate: 2, Withheld: true, EffectiveDate: pphDate},
+	}
+	createTaxRulesIfNotExist(rules, db)
+}
@@ -1,6 +1,11 @@
 package db
 
-import "gorm.io/gorm"
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
 
 // Check if an item with a specific name exists
 func workOrderExists(name string, db *gorm.DB) bool {
@@ -25,3 +30,62 @@ func createWorkOrderIfNotExist(workOrders []WorkType, db *gorm.DB) {
 		}
 	}
 }
+
+// Create the stages of a transaction type's pipeline only if it has none
+func createPipelineIfNotExist(transactionType string, stages []PipelineStage, db *gorm.DB) {
+	var count int64
+	db.Model(&PipelineStage{}).Where("transaction_type = ?", transactionType).Count(&count)
+	if count > 0 {
+		return
+	}
+	for _, stage := range stages {
+		result := db.Create(&stage)
+		if result.Error != nil {
+			panic("failed to create default pipeline stages")
+		}
+	}
+}
+
+// Create the tax rules only if none have been configured
+func createTaxRulesIfNotExist(rules []TaxRule, db *gorm.DB) {
+	var count int64
+	db.Model(&TaxRule{}).Unscoped().Count(&count)
+	if count > 0 {
+		return
+	}
+	for _, rule := range rules {
+		result := db.Create(&rule)
+		if result.Error != nil {
+			panic("failed to create default tax rules")
+		}
+	}
+}
+
+// Rewrite vendor NPWPs that still contain dots, dashes or spaces as digits only
+func normalizeVendorNPWPs(db *gorm.DB) {
+	var vendors []Vendor
+	db.Unscoped().Select("id", "npwp").Find(&vendors)
+	for _, vendor := range vendors {
+		digits := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, vendor.NPWP)
+		if digits == vendor.NPWP {
+			continue
+		}
+		result := db.Unscoped().Model(&Vendor{}).Where("id = ?", vendor.ID).Update("npwp", digits)
+		if result.Error != nil {
+			panic("failed to normalize vendor NPWPs")
+		}
+	}
+}
+
+// Unread notifications have no read time
+func clearUnreadNotificationTimes(db *gorm.DB) {
+	result := db.Unscoped().Model(&Notification{}).Where("read = ? AND read_at IS NOT NULL", false).Update("read_at", nil)
+	if result.Error != nil {
+		panic("failed to clear read times of unread notifications")
+	}
+}
@@ -0,0 +1,274 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Currency amounts are recorded in when none is provided
+const DefaultCurrency = "IDR"
+
+// Returned when adding amounts in different currencies
+var ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+
+// ISO 4217 currencies amounts can be recorded in, with their number of minor unit digits
+var currencyDigits = map[string]int{
+	"IDR": 2,
+	"USD": 2,
+	"AUD": 2,
+}
+
+// Checks that a currency code is one amounts can be recorded in
+func IsCurrency(code string) bool {
+	_, ok := currencyDigits[code]
+	return ok
+}
+
+// Monetary amount in minor units (eg. cents) of an ISO 4217 currency. Stored as an embedded
+// amount and currency column pair, eg. `gorm:"embedded;embeddedPrefix:fee_"`.
+// Formatted in JSON as {"amount": "2400000.00", "currency": "IDR"}, or null if no amount has been set
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0"`
+	Currency string `gorm:"size:3" valid:"currency"`
+}
+
+// Money from an amount in major units (eg. dollars), rounded to the currency's minor units
+func NewMoney(amount float64, currency string) Money {
+	m := Money{Currency: currency}
+	m.Amount = int64(math.Round(amount * m.scale()))
+	return m
+}
+
+// Currency code of the amount, the default currency if none is set
+func (m Money) Code() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// Checks that two amounts are in the same currency and can be added. Zero amounts and amounts
+// without a currency can be added to any currency
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency || m.Currency == "" || other.Currency == "" || m.IsZero() || other.IsZero()
+}
+
+// Sum of amounts, failing if they are in different currencies
+func Sum(amounts ...Money) (Money, error) {
+	total := Money{}
+	for _, amount := range amounts {
+		if !total.SameCurrency(amount) {
+			return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, total.Code(), amount.Code())
+		}
+		total = total.Add(amount)
+	}
+	return total, nil
+}
+
+// Checks that two amounts are equal and in the same currency. Amounts without a currency are in the default currency
+func (m Money) Equal(other Money) bool {
+	return m.Amount == other.Amount && m.Code() == other.Code()
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Amount in major units (eg. dollars). For rates and ratios only, amounts should be added as Money
+func (m Money) Float() float64 {
+	return float64(m.Amount) / m.scale()
+}
+
+// Sum of two amounts in the currency of the first (or the second if the first is zero or has none)
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.pick(other)}
+}
+
+// Difference of two amounts in the currency of the first (or the second if the first is zero or has none)
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.pick(other)}
+}
+
+// Amount without its sign
+func (m Money) Abs() Money {
+	if m.Amount < 0 {
+		return Money{Amount: -m.Amount, Currency: m.Currency}
+	}
+	return m
+}
+
+// Amount multiplied by a factor, rounded to the currency's minor units
+func (m Money) Mul(factor float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * factor)), Currency: m.Currency}
+}
+
+// Percentage of the amount, rounded to the currency's minor units
+func (m Money) Percent(rate float64) Money {
+	return m.Mul(rate / 100)
+}
+
+// Amount in major units with the currency's number of decimals, eg. 2400000.50
+func (m Money) String() string {
+	digits := currencyDigits[m.Code()]
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	units := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + units
+	}
+	if len(units) <= digits {
+		units = strings.Repeat("0", digits+1-len(units)) + units
+	}
+	return sign + units[:len(units)-digits] + "." + units[len(units)-digits:]
+}
+
+// Amount with currency code and thousands separators for documents, eg. IDR 2,400,000.50
+func (m Money) Format() string {
+	value := m.String()
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", value[1:]
+	}
+	units, decimals, _ := strings.Cut(value, ".")
+	for i := len(units) - 3; i > 0; i -= 3 {
+		units = units[:i] + "," + units[i:]
+	}
+	if decimals != "" {
+		units += "." + decimals
+	}
+	return fmt.Sprintf("%s %s%s", m.Code(), sign, units)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m == (Money{}) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.String(), m.Code()})
+}
+
+// Accepts the amount as a decimal string or number. The currency defaults to the default currency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	// Unsupported currencies are rejected by validation
+	m.Currency = strings.ToUpper(value.Currency)
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	amount := string(bytes.Trim(value.Amount, `"`))
+	if amount == "" || amount == "null" {
+		m.Amount = 0
+		return nil
+	}
+	minor, err := parseMinorUnits(amount, currencyDigits[m.Currency])
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", amount, err)
+	}
+	m.Amount = minor
+	return nil
+}
+
+// Currency of the result of adding two amounts
+func (m Money) pick(other Money) string {
+	if m.Currency == "" || m.IsZero() && other.Currency != "" {
+		return other.Currency
+	}
+	return m.Currency
+}
+
+// Minor units per major unit of the currency
+func (m Money) scale() float64 {
+	return math.Pow10(currencyDigits[m.Code()])
+}
+
+// Parses a decimal amount in major units into minor units without floating point rounding
+func parseMinorUnits(amount string, digits int) (int64, error) {
+	// Numbers in exponent notation are rounded to the minor units
+	if strings.ContainsAny(amount, "eE") {
+		value, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return 0, err
+		}
+		return int64(math.Round(value * math.Pow10(digits))), nil
+	}
+	units, decimals, _ := strings.Cut(amount, ".")
+	if len(decimals) > digits {
+		return 0, fmt.Errorf("more than %d decimals", digits)
+	}
+	decimals += strings.Repeat("0", digits-len(decimals))
+	negative := strings.HasPrefix(units, "-")
+	minor, err := strconv.ParseInt(strings.TrimPrefix(units, "-")+decimals, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		minor = -minor
+	}
+	return minor, nil
+}
+
+// Float amount columns replaced by money columns, by model
+var floatMoneyColumns = []struct {
+	model   interface{}
+	columns []string
+}{
+	{&Transaction{}, []string{"transaction_value", "fee", "own_agency_fee", "co_agency_fee"}},
+	{&MaintenanceRequest{}, []string{"total_cost", "tax"}},
+	{&VendorQuote{}, []string{"amount", "tax"}},
+	{&WorkOrder{}, []string{"cost", "tax"}},
+	{&VendorInvoice{}, []string{"subtotal", "ppn", "total", "amount_paid"}},
+	{&VendorInvoiceLine{}, []string{"unit_price", "amount"}},
+	{&VendorPayment{}, []string{"amount"}},
+	{&MaintenanceBudget{}, []string{"amount"}},
+	{&Lease{}, []string{"rent_amount", "deposit", "late_fee"}},
+	{&RentScheduleItem{}, []string{"amount"}},
+	{&LedgerEntry{}, []string{"debit", "credit"}},
+	{&OwnerStatement{}, []string{"opening_balance", "rent_received", "management_fees", "maintenance_costs", "vendor_invoices", "net_payable", "paid_to_owner", "closing_balance"}},
+	{&OwnerStatementLine{}, []string{"amount"}},
+	{&CommissionScheme{}, []string{"flat_fee"}},
+	{&CommissionTier{}, []string{"up_to"}},
+	{&CommissionSplit{}, []string{"amount"}},
+}
+
+// Moves amounts of float columns into their money columns in the default currency, then drops the float columns.
+// Amounts recorded before currencies were tracked are assumed to be rupiah
+func migrateMoneyColumns(db *gorm.DB) {
+	for _, table := range floatMoneyColumns {
+		for _, column := range table.columns {
+			if !db.Migrator().HasColumn(table.model, column) {
+				continue
+			}
+			result := db.Unscoped().Model(table.model).Where(column + " IS NOT NULL").Updates(map[string]interface{}{
+				column + "_amount":   gorm.Expr(fmt.Sprintf("ROUND(%s * %v)", column, math.Pow10(currencyDigits[DefaultCurrency]))),
+				column + "_currency": DefaultCurrency,
+			})
+			if result.Error != nil {
+				panic(fmt.Sprintf("failed to migrate %s amounts: %v", column, result.Error))
+			}
+			err := db.Migrator().DropColumn(table.model, column)
+			if err != nil {
+				panic(fmt.Sprintf("failed to drop %s column: %v", column, err))
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package db_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+func TestMoneyJSON(t *testing.T) {
+	var testTable = []struct {
+		name     string
+		data     string
+		expected db.Money
+		isErr    bool
+	}{
+		{"decimal-string", `{"amount": "2400000.50", "currency": "IDR"}`, db.Money{Amount: 240000050, Currency: "IDR"}, false},
+		{"number", `{"amount": 19.99, "currency": "usd"}`, db.Money{Amount: 1999, Currency: "USD"}, false},
+		{"default-currency", `{"amount": "150"}`, db.Money{Amount: 15000, Currency: "IDR"}, false},
+		{"negative", `{"amount": "-0.05", "currency": "AUD"}`, db.Money{Amount: -5, Currency: "AUD"}, false},
+		{"exponent", `{"amount": 1.5e6, "currency": "IDR"}`, db.Money{Amount: 150000000, Currency: "IDR"}, false},
+		{"null", `null`, db.Money{}, false},
+		{"too-many-decimals", `{"amount": "1.005", "currency": "USD"}`, db.Money{}, true},
+		{"not-a-number", `{"amount": "ten", "currency": "USD"}`, db.Money{}, true},
+	}
+
+	for _, v := range testTable {
+		var amount db.Money
+		err := json.Unmarshal([]byte(v.data), &amount)
+		if (err != nil) != v.isErr {
+			t.Errorf("%v: expected error %v, got %v", v.name, v.isErr, err)
+			continue
+		}
+		if !v.isErr && amount != v.expected {
+			t.Errorf("%v: expected %+v, got %+v", v.name, v.expected, amount)
+		}
+	}
+
+	// Amounts are formatted with the currency's decimals
+	data, _ := json.Marshal(db.NewMoney(2400000.5, "IDR"))
+	if string(data) != `{"amount":"2400000.50","currency":"IDR"}` {
+		t.Errorf("Marshal: got %s", data)
+	}
+	data, _ = json.Marshal(db.Money{})
+	if string(data) != "null" {
+		t.Errorf("Marshal unset amount: expected null, got %s", data)
+	}
+	if formatted := db.NewMoney(-1234567.8, "AUD").Format(); formatted != "AUD -1,234,567.80" {
+		t.Errorf("Format: got %v", formatted)
+	}
+}
+
+func TestMoneySum(t *testing.T) {
+	total, err := db.Sum(db.Money{}, db.NewMoney(0.1, "USD"), db.NewMoney(0.2, "USD"), db.NewMoney(0, "IDR"))
+	if err != nil || !total.Equal(db.NewMoney(0.3, "USD")) {
+		t.Errorf("Sum: expected USD 0.30, got %v %v", total.Format(), err)
+	}
+	_, err = db.Sum(db.NewMoney(10, "USD"), db.NewMoney(10, "AUD"))
+	if !errors.Is(err, db.ErrCurrencyMismatch) {
+		t.Errorf("Sum of currencies: expected a currency mismatch, got %v", err)
+	}
+	if share := db.NewMoney(100, "IDR").Percent(33.333); share.Amount != 3333 {
+		t.Errorf("Percent: expected 33.33, got %v", share)
+	}
+}
@@ -19,6 +19,8 @@ type User struct {
 	Email     string         `json:"email,omitempty" gorm:"uniqueIndex"`
 	Password  string         `json:"-"`
 	Role      string         `json:"role,omitempty" gorm:"default:user"`
+	// Currency the user's financial reports are shown in. The default currency if not set
+	ReportingCurrency string `json:"reporting_currency,omitempty" gorm:"size:3;default:null"`
 	// Foreign keys
 	PropertyLogs []PropertyLog `json:"property_logs"`
 	TaskLogs     []TaskLog     `json:"task_logs"`
@@ -35,6 +37,7 @@ type Property struct {
 	Property_Name    string         `json:"property_name" gorm:"not null;uniqueIndex"`
 	Suburb           string         `json:"suburb"`
 	City             string         `json:"city"`
+	Province         string         `json:"province" gorm:"not null;default:Bali"`
 	Street_Address_1 string         `json:"street_address_1"`
 	Street_Address_2 string         `json:"street_address_2"`
 	Bedrooms         float32        `json:"bedrooms"`
@@ -44,9 +47,13 @@ type Property struct {
 	Description      string         `json:"description"`
 	Notes            string         `json:"notes"`
 	Managed          bool           `json:"managed" gorm:"default:false"`
+	// Token identifying the property on the public tenant portal
+	PortalToken *string `json:"portal_token,omitempty" gorm:"uniqueIndex"`
 	// One to many
 	PropertyLogs []PropertyLog `json:"property_logs" gorm:"foreignKey:PropertyID"`
 	Transactions []Transaction `json:"transactions" gorm:"foreignKey:PropertyID"`
+	// Rentable units of multi-unit properties (eg. villas in a complex or shophouse floors)
+	Units []Unit `json:"units,omitempty" gorm:"foreignKey:PropertyID"`
 
 	// Many to many
 	Features    []Feature            `json:"features" gorm:"many2many:prop_features"`
@@ -54,6 +61,26 @@ type Property struct {
 	Attachments []PropertyAttachment `json:"attachments" gorm:"foreignKey:PropertyID"`
 }
 
+// Rentable unit of a multi-unit property
+type Unit struct {
+	ID          uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt   time.Time      `json:"created_at,omitempty"`
+	UpdatedAt   time.Time      `json:"updated_at,omitempty"`
+	DeletedAt   gorm.DeletedAt `gorm:"index,omitempty"`
+	Name        string         `json:"name,omitempty" gorm:"not null;uniqueIndex:idx_property_unit_name"`
+	Bedrooms    float32        `json:"bedrooms"`
+	Bathrooms   float32        `json:"bathrooms"`
+	Area        float64        `json:"area"`
+	Area_Metric string         `json:"area_metric,omitempty"`
+	Description string         `json:"description,omitempty" gorm:"default:null"`
+	Notes       string         `json:"notes,omitempty" gorm:"default:null"`
+	// Many to one
+	PropertyID uint      `json:"property_id,omitempty" gorm:"not null;uniqueIndex:idx_property_unit_name"`
+	Property   *Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	// Many to many
+	Features []Feature `json:"features,omitempty" gorm:"many2many:unit_features"`
+}
+
 type Feature struct {
 	ID           uint           `json:"id" gorm:"primaryKey"`
 	CreatedAt    time.Time      `json:"created_at"`
@@ -123,17 +150,33 @@ type Task struct {
 	TaskName string `json:"task_name,omitempty" gorm:"not null"`
 	Type     string `json:"type,omitempty" gorm:"not null, enum:Maintenance,Inspection,Transaction,Other"`
 	// Default fields
-	Status    string `json:"status,omitempty" gorm:"default:created;enum:Created,Open,Pending,Cancelled,Processing,Active,Completed,Archived"`
+	Status    string `json:"status,omitempty" gorm:"default:created;enum:Triage,Created,Open,Pending,Cancelled,Processing,Active,Completed,Archived"`
 	Notes     string `json:"notes,omitempty" gorm:"default:null"`
 	Snoozed   bool   `json:"snoozed,omitempty" gorm:"default:false"`
 	Completed bool   `json:"completed,omitempty" gorm:"default:false"`
 	// Optional fields
 	SnoozedTill time.Time `json:"snoozed_till,omitempty"`
+	DueDate     time.Time `json:"due_date,omitempty" gorm:"default:null"`
+	// Manual order within its status column on the task board (unranked tasks are listed last)
+	BoardRank int `json:"board_rank,omitempty"`
+	// Blocks completing the task until all of its subtasks are complete
+	RequireSubtasksComplete bool `json:"require_subtasks_complete,omitempty"`
+	// Percentage of checklist items and subtasks complete (computed, not stored)
+	Progress int `json:"progress" gorm:"-"`
 	// Relationship fields
 	// Many to many
 	Assignment []User `json:"assignment,omitempty" gorm:"many2many:user_tasks"`
 	// One to many
-	Log []TaskLog `json:"log,omitempty" gorm:"foreignKey:TaskID"`
+	Log       []TaskLog           `json:"log,omitempty" gorm:"foreignKey:TaskID"`
+	Checklist []TaskChecklistItem `json:"checklist,omitempty" gorm:"foreignKey:TaskID"`
+	// Self referencing for subtasks (children point to their parent task)
+	ParentID *uint  `json:"parent_id,omitempty" gorm:"default:null"`
+	Subtasks []Task `json:"subtasks,omitempty" gorm:"foreignKey:ParentID"`
+	// Time recorded against the task
+	TimeEntries []TimeEntry `json:"time_entries,omitempty" gorm:"foreignKey:TaskID"`
+	// Dependencies on other tasks (blocked by) and tasks depending on this task (blocks)
+	BlockedBy []TaskDependency `json:"blocked_by,omitempty" gorm:"foreignKey:TaskID"`
+	Blocks    []TaskDependency `json:"blocks,omitempty" gorm:"foreignKey:BlockedByID"`
 	// One to one
 	// Note: Relationship between tasks and properties are handled within transactions
 	// TransactionID uint `json:"transaction_id,omitempty" gorm:"unique;default:null"`
@@ -157,6 +200,150 @@ type TaskLog struct {
 	Task   Task `json:"task" gorm:"not null;foreignKey:TaskID"`
 }
 
+// Time spent by a user on a task. Entries without an end time are running timers
+type TimeEntry struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	StartedAt time.Time `json:"started_at,omitempty" gorm:"not null;index"`
+	// Optional fields (end time not set while timer is running)
+	EndedAt         *time.Time `json:"ended_at,omitempty" gorm:"default:null"`
+	DurationMinutes int        `json:"duration_minutes,omitempty"`
+	Billable        bool       `json:"billable,omitempty"`
+	Note            string     `json:"note,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one (only one running timer allowed per user)
+	UserID uint `json:"user_id,omitempty" gorm:"not null;index;uniqueIndex:idx_running_timer,where:ended_at IS NULL AND deleted_at IS NULL"`
+	User   User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	TaskID uint `json:"task_id,omitempty" gorm:"not null;index"`
+	Task   Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+}
+
+// Dependency between tasks. The task can't be made active until the blocking task is complete
+type TaskDependency struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// Relationships
+	// Many to one (blocked task)
+	TaskID uint `json:"task_id,omitempty" gorm:"not null;uniqueIndex:idx_task_blocked_by"`
+	Task   Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	// Many to one (blocking task)
+	BlockedByID uint `json:"blocked_by_id,omitempty" gorm:"not null;uniqueIndex:idx_task_blocked_by"`
+	BlockedBy   Task `json:"blocked_by,omitempty" gorm:"foreignKey:BlockedByID"`
+}
+
+// Ordered checklist steps within a task (eg. inspection or move out steps)
+type TaskChecklistItem struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Description string `json:"description,omitempty" gorm:"not null"`
+	Position    int    `json:"position,omitempty" gorm:"not null"`
+	// Default fields
+	Done bool `json:"done,omitempty"`
+	// Set when item is marked as done
+	DoneAt   *time.Time `json:"done_at,omitempty" gorm:"default:null"`
+	DoneByID *uint      `json:"done_by_id,omitempty" gorm:"default:null"`
+	DoneBy   *User      `json:"done_by,omitempty" gorm:"foreignKey:DoneByID"`
+	// Relationships
+	// Many to one
+	TaskID uint `json:"task_id,omitempty" gorm:"not null;index"`
+}
+
+// Task comments (threaded discussion on tasks with @username mentions)
+type TaskComment struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Comment string `json:"comment,omitempty" gorm:"not null"`
+	// Default fields
+	Edited bool `json:"edited,omitempty" gorm:"default:false"`
+	// Relationships
+	// Many to one
+	UserID uint `json:"user_id,omitempty" gorm:"not null"`
+	User   User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	TaskID uint `json:"task_id,omitempty" gorm:"not null"`
+	Task   Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	// Self referencing for threads (replies point to their parent comment)
+	ParentID *uint         `json:"parent_id,omitempty" gorm:"default:null"`
+	Replies  []TaskComment `json:"replies,omitempty" gorm:"foreignKey:ParentID"`
+	// One to many
+	History []TaskCommentEdit `json:"history,omitempty" gorm:"foreignKey:TaskCommentID"`
+	// Many to many
+	Mentions []User `json:"mentions,omitempty" gorm:"many2many:task_comment_mentions"`
+}
+
+// Previous versions of a task comment, stored whenever the comment is edited
+type TaskCommentEdit struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Comment text prior to the edit
+	PreviousComment string `json:"previous_comment,omitempty" gorm:"not null"`
+	// Use TaskCommentID as foreign key
+	TaskCommentID uint `json:"task_comment_id,omitempty" gorm:"not null"`
+	// User that made the edit
+	UserID uint `json:"user_id,omitempty" gorm:"not null"`
+}
+
+// Notifications (per user inbox)
+type Notification struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Type    string `json:"type,omitempty" gorm:"not null;enum:Mention,TaskAssigned,SnoozeExpired,MaintenanceEscalated,TransactionCompleted,VendorNonCompliant,VendorDocumentExpiring,MaintenanceBudgetExceeded,TenantRequestSubmitted,LeaseExpiring"`
+	Message string `json:"message,omitempty" gorm:"not null"`
+	// Default fields
+	Read bool `json:"read" gorm:"default:false"`
+	// Optional fields
+	ReadAt *time.Time `json:"read_at,omitempty" gorm:"default:null"`
+	// Relationships
+	// Recipient of the notification
+	UserID uint `json:"user_id,omitempty" gorm:"not null;index"`
+	// Source of the notification
+	TaskID        uint `json:"task_id,omitempty" gorm:"default:null"`
+	TaskCommentID uint `json:"task_comment_id,omitempty" gorm:"default:null"`
+}
+
+// Notification delivery preferences per user and event type
+type NotificationPreference struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// Required fields
+	UserID    uint   `json:"user_id,omitempty" gorm:"not null;uniqueIndex:idx_user_event"`
+	EventType string `json:"event_type,omitempty" gorm:"not null;uniqueIndex:idx_user_event;enum:Mention,TaskAssigned,SnoozeExpired,MaintenanceEscalated,TransactionCompleted,VendorNonCompliant,VendorDocumentExpiring,MaintenanceBudgetExceeded,TenantRequestSubmitted,LeaseExpiring"`
+	// Delivery channels
+	InApp   bool `json:"in_app"`
+	Email   bool `json:"email"`
+	Webhook bool `json:"webhook"`
+	// Optional fields
+	WebhookURL string `json:"webhook_url,omitempty" gorm:"default:null"`
+}
+
+// Notifications that could not be delivered after all retry attempts
+type NotificationDeadLetter struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Delivery details
+	UserID    uint   `json:"user_id,omitempty" gorm:"not null"`
+	EventType string `json:"event_type,omitempty" gorm:"not null"`
+	Channel   string `json:"channel,omitempty" gorm:"not null;enum:InApp,Email,Webhook"`
+	Message   string `json:"message,omitempty" gorm:"not null"`
+	TaskID    uint   `json:"task_id,omitempty" gorm:"default:null"`
+	// Failure details
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
 // Types of tasks: Transactions, Maintenance Requests, Inspections, Appraisals, Other
 // Transactions
 type Transaction struct {
@@ -172,13 +359,36 @@ type Transaction struct {
 	TenancyType           string    `json:"tenancy_type,omitempty" gorm:"enum:Monthly,LongTerm,ShortTerm,Commercial,NA"`
 	AgencyName            string    `json:"agency_name,omitempty" gorm:""`
 	TransactionNotes      string    `json:"transaction_notes,omitempty" gorm:"default:null"`
-	TransactionValue      float64   `json:"transaction_value,omitempty" gorm:"default:null"`
+	TransactionValue      Money     `json:"transaction_value,omitempty" gorm:"embedded;embeddedPrefix:transaction_value_"`
 	TransactionCompletion time.Time `json:"transaction_completion,omitempty" gorm:"default:null"`
-	Fee                   float32   `json:"fee,omitempty" gorm:"default:null"`
+	// Commission earned. Calculated from the commission scheme when the transaction is completed, otherwise as entered
+	Fee Money `json:"fee,omitempty" gorm:"embedded;embeddedPrefix:fee_"`
+	// Shares of the commission kept by the agency and paid to the co-agency (AgencyName)
+	OwnAgencyFee Money `json:"own_agency_fee,omitempty" gorm:"embedded;embeddedPrefix:own_agency_fee_"`
+	CoAgencyFee  Money `json:"co_agency_fee,omitempty" gorm:"embedded;embeddedPrefix:co_agency_fee_"`
+	// Percentage of rent received charged to the owner by management agreements. Defaults to the rate of a Percentage scheme
+	ManagementFeeRate float64 `json:"management_fee_rate,omitempty" gorm:"default:null"`
+	// Scheme the commission is calculated with
+	CommissionSchemeID *uint             `json:"commission_scheme_id,omitempty" gorm:""`
+	CommissionScheme   *CommissionScheme `json:"commission_scheme,omitempty" gorm:"foreignKey:CommissionSchemeID"`
+	// Agents sharing the agency's commission
+	CommissionSplits []CommissionSplit `json:"commission_splits,omitempty" gorm:"foreignKey:TransactionID"`
+	// Current stage of the pipeline for the transaction type
+	StageID        *uint          `json:"stage_id,omitempty" gorm:""`
+	Stage          *PipelineStage `json:"stage,omitempty" gorm:"foreignKey:StageID"`
+	StageEnteredAt *time.Time     `json:"stage_entered_at,omitempty"`
+	// Moves between stages, oldest first
+	StageHistory []TransactionStageChange `json:"stage_history,omitempty" gorm:"foreignKey:TransactionID"`
+	Milestones   []TransactionMilestone   `json:"milestones,omitempty" gorm:"foreignKey:TransactionID"`
+	// Tax on the agency's commission
+	TaxLines []TaxLine `json:"tax_lines,omitempty" gorm:"foreignKey:TransactionID"`
 
 	// Many to one (requires uint for key and Property for object data)
 	PropertyID uint     `json:"property_id,omitempty" gorm:"not null"`
 	Property   Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	// Unit of the property sold or leased, if any
+	UnitID *uint `json:"unit_id,omitempty" gorm:"index"`
+	Unit   *Unit `json:"unit,omitempty" gorm:"foreignKey:UnitID"`
 	// Many to many
 	Contacts []Contact `json:"contacts,omitempty" gorm:"many2many:contact_transactions"`
 	// One to one
@@ -186,30 +396,513 @@ type Transaction struct {
 	// Task   Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
 }
 
+// Lease of a property to one or more tenants
+type Lease struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	StartDate time.Time `json:"start_date,omitempty" gorm:"not null"`
+	// Date the lease ends (not included in the final rent period)
+	EndDate time.Time `json:"end_date,omitempty" gorm:"not null"`
+	// Rent payable each period
+	RentAmount Money  `json:"rent_amount" gorm:"embedded;embeddedPrefix:rent_amount_"`
+	Frequency  string `json:"frequency,omitempty" gorm:"not null;enum:Monthly,Quarterly,Yearly"`
+	// Optional fields
+	Deposit       Money  `json:"deposit,omitempty" gorm:"embedded;embeddedPrefix:deposit_"`
+	RenewalOption string `json:"renewal_option,omitempty" gorm:"not null;default:None;enum:None,Option to Renew,First Refusal"`
+	// Days before the end date that the lease is expiring and admins are reminded
+	RenewalNoticeDays int `json:"renewal_notice_days" gorm:"not null;default:60"`
+	// Set once admins have been reminded of the upcoming expiry
+	RenewalReminderSentAt *time.Time `json:"renewal_reminder_sent_at,omitempty"`
+	// Charged once per rent period still unpaid after the grace period
+	LateFee          Money  `json:"late_fee,omitempty" gorm:"embedded;embeddedPrefix:late_fee_"`
+	LateFeeGraceDays int    `json:"late_fee_grace_days" gorm:"not null;default:7"`
+	Notes            string `json:"notes,omitempty" gorm:"default:null"`
+	// Upcoming, Active, Expiring or Ended (computed, not stored)
+	Status string `json:"status,omitempty" gorm:"-"`
+	// Relationships
+	// Many to one
+	PropertyID uint     `json:"property_id,omitempty" gorm:"not null;index"`
+	Property   Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	// Unit leased. Leases without a unit are of the whole property
+	UnitID *uint `json:"unit_id,omitempty" gorm:"index"`
+	Unit   *Unit `json:"unit,omitempty" gorm:"foreignKey:UnitID"`
+	// Lease transaction the lease was agreed under
+	TransactionID *uint        `json:"transaction_id,omitempty" gorm:"index"`
+	Transaction   *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+	// Lease this lease renewed
+	RenewedFromID *uint `json:"renewed_from_id,omitempty" gorm:"index"`
+	// Many to many
+	Tenants []Contact `json:"tenants,omitempty" gorm:"many2many:lease_tenants"`
+	// One to many
+	Schedule []RentScheduleItem `json:"schedule,omitempty" gorm:"foreignKey:LeaseID"`
+}
+
+// Rent period of a lease
+type RentScheduleItem struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// Rent is due in advance at the start of the period
+	DueDate     time.Time `json:"due_date,omitempty" gorm:"not null"`
+	PeriodStart time.Time `json:"period_start,omitempty" gorm:"not null"`
+	// Start of the next period (or the lease end date)
+	PeriodEnd time.Time `json:"period_end,omitempty" gorm:"not null"`
+	Amount    Money     `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	// Relationships
+	LeaseID uint `json:"lease_id,omitempty" gorm:"not null;index"`
+}
+
+// Entry in a lease's ledger. Rent account debits are owed by the tenant and credits are paid,
+// while deposit account credits are held for the tenant and debits are refunded
+type LedgerEntry struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	EntryDate time.Time `json:"entry_date,omitempty" gorm:"not null;index"`
+	Type      string    `json:"type,omitempty" gorm:"not null;enum:Rent Charge,Receipt,Adjustment,Late Fee,Deposit Received,Deposit Refund"`
+	Account   string    `json:"account,omitempty" gorm:"not null;enum:Rent,Deposit"`
+	Debit     Money     `json:"debit" gorm:"embedded;embeddedPrefix:debit_"`
+	Credit    Money     `json:"credit" gorm:"embedded;embeddedPrefix:credit_"`
+	// Optional fields
+	Description string `json:"description,omitempty" gorm:"default:null"`
+	// Receipt, transfer or invoice number
+	Reference string `json:"reference,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one
+	LeaseID uint `json:"lease_id,omitempty" gorm:"not null;index"`
+	// Rent period a rent charge or late fee is for
+	RentScheduleItemID *uint `json:"rent_schedule_item_id,omitempty" gorm:"index;uniqueIndex:idx_rent_charge,where:type = 'Rent Charge' AND deleted_at IS NULL"`
+	// One to many
+	// Tax on a rent charge
+	TaxLines []TaxLine `json:"tax_lines,omitempty" gorm:"foreignKey:LedgerEntryID"`
+}
+
+// Stage of the pipeline transactions of a type move through (eg. enquiry, viewing, offer)
+type PipelineStage struct {
+	ID              uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt       time.Time `json:"created_at,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at,omitempty"`
+	Name            string    `json:"name,omitempty" gorm:"not null;uniqueIndex:idx_pipeline_stage_name"`
+	TransactionType string    `json:"transaction_type,omitempty" gorm:"not null;uniqueIndex:idx_pipeline_stage_name;enum:Sale,Lease,Management,Other"`
+	// Order within the pipeline
+	Position int `json:"position,omitempty" gorm:"not null"`
+	// Days after entering the stage its milestone is due. No milestone if zero
+	DueDays int `json:"due_days,omitempty" gorm:"default:null"`
+	// Entering the stage completes the transaction
+	Completes bool `json:"completes,omitempty" gorm:"default:false"`
+}
+
+// Move of a transaction to a pipeline stage
+type TransactionStageChange struct {
+	ID            uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt     time.Time      `json:"created_at,omitempty"`
+	ChangedAt     time.Time      `json:"changed_at,omitempty" gorm:"not null"`
+	Notes         string         `json:"notes,omitempty" gorm:"default:null"`
+	TransactionID uint           `json:"transaction_id,omitempty" gorm:"not null;index"`
+	FromStageID   *uint          `json:"from_stage_id,omitempty" gorm:""`
+	FromStage     *PipelineStage `json:"from_stage,omitempty" gorm:"foreignKey:FromStageID"`
+	ToStageID     uint           `json:"to_stage_id,omitempty" gorm:"not null"`
+	ToStage       PipelineStage  `json:"to_stage,omitempty" gorm:"foreignKey:ToStageID"`
+	// User who moved the transaction. Empty when it entered its first stage on creation
+	UserID *uint `json:"user_id,omitempty" gorm:""`
+	User   *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// Date a transaction must reach a milestone by (eg. deposit paid, contract signed)
+type TransactionMilestone struct {
+	ID          uint       `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt   time.Time  `json:"created_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at,omitempty"`
+	Name        string     `json:"name,omitempty" gorm:"not null"`
+	DueDate     time.Time  `json:"due_date,omitempty" gorm:"not null"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Past its due date without being completed
+	Overdue       bool `json:"overdue" gorm:"-"`
+	TransactionID uint `json:"transaction_id,omitempty" gorm:"not null;index"`
+	// Stage the milestone was set for on entering it. Completed when the transaction leaves the stage
+	PipelineStageID *uint `json:"pipeline_stage_id,omitempty" gorm:""`
+}
+
+// Rate to convert amounts from a base currency into a quote currency, effective from a date until a later rate
+type ExchangeRate struct {
+	ID            uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+	BaseCurrency  string    `json:"base_currency,omitempty" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_date"`
+	QuoteCurrency string    `json:"quote_currency,omitempty" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_date"`
+	// Units of the quote currency per unit of the base currency
+	Rate          float64   `json:"rate,omitempty" gorm:"not null"`
+	EffectiveDate time.Time `json:"effective_date,omitempty" gorm:"not null;uniqueIndex:idx_exchange_rate_date"`
+	// Entered manually or imported from CSV
+	Source string `json:"source,omitempty" gorm:"not null;default:Manual;enum:Manual,CSV"`
+}
+
+// Percentage of a tax charged on rent, commission or vendor invoices, effective from a date until a later rule
+// of the same tax
+type TaxRule struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// PPN (VAT), PPh 4(2) (final income tax on land and building rent) or PPh 23 (income tax on services)
+	Tax       string  `json:"tax,omitempty" gorm:"not null;enum:PPN,PPh 4(2),PPh 23"`
+	AppliesTo string  `json:"applies_to,omitempty" gorm:"not null;enum:Rent,Commission,Vendor Invoice"`
+	Rate      float64 `json:"rate" gorm:"not null"`
+	// Withheld from the amount paid by the payer rather than added to it
+	Withheld      bool      `json:"withheld"`
+	EffectiveDate time.Time `json:"effective_date,omitempty" gorm:"not null"`
+	Notes         string    `json:"notes,omitempty" gorm:"default:null"`
+}
+
+// Tax computed on a rent charge, a transaction's commission or a vendor invoice
+type TaxLine struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Tax       string    `json:"tax,omitempty" gorm:"not null"`
+	AppliesTo string    `json:"applies_to,omitempty" gorm:"not null"`
+	Rate      float64   `json:"rate"`
+	Withheld  bool      `json:"withheld"`
+	// Date of the rent charge, commission or invoice the tax is reported in
+	TaxDate time.Time `json:"tax_date,omitempty" gorm:"not null;index"`
+	// Amount the tax is calculated on
+	Base   Money `json:"base" gorm:"embedded;embeddedPrefix:base_"`
+	Amount Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	// Relationships
+	// Many to one. Rule the tax was calculated with (none for rates entered on an invoice)
+	TaxRuleID       *uint `json:"tax_rule_id,omitempty" gorm:""`
+	LeaseID         *uint `json:"lease_id,omitempty" gorm:"index"`
+	LedgerEntryID   *uint `json:"ledger_entry_id,omitempty" gorm:"index"`
+	TransactionID   *uint `json:"transaction_id,omitempty" gorm:"index"`
+	VendorInvoiceID *uint `json:"vendor_invoice_id,omitempty" gorm:"index"`
+}
+
+// Configurable calculation of the commission earned on transactions
+type CommissionScheme struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	Name      string         `json:"name,omitempty" gorm:"not null;uniqueIndex"`
+	// Percentage of the transaction value, Tiered percentages of the bands of transaction value or a Flat fee
+	Method string `json:"method,omitempty" gorm:"not null;enum:Percentage,Tiered,Flat"`
+	// Percentage of the transaction value (Percentage schemes)
+	Rate float64 `json:"rate,omitempty" gorm:"default:null"`
+	// Fee per transaction (Flat schemes)
+	FlatFee Money `json:"flat_fee,omitempty" gorm:"embedded;embeddedPrefix:flat_fee_"`
+	// Percentage of the commission paid to the co-agency of transactions through another agency
+	CoAgencySplit float64 `json:"co_agency_split,omitempty" gorm:"default:null"`
+	// Transaction type new transactions use the scheme for by default
+	DefaultFor string `json:"default_for,omitempty" gorm:"default:null;enum:Sale,Lease,Management,Other"`
+	// Bands of transaction value (Tiered schemes)
+	Tiers []CommissionTier `json:"tiers,omitempty" gorm:"foreignKey:CommissionSchemeID"`
+}
+
+// Band of transaction value charged at a percentage
+type CommissionTier struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// Transaction value the band ends at. Zero for the top band
+	UpTo               Money   `json:"up_to,omitempty" gorm:"embedded;embeddedPrefix:up_to_"`
+	Rate               float64 `json:"rate,omitempty" gorm:"not null"`
+	CommissionSchemeID uint    `json:"commission_scheme_id,omitempty" gorm:"not null;index"`
+}
+
+// Agent's share of the agency's commission on a transaction
+type CommissionSplit struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// Percentage of the agency's commission
+	Share float64 `json:"share,omitempty" gorm:"not null"`
+	// Calculated when the transaction is completed
+	Amount        Money `json:"amount,omitempty" gorm:"embedded;embeddedPrefix:amount_"`
+	TransactionID uint  `json:"transaction_id,omitempty" gorm:"not null;uniqueIndex:idx_commission_split_agent"`
+	UserID        uint  `json:"user_id,omitempty" gorm:"not null;uniqueIndex:idx_commission_split_agent"`
+	User          User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// Monthly statement of what is owed to the owner of a managed property
+type OwnerStatement struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Year   int    `json:"year,omitempty" gorm:"not null;index"`
+	Month  int    `json:"month,omitempty" gorm:"not null"`
+	Status string `json:"status,omitempty" gorm:"not null;default:Draft;enum:Draft,Final"`
+	// Closing balance of the previous month's statement
+	OpeningBalance Money `json:"opening_balance" gorm:"embedded;embeddedPrefix:opening_balance_"`
+	RentReceived   Money `json:"rent_received" gorm:"embedded;embeddedPrefix:rent_received_"`
+	// Percentage of rent received from the property's management transaction
+	ManagementFeeRate float64 `json:"management_fee_rate"`
+	ManagementFees    Money   `json:"management_fees" gorm:"embedded;embeddedPrefix:management_fees_"`
+	// Costs of maintenance requests raised in the month without vendor invoices
+	MaintenanceCosts Money `json:"maintenance_costs" gorm:"embedded;embeddedPrefix:maintenance_costs_"`
+	VendorInvoices   Money `json:"vendor_invoices" gorm:"embedded;embeddedPrefix:vendor_invoices_"`
+	// Rent received less fees and costs
+	NetPayable  Money `json:"net_payable" gorm:"embedded;embeddedPrefix:net_payable_"`
+	PaidToOwner Money `json:"paid_to_owner" gorm:"embedded;embeddedPrefix:paid_to_owner_"`
+	// Carried forward to the next month's statement
+	ClosingBalance Money `json:"closing_balance" gorm:"embedded;embeddedPrefix:closing_balance_"`
+	// Optional fields
+	PaymentReference string     `json:"payment_reference,omitempty" gorm:"default:null"`
+	Notes            string     `json:"notes,omitempty" gorm:"default:null"`
+	FinalisedAt      *time.Time `json:"finalised_at,omitempty"`
+	// Relationships
+	// Many to one
+	PropertyID uint     `json:"property_id,omitempty" gorm:"not null;index"`
+	Property   Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	// One to many
+	Lines []OwnerStatementLine `json:"lines,omitempty" gorm:"foreignKey:OwnerStatementID"`
+}
+
+// Amount paid to (positive) or deducted from (negative) the owner on a statement
+type OwnerStatementLine struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Date      time.Time `json:"date,omitempty" gorm:"not null"`
+	Category  string    `json:"category,omitempty" gorm:"not null;enum:Rent Received,Management Fee,Maintenance,Vendor Invoice"`
+	// Optional fields
+	Description string `json:"description,omitempty" gorm:"default:null"`
+	Reference   string `json:"reference,omitempty" gorm:"default:null"`
+	Amount      Money  `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	// Relationships
+	OwnerStatementID uint `json:"owner_statement_id,omitempty" gorm:"not null;index"`
+}
+
 type MaintenanceRequest struct {
 	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
 	CreatedAt time.Time      `json:"created_at,omitempty"`
 	UpdatedAt time.Time      `json:"updated_at,omitempty"`
 	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
 	// Required fields
-	WorkDefinition string  `json:"work_definition,omitempty" gorm:"not null;enum:Repair,Replacement,Project,Investigation,Pest Control,Other"`
-	Type           string  `json:"type,omitempty" gorm:"not null;enum:Electrical,Plumbing,Painting,HVAC,Civil,Other"`
-	Notes          string  `json:"notes,omitempty" gorm:"default:null"`
-	Scale          string  `json:"scale,omitempty" gorm:"not null;enum:Urgent,High,Medium,Low"`
-	TotalCost      float64 `json:"cost,omitempty" gorm:"default:null"`
-	Tax            float64 `json:"tax,omitempty" gorm:"default:null"`
+	WorkDefinition string `json:"work_definition,omitempty" gorm:"not null;enum:Repair,Replacement,Project,Investigation,Pest Control,Other"`
+	Type           string `json:"type,omitempty" gorm:"not null;enum:Electrical,Plumbing,Painting,HVAC,Civil,Other"`
+	Notes          string `json:"notes,omitempty" gorm:"default:null"`
+	Scale          string `json:"scale,omitempty" gorm:"not null;enum:Urgent,High,Medium,Low"`
+	TotalCost      Money  `json:"cost,omitempty" gorm:"embedded;embeddedPrefix:total_cost_"`
+	Tax            Money  `json:"tax,omitempty" gorm:"embedded;embeddedPrefix:tax_"`
+	// Set for requests submitted by tenants through the portal
+	TrackingCode    *string `json:"tracking_code,omitempty" gorm:"uniqueIndex"`
+	ReporterName    string  `json:"reporter_name,omitempty" gorm:"default:null"`
+	ReporterContact string  `json:"reporter_contact,omitempty" gorm:"default:null"`
 
 	// Relationships
 	// Many to one (requires uint for key and Property for object data)
 	PropertyID uint     `json:"property_id,omitempty" gorm:"not null"`
 	Property   Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	// Unit of the property the work is for, if any
+	UnitID *uint `json:"unit_id,omitempty" gorm:"index"`
+	Unit   *Unit `json:"unit,omitempty" gorm:"foreignKey:UnitID"`
 	// One to one
 	WorkTypeID uint     `json:"work_type_id,omitempty" gorm:""`
 	WorkType   WorkType `json:"work_type,omitempty" gorm:"foreignKey:WorkTypeID"`
 	TaskID     uint     `json:"task,omitempty" gorm:"unique"`
+	// Many to one (vendor assigned by accepting a quote)
+	VendorID *uint   `json:"vendor_id,omitempty" gorm:""`
+	Vendor   *Vendor `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+	// One to many
+	Quotes     []VendorQuote `json:"quotes,omitempty" gorm:"foreignKey:MaintenanceRequestID"`
+	WorkOrders []WorkOrder   `json:"work_orders,omitempty" gorm:"foreignKey:MaintenanceRequestID"`
+	// Many to many
+	Photos []PropertyAttachment `json:"photos,omitempty" gorm:"many2many:maintenance_request_photos"`
+}
+
+type VendorQuote struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Invited until the vendor's quote is recorded
+	Status     string    `json:"status,omitempty" gorm:"not null;default:Invited;enum:Invited,Submitted,Accepted,Rejected"`
+	Amount     Money     `json:"amount,omitempty" gorm:"embedded;embeddedPrefix:amount_"`
+	Tax        Money     `json:"tax,omitempty" gorm:"embedded;embeddedPrefix:tax_"`
+	ValidUntil time.Time `json:"valid_until,omitempty" gorm:"default:null"`
+	Notes      string    `json:"notes,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one (one quote per vendor for each request)
+	MaintenanceRequestID uint               `json:"maintenance_request_id,omitempty" gorm:"not null;uniqueIndex:idx_request_vendor"`
+	MaintenanceRequest   MaintenanceRequest `json:"maintenance_request,omitempty" gorm:"foreignKey:MaintenanceRequestID"`
+	VendorID             uint               `json:"vendor_id,omitempty" gorm:"not null;uniqueIndex:idx_request_vendor"`
+	Vendor               Vendor             `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+	// Many to many (documents uploaded to the request's property)
+	Attachments []PropertyAttachment `json:"attachments,omitempty" gorm:"many2many:vendor_quote_attachments"`
+	// Compliance issues of the vendor when accepted (computed, not stored)
+	ComplianceWarnings []string `json:"compliance_warnings,omitempty" gorm:"-"`
+}
+
+// Work issued to a vendor for a maintenance request
+type WorkOrder struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Description string `json:"description,omitempty" gorm:"not null"`
+	Status      string `json:"status,omitempty" gorm:"not null;default:Issued;enum:Issued,Accepted,Scheduled,In Progress,Completed,Invoiced,Paid"`
+	// Scheduled visit window
+	ScheduledStart *time.Time `json:"scheduled_start,omitempty"`
+	ScheduledEnd   *time.Time `json:"scheduled_end,omitempty"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	Cost           Money      `json:"cost,omitempty" gorm:"embedded;embeddedPrefix:cost_"`
+	Tax            Money      `json:"tax,omitempty" gorm:"embedded;embeddedPrefix:tax_"`
+	Notes          string     `json:"notes,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one
+	MaintenanceRequestID uint               `json:"maintenance_request_id,omitempty" gorm:"not null;index"`
+	MaintenanceRequest   MaintenanceRequest `json:"maintenance_request,omitempty" gorm:"foreignKey:MaintenanceRequestID"`
+	VendorID             uint               `json:"vendor_id,omitempty" gorm:"not null;index"`
+	Vendor               Vendor             `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+	// Parent task receiving status change logs
+	TaskID uint `json:"task_id,omitempty" gorm:"not null;index"`
+	Task   Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	// Compliance issues of the vendor when issued (computed, not stored)
+	ComplianceWarnings []string `json:"compliance_warnings,omitempty" gorm:"-"`
+}
+
+// Invoice received from a vendor for a work order or maintenance request
+type VendorInvoice struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	InvoiceNumber string    `json:"invoice_number,omitempty" gorm:"not null;uniqueIndex:idx_vendor_invoice_number"`
+	VendorNPWP    string    `json:"vendor_npwp,omitempty" gorm:"not null"`
+	InvoiceDate   time.Time `json:"invoice_date,omitempty" gorm:"not null"`
+	DueDate       time.Time `json:"due_date,omitempty" gorm:"not null"`
+	// Totals calculated from line items
+	Subtotal Money   `json:"subtotal" gorm:"embedded;embeddedPrefix:subtotal_"`
+	PPNRate  float64 `json:"ppn_rate"`
+	PPN      Money   `json:"ppn" gorm:"embedded;embeddedPrefix:ppn_"`
+	Total    Money   `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	// Income tax (eg. PPh 23) withheld from the payment to the vendor and paid to the tax office instead
+	Withholding Money  `json:"withholding" gorm:"embedded;embeddedPrefix:withholding_"`
+	AmountPaid  Money  `json:"amount_paid" gorm:"embedded;embeddedPrefix:amount_paid_"`
+	Status      string `json:"status,omitempty" gorm:"not null;default:Unpaid;enum:Unpaid,Partially Paid,Paid"`
+	Notes       string `json:"notes,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one
+	VendorID             uint                `json:"vendor_id,omitempty" gorm:"not null;uniqueIndex:idx_vendor_invoice_number"`
+	Vendor               Vendor              `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+	WorkOrderID          *uint               `json:"work_order_id,omitempty" gorm:"index"`
+	WorkOrder            *WorkOrder          `json:"work_order,omitempty" gorm:"foreignKey:WorkOrderID"`
+	MaintenanceRequestID *uint               `json:"maintenance_request_id,omitempty" gorm:"index"`
+	MaintenanceRequest   *MaintenanceRequest `json:"maintenance_request,omitempty" gorm:"foreignKey:MaintenanceRequestID"`
 	// One to many
-	// VendorID uint `json:"vendor_id,omitempty" gorm:""`
-	// Vendor Vendor `json:"vendor_id,omitempty" gorm:""`
+	Lines    []VendorInvoiceLine `json:"lines,omitempty" gorm:"foreignKey:VendorInvoiceID"`
+	Payments []VendorPayment     `json:"payments,omitempty" gorm:"foreignKey:VendorInvoiceID"`
+	TaxLines []TaxLine           `json:"tax_lines,omitempty" gorm:"foreignKey:VendorInvoiceID"`
+}
+
+type VendorInvoiceLine struct {
+	ID          uint      `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	Description string    `json:"description,omitempty" gorm:"not null"`
+	Quantity    float64   `json:"quantity,omitempty" gorm:"not null"`
+	UnitPrice   Money     `json:"unit_price,omitempty" gorm:"embedded;embeddedPrefix:unit_price_"`
+	Amount      Money     `json:"amount,omitempty" gorm:"embedded;embeddedPrefix:amount_"`
+	// Line isn't subject to PPN
+	PPNExempt       bool `json:"ppn_exempt,omitempty"`
+	VendorInvoiceID uint `json:"vendor_invoice_id,omitempty" gorm:"not null;index"`
+}
+
+// Licence, insurance or tax registration held by a vendor
+type VendorDocument struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Type      string    `json:"type,omitempty" gorm:"not null;enum:Business Licence,Insurance,Tax Registration"`
+	Number    string    `json:"number,omitempty" gorm:"not null"`
+	IssueDate time.Time `json:"issue_date,omitempty" gorm:"not null"`
+	// Documents without an expiry date don't expire
+	ExpiryDate *time.Time `json:"expiry_date,omitempty"`
+	// Set once admins have been warned of the upcoming expiry
+	ExpiryNotifiedAt *time.Time `json:"expiry_notified_at,omitempty"`
+	// Uploaded file in object storage
+	FileName  string `json:"file_name,omitempty" gorm:"default:null"`
+	FileSize  int64  `json:"file_size,omitempty" gorm:"default:null"`
+	FileType  string `json:"file_type,omitempty" gorm:"default:null"`
+	ObjectKey string `json:"object_key,omitempty" gorm:"default:null"`
+	ETag      string `json:"etag,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one
+	VendorID uint   `json:"vendor_id,omitempty" gorm:"not null;index"`
+	Vendor   Vendor `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+}
+
+// Maintenance budget of a property for a year or a single month
+type MaintenanceBudget struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Required fields
+	Year int `json:"year,omitempty" gorm:"not null"`
+	// 1 to 12 for a monthly budget, 0 for an annual budget
+	Month  int   `json:"month" gorm:"not null;default:0"`
+	Amount Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	// Percentage of the budget spent before admins are alerted
+	AlertThreshold float64 `json:"alert_threshold" gorm:"not null;default:90"`
+	// Set once admins have been alerted that the threshold was reached
+	AlertSentAt *time.Time `json:"alert_sent_at,omitempty"`
+	Notes       string     `json:"notes,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one
+	PropertyID uint     `json:"property_id,omitempty" gorm:"not null;index"`
+	Property   Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	// Budget covers all work types if not set
+	WorkTypeID *uint     `json:"work_type_id,omitempty" gorm:""`
+	WorkType   *WorkType `json:"work_type,omitempty" gorm:"foreignKey:WorkTypeID"`
+}
+
+// Rating of a vendor's work on a completed maintenance request
+type VendorRating struct {
+	ID        uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index,omitempty"`
+	// Scores out of 5
+	Quality       int    `json:"quality,omitempty" gorm:"not null"`
+	Timeliness    int    `json:"timeliness,omitempty" gorm:"not null"`
+	Price         int    `json:"price,omitempty" gorm:"not null"`
+	Communication int    `json:"communication,omitempty" gorm:"not null"`
+	Comment       string `json:"comment,omitempty" gorm:"default:null"`
+	// Relationships
+	// Many to one (one rating per vendor for each request)
+	MaintenanceRequestID uint               `json:"maintenance_request_id,omitempty" gorm:"not null;uniqueIndex:idx_rating_request_vendor"`
+	MaintenanceRequest   MaintenanceRequest `json:"maintenance_request,omitempty" gorm:"foreignKey:MaintenanceRequestID"`
+	VendorID             uint               `json:"vendor_id,omitempty" gorm:"not null;uniqueIndex:idx_rating_request_vendor"`
+	Vendor               Vendor             `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+	// Rated by
+	UserID uint `json:"user_id,omitempty" gorm:"not null"`
+	User   User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// Payment made against a vendor invoice
+type VendorPayment struct {
+	ID              uint           `json:"id,omitempty" gorm:"primaryKey"`
+	CreatedAt       time.Time      `json:"created_at,omitempty"`
+	UpdatedAt       time.Time      `json:"updated_at,omitempty"`
+	DeletedAt       gorm.DeletedAt `gorm:"index,omitempty"`
+	Amount          Money          `json:"amount,omitempty" gorm:"embedded;embeddedPrefix:amount_"`
+	PaidAt          time.Time      `json:"paid_at,omitempty" gorm:"not null"`
+	Method          string         `json:"method,omitempty" gorm:"default:null"`
+	Reference       string         `json:"reference,omitempty" gorm:"default:null"`
+	VendorInvoiceID uint           `json:"vendor_invoice_id,omitempty" gorm:"not null;index"`
 }
 
 type WorkType struct {
@@ -247,7 +940,12 @@ type Vendor struct {
 	Suburb           string `json:"suburb,omitempty" gorm:"not null;default:Badung"`
 	// Relationships
 	// One to many
-	// MaintenanceRequests []MaintenanceRequest `json:"maintenance_requests,omitempty" gorm:"foreignKey:VendorID"`
+	MaintenanceRequests []MaintenanceRequest `json:"maintenance_requests,omitempty" gorm:"foreignKey:VendorID"`
+	Quotes              []VendorQuote        `json:"quotes,omitempty" gorm:"foreignKey:VendorID"`
+	WorkOrders          []WorkOrder          `json:"work_orders,omitempty" gorm:"foreignKey:VendorID"`
+	Invoices            []VendorInvoice      `json:"invoices,omitempty" gorm:"foreignKey:VendorID"`
+	Ratings             []VendorRating       `json:"ratings,omitempty" gorm:"foreignKey:VendorID"`
+	Documents           []VendorDocument     `json:"documents,omitempty" gorm:"foreignKey:VendorID"`
 	// Many to many
 	WorkTypes []WorkType `json:"work_types,omitempty" gorm:"many2many:vendor_work_types"`
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/asaskevich/govalidator"
@@ -25,20 +26,23 @@ func WriteAsJSON(w http.ResponseWriter, data interface{}) error {
 	return nil
 }
 
-// Extract base path from request
+// Extract base path from request (eg. /api/maintenance/5/suggested-vendors becomes /api/maintenance/suggested-vendors)
 func ExtractBasePath(r *http.Request) string {
 	// Extract current URL being accessed
 	extractedPath := r.URL.Path
 	// Split path
 	fullPathArray := strings.Split(extractedPath, "/")
 
-	// If the final item in the slice is determined to be numeric
-	if govalidator.IsNumeric(fullPathArray[len(fullPathArray)-1]) {
-		// Remove final element from slice
-		fullPathArray = fullPathArray[:len(fullPathArray)-1]
+	// Remove numeric ID parameters (and a trailing slash) wherever they appear after the leading slash
+	basePathArray := []string{}
+	for i, segment := range fullPathArray {
+		if i > 0 && govalidator.IsNumeric(segment) {
+			continue
+		}
+		basePathArray = append(basePathArray, segment)
 	}
 	// Join strings in slice for clean URL
-	pathWithoutParameters := strings.Join(fullPathArray, "/")
+	pathWithoutParameters := strings.Join(basePathArray, "/")
 	return pathWithoutParameters
 }
 
@@ -117,3 +121,26 @@ func UpdateStructField(structPtr interface{}, fieldName string, fieldValue inter
 	field.Set(fieldValueRef)
 	return nil
 }
+
+// Regular expression used to find @username mentions within text
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@])@([\w.\-]+)`)
+
+// Extracts a unique list of usernames mentioned (eg. @username) within text
+func ExtractMentions(text string) []string {
+	// Init
+	mentions := []string{}
+	found := make(map[string]bool)
+
+	// Find all matches of mention pattern
+	matches := mentionRegex.FindAllStringSubmatch(text, -1)
+	for _, match := range matches {
+		// Trim trailing punctuation (eg. "@username." at end of sentence)
+		username := strings.TrimRight(match[1], ".-")
+		// Only add if not empty and not already found
+		if username != "" && !found[username] {
+			found[username] = true
+			mentions = append(mentions, username)
+		}
+	}
+	return mentions
+}
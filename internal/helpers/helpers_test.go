@@ -1,7 +1,10 @@
 package helpers_test
 
 import (
+	"bytes"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dmawardi/Go-Template/internal/helpers"
@@ -95,3 +98,127 @@ func TestGoValidateStruct(t *testing.T) {
 	}
 
 }
+
+func TestExtractBasePath(t *testing.T) {
+	var testTable = []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"collection", "/api/maintenance", "/api/maintenance"},
+		{"trailing-id", "/api/maintenance/5", "/api/maintenance"},
+		{"trailing-slash", "/api/maintenance/", "/api/maintenance"},
+		{"nested-id", "/api/maintenance/5/suggested-vendors", "/api/maintenance/suggested-vendors"},
+		{"named-action-id", "/api/properties/portal-token/12", "/api/properties/portal-token"},
+	}
+	// for test struct in tests array
+	for _, tt := range testTable {
+		r := httptest.NewRequest("GET", tt.path, nil)
+		if path := helpers.ExtractBasePath(r); path != tt.expected {
+			t.Errorf("Error: %s value received: %v\n not as expected: %v\n", tt.name, path, tt.expected)
+		}
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	var testTable = []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"no-mentions", "The plumber is coming tomorrow", []string{}},
+		{"single-mention", "@Jabar can you check the plumbing?", []string{"Jabar"}},
+		{"multiple-mentions", "@Jabar and @wayan.putu please review", []string{"Jabar", "wayan.putu"}},
+		{"duplicate-mentions", "@Jabar @Jabar please check", []string{"Jabar"}},
+		{"trailing-punctuation", "Please check with @Jabar.", []string{"Jabar"}},
+		{"email-is-not-mention", "Send it to jabar@ymail.com", []string{}},
+	}
+	// for test struct in tests array
+	for _, tt := range testTable {
+		mentions := helpers.ExtractMentions(tt.text)
+		if !reflect.DeepEqual(mentions, tt.expected) {
+			t.Errorf("Error: %s value received: %v\n not as expected: %v\n", tt.name, mentions, tt.expected)
+		}
+	}
+}
+
+func TestIsValidNPWP(t *testing.T) {
+	var testTable = []struct {
+		name     string
+		npwp     string
+		expected bool
+	}{
+		{"formatted-15-digit", "01.234.567.4-012.000", true},
+		{"unformatted-15-digit", "012345674012000", true},
+		{"prefixed-16-digit", "0012345674012000", true},
+		{"nik-16-digit", "5171011205800003", true},
+		{"nik-female-birth-day", "5171015205800003", true},
+		{"incorrect-check-digit", "01.234.567.5-012.000", false},
+		{"incorrect-length", "01.234.567.4-012", false},
+		{"letters", "01.234.567.4-012.00A", false},
+		{"nik-invalid-province", "9971011205800003", false},
+		{"nik-invalid-month", "5171011213800003", false},
+	}
+	// for test struct in tests array
+	for _, tt := range testTable {
+		if valid := helpers.IsValidNPWP(tt.npwp); valid != tt.expected {
+			t.Errorf("Error: %s value received: %v\n not as expected: %v\n", tt.name, valid, tt.expected)
+		}
+	}
+
+	// Formatting and equivalence
+	if formatted := helpers.FormatNPWP("012345674012000"); formatted != "01.234.567.4-012.000" {
+		t.Errorf("Error: format NPWP value received: %v\n not as expected: 01.234.567.4-012.000\n", formatted)
+	}
+	if !helpers.SameNPWP("01.234.567.4-012.000", "0012345674012000") || helpers.SameNPWP("", "") {
+		t.Errorf("Error: 15 and 16 digit forms of the same NPWP should match")
+	}
+}
+
+func TestIsPublicWebhookURL(t *testing.T) {
+	var testTable = []struct {
+		name     string
+		url      string
+		expected bool
+	}{
+		{"public-https", "https://hooks.example.com/notify", true},
+		{"public-ip", "https://203.0.113.10/notify", true},
+		{"plain-http", "http://hooks.example.com/notify", false},
+		{"localhost", "https://localhost/notify", false},
+		{"loopback-ip", "https://127.0.0.1:8080/notify", false},
+		{"private-ip", "https://10.0.0.5/notify", false},
+		{"metadata-ip", "https://169.254.169.254/latest/meta-data", false},
+		{"ipv6-loopback", "https://[::1]/notify", false},
+		{"internal-host", "https://metadata.google.internal/", false},
+	}
+	// for test struct in tests array
+	for _, tt := range testTable {
+		if valid := helpers.IsPublicWebhookURL(tt.url); valid != tt.expected {
+			t.Errorf("Error: %s value received: %v\n not as expected: %v\n", tt.name, valid, tt.expected)
+		}
+	}
+}
+
+func TestRenderTextPDF(t *testing.T) {
+	// Enough lines for a second page
+	lines := []helpers.PDFLine{{Text: "Owner Statement (September)", Size: 16, Font: helpers.PDFBold}}
+	for i := 0; i < 60; i++ {
+		lines = append(lines, helpers.PDFLine{Text: "Rent received    Rp 10000000.00", Font: helpers.PDFMono})
+	}
+	document := helpers.RenderTextPDF("Statement", lines)
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4")) || !bytes.HasSuffix(document, []byte("%%EOF\n")) {
+		t.Errorf("Error: PDF missing header or trailer")
+	}
+	if !strings.Contains(string(document), "/Count 2") {
+		t.Errorf("Error: PDF expected to have 2 pages")
+	}
+	if !strings.Contains(string(document), "(Owner Statement \\(September\\)) Tj") {
+		t.Errorf("Error: PDF text parentheses not escaped")
+	}
+	// Cross reference table points at the first object
+	xref := bytes.Index(document, []byte("xref\n"))
+	if xref == -1 || !bytes.Contains(document[xref:], []byte("0000000009 00000 n")) {
+		t.Errorf("Error: PDF cross reference table missing or incorrect")
+	}
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Fonts available in text PDFs (standard PDF fonts, so nothing is embedded)
+const (
+	PDFRegular = "F1"
+	PDFBold    = "F2"
+	// Fixed width, for lining up columns
+	PDFMono = "F3"
+)
+
+// Line of text in a PDF. A blank line adds spacing
+type PDFLine struct {
+	Text string
+	// Defaults to 10pt
+	Size float64
+	// Defaults to PDFRegular
+	Font string
+}
+
+// A4 page layout in points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// Renders lines of text as an A4 PDF document, starting new pages as needed
+func RenderTextPDF(title string, lines []PDFLine) []byte {
+	// Lay out lines into page content streams
+	pages := []string{}
+	var content strings.Builder
+	y := pdfPageHeight - pdfMargin
+	for _, line := range lines {
+		size := line.Size
+		if size == 0 {
+			size = 10
+		}
+		font := line.Font
+		if font == "" {
+			font = PDFRegular
+		}
+		lineHeight := size * 1.4
+		if y-lineHeight < pdfMargin {
+			pages = append(pages, content.String())
+			content.Reset()
+			y = pdfPageHeight - pdfMargin
+		}
+		y -= lineHeight
+		if line.Text != "" {
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, pdfMargin, y, escapePDFText(line.Text))
+		}
+	}
+	pages = append(pages, content.String())
+
+	// Objects: catalog, page tree, fonts, info, then a page and its content for each page
+	objects := []string{"", "",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Title (%s) /Producer (Go-Template) >>", escapePDFText(title)),
+	}
+	pageRefs := []string{}
+	for _, pageContent := range pages {
+		pageNumber := len(objects) + 1
+		pageRefs = append(pageRefs, fmt.Sprintf("%d 0 R", pageNumber))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, pageNumber+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(pageContent), pageContent),
+		)
+	}
+	objects[0] = "<< /Type /Catalog /Pages 2 0 R >>"
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(pages))
+
+	// Write objects with the cross reference table of their offsets
+	var document bytes.Buffer
+	document.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, document.Len())
+		fmt.Fprintf(&document, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xrefOffset := document.Len()
+	fmt.Fprintf(&document, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&document, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&document, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+	return document.Bytes()
+}
+
+// Escapes text for a PDF string. Characters outside printable ASCII are replaced
+func escapePDFText(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		case r < 32 || r > 126:
+			escaped.WriteRune('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Registers custom validators for use within DTO "valid" tags
+func init() {
+	govalidator.TagMap["npwp"] = govalidator.Validator(IsValidNPWP)
+	govalidator.TagMap["nib"] = govalidator.Validator(IsValidNIB)
+	govalidator.TagMap["currency"] = govalidator.Validator(db.IsCurrency)
+	govalidator.TagMap["webhookurl"] = govalidator.Validator(IsPublicWebhookURL)
+	// Amounts that weren't provided are left to "required"
+	govalidator.CustomTypeTagMap.Set("positive", func(i interface{}, o interface{}) bool {
+		amount, ok := i.(db.Money)
+		return ok && (amount.Amount > 0 || amount == db.Money{})
+	})
+	govalidator.CustomTypeTagMap.Set("nonnegative", func(i interface{}, o interface{}) bool {
+		amount, ok := i.(db.Money)
+		return ok && amount.Amount >= 0
+	})
+}
+
+// Strips formatting (dots, dashes and spaces) from an identifier so only digits remain
+func NormalizeDigits(identifier string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, identifier)
+}
+
+// Strips formatting from an NPWP (eg. 01.234.567.8-901.000 becomes 012345678901000)
+func NormalizeNPWP(npwp string) string {
+	return NormalizeDigits(npwp)
+}
+
+// Validates an Indonesian tax number (NPWP).
+// Accepts the 15 digit format (with or without dots and dashes), where the 9th digit
+// is a Luhn check digit over the first eight, and the 16 digit format which is either
+// a 15 digit NPWP prefixed with 0 or a resident's NIK
+func IsValidNPWP(npwp string) bool {
+	// Only digits and formatting characters allowed
+	for _, r := range npwp {
+		if !unicode.IsDigit(r) && r != '.' && r != '-' && r != ' ' {
+			return false
+		}
+	}
+	digits := NormalizeNPWP(npwp)
+
+	switch len(digits) {
+	case 15:
+		return luhnValid(digits[:9])
+	case 16:
+		// Business NPWP in the 16 digit format
+		if digits[0] == '0' {
+			return luhnValid(digits[1:10])
+		}
+		return isValidNIK(digits)
+	}
+	return false
+}
+
+// Determines whether two NPWPs refer to the same taxpayer, treating a
+// 15 digit NPWP and its 16 digit (0 prefixed) equivalent as the same
+func SameNPWP(a string, b string) bool {
+	a, b = canonicalNPWP(a), canonicalNPWP(b)
+	return a != "" && a == b
+}
+
+// Returns the stored forms an NPWP may take (eg. both the 15 and 16 digit formats)
+func NPWPVariants(npwp string) []string {
+	digits := NormalizeNPWP(npwp)
+	switch {
+	case len(digits) == 15:
+		return []string{digits, "0" + digits}
+	case len(digits) == 16 && digits[0] == '0':
+		return []string{digits, digits[1:]}
+	}
+	return []string{digits}
+}
+
+// Formats an NPWP for display. 15 digit NPWPs use the XX.XXX.XXX.X-XXX.XXX format
+// while 16 digit NPWPs are displayed as digits only
+func FormatNPWP(npwp string) string {
+	digits := NormalizeNPWP(npwp)
+	if len(digits) != 15 {
+		return digits
+	}
+	return digits[0:2] + "." + digits[2:5] + "." + digits[5:8] + "." + digits[8:9] + "-" + digits[9:12] + "." + digits[12:15]
+}
+
+// Validates a business identification number (NIB) issued through OSS. Must be 13 digits
+func IsValidNIB(nib string) bool {
+	digits := NormalizeDigits(nib)
+	// Only spaces allowed as formatting
+	if len(digits) != len(strings.ReplaceAll(nib, " ", "")) {
+		return false
+	}
+	return len(digits) == 13
+}
+
+// Validates a webhook URL users may have notifications posted to. Must be https and must not name
+// localhost or an internal address (the address it resolves to is checked again when delivering)
+func IsPublicWebhookURL(webhookURL string) bool {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+	return true
+}
+
+// Checks an IP address isn't loopback, private, link local (eg. cloud metadata), multicast or unspecified
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// Converts an NPWP to its 16 digit form for comparison
+func canonicalNPWP(npwp string) string {
+	digits := NormalizeNPWP(npwp)
+	if len(digits) == 15 {
+		return "0" + digits
+	}
+	return digits
+}
+
+// Checks a string of digits using the Luhn algorithm (final digit is the check digit)
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Checks the structure of a resident identity number (NIK):
+// province code, then date of birth (day + 40 for women) and a non zero serial
+func isValidNIK(digits string) bool {
+	province := atoi2(digits[0:2])
+	day := atoi2(digits[6:8])
+	month := atoi2(digits[8:10])
+	if province < 11 || province > 94 {
+		return false
+	}
+	if day > 40 {
+		day -= 40
+	}
+	if day < 1 || day > 31 || month < 1 || month > 12 {
+		return false
+	}
+	return digits[12:16] != "0000"
+}
+
+// Converts two digits to an int
+func atoi2(digits string) int {
+	return int(digits[0]-'0')*10 + int(digits[1]-'0')
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateCommissionScheme struct {
+	Name string `json:"name" valid:"required,length(2|100)"`
+	// Percentage, Tiered or Flat
+	Method string `json:"method" valid:"required,in(Percentage|Tiered|Flat)"`
+	// Percentage of the transaction value (Percentage schemes)
+	Rate float64 `json:"rate,omitempty" valid:"range(0|100)"`
+	// Fee per transaction (Flat schemes)
+	FlatFee db.Money `json:"flat_fee,omitempty" valid:"nonnegative"`
+	// Percentage of the commission paid to the co-agency of transactions through another agency
+	CoAgencySplit float64 `json:"co_agency_split,omitempty" valid:"range(0|100)"`
+	// Transaction type new transactions use the scheme for by default
+	DefaultFor string `json:"default_for,omitempty" valid:"in(Sale|Lease|Management|Other)"`
+	// Bands of transaction value in ascending order (Tiered schemes)
+	Tiers []CommissionTier `json:"tiers,omitempty" valid:""`
+}
+
+type CommissionTier struct {
+	// Transaction value the band ends at. Zero for the top band
+	UpTo db.Money `json:"up_to,omitempty" valid:"nonnegative"`
+	Rate float64  `json:"rate" valid:"required,range(0|100)"`
+}
+
+type UpdateCommissionScheme struct {
+	Name          string   `json:"name,omitempty" valid:"length(2|100)"`
+	Method        string   `json:"method,omitempty" valid:"in(Percentage|Tiered|Flat)"`
+	Rate          float64  `json:"rate,omitempty" valid:"range(0|100)"`
+	FlatFee       db.Money `json:"flat_fee,omitempty" valid:"nonnegative"`
+	CoAgencySplit float64  `json:"co_agency_split,omitempty" valid:"range(0|100)"`
+	DefaultFor    string   `json:"default_for,omitempty" valid:"in(Sale|Lease|Management|Other)"`
+	// Replaces the bands if provided
+	Tiers []CommissionTier `json:"tiers,omitempty" valid:""`
+}
+
+// Agents sharing the agency's commission on a transaction. Shares must total 100
+type SetCommissionSplits struct {
+	Splits []CommissionSplitShare `json:"splits" valid:"required"`
+}
+
+type CommissionSplitShare struct {
+	User db.User `json:"user" valid:"required"`
+	// Percentage of the agency's commission
+	Share float64 `json:"share" valid:"required,range(0|100)"`
+}
+
+// Commission earned by an agent in a month
+type AgentCommission struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	// Month of completion (YYYY-MM)
+	Period       string   `json:"period"`
+	Transactions int      `json:"transactions"`
+	Amount       db.Money `json:"amount"`
+}
+
+// Commission on transactions completed over a period
+type CommissionReport struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Transactions int       `json:"transactions"`
+	// Currency amounts are reported in
+	Currency string `json:"currency"`
+	// Commission including the co-agencies' shares
+	TotalFees     db.Money `json:"total_fees"`
+	OwnAgencyFees db.Money `json:"own_agency_fees"`
+	CoAgencyFees  db.Money `json:"co_agency_fees"`
+	// Agency commission on transactions without agent splits
+	Unallocated db.Money          `json:"unallocated"`
+	Agents      []AgentCommission `json:"agents"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateExchangeRate struct {
+	BaseCurrency  string `json:"base_currency" valid:"required,currency"`
+	QuoteCurrency string `json:"quote_currency" valid:"required,currency"`
+	// Units of the quote currency per unit of the base currency
+	Rate          float64   `json:"rate" valid:"required,range(0|1000000000)"`
+	EffectiveDate time.Time `json:"effective_date" valid:"required"`
+}
+
+type UpdateExchangeRate struct {
+	Rate          float64   `json:"rate,omitempty" valid:"range(0|1000000000)"`
+	EffectiveDate time.Time `json:"effective_date,omitempty" valid:""`
+}
+
+// Result of importing exchange rates from CSV. Rows with errors are skipped
+type ExchangeRateImport struct {
+	// Rates added
+	Created int `json:"created"`
+	// Rates replaced for a currency pair and date that already had one
+	Updated int      `json:"updated"`
+	Errors  []string `json:"errors"`
+}
+
+// Amount converted into another currency at the rate effective on a date
+type CurrencyConversion struct {
+	Amount    db.Money  `json:"amount"`
+	Converted db.Money  `json:"converted"`
+	Rate      float64   `json:"rate"`
+	Date      time.Time `json:"date"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateLease struct {
+	StartDate time.Time `json:"start_date" valid:"required"`
+	// Date the lease ends. Must be after the start date
+	EndDate time.Time `json:"end_date" valid:"required"`
+	// Rent payable each period
+	RentAmount db.Money `json:"rent_amount" valid:"required,positive"`
+	// Yearly rent is paid in advance for the year, as is common in Bali
+	Frequency     string   `json:"frequency" valid:"required,in(Monthly|Quarterly|Yearly)"`
+	Deposit       db.Money `json:"deposit,omitempty" valid:"nonnegative"`
+	RenewalOption string   `json:"renewal_option,omitempty" valid:"in(None|Option to Renew|First Refusal)"`
+	// Days before the end date that admins are reminded of the expiry. Defaults to 60
+	RenewalNoticeDays int `json:"renewal_notice_days,omitempty" valid:"range(0|365)"`
+	// Charged when a rent period is unpaid after the grace period (defaults to 7 days)
+	LateFee          db.Money    `json:"late_fee,omitempty" valid:"nonnegative"`
+	LateFeeGraceDays int         `json:"late_fee_grace_days,omitempty" valid:"range(0|90)"`
+	Notes            string      `json:"notes,omitempty" valid:"length(2|500)"`
+	Property         db.Property `json:"property" valid:"required"`
+	// Unit of the property leased. Leases the whole property if not provided
+	Unit db.Unit `json:"unit,omitempty" valid:""`
+	// Contacts leasing the property
+	Tenants     []db.Contact   `json:"tenants" valid:"required"`
+	Transaction db.Transaction `json:"transaction,omitempty" valid:""`
+}
+
+// Changing the dates, rent or frequency regenerates the uncharged rent schedule
+type UpdateLease struct {
+	StartDate         time.Time    `json:"start_date,omitempty" valid:""`
+	EndDate           time.Time    `json:"end_date,omitempty" valid:""`
+	RentAmount        db.Money     `json:"rent_amount,omitempty" valid:"positive"`
+	Frequency         string       `json:"frequency,omitempty" valid:"in(Monthly|Quarterly|Yearly)"`
+	Deposit           db.Money     `json:"deposit,omitempty" valid:"nonnegative"`
+	RenewalOption     string       `json:"renewal_option,omitempty" valid:"in(None|Option to Renew|First Refusal)"`
+	RenewalNoticeDays int          `json:"renewal_notice_days,omitempty" valid:"range(0|365)"`
+	LateFee           db.Money     `json:"late_fee,omitempty" valid:"nonnegative"`
+	LateFeeGraceDays  int          `json:"late_fee_grace_days,omitempty" valid:"range(0|90)"`
+	Notes             string       `json:"notes,omitempty" valid:"length(2|500)"`
+	Tenants           []db.Contact `json:"tenants,omitempty" valid:""`
+}
+
+// Renews a lease as a new lease starting when it ends
+type RenewLease struct {
+	// Length of the new term
+	TermMonths int `json:"term_months" valid:"required,range(1|360)"`
+	// Rent of the new term. Defaults to the current rent plus any increase
+	RentAmount db.Money `json:"rent_amount,omitempty" valid:"positive"`
+	// Percentage increase on the current rent when no rent amount is provided
+	RentIncreasePercent float64 `json:"rent_increase_percent,omitempty" valid:"range(0|1000)"`
+	// Defaults to the current frequency
+	Frequency string `json:"frequency,omitempty" valid:"in(Monthly|Quarterly|Yearly)"`
+	Notes     string `json:"notes,omitempty" valid:"length(2|500)"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service. Rent charges are generated from the lease's rent schedule
+type CreateLedgerEntry struct {
+	// Receipt, Adjustment, Late Fee, Deposit Received or Deposit Refund
+	Type string `json:"type" valid:"required,in(Receipt|Adjustment|Late Fee|Deposit Received|Deposit Refund)"`
+	// Positive except for adjustments, where a negative amount reduces what the tenant owes
+	Amount db.Money `json:"amount" valid:"required"`
+	// Defaults to today
+	EntryDate   time.Time `json:"entry_date,omitempty" valid:""`
+	Description string    `json:"description,omitempty" valid:"length(2|500)"`
+	// Receipt, transfer or invoice number
+	Reference string   `json:"reference,omitempty" valid:"length(1|100)"`
+	Lease     db.Lease `json:"lease" valid:"required"`
+}
+
+// Ledger entry with the balances after it
+type StatementLine struct {
+	db.LedgerEntry
+	// Owed by the tenant
+	RentBalance db.Money `json:"rent_balance"`
+	// Held for the tenant
+	DepositBalance db.Money `json:"deposit_balance"`
+}
+
+// Statement of a lease's ledger over a period
+type LeaseStatement struct {
+	LeaseID    uint      `json:"lease_id"`
+	PropertyID uint      `json:"property_id"`
+	Property   string    `json:"property"`
+	Tenants    []string  `json:"tenants"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	// Balances before the period
+	OpeningRentBalance    db.Money        `json:"opening_rent_balance"`
+	OpeningDepositBalance db.Money        `json:"opening_deposit_balance"`
+	Lines                 []StatementLine `json:"lines"`
+	ClosingRentBalance    db.Money        `json:"closing_rent_balance"`
+	ClosingDepositBalance db.Money        `json:"closing_deposit_balance"`
+	// Unpaid charges past their due date
+	Arrears ArrearsAging `json:"arrears"`
+}
+
+// Unpaid charges by days past their due date
+type ArrearsAging struct {
+	Days1To30  db.Money `json:"days_1_to_30"`
+	Days31To60 db.Money `json:"days_31_to_60"`
+	Days61To90 db.Money `json:"days_61_to_90"`
+	Over90     db.Money `json:"over_90"`
+	Total      db.Money `json:"total"`
+}
+
+// Arrears of a lease
+type LeaseArrears struct {
+	LeaseID    uint     `json:"lease_id"`
+	PropertyID uint     `json:"property_id"`
+	Property   string   `json:"property"`
+	Tenants    []string `json:"tenants"`
+	// Lease status (Upcoming, Active, Expiring or Ended)
+	Status string `json:"status"`
+	// Owed including charges that aren't yet overdue
+	RentBalance    db.Money  `json:"rent_balance"`
+	DepositBalance db.Money  `json:"deposit_balance"`
+	OldestDueDate  time.Time `json:"oldest_due_date"`
+	DaysOverdue    int       `json:"days_overdue"`
+	ArrearsAging
+}
+
+// Leases of managed properties with overdue charges
+type ArrearsReport struct {
+	AsOf time.Time `json:"as_of"`
+	// Currency amounts are reported in
+	Currency string         `json:"currency"`
+	Leases   []LeaseArrears `json:"leases"`
+	Total    ArrearsAging   `json:"total"`
+}
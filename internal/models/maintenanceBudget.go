@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateMaintenanceBudget struct {
+	Year int `json:"year" valid:"required,range(2000|2100)"`
+	// 1 to 12 for a monthly budget. Leave empty for an annual budget
+	Month  int      `json:"month,omitempty" valid:"range(0|12)"`
+	Amount db.Money `json:"amount" valid:"required,positive"`
+	// Percentage of the budget spent before admins are alerted. Defaults to 90
+	AlertThreshold float64     `json:"alert_threshold,omitempty" valid:"range(1|1000)"`
+	Notes          string      `json:"notes,omitempty" valid:"length(2|500)"`
+	Property       db.Property `json:"property" valid:"required"`
+	// Budget covers all work types if not provided
+	WorkType db.WorkType `json:"work_type,omitempty" valid:""`
+}
+
+type UpdateMaintenanceBudget struct {
+	Amount         db.Money `json:"amount,omitempty" valid:"positive"`
+	AlertThreshold float64  `json:"alert_threshold,omitempty" valid:"range(1|1000)"`
+	Notes          string   `json:"notes,omitempty" valid:"length(2|500)"`
+}
+
+// Actual maintenance spend against a budget
+type MaintenanceBudgetStatus struct {
+	Budget      db.MaintenanceBudget `json:"budget"`
+	PeriodStart time.Time            `json:"period_start"`
+	PeriodEnd   time.Time            `json:"period_end"`
+	MaintenanceCosts
+	Remaining   db.Money `json:"remaining"`
+	PercentUsed float64  `json:"percent_used"`
+	// Spend has reached the budget's alert threshold
+	OverThreshold bool `json:"over_threshold"`
+}
+
+// Maintenance spend over a period
+type MaintenanceCosts struct {
+	// Vendor invoices dated within the period
+	InvoicedCosts db.Money `json:"invoiced_costs"`
+	// Cost and tax recorded on requests raised within the period without vendor invoices
+	RequestCosts db.Money `json:"request_costs"`
+	Actual       db.Money `json:"actual"`
+}
+
+// Actual vs budget report of a property for a year
+type MaintenanceBudgetReport struct {
+	PropertyID uint `json:"property_id"`
+	Year       int  `json:"year"`
+	// Currency amounts are reported in
+	Currency string `json:"currency"`
+	// Annual and monthly budgets for the year
+	Budgets []MaintenanceBudgetStatus `json:"budgets"`
+	// Spend across all work types per month (January first)
+	Months []MaintenanceCosts `json:"months"`
+	Total  MaintenanceCosts   `json:"total"`
+}
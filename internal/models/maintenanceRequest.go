@@ -5,25 +5,55 @@ import (
 )
 
 type CreateMaintenanceRequest struct {
-	WorkDefinition string  `json:"work_definition,omitempty" valid:"required,in(Repair|Replacement|Project|Investigation|Pest Control|Other)"`
-	Type           string  `json:"type,omitempty" valid:"in(Electrical|Plumbing|Painting|HVAC|Civil|Other)"`
-	Notes          string  `json:"notes,omitempty" valid:"length(5|500)"`
-	Scale          string  `json:"scale,omitempty" valid:"required,in(Urgent|High|Medium|Low)"`
-	TotalCost      float64 `json:"total_cost,omitempty" valid:"float"`
-	Tax            float64 `json:"tax,omitempty" valid:"float"`
+	WorkDefinition string   `json:"work_definition,omitempty" valid:"required,in(Repair|Replacement|Project|Investigation|Pest Control|Other)"`
+	Type           string   `json:"type,omitempty" valid:"in(Electrical|Plumbing|Painting|HVAC|Civil|Other)"`
+	Notes          string   `json:"notes,omitempty" valid:"length(5|500)"`
+	Scale          string   `json:"scale,omitempty" valid:"required,in(Urgent|High|Medium|Low)"`
+	TotalCost      db.Money `json:"total_cost,omitempty" valid:"nonnegative"`
+	Tax            db.Money `json:"tax,omitempty" valid:"nonnegative"`
 
 	// Relationships (Not editable through update)
 	Property db.Property `json:"property,omitempty" valid:"required"`
-	Task     db.Task     `json:"task,omitempty" valid:"required"`
+	// Unit of the property the work is for, if any
+	Unit db.Unit `json:"unit,omitempty" valid:""`
+	// Existing task the request belongs to (see POST /api/tasks/with-maintenance to create both)
+	Task db.Task `json:"task,omitempty" valid:""`
+	// Used to find vendors to invite to quote
+	WorkType db.WorkType `json:"work_type,omitempty" valid:""`
 }
 
 type UpdateMaintenanceRequest struct {
-	WorkDefinition string  `json:"work_definition,omitempty" valid:"in(Repair|Replacement|Project|Investigation|Pest Control|Other)"`
-	Type           string  `json:"type,omitempty" valid:"in(Electrical|Plumbing|Painting|HVAC|Civil|Other)"`
-	Notes          string  `json:"notes,omitempty" valid:"length(5|500)"`
-	Scale          string  `json:"scale,omitempty" valid:"in(Urgent|High|Medium|Low)"`
-	TotalCost      float64 `json:"total_cost,omitempty" valid:"float"`
-	Tax            float64 `json:"tax,omitempty" valid:"float"`
+	WorkDefinition string   `json:"work_definition,omitempty" valid:"in(Repair|Replacement|Project|Investigation|Pest Control|Other)"`
+	Type           string   `json:"type,omitempty" valid:"in(Electrical|Plumbing|Painting|HVAC|Civil|Other)"`
+	Notes          string   `json:"notes,omitempty" valid:"length(5|500)"`
+	Scale          string   `json:"scale,omitempty" valid:"in(Urgent|High|Medium|Low)"`
+	TotalCost      db.Money `json:"total_cost,omitempty" valid:"nonnegative"`
+	Tax            db.Money `json:"tax,omitempty" valid:"nonnegative"`
 	// Relationships (Not editable through update)
 	Property db.Property `json:"property,omitempty" valid:""`
+	WorkType db.WorkType `json:"work_type,omitempty" valid:""`
+	// Unit of the property. The current unit must belong to a new property
+	Unit db.Unit `json:"unit,omitempty" valid:""`
+}
+
+// Vendor ranked for dispatch to a maintenance request
+type SuggestedVendor struct {
+	VendorID    uint   `json:"vendor_id"`
+	CompanyName string `json:"company_name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	// Higher scores are better matches
+	Score float64 `json:"score"`
+	// Vendor offers the request's work type
+	WorkTypeMatch bool `json:"work_type_match"`
+	// Closest shared location with the property: Suburb, City, Province or None
+	Proximity string `json:"proximity"`
+	// Past performance
+	CompletedWorkOrders int     `json:"completed_work_orders"`
+	OnTimeRate          float64 `json:"on_time_rate"`
+	Ratings             int     `json:"ratings"`
+	// Average of all rating scores out of 5
+	AverageRating float64 `json:"average_rating"`
+	// Current workload
+	OpenWorkOrders int `json:"open_work_orders"`
 }
@@ -0,0 +1,30 @@
+package models
+
+// Event types that users can be notified of
+var NotificationEventTypes = []string{"Mention", "TaskAssigned", "SnoozeExpired", "MaintenanceEscalated", "TransactionCompleted", "VendorNonCompliant", "VendorDocumentExpiring", "MaintenanceBudgetExceeded", "TenantRequestSubmitted", "LeaseExpiring"}
+
+// Event sent to the notification service for delivery.
+// If no recipients are provided, the assignees of the task are notified
+type NotificationEvent struct {
+	Type          string `json:"type" valid:"required,in(Mention|TaskAssigned|SnoozeExpired|MaintenanceEscalated|TransactionCompleted|VendorNonCompliant|VendorDocumentExpiring|MaintenanceBudgetExceeded|TenantRequestSubmitted|LeaseExpiring)"`
+	Message       string `json:"message" valid:"required,length(3|300)"`
+	UserIDs       []uint `json:"user_ids,omitempty" valid:""`
+	TaskID        uint   `json:"task_id,omitempty" valid:""`
+	TaskCommentID uint   `json:"task_comment_id,omitempty" valid:""`
+	// User that triggered the event (not notified)
+	ActorID uint `json:"actor_id,omitempty" valid:""`
+}
+
+// Struct received by controller/handler to change read state
+type UpdateNotification struct {
+	Read bool `json:"read" valid:""`
+}
+
+// Struct received by controller/handler to change delivery preferences for an event type. Webhook URLs must be https on a public host
+type UpdateNotificationPreference struct {
+	EventType  string `json:"event_type" valid:"required,in(Mention|TaskAssigned|SnoozeExpired|MaintenanceEscalated|TransactionCompleted|VendorNonCompliant|VendorDocumentExpiring|MaintenanceBudgetExceeded|TenantRequestSubmitted|LeaseExpiring)"`
+	InApp      bool   `json:"in_app" valid:""`
+	Email      bool   `json:"email" valid:""`
+	Webhook    bool   `json:"webhook" valid:""`
+	WebhookURL string `json:"webhook_url,omitempty" valid:"url,webhookurl"`
+}
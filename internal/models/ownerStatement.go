@@ -0,0 +1,17 @@
+package models
+
+import "github.com/dmawardi/Go-Template/internal/db"
+
+// Struct received by controller/handler and service. Generates (or regenerates a draft) statement for the month
+type GenerateOwnerStatement struct {
+	Year     int         `json:"year" valid:"required,range(2000|2100)"`
+	Month    int         `json:"month" valid:"required,range(1|12)"`
+	Property db.Property `json:"property" valid:"required"`
+}
+
+// Records the payment to the owner of a draft statement
+type UpdateOwnerStatement struct {
+	PaidToOwner      db.Money `json:"paid_to_owner,omitempty" valid:"nonnegative"`
+	PaymentReference string   `json:"payment_reference,omitempty" valid:"length(1|100)"`
+	Notes            string   `json:"notes,omitempty" valid:"length(2|500)"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreatePipelineStage struct {
+	Name            string `json:"name" valid:"required,length(2|50)"`
+	TransactionType string `json:"transaction_type" valid:"required,in(Sale|Lease|Management|Other)"`
+	// Added to the end of the pipeline if not provided
+	Position int `json:"position,omitempty" valid:"range(1|100)"`
+	// Days after entering the stage its milestone is due
+	DueDays   int  `json:"due_days,omitempty" valid:"range(1|3650)"`
+	Completes bool `json:"completes,omitempty" valid:""`
+}
+
+type UpdatePipelineStage struct {
+	Name      string `json:"name,omitempty" valid:"length(2|50)"`
+	Position  int    `json:"position,omitempty" valid:"range(1|100)"`
+	DueDays   int    `json:"due_days,omitempty" valid:"range(1|3650)"`
+	Completes bool   `json:"completes,omitempty" valid:""`
+}
+
+// Moves a transaction to a stage of its pipeline
+type MoveTransactionStage struct {
+	Stage db.PipelineStage `json:"stage" valid:"required"`
+	Notes string           `json:"notes,omitempty" valid:"length(2|500)"`
+}
+
+type CreateTransactionMilestone struct {
+	Name        string         `json:"name" valid:"required,length(2|100)"`
+	DueDate     time.Time      `json:"due_date" valid:"required"`
+	Transaction db.Transaction `json:"transaction" valid:"required"`
+}
+
+type UpdateTransactionMilestone struct {
+	Name    string    `json:"name,omitempty" valid:"length(2|100)"`
+	DueDate time.Time `json:"due_date,omitempty" valid:""`
+	// Marks the milestone as reached
+	CompletedAt time.Time `json:"completed_at,omitempty" valid:""`
+}
+
+// Transactions currently at a pipeline stage
+type PipelineStageSummary struct {
+	StageID      uint     `json:"stage_id"`
+	Stage        string   `json:"stage"`
+	Position     int      `json:"position"`
+	Transactions int      `json:"transactions"`
+	Value        db.Money `json:"value"`
+	// Milestones past their due date of the stage's transactions
+	OverdueMilestones int `json:"overdue_milestones"`
+}
+
+// Pipeline of a transaction type
+type PipelineSummary struct {
+	TransactionType string `json:"transaction_type"`
+	// Currency values are reported in
+	Currency string                 `json:"currency"`
+	Stages   []PipelineStageSummary `json:"stages"`
+	// Transactions that haven't entered the pipeline
+	Unstaged      int      `json:"unstaged"`
+	UnstagedValue db.Money `json:"unstaged_value"`
+	Transactions  int      `json:"transactions"`
+	Value         db.Money `json:"value"`
+}
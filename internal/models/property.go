@@ -7,6 +7,7 @@ type CreateProperty struct {
 	Property_Name    string       `json:"property_name" valid:"length(6|25),required"`
 	Suburb           string       `json:"suburb" valid:"length(4|25)"`
 	City             string       `json:"city" valid:"length(4|25)"`
+	Province         string       `json:"province" valid:"in(Aceh|Bali|Banten|Bengkulu|Central Java|Central Kalimantan|Central Sulawesi|East Java|East Kalimantan|East Nusa Tenggara|Gorontalo|DKI Jakarta|Jambi|Lampung|Maluku|North Kalimantan|North Maluku|North Sulawesi|North Sumatra|Papua|Riau|Riau Islands|South Kalimantan|South Sulawesi|South Sumatra|Southeast Sulawesi|West Java|West Kalimantan|West Nusa Tenggara|West Papua|West Sulawesi|West Sumatra|Yogyakarta Special Region)"`
 	Street_Address_1 string       `json:"street_address_1" valid:"length(6|32),required"`
 	Street_Address_2 string       `json:"street_address_2" valid:"length(6|32)"`
 	Bedrooms         float32      `json:"bedrooms" valid:"float"`
@@ -23,6 +24,7 @@ type UpdateProperty struct {
 	Property_Name    string       `json:"property_name,omitempty" valid:"length(6|25)"`
 	Suburb           string       `json:"suburb,omitempty" valid:"length(4|25)"`
 	City             string       `json:"city,omitempty" valid:"length(4|25)"`
+	Province         string       `json:"province,omitempty" valid:"in(Aceh|Bali|Banten|Bengkulu|Central Java|Central Kalimantan|Central Sulawesi|East Java|East Kalimantan|East Nusa Tenggara|Gorontalo|DKI Jakarta|Jambi|Lampung|Maluku|North Kalimantan|North Maluku|North Sulawesi|North Sumatra|Papua|Riau|Riau Islands|South Kalimantan|South Sulawesi|South Sumatra|Southeast Sulawesi|West Java|West Kalimantan|West Nusa Tenggara|West Papua|West Sulawesi|West Sumatra|Yogyakarta Special Region)"`
 	Street_Address_1 string       `json:"street_address_1,omitempty" valid:"length(6|32)"`
 	Street_Address_2 string       `json:"street_address_2,omitempty" valid:"length(6|32)"`
 	Bedrooms         float32      `json:"bedrooms,omitempty" valid:"number"`
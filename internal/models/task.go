@@ -9,27 +9,85 @@ import (
 type CreateTask struct {
 	// Required fields
 	TaskName string `json:"task_name,omitempty" valid:"required, length(3|36)"`
-	Status   string `json:"status,omitempty" valid:"length(3|36), in(Created|Open|Pending|Cancelled|Processing|Active|Completed|Archived)"`
+	Status   string `json:"status,omitempty" valid:"length(3|36), in(Triage|Created|Open|Pending|Cancelled|Processing|Active|Completed|Archived)"`
 	Type     string `json:"type,omitempty" valid:"required,in(Maintenance|Inspection|Transaction|Other)"`
 	// Optional fields
 	Notes       string    `json:"notes,omitempty" valid:"length(5|320)"`
 	SnoozedTill time.Time `json:"snoozed_till,omitempty" valid:"time"`
-	Snoozed     bool      `json:"snoozed,omitempty" valid:"bool"`
-	Completed   bool      `json:"completed,omitempty" valid:"bool"`
+	DueDate     time.Time `json:"due_date,omitempty" valid:""`
+	Snoozed     bool      `json:"snoozed,omitempty" valid:""`
+	Completed   bool      `json:"completed,omitempty" valid:""`
+	// Blocks completion until all subtasks are complete
+	RequireSubtasksComplete bool `json:"require_subtasks_complete,omitempty" valid:""`
 	// Relationships
 	Assignment []db.User `json:"assignment,omitempty"`
+	// Parent task ID if task is a subtask
+	ParentID uint `json:"parent_id,omitempty" valid:""`
 }
 
 type UpdateTask struct {
 	// Required fields
 	TaskName string `json:"task_name,omitempty" valid:"length(2|36)"`
-	Status   string `json:"status,omitempty" valid:"length(2|36), in(Created|Open|Pending|Cancelled|Processing|Active|Completed|Archived)"`
+	Status   string `json:"status,omitempty" valid:"length(2|36), in(Triage|Created|Open|Pending|Cancelled|Processing|Active|Completed|Archived)"`
 	Type     string `json:"type,omitempty" valid:"in(Maintenance|Inspection|Transaction|Other)"`
 	// Optional fields
 	Notes       string    `json:"notes,omitempty" valid:"length(5|320)"`
 	SnoozedTill time.Time `json:"snoozed_till,omitempty" valid:"time"`
-	Snoozed     bool      `json:"snoozed,omitempty" valid:"bool"`
-	Completed   bool      `json:"completed,omitempty" valid:"bool"`
+	DueDate     time.Time `json:"due_date,omitempty" valid:""`
+	Snoozed     bool      `json:"snoozed,omitempty" valid:""`
+	Completed   bool      `json:"completed,omitempty" valid:""`
+	// Blocks completion until all subtasks are complete
+	RequireSubtasksComplete bool `json:"require_subtasks_complete,omitempty" valid:""`
 	// Relationships
 	Assignment []db.User `json:"assignment,omitempty"`
+	// Parent task ID if task is a subtask
+	ParentID uint `json:"parent_id,omitempty" valid:""`
+}
+
+// Struct received by controller/handler when creating a task together with its transaction.
+// The transaction's task is ignored
+type CreateTaskWithTransaction struct {
+	Task        CreateTask        `json:"task" valid:"required"`
+	Transaction CreateTransaction `json:"transaction" valid:"required"`
+}
+
+// Struct received by controller/handler when creating a task together with its maintenance request.
+// The maintenance request's task is ignored
+type CreateTaskWithMaintenanceRequest struct {
+	Task               CreateTask               `json:"task" valid:"required"`
+	MaintenanceRequest CreateMaintenanceRequest `json:"maintenance_request" valid:"required"`
+}
+
+// Order of status columns on the task board
+var TaskBoardStatuses = []string{"Triage", "Created", "Open", "Pending", "Processing", "Active", "Completed", "Cancelled", "Archived"}
+
+// Tasks grouped by status
+type TaskBoard struct {
+	Columns []TaskBoardColumn `json:"columns"`
+}
+
+type TaskBoardColumn struct {
+	Status string `json:"status"`
+	// Total tasks with status (tasks list may be limited)
+	Count int64     `json:"count"`
+	Tasks []db.Task `json:"tasks"`
+}
+
+// Struct received by controller/handler when moving a task on the board
+type MoveTask struct {
+	// Column to move to. Unchanged if not provided
+	Status string `json:"status,omitempty" valid:"in(Triage|Created|Open|Pending|Cancelled|Processing|Active|Completed|Archived)"`
+	// Position within the column starting at 1. Moved to the end of the column if not provided
+	Rank int `json:"rank,omitempty" valid:""`
+}
+
+// Tasks relevant to the current user
+type MyTasks struct {
+	// Open tasks assigned to the user
+	Assigned []db.Task `json:"assigned"`
+	// Open tasks past their due date
+	Overdue []db.Task `json:"overdue"`
+	Snoozed []db.Task `json:"snoozed"`
+	// Tasks completed within the last two weeks
+	RecentlyCompleted []db.Task `json:"recently_completed"`
 }
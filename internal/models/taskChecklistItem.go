@@ -0,0 +1,19 @@
+package models
+
+import "github.com/dmawardi/Go-Template/internal/db"
+
+// Struct received by controller/handler and service
+type CreateTaskChecklistItem struct {
+	Description string `json:"description" valid:"required,length(1|255)"`
+	// Position within the checklist. Added to the end of the checklist if not provided
+	Position int     `json:"position,omitempty" valid:""`
+	Task     db.Task `json:"task" valid:"required"`
+}
+
+// Struct received by controller/handler
+type UpdateTaskChecklistItem struct {
+	Description string `json:"description,omitempty" valid:"length(1|255)"`
+	Position    int    `json:"position,omitempty" valid:""`
+	// Marks item as done or not done. Unchanged if not provided
+	Done *bool `json:"done,omitempty" valid:""`
+}
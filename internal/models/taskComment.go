@@ -0,0 +1,23 @@
+package models
+
+import "github.com/dmawardi/Go-Template/internal/db"
+
+// Struct required by Task Comment service
+type CreateTaskComment struct {
+	User     db.User `json:"user" valid:"required"`
+	Task     db.Task `json:"task" valid:"required"`
+	Comment  string  `json:"comment" valid:"required,length(1|1000)"`
+	ParentID uint    `json:"parent_id,omitempty" valid:""`
+}
+
+// Struct received by controller/handler
+type RecvTaskComment struct {
+	Comment  string  `json:"comment" valid:"required,length(1|1000)"`
+	Task     db.Task `json:"task" valid:"required"`
+	ParentID uint    `json:"parent_id,omitempty" valid:""`
+}
+
+// Struct received by service (only the comment can be edited)
+type UpdateTaskComment struct {
+	Comment string `json:"comment" valid:"required,length(1|1000)"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "github.com/dmawardi/Go-Template/internal/db"
+
+// Struct received by controller/handler and service
+type CreateTaskDependency struct {
+	// Task that is blocked
+	Task db.Task `json:"task" valid:"required"`
+	// Task that must be completed first
+	BlockedBy db.Task `json:"blocked_by" valid:"required"`
+}
+
+// Dependency graph of a property's open tasks
+type TaskDependencyGraph struct {
+	Nodes []TaskGraphNode `json:"nodes"`
+	Edges []TaskGraphEdge `json:"edges"`
+}
+
+type TaskGraphNode struct {
+	ID       uint   `json:"id"`
+	TaskName string `json:"task_name"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	// True if the task has incomplete blocking tasks
+	Blocked bool `json:"blocked"`
+}
+
+// Edge from the blocking task to the task it blocks
+type TaskGraphEdge struct {
+	From uint `json:"from"`
+	To   uint `json:"to"`
+}
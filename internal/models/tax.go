@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateTaxRule struct {
+	// PPN, PPh 4(2) or PPh 23
+	Tax string `json:"tax" valid:"required,in(PPN|PPh 4(2)|PPh 23)"`
+	// Rent, Commission or Vendor Invoice
+	AppliesTo string `json:"applies_to" valid:"required,in(Rent|Commission|Vendor Invoice)"`
+	// Percentage of the taxable amount. Zero exempts the income or expense from the tax
+	Rate float64 `json:"rate" valid:"range(0|100)"`
+	// Withheld from the amount paid rather than added to it (eg. PPh 23 withheld from vendor payments)
+	Withheld      bool      `json:"withheld,omitempty"`
+	EffectiveDate time.Time `json:"effective_date" valid:"required"`
+	Notes         string    `json:"notes,omitempty" valid:"length(2|500)"`
+}
+
+// Rules that have been applied should be replaced by a rule effective from a later date rather than updated.
+// A new rule with a zero rate ends a tax
+type UpdateTaxRule struct {
+	Rate          float64   `json:"rate,omitempty" valid:"range(0|100)"`
+	EffectiveDate time.Time `json:"effective_date,omitempty" valid:""`
+	Notes         string    `json:"notes,omitempty" valid:"length(2|500)"`
+}
+
+// Tax lines of a tax and what it applies to over a month
+type TaxSummaryLine struct {
+	Tax       string `json:"tax"`
+	AppliesTo string `json:"applies_to"`
+	Withheld  bool   `json:"withheld"`
+	Lines     int    `json:"lines"`
+	// Amount taxed
+	Base   db.Money `json:"base"`
+	Amount db.Money `json:"amount"`
+}
+
+// Tax computed on rent, commission and vendor invoices over a month
+type TaxSummary struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	// Currency amounts are reported in
+	Currency string           `json:"currency"`
+	Taxes    []TaxSummaryLine `json:"taxes"`
+	// PPN charged on commission (output tax)
+	PPNOutput db.Money `json:"ppn_output"`
+	// PPN paid on vendor invoices (input tax credited against output tax)
+	PPNInput db.Money `json:"ppn_input"`
+	// Output less input tax. Negative when input tax exceeds output tax
+	PPNPayable db.Money `json:"ppn_payable"`
+	// PPh 4(2) due on rent
+	RentIncomeTax db.Money `json:"rent_income_tax"`
+	// Income tax withheld from vendor payments
+	WithheldTax db.Money `json:"withheld_tax"`
+}
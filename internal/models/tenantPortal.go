@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Maximum number of photos attached to a tenant maintenance request
+const TenantPortalMaxPhotos = 5
+
+// Maximum size of each photo attached to a tenant maintenance request
+const TenantPortalMaxPhotoSize = 5 << 20
+
+// Maximum size of a tenant maintenance submission, allowing 1 MB for the form fields alongside the photos
+const TenantPortalMaxSubmissionSize = TenantPortalMaxPhotos*TenantPortalMaxPhotoSize + 1<<20
+
+// Maintenance request submitted by a tenant through the portal (multipart form)
+type TenantMaintenanceRequest struct {
+	Description string `json:"description" valid:"required,length(10|2000)"`
+	Category    string `json:"category" valid:"required,in(Electrical|Plumbing|Painting|HVAC|Civil|Other)"`
+	Name        string `json:"name" valid:"required,length(2|100)"`
+	// Phone number, WhatsApp or email to follow up with
+	Contact string `json:"contact" valid:"required,length(5|100)"`
+}
+
+// Returned to the tenant after submitting a maintenance request
+type TenantMaintenanceReceipt struct {
+	TrackingCode string    `json:"tracking_code"`
+	Status       string    `json:"status"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	Photos       int       `json:"photos"`
+}
+
+// Progress of a tenant's maintenance request. Excludes internal details
+type TenantMaintenanceStatus struct {
+	TrackingCode string `json:"tracking_code"`
+	Category     string `json:"category"`
+	Description  string `json:"description"`
+	// Received, In Progress, Scheduled, Completed or Closed
+	Status      string    `json:"status"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Start of the next scheduled vendor visit
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+}
+
+// Portal access of a property
+type PropertyPortalToken struct {
+	PropertyID  uint   `json:"property_id"`
+	PortalToken string `json:"portal_token"`
+	// Path of the property's tenant maintenance intake
+	MaintenancePath string `json:"maintenance_path"`
+}
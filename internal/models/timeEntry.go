@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct required by time entry service
+type CreateTimeEntry struct {
+	User      db.User   `json:"user" valid:"required"`
+	Task      db.Task   `json:"task" valid:"required"`
+	StartedAt time.Time `json:"started_at" valid:"required"`
+	// Either end time or duration is required
+	EndedAt         time.Time `json:"ended_at,omitempty" valid:""`
+	DurationMinutes int       `json:"duration_minutes,omitempty" valid:""`
+	Billable        bool      `json:"billable,omitempty" valid:""`
+	Note            string    `json:"note,omitempty" valid:"length(0|500)"`
+}
+
+// Struct received by controller/handler
+type RecvTimeEntry struct {
+	Task      db.Task   `json:"task" valid:"required"`
+	StartedAt time.Time `json:"started_at" valid:"required"`
+	// Either end time or duration is required
+	EndedAt         time.Time `json:"ended_at,omitempty" valid:""`
+	DurationMinutes int       `json:"duration_minutes,omitempty" valid:""`
+	Billable        bool      `json:"billable,omitempty" valid:""`
+	Note            string    `json:"note,omitempty" valid:"length(0|500)"`
+}
+
+// Struct received by controller/handler
+type UpdateTimeEntry struct {
+	StartedAt       time.Time `json:"started_at,omitempty" valid:""`
+	EndedAt         time.Time `json:"ended_at,omitempty" valid:""`
+	DurationMinutes int       `json:"duration_minutes,omitempty" valid:""`
+	// Unchanged if not provided
+	Billable *bool  `json:"billable,omitempty" valid:""`
+	Note     string `json:"note,omitempty" valid:"length(0|500)"`
+}
+
+// Struct received by controller/handler when starting a timer
+type StartTimer struct {
+	Task     db.Task `json:"task" valid:"required"`
+	Billable bool    `json:"billable,omitempty" valid:""`
+	Note     string  `json:"note,omitempty" valid:"length(0|500)"`
+}
+
+// Total time recorded for a task, property or user
+type TimeRollup struct {
+	// ID of the task, property or user
+	ID              uint `json:"id"`
+	Entries         int  `json:"entries"`
+	Minutes         int  `json:"minutes"`
+	BillableMinutes int  `json:"billable_minutes"`
+}
@@ -11,29 +11,45 @@ type CreateTransaction struct {
 	Type   string `json:"type,omitempty" valid:"required,in(Sale|Lease|Management|Other)"`
 	Agency string `json:"agency,omitempty" valid:"required,in(Own|Other)"`
 	// Optional fields
-	TenancyType      string  `json:"tenancy_type,omitempty" valid:"in(Monthly|LongTerm|ShortTerm|Commercial|NA)"`
-	IsLease          bool    `json:"is_lease,omitempty" valid:"bool"`
-	AgencyName       string  `json:"agency_name,omitempty" valid:"length(2|36)"`
-	TransactionNotes string  `json:"transaction_notes,omitempty" valid:"length(5|320)"`
-	TransactionValue float64 `json:"transaction_value,omitempty" valid:"float"`
-	Fee              float32 `json:"fee,omitempty" valid:"float"`
+	TenancyType      string   `json:"tenancy_type,omitempty" valid:"in(Monthly|LongTerm|ShortTerm|Commercial|NA)"`
+	IsLease          bool     `json:"is_lease,omitempty" valid:"bool"`
+	AgencyName       string   `json:"agency_name,omitempty" valid:"length(2|36)"`
+	TransactionNotes string   `json:"transaction_notes,omitempty" valid:"length(5|320)"`
+	TransactionValue db.Money `json:"transaction_value,omitempty" valid:"nonnegative"`
+	// Calculated from the commission scheme when the transaction is completed
+	Fee db.Money `json:"fee,omitempty" valid:"nonnegative"`
+	// Percentage of rent received charged to the owner (Management transactions). Defaults to the rate of a Percentage scheme
+	ManagementFeeRate float64 `json:"management_fee_rate,omitempty" valid:"range(0|100)"`
+
+	// Scheme the commission is calculated with. Defaults to the scheme for the transaction type
+	CommissionScheme db.CommissionScheme `json:"commission_scheme,omitempty" valid:""`
 
 	// Relationships (Not editable through update)
 	Property db.Property `json:"property,omitempty" valid:"required"`
-	Task     db.Task     `json:"task,omitempty" valid:"required"`
+	// Unit of the property sold or leased, if any
+	Unit db.Unit `json:"unit,omitempty" valid:""`
+	// Existing task the transaction belongs to (see POST /api/tasks/with-transaction to create both)
+	Task db.Task `json:"task,omitempty" valid:""`
 }
 type UpdateTransaction struct {
 	Type   string `json:"type,omitempty" valid:"in(Sale|Lease|Management|Other)"`
 	Agency string `json:"agency,omitempty" valid:"in(Own|Other)"`
 	// Optional fields
-	IsLease          bool    `json:"is_lease,omitempty" valid:"bool"`
-	TenancyType      string  `json:"tenancy_type,omitempty" valid:"in(Monthly|LongTerm|ShortTerm|Commercial|NA)"`
-	AgencyName       string  `json:"agency_name,omitempty" valid:"length(2|36)"`
-	TransactionNotes string  `json:"transaction_notes,omitempty" valid:"length(5|320)"`
-	TransactionValue float64 `json:"transaction_value,omitempty" valid:"float"`
-	Fee              float32 `json:"fee,omitempty" valid:"float"`
+	IsLease          bool     `json:"is_lease,omitempty" valid:"bool"`
+	TenancyType      string   `json:"tenancy_type,omitempty" valid:"in(Monthly|LongTerm|ShortTerm|Commercial|NA)"`
+	AgencyName       string   `json:"agency_name,omitempty" valid:"length(2|36)"`
+	TransactionNotes string   `json:"transaction_notes,omitempty" valid:"length(5|320)"`
+	TransactionValue db.Money `json:"transaction_value,omitempty" valid:"nonnegative"`
+	// Calculated from the commission scheme when the transaction is completed
+	Fee db.Money `json:"fee,omitempty" valid:"nonnegative"`
+	// Percentage of rent received charged to the owner (Management transactions)
+	ManagementFeeRate float64 `json:"management_fee_rate,omitempty" valid:"range(0|100)"`
 	// Editable only through update
-	TransactionCompletion time.Time `json:"transaction_completion,omitempty" valid:"time"`
+	TransactionCompletion time.Time `json:"transaction_completion,omitempty" valid:""`
+	// Scheme the commission is calculated with
+	CommissionScheme db.CommissionScheme `json:"commission_scheme,omitempty" valid:""`
+	// Unit of the transaction's property sold or leased
+	Unit db.Unit `json:"unit,omitempty" valid:""`
 
 	// Relationships (Can only update contacts)
 	Contacts []db.Contact `json:"contacts,omitempty"`
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+type CreateUnit struct {
+	// Unique within the property (eg. Villa 2 or Ground Floor)
+	Name        string       `json:"name" valid:"length(1|50),required"`
+	Bedrooms    float32      `json:"bedrooms" valid:"float"`
+	Bathrooms   float32      `json:"bathrooms" valid:"float"`
+	Area        float64      `json:"area" valid:"float"`
+	Area_Metric string       `json:"area_metric" valid:"length(2|32)"`
+	Description string       `json:"description" valid:"length(5|250)"`
+	Notes       string       `json:"notes" valid:"length(5|250)"`
+	Property    db.Property  `json:"property" valid:"required"`
+	Features    []db.Feature `json:"features" valid:""`
+}
+
+// Units can't be moved to another property
+type UpdateUnit struct {
+	Name        string       `json:"name,omitempty" valid:"length(1|50)"`
+	Bedrooms    float32      `json:"bedrooms,omitempty" valid:"float"`
+	Bathrooms   float32      `json:"bathrooms,omitempty" valid:"float"`
+	Area        float64      `json:"area,omitempty" valid:"float"`
+	Area_Metric string       `json:"area_metric,omitempty" valid:"length(2|32)"`
+	Description string       `json:"description,omitempty" valid:"length(5|250)"`
+	Notes       string       `json:"notes,omitempty" valid:"length(5|250)"`
+	Features    []db.Feature `json:"features,omitempty" valid:""`
+}
+
+// Occupancy and rent received of a unit over a period
+type UnitOccupancy struct {
+	// Zero for leases of the whole property
+	UnitID uint   `json:"unit_id"`
+	Name   string `json:"name"`
+	// Leased at the end of the period (or today if earlier)
+	Occupied bool `json:"occupied"`
+	// Days of the period the unit was leased
+	LeasedDays int `json:"leased_days"`
+	// Percentage of the period the unit was leased
+	OccupancyRate float64  `json:"occupancy_rate"`
+	Income        db.Money `json:"income"`
+}
+
+// Occupancy and rent received of a property's units rolled up to the property over a period
+type PropertyOccupancy struct {
+	PropertyID   uint      `json:"property_id"`
+	PropertyName string    `json:"property_name"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	// Currency amounts are reported in
+	Currency string `json:"currency"`
+	// Properties without units are counted as a single unit
+	Units         int `json:"units"`
+	OccupiedUnits int `json:"occupied_units"`
+	VacantUnits   int `json:"vacant_units"`
+	// Percentage of unit days leased over the period
+	OccupancyRate float64         `json:"occupancy_rate"`
+	UnitOccupancy []UnitOccupancy `json:"unit_occupancy"`
+	// Rent received on leases of the whole property, which isn't split between units
+	WholePropertyIncome db.Money `json:"whole_property_income"`
+	// Rent received on all leases of the property
+	Income db.Money `json:"income"`
+}
@@ -47,6 +47,8 @@ type UpdateUser struct {
 	Password string `json:"password,omitempty" valid:"length(6|30)"`
 	Name     string `json:"name,omitempty" valid:"length(6|80)"`
 	Email    string `json:"email,omitempty" valid:"email"`
+	// Currency financial reports are shown in (IDR, USD or AUD)
+	ReportingCurrency string `json:"reporting_currency,omitempty" valid:"currency"`
 }
 type UpdatedUser struct {
 	ID        uint           `json:"id"`
@@ -5,8 +5,8 @@ import "github.com/dmawardi/Go-Template/internal/db"
 type CreateVendor struct {
 	// Required fields
 	CompanyName string `json:"company_name,omitempty" valid:"required"`
-	NPWP        string `json:"npwp,omitempty" valid:"required;length(12|20)"`
-	NIB         string `json:"nib,omitempty" valid:"length(12|20)"`
+	NPWP        string `json:"npwp,omitempty" valid:"required,npwp"`
+	NIB         string `json:"nib,omitempty" valid:"nib"`
 	Email       string `json:"email,omitempty" valid:"email"`
 	Phone       string `json:"phone,omitempty" valid:"length(8|20)"`
 	Notes       string `json:"notes,omitempty" valid:"length(8|500)"`
@@ -24,8 +24,8 @@ type CreateVendor struct {
 type UpdateVendor struct {
 	// Required fields
 	CompanyName string `json:"company_name,omitempty" valid:""`
-	NPWP        string `json:"npwp,omitempty" valid:"length(12|20)"`
-	NIB         string `json:"nib,omitempty" valid:"length(12|20)"`
+	NPWP        string `json:"npwp,omitempty" valid:"npwp"`
+	NIB         string `json:"nib,omitempty" valid:"nib"`
 	Email       string `json:"email,omitempty" valid:"email"`
 	Phone       string `json:"phone,omitempty" valid:"length(8|20)"`
 	Notes       string `json:"notes,omitempty" valid:"length(8|500)"`
@@ -39,3 +39,16 @@ type UpdateVendor struct {
 	// Relationships
 	WorkTypes []db.WorkType `json:"work_types,omitempty" valid:""`
 }
+
+// Vendor with the aggregates of its work orders and ratings used to rank it for dispatch
+type VendorWorkload struct {
+	Vendor              db.Vendor
+	CompletedWorkOrders int
+	OpenWorkOrders      int
+	// Completed work orders with a visit window, and those completed by its end
+	ScheduledCompletions int
+	OnTimeCompletions    int
+	Ratings              int
+	// Average of all rating scores out of 5
+	AverageRating float64
+}
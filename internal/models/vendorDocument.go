@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Documents a vendor must hold before being dispatched
+var RequiredVendorDocumentTypes = []string{"Business Licence", "Insurance", "Tax Registration"}
+
+// Struct received by controller/handler and service
+type CreateVendorDocument struct {
+	Type      string    `json:"type" valid:"required,in(Business Licence|Insurance|Tax Registration)"`
+	Number    string    `json:"number" valid:"required,length(1|100)"`
+	IssueDate time.Time `json:"issue_date" valid:"required"`
+	// Leave empty for documents that don't expire
+	ExpiryDate time.Time `json:"expiry_date,omitempty" valid:""`
+	Vendor     db.Vendor `json:"vendor" valid:"required"`
+}
+
+type UpdateVendorDocument struct {
+	Number     string    `json:"number,omitempty" valid:"length(1|100)"`
+	IssueDate  time.Time `json:"issue_date,omitempty" valid:""`
+	ExpiryDate time.Time `json:"expiry_date,omitempty" valid:""`
+}
+
+// Compliance of a vendor's documents
+type VendorCompliance struct {
+	VendorID    uint   `json:"vendor_id"`
+	CompanyName string `json:"company_name"`
+	// Compliant, Expiring Soon or Non-compliant
+	Status string `json:"status"`
+	// Required document types without a current document
+	Missing []string `json:"missing"`
+	// Current documents expiring within the warning period
+	Expiring []db.VendorDocument `json:"expiring"`
+	// Readable list of issues preventing dispatch
+	Warnings []string `json:"warnings"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateVendorInvoice struct {
+	InvoiceNumber string `json:"invoice_number" valid:"required,length(1|50)"`
+	// NPWP printed on the invoice. Must match the vendor's NPWP
+	VendorNPWP  string    `json:"vendor_npwp" valid:"required"`
+	InvoiceDate time.Time `json:"invoice_date" valid:"required"`
+	DueDate     time.Time `json:"due_date" valid:"required"`
+	// PPN percentage (0 for vendors that aren't PPN registered). Standard rate used if not provided
+	PPNRate *float64 `json:"ppn_rate,omitempty" valid:"range(0|100)"`
+	Notes   string   `json:"notes,omitempty" valid:"length(2|500)"`
+	// Vendor of the work order or maintenance request if not provided
+	Vendor db.Vendor `json:"vendor,omitempty" valid:""`
+	// Work order or maintenance request being invoiced (at least one required)
+	WorkOrder          db.WorkOrder          `json:"work_order,omitempty" valid:""`
+	MaintenanceRequest db.MaintenanceRequest `json:"maintenance_request,omitempty" valid:""`
+	Lines              []VendorInvoiceLine   `json:"lines" valid:"required"`
+}
+
+type VendorInvoiceLine struct {
+	Description string   `json:"description" valid:"required,length(2|300)"`
+	Quantity    float64  `json:"quantity" valid:"required"`
+	UnitPrice   db.Money `json:"unit_price" valid:"required"`
+	PPNExempt   bool     `json:"ppn_exempt,omitempty" valid:""`
+}
+
+type UpdateVendorInvoice struct {
+	InvoiceNumber string    `json:"invoice_number,omitempty" valid:"length(1|50)"`
+	InvoiceDate   time.Time `json:"invoice_date,omitempty" valid:""`
+	DueDate       time.Time `json:"due_date,omitempty" valid:""`
+	Notes         string    `json:"notes,omitempty" valid:"length(2|500)"`
+	// Replaces line items if provided. Only allowed before payments are recorded
+	Lines []VendorInvoiceLine `json:"lines,omitempty" valid:""`
+}
+
+type RecordVendorPayment struct {
+	Amount db.Money `json:"amount" valid:"required"`
+	// Now if not provided
+	PaidAt    time.Time `json:"paid_at,omitempty" valid:""`
+	Method    string    `json:"method,omitempty" valid:"in(Bank Transfer|Cash|Cheque|Card|Other)"`
+	Reference string    `json:"reference,omitempty" valid:"length(1|100)"`
+}
+
+// Outstanding vendor balances grouped by days past due
+type PayablesAging struct {
+	AsOf time.Time `json:"as_of"`
+	// Currency balances are reported in
+	Currency string              `json:"currency"`
+	Vendors  []VendorAging       `json:"vendors"`
+	Totals   PayablesAgingBucket `json:"totals"`
+}
+
+type VendorAging struct {
+	VendorID    uint   `json:"vendor_id"`
+	CompanyName string `json:"company_name"`
+	Invoices    int    `json:"invoices"`
+	PayablesAgingBucket
+}
+
+type PayablesAgingBucket struct {
+	// Not yet due
+	Current db.Money `json:"current"`
+	// 1 to 30 days past due
+	Days30 db.Money `json:"days_30"`
+	// 31 to 60 days past due
+	Days60 db.Money `json:"days_60"`
+	// 61 to 90 days past due
+	Days90 db.Money `json:"days_90"`
+	// More than 90 days past due
+	Over90 db.Money `json:"over_90"`
+	Total  db.Money `json:"total"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Invites vendors to quote on a maintenance request
+type InviteVendorQuotes struct {
+	MaintenanceRequest db.MaintenanceRequest `json:"maintenance_request" valid:"required"`
+	// Vendors to invite. All vendors offering the request's work type are invited if not provided
+	Vendors []db.Vendor `json:"vendors,omitempty" valid:""`
+}
+
+// Records the vendor's quote
+type UpdateVendorQuote struct {
+	Amount     db.Money  `json:"amount,omitempty" valid:"nonnegative"`
+	Tax        db.Money  `json:"tax,omitempty" valid:"nonnegative"`
+	ValidUntil time.Time `json:"valid_until,omitempty" valid:""`
+	Notes      string    `json:"notes,omitempty" valid:"length(2|500)"`
+	// Attachments of the request's property. Unchanged if not provided
+	Attachments []db.PropertyAttachment `json:"attachments,omitempty" valid:""`
+}
+
+// Side by side comparison of a maintenance request's quotes
+type VendorQuoteComparison struct {
+	MaintenanceRequestID uint                 `json:"maintenance_request_id"`
+	Quotes               []VendorQuoteSummary `json:"quotes"`
+	// Currency quotes are ranked in
+	Currency string `json:"currency"`
+}
+
+type VendorQuoteSummary struct {
+	ID          uint      `json:"id"`
+	VendorID    uint      `json:"vendor_id"`
+	CompanyName string    `json:"company_name"`
+	Status      string    `json:"status"`
+	Amount      db.Money  `json:"amount"`
+	Tax         db.Money  `json:"tax"`
+	Total       db.Money  `json:"total"`
+	ValidUntil  time.Time `json:"valid_until,omitempty"`
+	Attachments int       `json:"attachments"`
+	// Total converted into the comparison currency at today's rate
+	ComparableTotal db.Money `json:"comparable_total"`
+	// True if the validity date has passed
+	Expired bool `json:"expired"`
+	// True for the cheapest quote that can be accepted
+	Lowest bool `json:"lowest"`
+}
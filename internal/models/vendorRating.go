@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Struct received by controller/handler and service
+type CreateVendorRating struct {
+	Quality       int    `json:"quality" valid:"required,range(1|5)"`
+	Timeliness    int    `json:"timeliness" valid:"required,range(1|5)"`
+	Price         int    `json:"price" valid:"required,range(1|5)"`
+	Communication int    `json:"communication" valid:"required,range(1|5)"`
+	Comment       string `json:"comment,omitempty" valid:"length(2|500)"`
+	// Request must have a completed work order from the vendor
+	MaintenanceRequest db.MaintenanceRequest `json:"maintenance_request" valid:"required"`
+	// Vendor assigned to the request if not provided
+	Vendor db.Vendor `json:"vendor,omitempty" valid:""`
+}
+
+type UpdateVendorRating struct {
+	Quality       int    `json:"quality,omitempty" valid:"range(1|5)"`
+	Timeliness    int    `json:"timeliness,omitempty" valid:"range(1|5)"`
+	Price         int    `json:"price,omitempty" valid:"range(1|5)"`
+	Communication int    `json:"communication,omitempty" valid:"range(1|5)"`
+	Comment       string `json:"comment,omitempty" valid:"length(2|500)"`
+}
+
+// Rolling performance summary of a vendor
+type VendorScorecard struct {
+	VendorID    uint      `json:"vendor_id"`
+	CompanyName string    `json:"company_name"`
+	Since       time.Time `json:"since"`
+	VendorRatingAverages
+	// Hours from work order issue to vendor acceptance
+	AverageResponseHours float64 `json:"average_response_hours"`
+	// Hours from work order issue to completion
+	AverageCompletionHours float64 `json:"average_completion_hours"`
+	// Average percentage invoiced above (positive) or below (negative) accepted quotes
+	CostVariancePercent float64 `json:"cost_variance_percent"`
+	QuotesCompared      int     `json:"quotes_compared"`
+	// Ratings grouped by the maintenance request's work type
+	WorkTypes []VendorWorkTypeScore `json:"work_types"`
+}
+
+type VendorWorkTypeScore struct {
+	WorkTypeID uint   `json:"work_type_id"`
+	Name       string `json:"name"`
+	VendorRatingAverages
+}
+
+type VendorRatingAverages struct {
+	Ratings       int     `json:"ratings"`
+	Quality       float64 `json:"quality"`
+	Timeliness    float64 `json:"timeliness"`
+	Price         float64 `json:"price"`
+	Communication float64 `json:"communication"`
+	// Average of all four scores
+	Overall float64 `json:"overall"`
+}
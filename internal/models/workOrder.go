@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+)
+
+// Work order states in lifecycle order
+var WorkOrderStatuses = []string{"Issued", "Accepted", "Scheduled", "In Progress", "Completed", "Invoiced", "Paid"}
+
+// Struct received by controller/handler and service
+type CreateWorkOrder struct {
+	MaintenanceRequest db.MaintenanceRequest `json:"maintenance_request" valid:"required"`
+	// Vendor assigned to the maintenance request if not provided
+	Vendor      db.Vendor `json:"vendor,omitempty" valid:""`
+	Description string    `json:"description,omitempty" valid:"required,length(3|1000)"`
+	// Scheduled visit window
+	ScheduledStart time.Time `json:"scheduled_start,omitempty" valid:""`
+	ScheduledEnd   time.Time `json:"scheduled_end,omitempty" valid:""`
+	// Maintenance request's cost if not provided
+	Cost  db.Money `json:"cost,omitempty" valid:"nonnegative"`
+	Tax   db.Money `json:"tax,omitempty" valid:"nonnegative"`
+	Notes string   `json:"notes,omitempty" valid:"length(2|500)"`
+}
+
+type UpdateWorkOrder struct {
+	Description    string    `json:"description,omitempty" valid:"length(3|1000)"`
+	ScheduledStart time.Time `json:"scheduled_start,omitempty" valid:""`
+	ScheduledEnd   time.Time `json:"scheduled_end,omitempty" valid:""`
+	Cost           db.Money  `json:"cost,omitempty" valid:"nonnegative"`
+	Tax            db.Money  `json:"tax,omitempty" valid:"nonnegative"`
+	Notes          string    `json:"notes,omitempty" valid:"length(2|500)"`
+}
+
+// Moves a work order to its next state. A visit window is required to schedule
+type UpdateWorkOrderStatus struct {
+	Status         string    `json:"status" valid:"required,in(Issued|Accepted|Scheduled|In Progress|Completed|Invoiced|Paid)"`
+	ScheduledStart time.Time `json:"scheduled_start,omitempty" valid:""`
+	ScheduledEnd   time.Time `json:"scheduled_end,omitempty" valid:""`
+}
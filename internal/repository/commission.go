@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type CommissionRepository interface {
+	FindAllSchemes(int, int, string) (*[]db.CommissionScheme, error)
+	FindSchemeById(int) (*db.CommissionScheme, error)
+	CreateScheme(*db.CommissionScheme) (*db.CommissionScheme, error)
+	UpdateScheme(int, *db.CommissionScheme) (*db.CommissionScheme, error)
+	DeleteScheme(int) error
+	// Replaces the bands of a tiered scheme
+	ReplaceTiers(uint, []db.CommissionTier) error
+	// Find the scheme new transactions of a type use by default
+	FindDefaultScheme(string) (*db.CommissionScheme, error)
+	// Clears the default transaction type of other schemes
+	ClearDefault(string, uint) error
+	// Count of transactions calculated with a scheme
+	CountSchemeTransactions(uint) (int64, error)
+	// Replaces the agents sharing a transaction's commission
+	ReplaceSplits(uint, []db.CommissionSplit) error
+	// Saves a transaction's calculated commission, agent split amounts and tax
+	SaveCommission(*db.Transaction) error
+	// Find transactions completed between two times with their agent splits
+	FindCompletedTransactions(time.Time, time.Time) (*[]db.Transaction, error)
+}
+
+type commissionRepository struct {
+	DB *gorm.DB
+}
+
+func NewCommissionRepository(db *gorm.DB) CommissionRepository {
+	return &commissionRepository{db}
+}
+
+// Creates a commission scheme with its tiers in the database
+func (r *commissionRepository) CreateScheme(scheme *db.CommissionScheme) (*db.CommissionScheme, error) {
+	// Create new scheme in database
+	result := r.DB.Create(&scheme)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating commission scheme: %w", result.Error)
+	}
+
+	return scheme, nil
+}
+
+// Find a list of commission schemes in the database
+func (r *commissionRepository) FindAllSchemes(limit int, offset int, order string) (*[]db.CommissionScheme, error) {
+	// Query all schemes based on the received parameters
+	schemes, err := QueryAllCommissionSchemesBasedOnParams(limit, offset, order, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of commission schemes: %s", err)
+		return nil, err
+	}
+
+	return &schemes, nil
+}
+
+// Find a commission scheme in database by ID
+func (r *commissionRepository) FindSchemeById(id int) (*db.CommissionScheme, error) {
+	// Create an empty ref object of type commission scheme
+	scheme := db.CommissionScheme{}
+	// Grab scheme from db if exists
+	result := r.DB.Preload("Tiers", func(tx *gorm.DB) *gorm.DB { return tx.Order("id ASC") }).First(&scheme, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &scheme, nil
+}
+
+// Delete commission scheme and its tiers in database
+func (r *commissionRepository) DeleteScheme(id int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("commission_scheme_id = ?", id).Delete(&db.CommissionTier{})
+		if result.Error != nil {
+			return fmt.Errorf("failed removing commission tiers: %w", result.Error)
+		}
+		result = tx.Delete(&db.CommissionScheme{}, id)
+		if result.Error != nil {
+			fmt.Println("error in deleting commission scheme: ", result.Error)
+			return result.Error
+		}
+		return nil
+	})
+}
+
+// Updates commission scheme in database
+func (r *commissionRepository) UpdateScheme(id int, scheme *db.CommissionScheme) (*db.CommissionScheme, error) {
+	// Init
+	var err error
+	// Find scheme by id to ensure it exists
+	foundScheme, err := r.FindSchemeById(id)
+	if err != nil {
+		fmt.Println("Commission scheme to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found scheme with details from scheme
+	updateResult := r.DB.Model(&foundScheme).Omit("Tiers").Updates(scheme)
+	if updateResult.Error != nil {
+		fmt.Println("Commission scheme update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated scheme by id
+	updatedScheme, err := r.FindSchemeById(id)
+	if err != nil {
+		fmt.Println("Updated commission scheme not found: ", err)
+		return nil, err
+	}
+	return updatedScheme, nil
+}
+
+// Replaces the bands of a tiered scheme
+func (r *commissionRepository) ReplaceTiers(id uint, tiers []db.CommissionTier) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("commission_scheme_id = ?", id).Delete(&db.CommissionTier{})
+		if result.Error != nil {
+			return fmt.Errorf("failed removing commission tiers: %w", result.Error)
+		}
+		for i := range tiers {
+			tiers[i].CommissionSchemeID = id
+			result = tx.Create(&tiers[i])
+			if result.Error != nil {
+				return fmt.Errorf("failed creating commission tier: %w", result.Error)
+			}
+		}
+		return nil
+	})
+}
+
+// Find the scheme new transactions of a type use by default
+func (r *commissionRepository) FindDefaultScheme(transactionType string) (*db.CommissionScheme, error) {
+	scheme := db.CommissionScheme{}
+	result := r.DB.Where("default_for = ?", transactionType).Order("updated_at DESC").First(&scheme)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &scheme, nil
+}
+
+// Clears the default transaction type of schemes other than the one provided
+func (r *commissionRepository) ClearDefault(transactionType string, exceptId uint) error {
+	result := r.DB.Model(&db.CommissionScheme{}).Where("default_for = ? AND id <> ?", transactionType, exceptId).Update("default_for", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed clearing default commission scheme: %w", result.Error)
+	}
+	return nil
+}
+
+// Count of transactions calculated with a scheme
+func (r *commissionRepository) CountSchemeTransactions(id uint) (int64, error) {
+	var count int64
+	result := r.DB.Model(&db.Transaction{}).Where("commission_scheme_id = ?", id).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// Replaces the agents sharing a transaction's commission
+func (r *commissionRepository) ReplaceSplits(transactionId uint, splits []db.CommissionSplit) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("transaction_id = ?", transactionId).Delete(&db.CommissionSplit{})
+		if result.Error != nil {
+			return fmt.Errorf("failed removing commission splits: %w", result.Error)
+		}
+		for i := range splits {
+			splits[i].TransactionID = transactionId
+			result = tx.Omit("User").Create(&splits[i])
+			if result.Error != nil {
+				return fmt.Errorf("failed creating commission split: %w", result.Error)
+			}
+		}
+		return nil
+	})
+}
+
+// Saves a transaction's calculated commission, agent split amounts and tax
+func (r *commissionRepository) SaveCommission(transaction *db.Transaction) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&db.Transaction{}).Where("id = ?", transaction.ID).UpdateColumns(moneyColumns(map[string]db.Money{
+			"fee":            transaction.Fee,
+			"own_agency_fee": transaction.OwnAgencyFee,
+			"co_agency_fee":  transaction.CoAgencyFee,
+		}))
+		if result.Error != nil {
+			return fmt.Errorf("failed saving transaction commission: %w", result.Error)
+		}
+		for _, split := range transaction.CommissionSplits {
+			result = tx.Model(&db.CommissionSplit{}).Where("id = ?", split.ID).Updates(moneyColumns(map[string]db.Money{"amount": split.Amount}))
+			if result.Error != nil {
+				return fmt.Errorf("failed saving commission split: %w", result.Error)
+			}
+		}
+		// Replace the tax on the commission
+		result = tx.Where("transaction_id = ?", transaction.ID).Delete(&db.TaxLine{})
+		if result.Error != nil {
+			return fmt.Errorf("failed replacing commission tax: %w", result.Error)
+		}
+		for i := range transaction.TaxLines {
+			transaction.TaxLines[i].TransactionID = &transaction.ID
+		}
+		if len(transaction.TaxLines) > 0 {
+			result = tx.Create(&transaction.TaxLines)
+			if result.Error != nil {
+				return fmt.Errorf("failed saving commission tax: %w", result.Error)
+			}
+		}
+		return nil
+	})
+}
+
+// Find transactions completed from (inclusive) to (exclusive) with their agent splits
+func (r *commissionRepository) FindCompletedTransactions(from time.Time, to time.Time) (*[]db.Transaction, error) {
+	transactions := []db.Transaction{}
+	result := r.DB.Preload("CommissionSplits.User").
+		Where("transaction_completion >= ? AND transaction_completion < ?", from, to).
+		Order("transaction_completion ASC").Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &transactions, nil
+}
+
+// Takes limit, offset, and order parameters, builds a query and executes returning a list of commission schemes
+func QueryAllCommissionSchemesBasedOnParams(limit int, offset int, order string, dbClient *gorm.DB) ([]db.CommissionScheme, error) {
+	// Build model to query database
+	schemes := []db.CommissionScheme{}
+	// Build base query for commission schemes table
+	query := dbClient.Model(&schemes).Preload("Tiers")
+
+	// Add parameters into query as needed
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("name ASC")
+	}
+	// Query database
+	result := query.Find(&schemes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return schemes, nil
+}
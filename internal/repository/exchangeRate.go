@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepository interface {
+	FindAll(int, int, string, string, string) (*[]db.ExchangeRate, error)
+	FindById(int) (*db.ExchangeRate, error)
+	Create(*db.ExchangeRate) (*db.ExchangeRate, error)
+	Update(int, *db.ExchangeRate) (*db.ExchangeRate, error)
+	Delete(int) error
+	// Find the rate of a currency pair for an effective date
+	FindByDate(string, string, time.Time) (*db.ExchangeRate, error)
+	// Find the latest rate of a currency pair effective on a date
+	FindEffective(string, string, time.Time) (*db.ExchangeRate, error)
+	// Records rates in one transaction, replacing rates already recorded for the pair and date. Returns the number created and updated
+	Import([]db.ExchangeRate) (int, int, error)
+}
+
+type exchangeRateRepository struct {
+	DB *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db}
+}
+
+// Creates an exchange rate in the database
+func (r *exchangeRateRepository) Create(rate *db.ExchangeRate) (*db.ExchangeRate, error) {
+	// Create new rate in database
+	result := r.DB.Create(&rate)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating exchange rate: %w", result.Error)
+	}
+
+	return rate, nil
+}
+
+// Find a list of exchange rates in the database. Filters by base and quote currency if provided
+func (r *exchangeRateRepository) FindAll(limit int, offset int, order string, base string, quote string) (*[]db.ExchangeRate, error) {
+	// Query all rates based on the received parameters
+	rates, err := QueryAllExchangeRatesBasedOnParams(limit, offset, order, base, quote, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of exchange rates: %s", err)
+		return nil, err
+	}
+
+	return &rates, nil
+}
+
+// Find an exchange rate in database by ID
+func (r *exchangeRateRepository) FindById(id int) (*db.ExchangeRate, error) {
+	// Create an empty ref object of type exchange rate
+	rate := db.ExchangeRate{}
+	// Grab rate from db if exists
+	result := r.DB.First(&rate, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &rate, nil
+}
+
+// Delete exchange rate in database
+func (r *exchangeRateRepository) Delete(id int) error {
+	// Delete rate from db if exists
+	result := r.DB.Delete(&db.ExchangeRate{}, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting exchange rate: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates exchange rate in database
+func (r *exchangeRateRepository) Update(id int, rate *db.ExchangeRate) (*db.ExchangeRate, error) {
+	// Init
+	var err error
+	// Find rate by id to ensure it exists
+	foundRate, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Exchange rate to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found rate with details from rate
+	updateResult := r.DB.Model(&foundRate).Updates(rate)
+	if updateResult.Error != nil {
+		fmt.Println("Exchange rate update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated rate by id
+	updatedRate, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated exchange rate not found: ", err)
+		return nil, err
+	}
+	return updatedRate, nil
+}
+
+// Find the rate of a currency pair for an effective date
+func (r *exchangeRateRepository) FindByDate(base string, quote string, date time.Time) (*db.ExchangeRate, error) {
+	rate := db.ExchangeRate{}
+	result := r.DB.Where("base_currency = ? AND quote_currency = ? AND effective_date = ?", base, quote, date).First(&rate)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rate, nil
+}
+
+// Find the latest rate of a currency pair effective on a date
+func (r *exchangeRateRepository) FindEffective(base string, quote string, on time.Time) (*db.ExchangeRate, error) {
+	rate := db.ExchangeRate{}
+	result := r.DB.Where("base_currency = ? AND quote_currency = ? AND effective_date <= ?", base, quote, on).
+		Order("effective_date DESC").First(&rate)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rate, nil
+}
+
+// Records imported rates, replacing rates already recorded for the pair and date. Nothing is recorded if any rate fails
+func (r *exchangeRateRepository) Import(rates []db.ExchangeRate) (int, int, error) {
+	created, updated := 0, 0
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, rate := range rates {
+			existing := db.ExchangeRate{}
+			result := tx.Where("base_currency = ? AND quote_currency = ? AND effective_date = ?", rate.BaseCurrency, rate.QuoteCurrency, rate.EffectiveDate).
+				Limit(1).Find(&existing)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				result = tx.Model(&existing).Updates(db.ExchangeRate{Rate: rate.Rate, Source: rate.Source})
+				if result.Error != nil {
+					return fmt.Errorf("failed updating exchange rate: %w", result.Error)
+				}
+				updated++
+				continue
+			}
+			result = tx.Create(&rate)
+			if result.Error != nil {
+				return fmt.Errorf("failed creating exchange rate: %w", result.Error)
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
+
+// Takes limit, offset, order, base and quote currency parameters, builds a query and executes returning a list of exchange rates
+func QueryAllExchangeRatesBasedOnParams(limit int, offset int, order string, base string, quote string, dbClient *gorm.DB) ([]db.ExchangeRate, error) {
+	// Build model to query database
+	rates := []db.ExchangeRate{}
+	// Build base query for exchange rates table
+	query := dbClient.Model(&rates)
+
+	// Add parameters into query as needed
+	if base != "" {
+		query.Where("base_currency = ?", base)
+	}
+	if quote != "" {
+		query.Where("quote_currency = ?", quote)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("effective_date DESC, base_currency ASC, quote_currency ASC")
+	}
+	// Query database
+	result := query.Find(&rates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return rates, nil
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type LeaseRepository interface {
+	FindAll(int, int, string, int, int) (*[]db.Lease, error)
+	FindById(int) (*db.Lease, error)
+	Create(*db.Lease) (*db.Lease, error)
+	Update(int, *db.Lease) (*db.Lease, error)
+	Delete(int) error
+	// Find leases of a property (or one of its units) that overlap a period
+	FindOverlapping(uint, *uint, time.Time, time.Time) (*[]db.Lease, error)
+	// Find the lease that renewed a lease
+	FindRenewalOf(uint) (*db.Lease, error)
+	// Find leases ending within a period that haven't sent a renewal reminder
+	FindUnremindedEnding(time.Time, time.Time) (*[]db.Lease, error)
+	// Records that admins have been reminded of a lease's expiry
+	MarkReminderSent(uint, time.Time) error
+	// Replaces the tenants of a lease
+	ReplaceTenants(uint, []db.Contact) error
+	// Replaces the rent periods of a lease that haven't been charged to the ledger
+	ReplaceSchedule(uint, []db.RentScheduleItem) error
+	// End of the last rent period of a lease charged to the ledger (zero if none)
+	ChargedUntil(uint) (time.Time, error)
+}
+
+type leaseRepository struct {
+	DB *gorm.DB
+}
+
+func NewLeaseRepository(db *gorm.DB) LeaseRepository {
+	return &leaseRepository{db}
+}
+
+// Creates a lease in the database
+func (r *leaseRepository) Create(lease *db.Lease) (*db.Lease, error) {
+	// Create new lease in database
+	result := r.DB.Create(&lease)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating lease: %w", result.Error)
+	}
+
+	return lease, nil
+}
+
+// Find a list of leases in the database. Filters by property and tenant if provided
+func (r *leaseRepository) FindAll(limit int, offset int, order string, propertyId int, tenantId int) (*[]db.Lease, error) {
+	// Query all leases based on the received parameters
+	leases, err := QueryAllLeasesBasedOnParams(limit, offset, order, propertyId, tenantId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of leases: %s", err)
+		return nil, err
+	}
+
+	return &leases, nil
+}
+
+// Find a lease in database by ID
+func (r *leaseRepository) FindById(id int) (*db.Lease, error) {
+	// Create an empty ref object of type lease
+	lease := db.Lease{}
+	// Grab lease from db if exists
+	result := r.DB.Preload("Property").Preload("Unit").Preload("Tenants").Preload("Transaction").
+		Preload("Schedule", func(tx *gorm.DB) *gorm.DB { return tx.Order("period_start ASC") }).
+		First(&lease, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &lease, nil
+}
+
+// Find leases of a property that overlap a period (from inclusive, to exclusive). When a unit is provided,
+// only leases of that unit or of the whole property are found
+func (r *leaseRepository) FindOverlapping(propertyId uint, unitId *uint, from time.Time, to time.Time) (*[]db.Lease, error) {
+	leases := []db.Lease{}
+	query := r.DB.Where("property_id = ? AND start_date < ? AND end_date > ?", propertyId, to, from)
+	if unitId != nil {
+		query.Where("unit_id IS NULL OR unit_id = ?", *unitId)
+	}
+	result := query.Find(&leases)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &leases, nil
+}
+
+// Find the lease that renewed a lease
+func (r *leaseRepository) FindRenewalOf(id uint) (*db.Lease, error) {
+	lease := db.Lease{}
+	result := r.DB.Where("renewed_from_id = ?", id).First(&lease)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &lease, nil
+}
+
+// Find leases ending within a period that haven't sent a renewal reminder
+func (r *leaseRepository) FindUnremindedEnding(from time.Time, to time.Time) (*[]db.Lease, error) {
+	leases := []db.Lease{}
+	result := r.DB.Preload("Property").Preload("Tenants").
+		Where("end_date > ? AND end_date <= ? AND renewal_reminder_sent_at IS NULL", from, to).Find(&leases)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &leases, nil
+}
+
+// Records that admins have been reminded of a lease's expiry
+func (r *leaseRepository) MarkReminderSent(id uint, sentAt time.Time) error {
+	result := r.DB.Model(&db.Lease{}).Where("id = ?", id).Update("renewal_reminder_sent_at", sentAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed flagging lease renewal reminder: %w", result.Error)
+	}
+	return nil
+}
+
+// Replaces the tenants of a lease
+func (r *leaseRepository) ReplaceTenants(id uint, tenants []db.Contact) error {
+	err := r.DB.Model(&db.Lease{ID: id}).Association("Tenants").Replace(tenants)
+	if err != nil {
+		return fmt.Errorf("failed replacing lease tenants: %w", err)
+	}
+	return nil
+}
+
+// Replaces the rent periods of a lease that haven't been charged to the ledger
+func (r *leaseRepository) ReplaceSchedule(id uint, schedule []db.RentScheduleItem) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("lease_id = ?", id).
+			Where("NOT EXISTS (SELECT 1 FROM ledger_entries WHERE ledger_entries.rent_schedule_item_id = rent_schedule_items.id)").
+			Delete(&db.RentScheduleItem{})
+		if result.Error != nil {
+			return fmt.Errorf("failed removing rent schedule: %w", result.Error)
+		}
+		for i := range schedule {
+			schedule[i].LeaseID = id
+		}
+		if len(schedule) > 0 {
+			result = tx.Create(&schedule)
+			if result.Error != nil {
+				return fmt.Errorf("failed creating rent schedule: %w", result.Error)
+			}
+		}
+		return nil
+	})
+}
+
+// End of the last rent period of a lease charged to the ledger (zero if none)
+func (r *leaseRepository) ChargedUntil(id uint) (time.Time, error) {
+	item := db.RentScheduleItem{}
+	result := r.DB.Where("lease_id = ?", id).
+		Where("EXISTS (SELECT 1 FROM ledger_entries WHERE ledger_entries.rent_schedule_item_id = rent_schedule_items.id)").
+		Order("period_end DESC").Limit(1).Find(&item)
+	if result.Error != nil {
+		return time.Time{}, result.Error
+	}
+	return item.PeriodEnd, nil
+}
+
+// Delete lease in database
+func (r *leaseRepository) Delete(id int) error {
+	// Create an empty ref object of type lease
+	lease := db.Lease{}
+	// Delete lease from db if exists
+	result := r.DB.Delete(&lease, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting lease: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates lease in database. A changed end date or notice period clears the reminder flag
+func (r *leaseRepository) Update(id int, lease *db.Lease) (*db.Lease, error) {
+	// Init
+	var err error
+	// Find lease by id to ensure it exists
+	foundLease, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Lease to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found lease with incoming details
+	updateResult := r.DB.Model(&foundLease).Omit("Property", "Tenants", "Transaction", "Schedule").Updates(lease)
+	if updateResult.Error != nil {
+		fmt.Println("Lease update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+	// Lease is checked against the notice period again
+	if !lease.EndDate.IsZero() || lease.RenewalNoticeDays != 0 {
+		updateResult = r.DB.Model(&foundLease).Update("renewal_reminder_sent_at", nil)
+		if updateResult.Error != nil {
+			fmt.Println("Lease update failed: ", updateResult.Error)
+			return nil, updateResult.Error
+		}
+	}
+
+	// Retrieve updated lease by id
+	updatedLease, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated lease not found: ", err)
+		return nil, err
+	}
+	return updatedLease, nil
+}
+
+// Takes limit, offset, order, property and tenant parameters, builds a query and executes returning a list of leases
+func QueryAllLeasesBasedOnParams(limit int, offset int, order string, propertyId int, tenantId int, dbClient *gorm.DB) ([]db.Lease, error) {
+	// Build model to query database
+	leases := []db.Lease{}
+	// Build base query for leases table
+	query := dbClient.Model(&leases).Preload("Property").Preload("Unit").Preload("Tenants")
+
+	// Add parameters into query as needed
+	if propertyId != 0 {
+		query.Where("property_id = ?", propertyId)
+	}
+	if tenantId != 0 {
+		query.Where("id IN (SELECT lease_id FROM lease_tenants WHERE contact_id = ?)", tenantId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("end_date ASC")
+	}
+	// Query database
+	result := query.Find(&leases)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return leases, nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type LedgerEntryRepository interface {
+	FindAll(int, int, string, int) (*[]db.LedgerEntry, error)
+	FindById(int) (*db.LedgerEntry, error)
+	Create(*db.LedgerEntry) (*db.LedgerEntry, error)
+	Delete(int) error
+	// Find the entries of a lease before a time in date order
+	FindByLease(uint, time.Time) (*[]db.LedgerEntry, error)
+	// Find rent periods due by a time that haven't been charged
+	FindUnchargedRent(time.Time) (*[]db.RentScheduleItem, error)
+	// Creates the rent charge of a rent period. Returns false if the period has already been charged
+	CreateRentCharge(*db.LedgerEntry) (bool, error)
+	// Determines whether a late fee has been charged for a rent period (including waived fees)
+	HasLateFee(uint) (bool, error)
+	// Find leases with a late fee that have started by a time
+	FindLeasesWithLateFees(time.Time) (*[]db.Lease, error)
+	// Find leases of managed properties that have ledger entries or rent due by a time
+	FindManagedLeases(time.Time) (*[]db.Lease, error)
+}
+
+type ledgerEntryRepository struct {
+	DB *gorm.DB
+}
+
+func NewLedgerEntryRepository(db *gorm.DB) LedgerEntryRepository {
+	return &ledgerEntryRepository{db}
+}
+
+// Creates a ledger entry in the database
+func (r *ledgerEntryRepository) Create(entry *db.LedgerEntry) (*db.LedgerEntry, error) {
+	// Create new entry in database
+	result := r.DB.Create(&entry)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating ledger entry: %w", result.Error)
+	}
+
+	return entry, nil
+}
+
+// Find a list of ledger entries in the database. Filters by lease if provided
+func (r *ledgerEntryRepository) FindAll(limit int, offset int, order string, leaseId int) (*[]db.LedgerEntry, error) {
+	// Query all entries based on the received parameters
+	entries, err := QueryAllLedgerEntriesBasedOnParams(limit, offset, order, leaseId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of ledger entries: %s", err)
+		return nil, err
+	}
+
+	return &entries, nil
+}
+
+// Find a ledger entry in database by ID
+func (r *ledgerEntryRepository) FindById(id int) (*db.LedgerEntry, error) {
+	// Create an empty ref object of type ledger entry
+	entry := db.LedgerEntry{}
+	// Grab entry from db if exists
+	result := r.DB.Preload("TaxLines").First(&entry, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &entry, nil
+}
+
+// Find the entries of a lease before a time in date order
+func (r *ledgerEntryRepository) FindByLease(leaseId uint, before time.Time) (*[]db.LedgerEntry, error) {
+	entries := []db.LedgerEntry{}
+	result := r.DB.Where("lease_id = ? AND entry_date < ?", leaseId, before).Order("entry_date ASC, id ASC").Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &entries, nil
+}
+
+// Find rent periods of current leases due by a time that haven't been charged
+func (r *ledgerEntryRepository) FindUnchargedRent(asOf time.Time) (*[]db.RentScheduleItem, error) {
+	items := []db.RentScheduleItem{}
+	result := r.DB.Where("due_date <= ?", asOf).
+		Where("lease_id IN (SELECT id FROM leases WHERE deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM ledger_entries WHERE ledger_entries.rent_schedule_item_id = rent_schedule_items.id AND ledger_entries.type = ?)", "Rent Charge").
+		Order("due_date ASC").Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &items, nil
+}
+
+// Creates the rent charge of a rent period. Rent charges are unique per period, so when a concurrent run has
+// already charged the period nothing is created and false is returned
+func (r *ledgerEntryRepository) CreateRentCharge(entry *db.LedgerEntry) (bool, error) {
+	result := r.DB.Create(&entry)
+	if result.Error == nil {
+		return true, nil
+	}
+	var charged int64
+	r.DB.Model(&db.LedgerEntry{}).Where("rent_schedule_item_id = ? AND type = ?", entry.RentScheduleItemID, "Rent Charge").Count(&charged)
+	if charged > 0 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed creating rent charge: %w", result.Error)
+}
+
+// Determines whether a late fee has been charged for a rent period. Waived (deleted) fees are included
+func (r *ledgerEntryRepository) HasLateFee(rentScheduleItemId uint) (bool, error) {
+	var count int64
+	result := r.DB.Unscoped().Model(&db.LedgerEntry{}).
+		Where("rent_schedule_item_id = ? AND type = ?", rentScheduleItemId, "Late Fee").Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// Find leases with a late fee that have started by a time
+func (r *ledgerEntryRepository) FindLeasesWithLateFees(asOf time.Time) (*[]db.Lease, error) {
+	leases := []db.Lease{}
+	result := r.DB.Where("late_fee_amount > 0 AND start_date <= ?", asOf).Find(&leases)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &leases, nil
+}
+
+// Find leases of managed properties that have ledger entries
+func (r *ledgerEntryRepository) FindManagedLeases(asOf time.Time) (*[]db.Lease, error) {
+	leases := []db.Lease{}
+	result := r.DB.Preload("Property").Preload("Tenants").
+		Joins("JOIN properties ON properties.id = leases.property_id AND properties.deleted_at IS NULL").
+		Where("properties.managed = ?", true).
+		Where("EXISTS (SELECT 1 FROM ledger_entries WHERE ledger_entries.lease_id = leases.id AND ledger_entries.deleted_at IS NULL) OR "+
+			"EXISTS (SELECT 1 FROM rent_schedule_items WHERE rent_schedule_items.lease_id = leases.id AND rent_schedule_items.due_date <= ?)", asOf).
+		Order("leases.id ASC").Find(&leases)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &leases, nil
+}
+
+// Delete ledger entry in database
+func (r *ledgerEntryRepository) Delete(id int) error {
+	// Create an empty ref object of type ledger entry
+	entry := db.LedgerEntry{}
+	// Delete entry from db if exists
+	result := r.DB.Delete(&entry, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting ledger entry: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Takes limit, offset, order and lease parameters, builds a query and executes returning a list of ledger entries
+func QueryAllLedgerEntriesBasedOnParams(limit int, offset int, order string, leaseId int, dbClient *gorm.DB) ([]db.LedgerEntry, error) {
+	// Build model to query database
+	entries := []db.LedgerEntry{}
+	// Build base query for ledger entries table
+	query := dbClient.Model(&entries)
+
+	// Add parameters into query as needed
+	if leaseId != 0 {
+		query.Where("lease_id = ?", leaseId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("entry_date DESC, id DESC")
+	}
+	// Query database
+	result := query.Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return entries, nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type MaintenanceBudgetRepository interface {
+	FindAll(int, int, string, int, int) (*[]db.MaintenanceBudget, error)
+	FindById(int) (*db.MaintenanceBudget, error)
+	Create(*db.MaintenanceBudget) (*db.MaintenanceBudget, error)
+	Update(int, *db.MaintenanceBudget) (*db.MaintenanceBudget, error)
+	Delete(int) error
+	// Find the budgets of a property for a year
+	FindByPropertyYear(uint, int) (*[]db.MaintenanceBudget, error)
+	// Find budgets for a year and month (annual budgets included) that haven't alerted yet
+	FindUnalerted(int, int) (*[]db.MaintenanceBudget, error)
+	// Records that admins have been alerted of a budget
+	MarkAlertSent(uint, time.Time) error
+	// Sums invoiced and request maintenance costs of a property (and optional work type) between two times per currency
+	SumCosts(uint, *uint, time.Time, time.Time) ([]db.Money, []db.Money, error)
+}
+
+type maintenanceBudgetRepository struct {
+	DB *gorm.DB
+}
+
+func NewMaintenanceBudgetRepository(db *gorm.DB) MaintenanceBudgetRepository {
+	return &maintenanceBudgetRepository{db}
+}
+
+// Creates a maintenance budget in the database
+func (r *maintenanceBudgetRepository) Create(budget *db.MaintenanceBudget) (*db.MaintenanceBudget, error) {
+	// Create new budget in database
+	result := r.DB.Create(&budget)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating maintenance budget: %w", result.Error)
+	}
+
+	return budget, nil
+}
+
+// Find a list of maintenance budgets in the database. Filters by property and year if provided
+func (r *maintenanceBudgetRepository) FindAll(limit int, offset int, order string, propertyId int, year int) (*[]db.MaintenanceBudget, error) {
+	// Query all budgets based on the received parameters
+	budgets, err := QueryAllMaintenanceBudgetsBasedOnParams(limit, offset, order, propertyId, year, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of maintenance budgets: %s", err)
+		return nil, err
+	}
+
+	return &budgets, nil
+}
+
+// Find a maintenance budget in database by ID
+func (r *maintenanceBudgetRepository) FindById(id int) (*db.MaintenanceBudget, error) {
+	// Create an empty ref object of type maintenance budget
+	budget := db.MaintenanceBudget{}
+	// Grab budget from db if exists
+	result := r.DB.Preload("Property").Preload("WorkType").First(&budget, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &budget, nil
+}
+
+// Find the budgets of a property for a year
+func (r *maintenanceBudgetRepository) FindByPropertyYear(propertyId uint, year int) (*[]db.MaintenanceBudget, error) {
+	budgets := []db.MaintenanceBudget{}
+	result := r.DB.Preload("WorkType").Where("property_id = ? AND year = ?", propertyId, year).
+		Order("month ASC, work_type_id ASC").Find(&budgets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &budgets, nil
+}
+
+// Find budgets for a year and month (annual budgets included) that haven't alerted yet
+func (r *maintenanceBudgetRepository) FindUnalerted(year int, month int) (*[]db.MaintenanceBudget, error) {
+	budgets := []db.MaintenanceBudget{}
+	result := r.DB.Preload("Property").Preload("WorkType").
+		Where("year = ? AND month IN ? AND alert_sent_at IS NULL", year, []int{0, month}).Find(&budgets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &budgets, nil
+}
+
+// Records that admins have been alerted of a budget
+func (r *maintenanceBudgetRepository) MarkAlertSent(id uint, sentAt time.Time) error {
+	result := r.DB.Model(&db.MaintenanceBudget{}).Where("id = ?", id).Update("alert_sent_at", sentAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed flagging maintenance budget alert: %w", result.Error)
+	}
+	return nil
+}
+
+// Sums maintenance costs of a property (and optional work type) from (inclusive) to (exclusive).
+// Returns the total of vendor invoices dated within the period and the cost and tax of
+// requests raised within the period that haven't been invoiced, so spend isn't counted twice.
+// Both are summed per currency
+func (r *maintenanceBudgetRepository) SumCosts(propertyId uint, workTypeId *uint, from time.Time, to time.Time) ([]db.Money, []db.Money, error) {
+	var invoiced, requested []currencySum
+
+	// Vendor invoices against the property's maintenance requests
+	invoiceQuery := r.DB.Model(&db.VendorInvoice{}).
+		Joins("JOIN maintenance_requests ON maintenance_requests.id = vendor_invoices.maintenance_request_id AND maintenance_requests.deleted_at IS NULL").
+		Where("maintenance_requests.property_id = ? AND vendor_invoices.invoice_date >= ? AND vendor_invoices.invoice_date < ?", propertyId, from, to)
+	if workTypeId != nil {
+		invoiceQuery.Where("maintenance_requests.work_type_id = ?", *workTypeId)
+	}
+	result := invoiceQuery.Select("vendor_invoices.total_currency AS currency, SUM(vendor_invoices.total_amount) AS amount").
+		Group("vendor_invoices.total_currency").Scan(&invoiced)
+	if result.Error != nil {
+		return nil, nil, fmt.Errorf("failed summing invoiced maintenance costs: %w", result.Error)
+	}
+
+	// Costs recorded on requests without vendor invoices
+	requestQuery := r.DB.Model(&db.MaintenanceRequest{}).
+		Where("property_id = ? AND created_at >= ? AND created_at < ?", propertyId, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM vendor_invoices WHERE vendor_invoices.maintenance_request_id = maintenance_requests.id AND vendor_invoices.deleted_at IS NULL)")
+	if workTypeId != nil {
+		requestQuery.Where("work_type_id = ?", *workTypeId)
+	}
+	// Tax is recorded in the currency of the cost
+	result = requestQuery.Select("total_cost_currency AS currency, SUM(total_cost_amount + tax_amount) AS amount").
+		Where("total_cost_amount <> 0 OR tax_amount <> 0").
+		Group("total_cost_currency").Scan(&requested)
+	if result.Error != nil {
+		return nil, nil, fmt.Errorf("failed summing maintenance request costs: %w", result.Error)
+	}
+
+	return sumsToMoney(invoiced), sumsToMoney(requested), nil
+}
+
+// Delete maintenance budget in database
+func (r *maintenanceBudgetRepository) Delete(id int) error {
+	// Create an empty ref object of type maintenance budget
+	budget := db.MaintenanceBudget{}
+	// Delete budget from db if exists
+	result := r.DB.Delete(&budget, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting maintenance budget: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates maintenance budget in database. A changed amount or threshold clears the alert flag
+func (r *maintenanceBudgetRepository) Update(id int, budget *db.MaintenanceBudget) (*db.MaintenanceBudget, error) {
+	// Init
+	var err error
+	// Find budget by id to ensure it exists
+	foundBudget, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Maintenance budget to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found budget with incoming details
+	updateResult := r.DB.Model(&foundBudget).Omit("Property", "WorkType").Updates(budget)
+	if updateResult.Error != nil {
+		fmt.Println("Maintenance budget update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+	// Budget is checked against the threshold again
+	if !budget.Amount.IsZero() || budget.AlertThreshold != 0 {
+		updateResult = r.DB.Model(&foundBudget).Update("alert_sent_at", nil)
+		if updateResult.Error != nil {
+			fmt.Println("Maintenance budget update failed: ", updateResult.Error)
+			return nil, updateResult.Error
+		}
+	}
+
+	// Retrieve updated budget by id
+	updatedBudget, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated maintenance budget not found: ", err)
+		return nil, err
+	}
+	return updatedBudget, nil
+}
+
+// Takes limit, offset, order, property and year parameters, builds a query and executes returning a list of maintenance budgets
+func QueryAllMaintenanceBudgetsBasedOnParams(limit int, offset int, order string, propertyId int, year int, dbClient *gorm.DB) ([]db.MaintenanceBudget, error) {
+	// Build model to query database
+	budgets := []db.MaintenanceBudget{}
+	// Build base query for maintenance budgets table
+	query := dbClient.Model(&budgets).Preload("Property").Preload("WorkType")
+
+	// Add parameters into query as needed
+	if propertyId != 0 {
+		query.Where("property_id = ?", propertyId)
+	}
+	if year != 0 {
+		query.Where("year = ?", year)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("year DESC, month ASC")
+	}
+	// Query database
+	result := query.Find(&budgets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return budgets, nil
+}
@@ -13,6 +13,10 @@ type MaintenanceRequestRepository interface {
 	Create(*db.MaintenanceRequest) (*db.MaintenanceRequest, error)
 	Update(int, *db.MaintenanceRequest) (*db.MaintenanceRequest, error)
 	Delete(int) error
+	// Find a maintenance request by its tenant tracking code with its work orders
+	FindByTrackingCode(string) (*db.MaintenanceRequest, error)
+	// Adds photos to a maintenance request
+	AddPhotos(uint, []db.PropertyAttachment) error
 }
 
 type maintenanceRequestRepository struct {
@@ -34,6 +38,17 @@ func (r *maintenanceRequestRepository) Create(request *db.MaintenanceRequest) (*
 	return request, nil
 }
 
+// Adds photos to a maintenance request
+func (r *maintenanceRequestRepository) AddPhotos(id uint, photos []db.PropertyAttachment) error {
+	request := db.MaintenanceRequest{ID: id}
+	err := r.DB.Model(&request).Association("Photos").Append(photos)
+	if err != nil {
+		fmt.Println("Maintenance request photos update failed: ", err)
+		return err
+	}
+	return nil
+}
+
 // Find a list of maintenance requests in the database
 func (r *maintenanceRequestRepository) FindAll(limit int, offset int, order string) (*[]db.MaintenanceRequest, error) {
 	// Query all maintenance requests based on the received parameters
@@ -51,7 +66,7 @@ func (r *maintenanceRequestRepository) FindById(id int) (*db.MaintenanceRequest,
 	// Create an empty ref object of type maintenance request
 	request := db.MaintenanceRequest{}
 	// Grab maint. request from db if exists
-	result := r.DB.Preload("Property").First(&request, id)
+	result := r.DB.Preload("Property").Preload("Unit").Preload("WorkType").Preload("Vendor").First(&request, id)
 
 	// If error detected
 	if result.Error != nil {
@@ -61,17 +76,37 @@ func (r *maintenanceRequestRepository) FindById(id int) (*db.MaintenanceRequest,
 	return &request, nil
 }
 
-// Delete maintenance request in database
+// Find a maintenance request by its tenant tracking code with its work orders
+func (r *maintenanceRequestRepository) FindByTrackingCode(code string) (*db.MaintenanceRequest, error) {
+	request := db.MaintenanceRequest{}
+	result := r.DB.Preload("WorkOrders").Where("tracking_code = ?", code).First(&request)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// Delete maintenance request in database, archiving its task
 func (r *maintenanceRequestRepository) Delete(id int) error {
 	// Create an empty ref object of type maintenance request
 	request := db.MaintenanceRequest{}
-	// Delete maint. request from db if exists
-	result := r.DB.Delete(&request, id)
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.First(&request, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Delete maint. request from db and archive its task
+		result = tx.Delete(&request)
+		if result.Error != nil {
+			return result.Error
+		}
+		return archiveTask(tx, request.TaskID)
+	})
 
 	// If error detected
-	if result.Error != nil {
-		fmt.Println("error in deleting maintenance request: ", result.Error)
-		return result.Error
+	if err != nil {
+		fmt.Println("error in deleting maintenance request: ", err)
+		return err
 	}
 	// else
 	return nil
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	FindAllByUser(int, int, int, string, bool) (*[]db.Notification, error)
+	FindById(int) (*db.Notification, error)
+	Create(*db.Notification) (*db.Notification, error)
+	SetReadState(int, bool) (*db.Notification, error)
+	Delete(int) error
+	// Delivery preferences
+	FindPreferencesByUser(int) (*[]db.NotificationPreference, error)
+	UpsertPreference(*db.NotificationPreference) (*db.NotificationPreference, error)
+	// Failed deliveries
+	CreateDeadLetter(*db.NotificationDeadLetter) (*db.NotificationDeadLetter, error)
+	FindAllDeadLetters(int, int, string) (*[]db.NotificationDeadLetter, error)
+}
+
+type notificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db}
+}
+
+// Creates a notification in the database
+func (r *notificationRepository) Create(notification *db.Notification) (*db.Notification, error) {
+	// Create new notification in database
+	result := r.DB.Create(&notification)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating notification: %w", result.Error)
+	}
+
+	return notification, nil
+}
+
+// Find a list of notifications for a user in the database. Only returns unread if unreadOnly is true
+func (r *notificationRepository) FindAllByUser(userId int, limit int, offset int, order string, unreadOnly bool) (*[]db.Notification, error) {
+	// Query all notifications based on the received parameters
+	notifications, err := QueryAllNotificationsBasedOnParams(userId, limit, offset, order, unreadOnly, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of notifications: %s", err)
+		return nil, err
+	}
+
+	return &notifications, nil
+}
+
+// Find a notification in database by ID
+func (r *notificationRepository) FindById(id int) (*db.Notification, error) {
+	// Create an empty ref object of type notification
+	notification := db.Notification{}
+	// Grab notification from db if exists
+	result := r.DB.First(&notification, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &notification, nil
+}
+
+// Delete notification in database
+func (r *notificationRepository) Delete(id int) error {
+	// Create an empty ref object of type notification
+	notification := db.Notification{}
+	// Delete notification from db if exists
+	result := r.DB.Delete(&notification, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting notification: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Marks a notification as read or unread
+func (r *notificationRepository) SetReadState(id int, read bool) (*db.Notification, error) {
+	// Find notification by id to ensure it exists
+	foundNotification, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Notification to update not found: ", err)
+		return nil, err
+	}
+
+	// Build read state (map used as gorm ignores zero values in structs)
+	readState := map[string]interface{}{"read": read, "read_at": nil}
+	if read {
+		readAt := time.Now()
+		readState["read_at"] = &readAt
+	}
+
+	// Update found notification
+	updateResult := r.DB.Model(&foundNotification).Updates(readState)
+	if updateResult.Error != nil {
+		fmt.Println("Notification update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated notification by id
+	updatedNotification, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated notification not found: ", err)
+		return nil, err
+	}
+	return updatedNotification, nil
+}
+
+// Find the stored notification preferences of a user
+func (r *notificationRepository) FindPreferencesByUser(userId int) (*[]db.NotificationPreference, error) {
+	// Build model to query database
+	preferences := []db.NotificationPreference{}
+	// Find preferences belonging to user
+	result := r.DB.Where("user_id = ?", userId).Find(&preferences)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &preferences, nil
+}
+
+// Creates or updates a user's preference for an event type
+func (r *notificationRepository) UpsertPreference(preference *db.NotificationPreference) (*db.NotificationPreference, error) {
+	// Find existing preference for user and event type
+	foundPreference := db.NotificationPreference{}
+	result := r.DB.Where("user_id = ? AND event_type = ?", preference.UserID, preference.EventType).First(&foundPreference)
+
+	// If not found, create new preference
+	if result.Error != nil {
+		createResult := r.DB.Create(&preference)
+		if createResult.Error != nil {
+			return nil, fmt.Errorf("failed creating notification preference: %w", createResult.Error)
+		}
+		return preference, nil
+	}
+
+	// else, update found preference (map used as gorm ignores zero values in structs)
+	updateResult := r.DB.Model(&foundPreference).Updates(map[string]interface{}{
+		"in_app":      preference.InApp,
+		"email":       preference.Email,
+		"webhook":     preference.Webhook,
+		"webhook_url": preference.WebhookURL,
+	})
+	if updateResult.Error != nil {
+		fmt.Println("Notification preference update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated preference
+	r.DB.First(&foundPreference, foundPreference.ID)
+	return &foundPreference, nil
+}
+
+// Creates a dead letter record for a notification that couldn't be delivered
+func (r *notificationRepository) CreateDeadLetter(deadLetter *db.NotificationDeadLetter) (*db.NotificationDeadLetter, error) {
+	// Create dead letter in database
+	result := r.DB.Create(&deadLetter)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating notification dead letter: %w", result.Error)
+	}
+
+	return deadLetter, nil
+}
+
+// Find a list of notifications that failed delivery
+func (r *notificationRepository) FindAllDeadLetters(limit int, offset int, order string) (*[]db.NotificationDeadLetter, error) {
+	// Build model to query database
+	deadLetters := []db.NotificationDeadLetter{}
+	// Build base query for dead letters table
+	query := r.DB.Model(&deadLetters)
+
+	// Add parameters into query as needed
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("created_at DESC")
+	}
+	// Query database
+	result := query.Find(&deadLetters)
+	if result.Error != nil {
+		fmt.Printf("Error querying db for list of notification dead letters: %s", result.Error)
+		return nil, result.Error
+	}
+
+	return &deadLetters, nil
+}
+
+// Takes user id, limit, offset, order and unread parameters, builds a query and executes returning a list of notifications
+func QueryAllNotificationsBasedOnParams(userId int, limit int, offset int, order string, unreadOnly bool, dbClient *gorm.DB) ([]db.Notification, error) {
+	// Build model to query database
+	notifications := []db.Notification{}
+	// Build base query for notifications table (always restricted to recipient)
+	query := dbClient.Model(&notifications).Where("user_id = ?", userId)
+
+	// Add parameters into query as needed
+	if unreadOnly {
+		query.Where("read = ?", false)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("created_at DESC")
+	}
+	// Query database
+	result := query.Find(&notifications)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return notifications, nil
+}
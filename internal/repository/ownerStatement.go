@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type OwnerStatementRepository interface {
+	FindAll(int, int, string, int, int) (*[]db.OwnerStatement, error)
+	FindById(int) (*db.OwnerStatement, error)
+	Create(*db.OwnerStatement) (*db.OwnerStatement, error)
+	Update(int, *db.OwnerStatement) (*db.OwnerStatement, error)
+	Delete(int) error
+	// Find the statement of a property for a year and month
+	FindByPeriod(uint, int, int) (*db.OwnerStatement, error)
+	// Replaces the lines of a statement
+	ReplaceLines(uint, []db.OwnerStatementLine) error
+	// Find rent receipts of a property's leases between two times
+	FindRentReceipts(uint, time.Time, time.Time) (*[]db.LedgerEntry, error)
+	// Find a property's maintenance requests raised between two times without vendor invoices
+	FindUninvoicedRequests(uint, time.Time, time.Time) (*[]db.MaintenanceRequest, error)
+	// Find vendor invoices against a property's maintenance requests dated between two times
+	FindVendorInvoices(uint, time.Time, time.Time) (*[]db.VendorInvoice, error)
+	// Find the latest management transaction of a property
+	FindManagementTransaction(uint) (*db.Transaction, error)
+	// Find managed properties without a statement for a year and month
+	FindManagedPropertiesWithoutStatement(int, int) (*[]db.Property, error)
+}
+
+type ownerStatementRepository struct {
+	DB *gorm.DB
+}
+
+func NewOwnerStatementRepository(db *gorm.DB) OwnerStatementRepository {
+	return &ownerStatementRepository{db}
+}
+
+// Creates an owner statement in the database
+func (r *ownerStatementRepository) Create(statement *db.OwnerStatement) (*db.OwnerStatement, error) {
+	// Create new statement in database
+	result := r.DB.Create(&statement)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating owner statement: %w", result.Error)
+	}
+
+	return statement, nil
+}
+
+// Find a list of owner statements in the database. Filters by property and year if provided
+func (r *ownerStatementRepository) FindAll(limit int, offset int, order string, propertyId int, year int) (*[]db.OwnerStatement, error) {
+	// Query all statements based on the received parameters
+	statements, err := QueryAllOwnerStatementsBasedOnParams(limit, offset, order, propertyId, year, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of owner statements: %s", err)
+		return nil, err
+	}
+
+	return &statements, nil
+}
+
+// Find an owner statement in database by ID
+func (r *ownerStatementRepository) FindById(id int) (*db.OwnerStatement, error) {
+	// Create an empty ref object of type owner statement
+	statement := db.OwnerStatement{}
+	// Grab statement from db if exists
+	result := r.DB.Preload("Property.Contacts").
+		Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("date ASC, id ASC") }).
+		First(&statement, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &statement, nil
+}
+
+// Find the statement of a property for a year and month
+func (r *ownerStatementRepository) FindByPeriod(propertyId uint, year int, month int) (*db.OwnerStatement, error) {
+	statement := db.OwnerStatement{}
+	result := r.DB.Where("property_id = ? AND year = ? AND month = ?", propertyId, year, month).First(&statement)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &statement, nil
+}
+
+// Replaces the lines of a statement
+func (r *ownerStatementRepository) ReplaceLines(id uint, lines []db.OwnerStatementLine) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("owner_statement_id = ?", id).Delete(&db.OwnerStatementLine{})
+		if result.Error != nil {
+			return fmt.Errorf("failed removing owner statement lines: %w", result.Error)
+		}
+		// Created one at a time as lines without a reference use the column default
+		for i := range lines {
+			lines[i].OwnerStatementID = id
+			result = tx.Create(&lines[i])
+			if result.Error != nil {
+				return fmt.Errorf("failed creating owner statement line: %w", result.Error)
+			}
+		}
+		return nil
+	})
+}
+
+// Find rent receipts of a property's leases from (inclusive) to (exclusive)
+func (r *ownerStatementRepository) FindRentReceipts(propertyId uint, from time.Time, to time.Time) (*[]db.LedgerEntry, error) {
+	entries := []db.LedgerEntry{}
+	result := r.DB.Where("type = ? AND entry_date >= ? AND entry_date < ?", "Receipt", from, to).
+		Where("lease_id IN (SELECT id FROM leases WHERE property_id = ? AND deleted_at IS NULL)", propertyId).
+		Order("entry_date ASC, id ASC").Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &entries, nil
+}
+
+// Find a property's maintenance requests raised from (inclusive) to (exclusive) without vendor invoices
+func (r *ownerStatementRepository) FindUninvoicedRequests(propertyId uint, from time.Time, to time.Time) (*[]db.MaintenanceRequest, error) {
+	requests := []db.MaintenanceRequest{}
+	result := r.DB.Where("property_id = ? AND created_at >= ? AND created_at < ?", propertyId, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM vendor_invoices WHERE vendor_invoices.maintenance_request_id = maintenance_requests.id AND vendor_invoices.deleted_at IS NULL)").
+		Order("created_at ASC").Find(&requests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &requests, nil
+}
+
+// Find vendor invoices against a property's maintenance requests dated from (inclusive) to (exclusive)
+func (r *ownerStatementRepository) FindVendorInvoices(propertyId uint, from time.Time, to time.Time) (*[]db.VendorInvoice, error) {
+	invoices := []db.VendorInvoice{}
+	result := r.DB.Preload("Vendor").
+		Joins("JOIN maintenance_requests ON maintenance_requests.id = vendor_invoices.maintenance_request_id AND maintenance_requests.deleted_at IS NULL").
+		Where("maintenance_requests.property_id = ? AND vendor_invoices.invoice_date >= ? AND vendor_invoices.invoice_date < ?", propertyId, from, to).
+		Order("vendor_invoices.invoice_date ASC").Find(&invoices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invoices, nil
+}
+
+// Find the latest management transaction of a property
+func (r *ownerStatementRepository) FindManagementTransaction(propertyId uint) (*db.Transaction, error) {
+	transaction := db.Transaction{}
+	result := r.DB.Preload("CommissionScheme").Where("property_id = ? AND type = ?", propertyId, "Management").Order("created_at DESC").First(&transaction)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &transaction, nil
+}
+
+// Find managed properties without a statement for a year and month
+func (r *ownerStatementRepository) FindManagedPropertiesWithoutStatement(year int, month int) (*[]db.Property, error) {
+	properties := []db.Property{}
+	result := r.DB.Where("managed = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM owner_statements WHERE owner_statements.property_id = properties.id AND year = ? AND month = ? AND owner_statements.deleted_at IS NULL)", year, month).
+		Find(&properties)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &properties, nil
+}
+
+// Delete owner statement in database
+func (r *ownerStatementRepository) Delete(id int) error {
+	// Create an empty ref object of type owner statement
+	statement := db.OwnerStatement{}
+	// Delete statement from db if exists
+	result := r.DB.Delete(&statement, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting owner statement: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates owner statement in database. All fields are replaced
+func (r *ownerStatementRepository) Update(id int, statement *db.OwnerStatement) (*db.OwnerStatement, error) {
+	// Init
+	var err error
+	// Find statement by id to ensure it exists
+	foundStatement, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Owner statement to update not found: ", err)
+		return nil, err
+	}
+
+	// Replace found statement's details with the incoming statement, saving amounts even when zero
+	updateResult := r.DB.Model(&foundStatement).Select("*").Omit("ID", "CreatedAt", "DeletedAt", "Property", "Lines").Updates(statement)
+	if updateResult.Error != nil {
+		fmt.Println("Owner statement update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated statement by id
+	updatedStatement, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated owner statement not found: ", err)
+		return nil, err
+	}
+	return updatedStatement, nil
+}
+
+// Takes limit, offset, order, property and year parameters, builds a query and executes returning a list of owner statements
+func QueryAllOwnerStatementsBasedOnParams(limit int, offset int, order string, propertyId int, year int, dbClient *gorm.DB) ([]db.OwnerStatement, error) {
+	// Build model to query database
+	statements := []db.OwnerStatement{}
+	// Build base query for owner statements table
+	query := dbClient.Model(&statements).Preload("Property")
+
+	// Add parameters into query as needed
+	if propertyId != 0 {
+		query.Where("property_id = ?", propertyId)
+	}
+	if year != 0 {
+		query.Where("year = ?", year)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("year DESC, month DESC")
+	}
+	// Query database
+	result := query.Find(&statements)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return statements, nil
+}
@@ -0,0 +1,375 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"gorm.io/gorm"
+)
+
+type PipelineRepository interface {
+	FindAllStages(int, int, string, string) (*[]db.PipelineStage, error)
+	FindStageById(int) (*db.PipelineStage, error)
+	CreateStage(*db.PipelineStage) (*db.PipelineStage, error)
+	UpdateStage(int, *db.PipelineStage) (*db.PipelineStage, error)
+	DeleteStage(int) error
+	// Find the first stage of a transaction type's pipeline
+	FindFirstStage(string) (*db.PipelineStage, error)
+	// Position after the last stage of a transaction type's pipeline
+	NextStagePosition(string) (int, error)
+	// Count of transactions at or that have been through a stage
+	CountStageTransactions(uint) (int64, error)
+	// Records a move of a transaction to a stage at a time
+	RecordStageChange(*db.TransactionStageChange) error
+	// Completes a transaction's open milestones for a stage
+	CompleteStageMilestones(uint, uint, time.Time) error
+	FindAllMilestones(int, int, string, int, bool) (*[]db.TransactionMilestone, error)
+	FindMilestoneById(int) (*db.TransactionMilestone, error)
+	CreateMilestone(*db.TransactionMilestone) (*db.TransactionMilestone, error)
+	UpdateMilestone(int, *db.TransactionMilestone) (*db.TransactionMilestone, error)
+	DeleteMilestone(int) error
+	// Count and value of transactions at each stage of a transaction type's pipeline
+	StageTotals(string) (*[]models.PipelineStageSummary, error)
+	// Count of transactions of a type without a stage
+	UnstagedTotals(string) (int, error)
+	// Value of a transaction type's transactions per stage and currency. Transactions without a stage are under stage 0
+	StageValues(string) (map[uint][]db.Money, error)
+}
+
+type pipelineRepository struct {
+	DB *gorm.DB
+}
+
+func NewPipelineRepository(db *gorm.DB) PipelineRepository {
+	return &pipelineRepository{db}
+}
+
+// Creates a pipeline stage in the database
+func (r *pipelineRepository) CreateStage(stage *db.PipelineStage) (*db.PipelineStage, error) {
+	// Create new stage in database
+	result := r.DB.Create(&stage)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating pipeline stage: %w", result.Error)
+	}
+
+	return stage, nil
+}
+
+// Find a list of pipeline stages in the database. Filters by transaction type if provided
+func (r *pipelineRepository) FindAllStages(limit int, offset int, order string, transactionType string) (*[]db.PipelineStage, error) {
+	// Query all stages based on the received parameters
+	stages, err := QueryAllPipelineStagesBasedOnParams(limit, offset, order, transactionType, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of pipeline stages: %s", err)
+		return nil, err
+	}
+
+	return &stages, nil
+}
+
+// Find a pipeline stage in database by ID
+func (r *pipelineRepository) FindStageById(id int) (*db.PipelineStage, error) {
+	// Create an empty ref object of type pipeline stage
+	stage := db.PipelineStage{}
+	// Grab stage from db if exists
+	result := r.DB.First(&stage, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &stage, nil
+}
+
+// Delete pipeline stage in database
+func (r *pipelineRepository) DeleteStage(id int) error {
+	// Delete stage from db if exists
+	result := r.DB.Delete(&db.PipelineStage{}, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting pipeline stage: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates pipeline stage in database
+func (r *pipelineRepository) UpdateStage(id int, stage *db.PipelineStage) (*db.PipelineStage, error) {
+	// Init
+	var err error
+	// Find stage by id to ensure it exists
+	foundStage, err := r.FindStageById(id)
+	if err != nil {
+		fmt.Println("Pipeline stage to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found stage with details from stage
+	updateResult := r.DB.Model(&foundStage).Updates(stage)
+	if updateResult.Error != nil {
+		fmt.Println("Pipeline stage update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated stage by id
+	updatedStage, err := r.FindStageById(id)
+	if err != nil {
+		fmt.Println("Updated pipeline stage not found: ", err)
+		return nil, err
+	}
+	return updatedStage, nil
+}
+
+// Find the first stage of a transaction type's pipeline
+func (r *pipelineRepository) FindFirstStage(transactionType string) (*db.PipelineStage, error) {
+	stage := db.PipelineStage{}
+	result := r.DB.Where("transaction_type = ?", transactionType).Order("position ASC, id ASC").First(&stage)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &stage, nil
+}
+
+// Position after the last stage of a transaction type's pipeline
+func (r *pipelineRepository) NextStagePosition(transactionType string) (int, error) {
+	var maxPosition int
+	result := r.DB.Model(&db.PipelineStage{}).Where("transaction_type = ?", transactionType).Select("COALESCE(MAX(position), 0)").Scan(&maxPosition)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return maxPosition + 1, nil
+}
+
+// Count of transactions at or that have been through a stage
+func (r *pipelineRepository) CountStageTransactions(id uint) (int64, error) {
+	var count int64
+	result := r.DB.Model(&db.TransactionStageChange{}).Where("to_stage_id = ? OR from_stage_id = ?", id, id).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// Records a move of a transaction to a stage, updating the transaction's current stage
+func (r *pipelineRepository) RecordStageChange(change *db.TransactionStageChange) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit("FromStage", "ToStage", "User").Create(change)
+		if result.Error != nil {
+			return fmt.Errorf("failed recording stage change: %w", result.Error)
+		}
+		result = tx.Model(&db.Transaction{}).Where("id = ?", change.TransactionID).UpdateColumns(map[string]interface{}{
+			"stage_id":         change.ToStageID,
+			"stage_entered_at": change.ChangedAt,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed updating transaction stage: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// Completes a transaction's open milestones for a stage
+func (r *pipelineRepository) CompleteStageMilestones(transactionId uint, stageId uint, at time.Time) error {
+	result := r.DB.Model(&db.TransactionMilestone{}).
+		Where("transaction_id = ? AND pipeline_stage_id = ? AND completed_at IS NULL", transactionId, stageId).
+		Update("completed_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed completing stage milestones: %w", result.Error)
+	}
+	return nil
+}
+
+// Creates a transaction milestone in the database
+func (r *pipelineRepository) CreateMilestone(milestone *db.TransactionMilestone) (*db.TransactionMilestone, error) {
+	result := r.DB.Create(&milestone)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating transaction milestone: %w", result.Error)
+	}
+	return milestone, nil
+}
+
+// Find a list of transaction milestones. Filters by transaction and to overdue milestones if provided
+func (r *pipelineRepository) FindAllMilestones(limit int, offset int, order string, transactionId int, overdue bool) (*[]db.TransactionMilestone, error) {
+	milestones := []db.TransactionMilestone{}
+	query := r.DB.Model(&milestones)
+	if transactionId != 0 {
+		query.Where("transaction_id = ?", transactionId)
+	}
+	if overdue {
+		query.Where("completed_at IS NULL AND due_date < ?", time.Now())
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("due_date ASC")
+	}
+	result := query.Find(&milestones)
+	if result.Error != nil {
+		fmt.Printf("Error querying db for list of transaction milestones: %s", result.Error)
+		return nil, result.Error
+	}
+	return &milestones, nil
+}
+
+// Find a transaction milestone in database by ID
+func (r *pipelineRepository) FindMilestoneById(id int) (*db.TransactionMilestone, error) {
+	milestone := db.TransactionMilestone{}
+	result := r.DB.First(&milestone, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &milestone, nil
+}
+
+// Updates transaction milestone in database
+func (r *pipelineRepository) UpdateMilestone(id int, milestone *db.TransactionMilestone) (*db.TransactionMilestone, error) {
+	foundMilestone, err := r.FindMilestoneById(id)
+	if err != nil {
+		fmt.Println("Transaction milestone to update not found: ", err)
+		return nil, err
+	}
+
+	updateResult := r.DB.Model(&foundMilestone).Updates(milestone)
+	if updateResult.Error != nil {
+		fmt.Println("Transaction milestone update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+	return r.FindMilestoneById(id)
+}
+
+// Delete transaction milestone in database
+func (r *pipelineRepository) DeleteMilestone(id int) error {
+	result := r.DB.Delete(&db.TransactionMilestone{}, id)
+	if result.Error != nil {
+		fmt.Println("error in deleting transaction milestone: ", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// Count and value of transactions at each stage of a transaction type's pipeline, with their overdue milestones
+func (r *pipelineRepository) StageTotals(transactionType string) (*[]models.PipelineStageSummary, error) {
+	rows := []struct {
+		StageID      uint
+		Stage        string
+		Position     int
+		Transactions int
+	}{}
+	result := r.DB.Model(&db.PipelineStage{}).
+		Select("pipeline_stages.id AS stage_id, pipeline_stages.name AS stage, pipeline_stages.position AS position, "+
+			"COUNT(transactions.id) AS transactions").
+		Joins("LEFT JOIN transactions ON transactions.stage_id = pipeline_stages.id AND transactions.deleted_at IS NULL").
+		Where("pipeline_stages.transaction_type = ?", transactionType).
+		Group("pipeline_stages.id, pipeline_stages.name, pipeline_stages.position").
+		Order("pipeline_stages.position ASC, pipeline_stages.id ASC").Scan(&rows)
+	if result.Error != nil {
+		fmt.Println("Error querying db for pipeline stage totals: ", result.Error)
+		return nil, result.Error
+	}
+	totals := []models.PipelineStageSummary{}
+	for _, row := range rows {
+		totals = append(totals, models.PipelineStageSummary{StageID: row.StageID, Stage: row.Stage, Position: row.Position, Transactions: row.Transactions})
+	}
+
+	// Overdue milestones of the transactions at each stage
+	overdue := []struct {
+		StageID uint
+		Count   int
+	}{}
+	result = r.DB.Model(&db.TransactionMilestone{}).Select("transactions.stage_id AS stage_id, COUNT(*) AS count").
+		Joins("JOIN transactions ON transactions.id = transaction_milestones.transaction_id AND transactions.deleted_at IS NULL").
+		Where("transactions.type = ? AND transaction_milestones.completed_at IS NULL AND transaction_milestones.due_date < ?", transactionType, time.Now()).
+		Group("transactions.stage_id").Scan(&overdue)
+	if result.Error != nil {
+		fmt.Println("Error counting overdue milestones: ", result.Error)
+		return nil, result.Error
+	}
+	for _, row := range overdue {
+		for i := range totals {
+			if totals[i].StageID == row.StageID {
+				totals[i].OverdueMilestones = row.Count
+			}
+		}
+	}
+	return &totals, nil
+}
+
+// Count of transactions of a type without a stage
+func (r *pipelineRepository) UnstagedTotals(transactionType string) (int, error) {
+	var count int64
+	result := r.DB.Model(&db.Transaction{}).Where("type = ? AND stage_id IS NULL", transactionType).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(count), nil
+}
+
+// Value of a transaction type's transactions per stage and currency. Transactions without a stage are under stage 0
+func (r *pipelineRepository) StageValues(transactionType string) (map[uint][]db.Money, error) {
+	rows := []struct {
+		StageID  *uint
+		Currency string
+		Amount   int64
+	}{}
+	result := r.DB.Model(&db.Transaction{}).
+		Select("stage_id, transaction_value_currency AS currency, SUM(transaction_value_amount) AS amount").
+		Where("type = ? AND transaction_value_amount <> 0", transactionType).
+		Group("stage_id, transaction_value_currency").Scan(&rows)
+	if result.Error != nil {
+		fmt.Println("Error querying db for pipeline stage values: ", result.Error)
+		return nil, result.Error
+	}
+	values := map[uint][]db.Money{}
+	for _, row := range rows {
+		var stageId uint
+		if row.StageID != nil {
+			stageId = *row.StageID
+		}
+		values[stageId] = append(values[stageId], db.Money{Amount: row.Amount, Currency: row.Currency})
+	}
+	return values, nil
+}
+
+// Takes limit, offset, order and transaction type parameters, builds a query and executes returning a list of pipeline stages
+func QueryAllPipelineStagesBasedOnParams(limit int, offset int, order string, transactionType string, dbClient *gorm.DB) ([]db.PipelineStage, error) {
+	// Build model to query database
+	stages := []db.PipelineStage{}
+	// Build base query for pipeline stages table
+	query := dbClient.Model(&stages)
+
+	// Add parameters into query as needed
+	if transactionType != "" {
+		query.Where("transaction_type = ?", transactionType)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("transaction_type ASC, position ASC")
+	}
+	// Query database
+	result := query.Find(&stages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return stages, nil
+}
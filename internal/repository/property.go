@@ -13,6 +13,10 @@ type PropertyRepository interface {
 	Create(property *db.Property) (*db.Property, error)
 	Update(int, *db.Property) (*db.Property, error)
 	Delete(int) error
+	// Find property by its tenant portal token
+	FindByPortalToken(string) (*db.Property, error)
+	// Replaces a property's tenant portal token
+	SetPortalToken(int, string) error
 }
 
 type propertyRepository struct {
@@ -59,7 +63,7 @@ func (r *propertyRepository) FindById(id int) (*db.Property, error) {
 	// Create an empty ref object of type property
 	property := db.Property{}
 	// Check if property exists in db
-	result := r.DB.Preload("Features").Preload("PropertyLogs").Preload("Contacts").First(&property, id)
+	result := r.DB.Preload("Features").Preload("PropertyLogs").Preload("Contacts").Preload("Units").First(&property, id)
 
 	// Extract error result
 	err := result.Error
@@ -71,6 +75,28 @@ func (r *propertyRepository) FindById(id int) (*db.Property, error) {
 	return &property, nil
 }
 
+// Find property by its tenant portal token
+func (r *propertyRepository) FindByPortalToken(token string) (*db.Property, error) {
+	property := db.Property{}
+	result := r.DB.Where("portal_token = ?", token).First(&property)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &property, nil
+}
+
+// Replaces a property's tenant portal token
+func (r *propertyRepository) SetPortalToken(id int, token string) error {
+	result := r.DB.Model(&db.Property{}).Where("id = ?", id).Update("portal_token", token)
+	if result.Error != nil {
+		return fmt.Errorf("failed setting property portal token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // Delete property in database
 func (r *propertyRepository) Delete(id int) error {
 	// Create an empty ref object of type property
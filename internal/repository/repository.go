@@ -1 +1,28 @@
 package repository
+
+import "github.com/dmawardi/Go-Template/internal/db"
+
+// Amount summed per currency by a grouped query
+type currencySum struct {
+	Currency string
+	Amount   int64
+}
+
+// Converts amounts summed per currency into money
+func sumsToMoney(sums []currencySum) []db.Money {
+	amounts := []db.Money{}
+	for _, sum := range sums {
+		amounts = append(amounts, db.Money{Amount: sum.Amount, Currency: sum.Currency})
+	}
+	return amounts
+}
+
+// Column values of money amounts for map updates, keyed by their embedded prefix (eg. "fee" for fee_amount and fee_currency)
+func moneyColumns(amounts map[string]db.Money) map[string]interface{} {
+	columns := map[string]interface{}{}
+	for prefix, amount := range amounts {
+		columns[prefix+"_amount"] = amount.Amount
+		columns[prefix+"_currency"] = amount.Currency
+	}
+	return columns
+}
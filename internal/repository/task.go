@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dmawardi/Go-Template/internal/db"
 	"gorm.io/gorm"
@@ -12,7 +13,29 @@ type TaskRepository interface {
 	FindById(int) (*db.Task, error)
 	Create(*db.Task) (*db.Task, error)
 	Update(int, *db.Task) (*db.Task, error)
+	// Deletes a task along with its transaction and maintenance request
 	Delete(int) error
+	// Creates a task and its transaction in one database transaction
+	CreateWithTransaction(*db.Task, *db.Transaction) error
+	// Creates a task and its maintenance request in one database transaction
+	CreateWithMaintenanceRequest(*db.Task, *db.MaintenanceRequest) error
+	// Checks whether a task's transaction is completed or its maintenance request has been invoiced
+	HasCompletedRecords(int) (bool, error)
+	// Find snoozed tasks with a snooze date before the time
+	FindExpiredSnoozes(time.Time) (*[]db.Task, error)
+	// Clears snooze from task
+	Unsnooze(int) error
+	// Find open tasks for a property (through transactions and maintenance requests)
+	FindOpenByProperty(int) (*[]db.Task, error)
+	// Board
+	// Find tasks with status in board order (limit of 0 returns all)
+	FindByStatus(string, int) (*[]db.Task, error)
+	// Count tasks for each status
+	CountByStatus() (map[string]int64, error)
+	// Sets board rank of tasks to their position in the list
+	SetBoardRanks([]uint) error
+	// Find tasks assigned to user that are open or were completed after the time
+	FindAssignedToUser(int, time.Time) (*[]db.Task, error)
 }
 
 type taskRepository struct {
@@ -62,7 +85,11 @@ func (r *taskRepository) FindById(id int) (*db.Task, error) {
 	// Create an empty ref object of type task
 	task := db.Task{}
 	// Check if task exists in db
-	result := r.DB.Preload("Assignment").Preload("Log.User").Preload("Transaction").Preload("MaintenanceRequest").First(&task, id)
+	result := r.DB.Preload("Assignment").Preload("Log.User").Preload("Transaction").Preload("MaintenanceRequest").
+		Preload("Checklist", func(db *gorm.DB) *gorm.DB {
+			// Checklist is returned in order
+			return db.Order("position ASC")
+		}).Preload("Checklist.DoneBy").Preload("Subtasks").Preload("BlockedBy.BlockedBy").Preload("Blocks.Task").First(&task, id)
 
 	// Extract error result
 	err := result.Error
@@ -71,25 +98,111 @@ func (r *taskRepository) FindById(id int) (*db.Task, error) {
 		return nil, err
 	}
 	// else
+	// Compute progress from checklist and subtasks
+	task.Progress = TaskProgress(&task)
 	return &task, nil
 }
 
-// Delete task in database
+// Delete task in database along with its transaction, maintenance request and dependencies, so none are left with a
+// deleted task
 func (r *taskRepository) Delete(id int) error {
-	// Create an empty ref object of type task
-	task := db.Task{}
-	// Check if task exists in db
-	result := r.DB.Delete(&task, id)
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("task_id = ? OR blocked_by_id = ?", id, id).Delete(&db.TaskDependency{})
+		if result.Error != nil {
+			return result.Error
+		}
+		result = tx.Where("task_id = ?", id).Delete(&db.Transaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		result = tx.Where("task_id = ?", id).Delete(&db.MaintenanceRequest{})
+		if result.Error != nil {
+			return result.Error
+		}
+		// Create an empty ref object of type task
+		task := db.Task{}
+		return tx.Delete(&task, id).Error
+	})
 
 	// If error detected
-	if result.Error != nil {
-		fmt.Println("error in deleting task: ", result.Error)
-		return result.Error
+	if err != nil {
+		fmt.Println("error in deleting task: ", err)
+		return err
 	}
 	// else
 	return nil
 }
 
+// Creates a task and its transaction in one database transaction, so neither is created without the other
+func (r *taskRepository) CreateWithTransaction(task *db.Task, transaction *db.Transaction) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := createTask(tx, task)
+		if err != nil {
+			return err
+		}
+		transaction.TaskID = task.ID
+		result := tx.Create(transaction)
+		if result.Error != nil {
+			return fmt.Errorf("failed creating transaction: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// Creates a task and its maintenance request in one database transaction, so neither is created without the other
+func (r *taskRepository) CreateWithMaintenanceRequest(task *db.Task, request *db.MaintenanceRequest) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := createTask(tx, task)
+		if err != nil {
+			return err
+		}
+		request.TaskID = task.ID
+		result := tx.Create(request)
+		if result.Error != nil {
+			return fmt.Errorf("failed creating maintenance request: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// Checks whether a task's transaction is completed or its maintenance request has been invoiced
+func (r *taskRepository) HasCompletedRecords(id int) (bool, error) {
+	var completed int64
+	result := r.DB.Model(&db.Transaction{}).Where("task_id = ? AND transaction_completion IS NOT NULL", id).Count(&completed)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	var invoiced int64
+	result = r.DB.Model(&db.VendorInvoice{}).
+		Joins("JOIN maintenance_requests ON maintenance_requests.id = vendor_invoices.maintenance_request_id AND maintenance_requests.deleted_at IS NULL").
+		Where("maintenance_requests.task_id = ?", id).Count(&invoiced)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return completed+invoiced > 0, nil
+}
+
+// Archives the task of a deleted transaction or maintenance request, unless it has already been closed
+func archiveTask(tx *gorm.DB, id uint) error {
+	return tx.Model(&db.Task{}).Where("id = ? AND completed = ? AND status NOT IN ?", id, false, []string{"Completed", "Cancelled", "Archived"}).
+		Update("status", "Archived").Error
+}
+
+// Creates a task with its assignees within a database transaction
+func createTask(tx *gorm.DB, task *db.Task) error {
+	result := tx.Create(task)
+	if result.Error != nil {
+		return fmt.Errorf("failed creating task: %w", result.Error)
+	}
+	if len(task.Assignment) > 0 {
+		err := tx.Model(task).Association("Assignment").Replace(task.Assignment)
+		if err != nil {
+			return fmt.Errorf("failed assigning task: %w", err)
+		}
+	}
+	return nil
+}
+
 // Updates task in database
 func (r *taskRepository) Update(id int, task *db.Task) (*db.Task, error) {
 	// Init
@@ -134,6 +247,116 @@ func (r *taskRepository) Update(id int, task *db.Task) (*db.Task, error) {
 	return updatedTask, nil
 }
 
+// Find snoozed tasks with a snooze date before the time
+func (r *taskRepository) FindExpiredSnoozes(before time.Time) (*[]db.Task, error) {
+	// Build model to query database
+	tasks := []db.Task{}
+	// Find snoozed tasks that are due
+	result := r.DB.Preload("Assignment").Where("snoozed = ? AND snoozed_till < ?", true, before).Find(&tasks)
+	if result.Error != nil {
+		fmt.Println("Error querying db for expired snoozed tasks: ", result.Error)
+		return nil, result.Error
+	}
+	return &tasks, nil
+}
+
+// Clears snooze from task
+func (r *taskRepository) Unsnooze(id int) error {
+	// Map used as gorm ignores zero values in structs
+	result := r.DB.Model(&db.Task{}).Where("id = ?", id).Updates(map[string]interface{}{"snoozed": false})
+	if result.Error != nil {
+		fmt.Println("Task unsnooze failed: ", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// Find open tasks (not completed, cancelled or archived) for a property through its transactions and maintenance requests
+func (r *taskRepository) FindOpenByProperty(propertyId int) (*[]db.Task, error) {
+	// Build model to query database
+	tasks := []db.Task{}
+	// Subqueries for tasks linked to property
+	transactionTasks := r.DB.Model(&db.Transaction{}).Select("task_id").Where("property_id = ?", propertyId)
+	maintenanceTasks := r.DB.Model(&db.MaintenanceRequest{}).Select("task_id").Where("property_id = ?", propertyId)
+
+	result := r.DB.Preload("BlockedBy.BlockedBy").
+		Where("id IN (?) OR id IN (?)", transactionTasks, maintenanceTasks).
+		Where("completed = ? AND status NOT IN ?", false, []string{"Completed", "Cancelled", "Archived"}).
+		Order("id ASC").Find(&tasks)
+	if result.Error != nil {
+		fmt.Println("Error querying db for open property tasks: ", result.Error)
+		return nil, result.Error
+	}
+	return &tasks, nil
+}
+
+// Find tasks with status in board order (ranked tasks first). Limit of 0 returns all
+func (r *taskRepository) FindByStatus(status string, limit int) (*[]db.Task, error) {
+	// Build model to query database
+	tasks := []db.Task{}
+	query := r.DB.Preload("Assignment").Where("LOWER(status) = LOWER(?)", status).Order("board_rank = 0, board_rank ASC, id ASC")
+	if limit != 0 {
+		query.Limit(limit)
+	}
+
+	result := query.Find(&tasks)
+	if result.Error != nil {
+		fmt.Println("Error querying db for tasks by status: ", result.Error)
+		return nil, result.Error
+	}
+	return &tasks, nil
+}
+
+// Count tasks for each status (status in lower case)
+func (r *taskRepository) CountByStatus() (map[string]int64, error) {
+	// Scan counts into rows
+	rows := []struct {
+		Status string
+		Count  int64
+	}{}
+	result := r.DB.Model(&db.Task{}).Select("LOWER(status) AS status, COUNT(*) AS count").Group("LOWER(status)").Scan(&rows)
+	if result.Error != nil {
+		fmt.Println("Error counting tasks by status: ", result.Error)
+		return nil, result.Error
+	}
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// Sets board rank of tasks to their position in the list (starting at 1)
+func (r *taskRepository) SetBoardRanks(ids []uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			result := tx.Model(&db.Task{}).Where("id = ?", id).Update("board_rank", i+1)
+			if result.Error != nil {
+				fmt.Println("Task board rank update failed: ", result.Error)
+				return result.Error
+			}
+		}
+		return nil
+	})
+}
+
+// Find tasks assigned to user that are open or were completed after the time
+func (r *taskRepository) FindAssignedToUser(userId int, completedSince time.Time) (*[]db.Task, error) {
+	// Build model to query database
+	tasks := []db.Task{}
+	result := r.DB.Preload("Assignment").
+		Joins("JOIN user_tasks ON user_tasks.task_id = tasks.id AND user_tasks.user_id = ?", userId).
+		Where("(tasks.completed = ? AND tasks.status NOT IN ?) OR ((tasks.completed = ? OR tasks.status = ?) AND tasks.updated_at >= ?)",
+			false, []string{"Completed", "Cancelled", "Archived"}, true, "Completed", completedSince).
+		Order("tasks.due_date IS NULL, tasks.due_date ASC, tasks.id ASC").Find(&tasks)
+	if result.Error != nil {
+		fmt.Println("Error querying db for tasks assigned to user: ", result.Error)
+		return nil, result.Error
+	}
+	return &tasks, nil
+}
+
 // Takes limit, offset, and order parameters, builds a query and executes returning a list of tasks
 func QueryAllTasksBasedOnParams(limit int, offset int, order string, dbClient *gorm.DB) ([]db.Task, error) {
 	// Build model to query database
@@ -160,3 +383,39 @@ func QueryAllTasksBasedOnParams(limit int, offset int, order string, dbClient *g
 	// Return if no errors with result
 	return tasks, nil
 }
+
+// Returns true if the task has been completed
+func TaskIsComplete(task *db.Task) bool {
+	return task.Completed || task.Status == "Completed"
+}
+
+// Returns true if the blocking task of a dependency still exists and is incomplete
+func DependencyIsBlocking(dependency *db.TaskDependency) bool {
+	// Deleted blocking tasks aren't preloaded
+	return dependency.BlockedBy.ID != 0 && !TaskIsComplete(&dependency.BlockedBy)
+}
+
+// Returns the percentage of a task's checklist items and subtasks that are complete.
+// Tasks without either are 0 or 100 percent based on their own completion
+func TaskProgress(task *db.Task) int {
+	total := len(task.Checklist) + len(task.Subtasks)
+	if total == 0 {
+		if TaskIsComplete(task) {
+			return 100
+		}
+		return 0
+	}
+
+	complete := 0
+	for _, item := range task.Checklist {
+		if item.Done {
+			complete++
+		}
+	}
+	for _, subtask := range task.Subtasks {
+		if TaskIsComplete(&subtask) {
+			complete++
+		}
+	}
+	return complete * 100 / total
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type TaskChecklistItemRepository interface {
+	FindAll(int, int, string, int) (*[]db.TaskChecklistItem, error)
+	FindById(int) (*db.TaskChecklistItem, error)
+	Create(*db.TaskChecklistItem) (*db.TaskChecklistItem, error)
+	Update(int, *db.TaskChecklistItem) (*db.TaskChecklistItem, error)
+	Delete(int) error
+	// Marks item (id) as done or not done by user (id)
+	SetDoneState(int, bool, int) (*db.TaskChecklistItem, error)
+	// Returns the highest checklist position within a task
+	MaxPosition(int) (int, error)
+}
+
+type taskChecklistItemRepository struct {
+	DB *gorm.DB
+}
+
+func NewTaskChecklistItemRepository(db *gorm.DB) TaskChecklistItemRepository {
+	return &taskChecklistItemRepository{db}
+}
+
+// Creates a task checklist item in the database
+func (r *taskChecklistItemRepository) Create(item *db.TaskChecklistItem) (*db.TaskChecklistItem, error) {
+	// Create new item in database
+	result := r.DB.Create(&item)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating task checklist item: %w", result.Error)
+	}
+
+	return item, nil
+}
+
+// Find a list of task checklist items in the database. Filters by task if task id is not 0
+func (r *taskChecklistItemRepository) FindAll(limit int, offset int, order string, taskId int) (*[]db.TaskChecklistItem, error) {
+	// Query all checklist items based on the received parameters
+	items, err := QueryAllTaskChecklistItemsBasedOnParams(limit, offset, order, taskId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of task checklist items: %s", err)
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+// Find a task checklist item in database by ID
+func (r *taskChecklistItemRepository) FindById(id int) (*db.TaskChecklistItem, error) {
+	// Create an empty ref object of type task checklist item
+	item := db.TaskChecklistItem{}
+	// Grab item from db if exists
+	result := r.DB.Preload("DoneBy").First(&item, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &item, nil
+}
+
+// Delete task checklist item in database
+func (r *taskChecklistItemRepository) Delete(id int) error {
+	// Create an empty ref object of type task checklist item
+	item := db.TaskChecklistItem{}
+	// Delete item from db if exists
+	result := r.DB.Delete(&item, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting task checklist item: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates task checklist item in database
+func (r *taskChecklistItemRepository) Update(id int, item *db.TaskChecklistItem) (*db.TaskChecklistItem, error) {
+	// Find item by id to ensure it exists
+	foundItem, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Task checklist item to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found item
+	updateResult := r.DB.Model(&foundItem).Updates(item)
+	if updateResult.Error != nil {
+		fmt.Println("Task checklist item update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated item by id
+	updatedItem, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated task checklist item not found: ", err)
+		return nil, err
+	}
+	return updatedItem, nil
+}
+
+// Marks item as done (recording who and when) or clears done state
+func (r *taskChecklistItemRepository) SetDoneState(id int, done bool, userId int) (*db.TaskChecklistItem, error) {
+	// Map used as gorm ignores zero values in structs
+	updates := map[string]interface{}{"done": done, "done_at": nil, "done_by_id": nil}
+	if done {
+		updates["done_at"] = time.Now()
+		updates["done_by_id"] = userId
+	}
+
+	result := r.DB.Model(&db.TaskChecklistItem{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		fmt.Println("Task checklist item done state update failed: ", result.Error)
+		return nil, result.Error
+	}
+
+	// Retrieve updated item by id
+	return r.FindById(id)
+}
+
+// Returns the highest checklist position within a task (0 if task has no checklist)
+func (r *taskChecklistItemRepository) MaxPosition(taskId int) (int, error) {
+	var maxPosition int
+	result := r.DB.Model(&db.TaskChecklistItem{}).Where("task_id = ?", taskId).Select("COALESCE(MAX(position), 0)").Scan(&maxPosition)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return maxPosition, nil
+}
+
+// Takes limit, offset, order and task id parameters, builds a query and executes returning a list of task checklist items
+func QueryAllTaskChecklistItemsBasedOnParams(limit int, offset int, order string, taskId int, dbClient *gorm.DB) ([]db.TaskChecklistItem, error) {
+	// Build model to query database
+	items := []db.TaskChecklistItem{}
+	// Build base query for task checklist items table
+	query := dbClient.Model(&items).Preload("DoneBy")
+
+	// Add parameters into query as needed
+	if taskId != 0 {
+		query.Where("task_id = ?", taskId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("task_id ASC, position ASC")
+	}
+	// Query database
+	result := query.Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return items, nil
+}
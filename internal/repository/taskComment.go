@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type TaskCommentRepository interface {
+	FindAll(int, int, string, int) (*[]db.TaskComment, error)
+	FindById(int) (*db.TaskComment, error)
+	Create(*db.TaskComment) (*db.TaskComment, error)
+	Update(int, *db.TaskComment) (*db.TaskComment, error)
+	Delete(int) error
+	// Stores the previous version of a comment prior to an edit
+	CreateEdit(*db.TaskCommentEdit) (*db.TaskCommentEdit, error)
+}
+
+type taskCommentRepository struct {
+	DB *gorm.DB
+}
+
+func NewTaskCommentRepository(db *gorm.DB) TaskCommentRepository {
+	return &taskCommentRepository{db}
+}
+
+// Creates a task comment in the database
+func (r *taskCommentRepository) Create(comment *db.TaskComment) (*db.TaskComment, error) {
+	// Create new comment in database
+	result := r.DB.Create(&comment)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating task comment: %w", result.Error)
+	}
+
+	var assResult error
+	// Build associations
+	if len(comment.Mentions) > 0 {
+		assResult = r.DB.Model(&comment).Association("Mentions").Replace(comment.Mentions)
+	}
+	// Check if association update failed
+	if assResult != nil {
+		fmt.Println("Task comment association update failed: ", assResult)
+		return nil, assResult
+	}
+
+	return comment, nil
+}
+
+// Find a list of task comments in the database. Filters by task if task id is not 0
+func (r *taskCommentRepository) FindAll(limit int, offset int, order string, taskId int) (*[]db.TaskComment, error) {
+	// Query all task comments based on the received parameters
+	comments, err := QueryAllTaskCommentsBasedOnParams(limit, offset, order, taskId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of task comments: %s", err)
+		return nil, err
+	}
+
+	return &comments, nil
+}
+
+// Find a task comment in database by ID
+func (r *taskCommentRepository) FindById(id int) (*db.TaskComment, error) {
+	// Create an empty ref object of type task comment
+	comment := db.TaskComment{}
+	// Grab comment from db if exists
+	result := r.DB.Preload("User").Preload("Mentions").Preload("Replies.User").Preload("History").First(&comment, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &comment, nil
+}
+
+// Delete task comment in database
+func (r *taskCommentRepository) Delete(id int) error {
+	// Create an empty ref object of type task comment
+	comment := db.TaskComment{}
+	// Delete comment from db if exists
+	result := r.DB.Delete(&comment, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting task comment: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates task comment in database
+func (r *taskCommentRepository) Update(id int, comment *db.TaskComment) (*db.TaskComment, error) {
+	// Init
+	var err error
+	// Find task comment by id to ensure it exists
+	foundComment, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Task comment to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found comment
+	updateResult := r.DB.Model(&foundComment).Updates(comment)
+	if updateResult.Error != nil {
+		fmt.Println("Task comment update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Replace mentions with those of the updated comment, clearing them if there are none
+	assResult := r.DB.Model(&foundComment).Association("Mentions").Replace(comment.Mentions)
+	// Check if association update failed
+	if assResult != nil {
+		fmt.Println("Task comment association update failed: ", assResult)
+		return nil, assResult
+	}
+
+	// Retrieve updated task comment by id
+	updatedComment, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated task comment not found: ", err)
+		return nil, err
+	}
+	return updatedComment, nil
+}
+
+// Creates a task comment edit (history) record in the database
+func (r *taskCommentRepository) CreateEdit(edit *db.TaskCommentEdit) (*db.TaskCommentEdit, error) {
+	// Create edit in database
+	result := r.DB.Create(&edit)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating task comment edit: %w", result.Error)
+	}
+
+	return edit, nil
+}
+
+// Takes limit, offset, order and task id parameters, builds a query and executes returning a list of task comments
+func QueryAllTaskCommentsBasedOnParams(limit int, offset int, order string, taskId int, dbClient *gorm.DB) ([]db.TaskComment, error) {
+	// Build model to query database
+	comments := []db.TaskComment{}
+	// Build base query for task comments table
+	query := dbClient.Model(&comments).Preload("User").Preload("Mentions")
+
+	// Add parameters into query as needed
+	if taskId != 0 {
+		query.Where("task_id = ?", taskId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("created_at ASC")
+	}
+	// Query database
+	result := query.Find(&comments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return comments, nil
+}
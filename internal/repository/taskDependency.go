@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type TaskDependencyRepository interface {
+	FindAll(int, int, string, int) (*[]db.TaskDependency, error)
+	FindById(int) (*db.TaskDependency, error)
+	Create(*db.TaskDependency) (*db.TaskDependency, error)
+	Delete(int) error
+	// Returns the IDs of the tasks blocking a task
+	FindBlockerIds(int) ([]uint, error)
+}
+
+type taskDependencyRepository struct {
+	DB *gorm.DB
+}
+
+func NewTaskDependencyRepository(db *gorm.DB) TaskDependencyRepository {
+	return &taskDependencyRepository{db}
+}
+
+// Creates a task dependency in the database
+func (r *taskDependencyRepository) Create(dependency *db.TaskDependency) (*db.TaskDependency, error) {
+	// Create new dependency in database
+	result := r.DB.Create(&dependency)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating task dependency: %w", result.Error)
+	}
+
+	return dependency, nil
+}
+
+// Find a list of task dependencies in the database. Filters by task (either side of dependency) if task id is not 0
+func (r *taskDependencyRepository) FindAll(limit int, offset int, order string, taskId int) (*[]db.TaskDependency, error) {
+	// Query all dependencies based on the received parameters
+	dependencies, err := QueryAllTaskDependenciesBasedOnParams(limit, offset, order, taskId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of task dependencies: %s", err)
+		return nil, err
+	}
+
+	return &dependencies, nil
+}
+
+// Find a task dependency in database by ID
+func (r *taskDependencyRepository) FindById(id int) (*db.TaskDependency, error) {
+	// Create an empty ref object of type task dependency
+	dependency := db.TaskDependency{}
+	// Grab dependency from db if exists
+	result := r.DB.Preload("Task").Preload("BlockedBy").First(&dependency, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &dependency, nil
+}
+
+// Delete task dependency in database
+func (r *taskDependencyRepository) Delete(id int) error {
+	// Create an empty ref object of type task dependency
+	dependency := db.TaskDependency{}
+	// Delete dependency from db if exists
+	result := r.DB.Delete(&dependency, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting task dependency: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Returns the IDs of the tasks blocking a task
+func (r *taskDependencyRepository) FindBlockerIds(taskId int) ([]uint, error) {
+	blockerIds := []uint{}
+	result := r.DB.Model(&db.TaskDependency{}).Where("task_id = ?", taskId).Pluck("blocked_by_id", &blockerIds)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return blockerIds, nil
+}
+
+// Takes limit, offset, order and task id parameters, builds a query and executes returning a list of task dependencies
+func QueryAllTaskDependenciesBasedOnParams(limit int, offset int, order string, taskId int, dbClient *gorm.DB) ([]db.TaskDependency, error) {
+	// Build model to query database
+	dependencies := []db.TaskDependency{}
+	// Build base query for task dependencies table
+	query := dbClient.Model(&dependencies).Preload("Task").Preload("BlockedBy")
+
+	// Add parameters into query as needed
+	if taskId != 0 {
+		query.Where("task_id = ? OR blocked_by_id = ?", taskId, taskId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	}
+	// Query database
+	result := query.Find(&dependencies)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return dependencies, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	FindAllRules(int, int, string, string, string) (*[]db.TaxRule, error)
+	FindRuleById(int) (*db.TaxRule, error)
+	CreateRule(*db.TaxRule) (*db.TaxRule, error)
+	UpdateRule(int, *db.TaxRule) (*db.TaxRule, error)
+	DeleteRule(int) error
+	// Find the rule of a tax on what it applies to for an effective date
+	FindRuleByDate(string, string, time.Time) (*db.TaxRule, error)
+	// Find the latest rule of each tax on what they apply to effective on a date
+	FindEffectiveRules(string, time.Time) (*[]db.TaxRule, error)
+	// Find tax lines dated from (inclusive) to (exclusive)
+	FindLines(time.Time, time.Time) (*[]db.TaxLine, error)
+}
+
+type taxRepository struct {
+	DB *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) TaxRepository {
+	return &taxRepository{db}
+}
+
+// Creates a tax rule in the database
+func (r *taxRepository) CreateRule(rule *db.TaxRule) (*db.TaxRule, error) {
+	// Create new rule in database
+	result := r.DB.Create(&rule)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating tax rule: %w", result.Error)
+	}
+
+	return rule, nil
+}
+
+// Find a list of tax rules in the database. Filters by tax and what it applies to if provided
+func (r *taxRepository) FindAllRules(limit int, offset int, order string, tax string, appliesTo string) (*[]db.TaxRule, error) {
+	// Query all rules based on the received parameters
+	rules, err := QueryAllTaxRulesBasedOnParams(limit, offset, order, tax, appliesTo, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of tax rules: %s", err)
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// Find a tax rule in database by ID
+func (r *taxRepository) FindRuleById(id int) (*db.TaxRule, error) {
+	// Create an empty ref object of type tax rule
+	rule := db.TaxRule{}
+	// Grab rule from db if exists
+	result := r.DB.First(&rule, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &rule, nil
+}
+
+// Delete tax rule in database
+func (r *taxRepository) DeleteRule(id int) error {
+	// Delete rule from db if exists
+	result := r.DB.Delete(&db.TaxRule{}, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting tax rule: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates tax rule in database
+func (r *taxRepository) UpdateRule(id int, rule *db.TaxRule) (*db.TaxRule, error) {
+	// Init
+	var err error
+	// Find rule by id to ensure it exists
+	foundRule, err := r.FindRuleById(id)
+	if err != nil {
+		fmt.Println("Tax rule to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found rule with details from rule
+	updateResult := r.DB.Model(&foundRule).Updates(rule)
+	if updateResult.Error != nil {
+		fmt.Println("Tax rule update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated rule by id
+	updatedRule, err := r.FindRuleById(id)
+	if err != nil {
+		fmt.Println("Updated tax rule not found: ", err)
+		return nil, err
+	}
+	return updatedRule, nil
+}
+
+// Find the rule of a tax on what it applies to for an effective date
+func (r *taxRepository) FindRuleByDate(tax string, appliesTo string, date time.Time) (*db.TaxRule, error) {
+	rule := db.TaxRule{}
+	result := r.DB.Where("tax = ? AND applies_to = ? AND effective_date = ?", tax, appliesTo, date).First(&rule)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rule, nil
+}
+
+// Find the latest rule of each tax on what they apply to effective on a date
+func (r *taxRepository) FindEffectiveRules(appliesTo string, on time.Time) (*[]db.TaxRule, error) {
+	rules := []db.TaxRule{}
+	result := r.DB.Where("applies_to = ? AND effective_date <= ?", appliesTo, on).
+		Order("tax ASC, effective_date DESC").Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Rules are ordered latest first within each tax
+	effective := []db.TaxRule{}
+	for _, rule := range rules {
+		if len(effective) == 0 || effective[len(effective)-1].Tax != rule.Tax {
+			effective = append(effective, rule)
+		}
+	}
+	return &effective, nil
+}
+
+// Find tax lines dated from (inclusive) to (exclusive)
+func (r *taxRepository) FindLines(from time.Time, to time.Time) (*[]db.TaxLine, error) {
+	lines := []db.TaxLine{}
+	result := r.DB.Where("tax_date >= ? AND tax_date < ?", from, to).Order("tax_date ASC").Find(&lines)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &lines, nil
+}
+
+// Takes limit, offset, order, tax and applies to parameters, builds a query and executes returning a list of tax rules
+func QueryAllTaxRulesBasedOnParams(limit int, offset int, order string, tax string, appliesTo string, dbClient *gorm.DB) ([]db.TaxRule, error) {
+	// Build model to query database
+	rules := []db.TaxRule{}
+	// Build base query for tax rules table
+	query := dbClient.Model(&rules)
+
+	// Add parameters into query as needed
+	if tax != "" {
+		query.Where("tax = ?", tax)
+	}
+	if appliesTo != "" {
+		query.Where("applies_to = ?", appliesTo)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("applies_to ASC, tax ASC, effective_date DESC")
+	}
+	// Query database
+	result := query.Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return rules, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"gorm.io/gorm"
+)
+
+type TimeEntryRepository interface {
+	FindAll(int, int, string, int, int) (*[]db.TimeEntry, error)
+	FindById(int) (*db.TimeEntry, error)
+	Create(*db.TimeEntry) (*db.TimeEntry, error)
+	Update(int, *db.TimeEntry) (*db.TimeEntry, error)
+	Delete(int) error
+	// Find the user's running timer
+	FindRunningByUser(int) (*db.TimeEntry, error)
+	// Totals completed time entries between two times, grouped by task, property or user
+	Rollup(string, time.Time, time.Time) (*[]models.TimeRollup, error)
+}
+
+type timeEntryRepository struct {
+	DB *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db}
+}
+
+// Creates a time entry in the database
+func (r *timeEntryRepository) Create(entry *db.TimeEntry) (*db.TimeEntry, error) {
+	// Create new entry in database
+	result := r.DB.Create(&entry)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating time entry: %w", result.Error)
+	}
+
+	return entry, nil
+}
+
+// Find a list of time entries in the database. Filters by task and user if not 0
+func (r *timeEntryRepository) FindAll(limit int, offset int, order string, taskId int, userId int) (*[]db.TimeEntry, error) {
+	// Query all time entries based on the received parameters
+	entries, err := QueryAllTimeEntriesBasedOnParams(limit, offset, order, taskId, userId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of time entries: %s", err)
+		return nil, err
+	}
+
+	return &entries, nil
+}
+
+// Find a time entry in database by ID
+func (r *timeEntryRepository) FindById(id int) (*db.TimeEntry, error) {
+	// Create an empty ref object of type time entry
+	entry := db.TimeEntry{}
+	// Grab entry from db if exists
+	result := r.DB.Preload("User").Preload("Task").First(&entry, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &entry, nil
+}
+
+// Delete time entry in database
+func (r *timeEntryRepository) Delete(id int) error {
+	// Create an empty ref object of type time entry
+	entry := db.TimeEntry{}
+	// Delete entry from db if exists
+	result := r.DB.Delete(&entry, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting time entry: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates time entry in database. All time fields are saved (including zero values)
+func (r *timeEntryRepository) Update(id int, entry *db.TimeEntry) (*db.TimeEntry, error) {
+	// Find entry by id to ensure it exists
+	foundEntry, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Time entry to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found entry (select used as gorm ignores zero values in structs)
+	updateResult := r.DB.Model(&foundEntry).Select("started_at", "ended_at", "duration_minutes", "billable", "note").Updates(entry)
+	if updateResult.Error != nil {
+		fmt.Println("Time entry update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated entry by id
+	updatedEntry, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated time entry not found: ", err)
+		return nil, err
+	}
+	return updatedEntry, nil
+}
+
+// Find the user's running timer (entry without an end time)
+func (r *timeEntryRepository) FindRunningByUser(userId int) (*db.TimeEntry, error) {
+	entry := db.TimeEntry{}
+	result := r.DB.Preload("Task").Where("user_id = ? AND ended_at IS NULL", userId).First(&entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &entry, nil
+}
+
+// Totals completed time entries started between two times, grouped by task, property or user
+func (r *timeEntryRepository) Rollup(groupBy string, from time.Time, to time.Time) (*[]models.TimeRollup, error) {
+	// Determine column to group by
+	var groupColumn string
+	query := r.DB.Model(&db.TimeEntry{})
+	switch groupBy {
+	case "task":
+		groupColumn = "time_entries.task_id"
+	case "user":
+		groupColumn = "time_entries.user_id"
+	case "property":
+		// Tasks are linked to properties through transactions and maintenance requests
+		groupColumn = "COALESCE(transactions.property_id, maintenance_requests.property_id)"
+		query = query.Joins("LEFT JOIN transactions ON transactions.task_id = time_entries.task_id AND transactions.deleted_at IS NULL").
+			Joins("LEFT JOIN maintenance_requests ON maintenance_requests.task_id = time_entries.task_id AND maintenance_requests.deleted_at IS NULL").
+			Where(groupColumn + " IS NOT NULL")
+	default:
+		return nil, fmt.Errorf("can't group time entries by %s", groupBy)
+	}
+
+	rollups := []models.TimeRollup{}
+	result := query.Select(groupColumn+" AS id, COUNT(*) AS entries, SUM(time_entries.duration_minutes) AS minutes, "+
+		"SUM(CASE WHEN time_entries.billable THEN time_entries.duration_minutes ELSE 0 END) AS billable_minutes").
+		Where("time_entries.ended_at IS NOT NULL AND time_entries.started_at >= ? AND time_entries.started_at < ?", from, to).
+		Group(groupColumn).Order("id ASC").Scan(&rollups)
+	if result.Error != nil {
+		fmt.Println("Error querying db for time entry rollup: ", result.Error)
+		return nil, result.Error
+	}
+	return &rollups, nil
+}
+
+// Takes limit, offset, order, task id and user id parameters, builds a query and executes returning a list of time entries
+func QueryAllTimeEntriesBasedOnParams(limit int, offset int, order string, taskId int, userId int, dbClient *gorm.DB) ([]db.TimeEntry, error) {
+	// Build model to query database
+	entries := []db.TimeEntry{}
+	// Build base query for time entries table
+	query := dbClient.Model(&entries).Preload("User")
+
+	// Add parameters into query as needed
+	if taskId != 0 {
+		query.Where("task_id = ?", taskId)
+	}
+	if userId != 0 {
+		query.Where("user_id = ?", userId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("started_at DESC")
+	}
+	// Query database
+	result := query.Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return entries, nil
+}
@@ -51,7 +51,10 @@ func (r *transactionRepository) FindById(id int) (*db.Transaction, error) {
 	// Create an empty ref object of type transaction
 	transaction := db.Transaction{}
 	// Grab transaction from db if exists
-	result := r.DB.Preload("Property").Preload("Contacts").First(&transaction, id)
+	result := r.DB.Preload("Property").Preload("Unit").Preload("Contacts").Preload("CommissionScheme.Tiers").Preload("CommissionSplits.User").
+		Preload("Stage").Preload("StageHistory", func(tx *gorm.DB) *gorm.DB { return tx.Order("changed_at ASC, id ASC") }).
+		Preload("StageHistory.FromStage").Preload("StageHistory.ToStage").Preload("StageHistory.User").
+		Preload("Milestones", func(tx *gorm.DB) *gorm.DB { return tx.Order("due_date ASC") }).Preload("TaxLines").First(&transaction, id)
 
 	// If error detected
 	if result.Error != nil {
@@ -61,17 +64,27 @@ func (r *transactionRepository) FindById(id int) (*db.Transaction, error) {
 	return &transaction, nil
 }
 
-// Delete transaction in database
+// Delete transaction in database, archiving its task
 func (r *transactionRepository) Delete(id int) error {
 	// Create an empty ref object of type transaction
 	transaction := db.Transaction{}
-	// Delete transaction from db if exists
-	result := r.DB.Delete(&transaction, id)
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.First(&transaction, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Delete transaction from db and archive its task
+		result = tx.Delete(&transaction)
+		if result.Error != nil {
+			return result.Error
+		}
+		return archiveTask(tx, transaction.TaskID)
+	})
 
 	// If error detected
-	if result.Error != nil {
-		fmt.Println("error in deleting transaction: ", result.Error)
-		return result.Error
+	if err != nil {
+		fmt.Println("error in deleting transaction: ", err)
+		return err
 	}
 	// else
 	return nil
@@ -89,7 +102,7 @@ func (r *transactionRepository) Update(id int, transaction *db.Transaction) (*db
 	}
 
 	// Update found transaction with details from transaction
-	updateResult := r.DB.Model(&foundTransaction).Updates(transaction)
+	updateResult := r.DB.Model(&foundTransaction).Omit("CommissionScheme", "CommissionSplits", "Stage", "StageHistory", "Milestones").Updates(transaction)
 	if updateResult.Error != nil {
 		fmt.Println("Transaction update failed: ", updateResult.Error)
 		return nil, updateResult.Error
@@ -117,7 +130,7 @@ func QueryAllTransactionsBasedOnParams(limit int, offset int, order string, dbCl
 	// Build model to query database
 	transaction := []db.Transaction{}
 	// Build base query for property log messages table
-	query := dbClient.Model(&transaction)
+	query := dbClient.Model(&transaction).Preload("Stage")
 
 	// Add parameters into query as needed
 	if limit != 0 {
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type UnitRepository interface {
+	FindAll(int, int, string, int) (*[]db.Unit, error)
+	FindById(int) (*db.Unit, error)
+	Create(*db.Unit) (*db.Unit, error)
+	Update(int, *db.Unit) (*db.Unit, error)
+	Delete(int) error
+	// Find a unit of a property by name
+	FindByName(uint, string) (*db.Unit, error)
+	// Checks whether leases, maintenance requests or transactions target a unit
+	IsInUse(uint) (bool, error)
+	// Find rent receipts of a property's leases between two times
+	FindRentReceipts(uint, time.Time, time.Time) (*[]db.LedgerEntry, error)
+}
+
+type unitRepository struct {
+	DB *gorm.DB
+}
+
+func NewUnitRepository(db *gorm.DB) UnitRepository {
+	return &unitRepository{db}
+}
+
+// Creates a unit in the database
+func (r *unitRepository) Create(unit *db.Unit) (*db.Unit, error) {
+	// Create new unit in database
+	result := r.DB.Create(&unit)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating unit: %w", result.Error)
+	}
+
+	return unit, nil
+}
+
+// Find a list of units in the database. Filters by property if provided
+func (r *unitRepository) FindAll(limit int, offset int, order string, propertyId int) (*[]db.Unit, error) {
+	// Query all units based on the received parameters
+	units, err := QueryAllUnitsBasedOnParams(limit, offset, order, propertyId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of units: %s", err)
+		return nil, err
+	}
+
+	return &units, nil
+}
+
+// Find unit in database by ID
+func (r *unitRepository) FindById(id int) (*db.Unit, error) {
+	// Create an empty ref object of type unit
+	unit := db.Unit{}
+	// Grab unit from db if exists
+	result := r.DB.Preload("Features").First(&unit, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &unit, nil
+}
+
+// Find a unit of a property by name
+func (r *unitRepository) FindByName(propertyId uint, name string) (*db.Unit, error) {
+	unit := db.Unit{}
+	result := r.DB.Where("property_id = ? AND name = ?", propertyId, name).First(&unit)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &unit, nil
+}
+
+// Delete unit in database
+func (r *unitRepository) Delete(id int) error {
+	// Delete unit from db if exists
+	result := r.DB.Delete(&db.Unit{}, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting unit: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates unit in database. Features are replaced if provided
+func (r *unitRepository) Update(id int, unit *db.Unit) (*db.Unit, error) {
+	// Init
+	var err error
+	// Find unit by id to ensure it exists
+	foundUnit, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Unit to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found unit with details from unit
+	updateResult := r.DB.Model(&foundUnit).Omit("Features").Updates(unit)
+	if updateResult.Error != nil {
+		fmt.Println("Unit update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+	if len(unit.Features) > 0 {
+		err = r.DB.Model(&foundUnit).Association("Features").Replace(unit.Features)
+		if err != nil {
+			fmt.Println("Unit association update failed: ", err)
+			return nil, err
+		}
+	}
+
+	// Retrieve updated unit by id
+	updatedUnit, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated unit not found: ", err)
+		return nil, err
+	}
+	return updatedUnit, nil
+}
+
+// Checks whether leases, maintenance requests or transactions target a unit
+func (r *unitRepository) IsInUse(id uint) (bool, error) {
+	for _, model := range []interface{}{&db.Lease{}, &db.MaintenanceRequest{}, &db.Transaction{}} {
+		var count int64
+		result := r.DB.Model(model).Where("unit_id = ?", id).Count(&count)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Find rent receipts of a property's leases dated from (inclusive) to (exclusive)
+func (r *unitRepository) FindRentReceipts(propertyId uint, from time.Time, to time.Time) (*[]db.LedgerEntry, error) {
+	entries := []db.LedgerEntry{}
+	result := r.DB.Where("type = ? AND entry_date >= ? AND entry_date < ?", "Receipt", from, to).
+		Where("lease_id IN (SELECT id FROM leases WHERE property_id = ? AND deleted_at IS NULL)", propertyId).
+		Order("entry_date ASC, id ASC").Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &entries, nil
+}
+
+// Takes limit, offset, order and property parameters, builds a query and executes returning a list of units
+func QueryAllUnitsBasedOnParams(limit int, offset int, order string, propertyId int, dbClient *gorm.DB) ([]db.Unit, error) {
+	// Build model to query database
+	units := []db.Unit{}
+	// Build base query for units table
+	query := dbClient.Model(&units).Preload("Features")
+
+	// Add parameters into query as needed
+	if propertyId != 0 {
+		query.Where("property_id = ?", propertyId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("property_id ASC, name ASC")
+	}
+	// Query database
+	result := query.Find(&units)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return units, nil
+}
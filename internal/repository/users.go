@@ -13,6 +13,8 @@ type UserRepository interface {
 	FindAll(int, int, string) (*[]db.User, error)
 	FindById(int) (*db.User, error)
 	FindByEmail(string) (*db.User, error)
+	FindByUsernames([]string) (*[]db.User, error)
+	FindByRole(string) (*[]db.User, error)
 	Create(user *db.User) (*db.User, error)
 	Update(int, *db.User) (*db.User, error)
 	Delete(int) error
@@ -54,7 +56,7 @@ func (r *userRepository) FindById(userId int) (*db.User, error) {
 	// Create an empty ref object of type user
 	user := db.User{}
 	// Check if user exists in db
-	result := r.DB.Select("ID", "name", "username", "email", "role").First(&user, userId)
+	result := r.DB.Select("ID", "name", "username", "email", "role", "reporting_currency").First(&user, userId)
 
 	// If error detected
 	if result.Error != nil {
@@ -79,6 +81,40 @@ func (r *userRepository) FindByEmail(email string) (*db.User, error) {
 	return &user, nil
 }
 
+// Find users in database with a username in the list (used to resolve @mentions)
+func (r *userRepository) FindByUsernames(usernames []string) (*[]db.User, error) {
+	// Create an empty ref object of type user slice
+	users := []db.User{}
+	// Return early if no usernames to search
+	if len(usernames) == 0 {
+		return &users, nil
+	}
+	// Find users with matching usernames
+	result := r.DB.Select("ID", "name", "username", "email", "role").Where("username IN ?", usernames).Find(&users)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &users, nil
+}
+
+// Find users in database with a role (used to notify admins)
+func (r *userRepository) FindByRole(role string) (*[]db.User, error) {
+	// Create an empty ref object of type user slice
+	users := []db.User{}
+	// Find users with matching role
+	result := r.DB.Select("ID", "name", "username", "email", "role").Where("role = ?", role).Find(&users)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &users, nil
+}
+
 // Delete user in database
 func (r *userRepository) Delete(id int) error {
 	// Create an empty ref object of type user
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
 	"gorm.io/gorm"
 )
 
@@ -13,6 +14,12 @@ type VendorRepository interface {
 	Create(*db.Vendor) (*db.Vendor, error)
 	Update(int, *db.Vendor) (*db.Vendor, error)
 	Delete(int) error
+	// Find vendors offering a work type
+	FindByWorkType(int) (*[]db.Vendor, error)
+	// Find vendors offering a work type with their work order and rating aggregates
+	FindWithWorkload(int) (*[]models.VendorWorkload, error)
+	// Find vendors registered under any of the NPWPs
+	FindByNPWP([]string) (*[]db.Vendor, error)
 }
 
 type vendorRepository struct {
@@ -68,6 +75,100 @@ func (r *vendorRepository) FindById(id int) (*db.Vendor, error) {
 	return &vendor, nil
 }
 
+// Find vendors offering a work type
+func (r *vendorRepository) FindByWorkType(workTypeId int) (*[]db.Vendor, error) {
+	vendors := []db.Vendor{}
+	result := r.DB.Joins("JOIN vendor_work_types ON vendor_work_types.vendor_id = vendors.id").
+		Where("vendor_work_types.work_type_id = ?", workTypeId).
+		Preload("WorkTypes").Order("vendors.company_name ASC").Find(&vendors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &vendors, nil
+}
+
+// Find vendors offering a work type (all vendors if 0) with their work order counts and average rating
+func (r *vendorRepository) FindWithWorkload(workTypeId int) (*[]models.VendorWorkload, error) {
+	vendors := []db.Vendor{}
+	query := r.DB.Preload("WorkTypes").Order("company_name ASC")
+	if workTypeId != 0 {
+		query = query.Where("id IN (?)", r.DB.Table("vendor_work_types").Select("vendor_id").Where("work_type_id = ?", workTypeId))
+	}
+	result := query.Find(&vendors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	vendorIds := []uint{}
+	for _, vendor := range vendors {
+		vendorIds = append(vendorIds, vendor.ID)
+	}
+
+	// Work order counts of each vendor
+	completed := []string{"Completed", "Invoiced", "Paid"}
+	orders := []struct {
+		VendorID             uint
+		CompletedWorkOrders  int
+		OpenWorkOrders       int
+		ScheduledCompletions int
+		OnTimeCompletions    int
+	}{}
+	result = r.DB.Model(&db.WorkOrder{}).Select("vendor_id, "+
+		"SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS completed_work_orders, "+
+		"SUM(CASE WHEN status NOT IN ? THEN 1 ELSE 0 END) AS open_work_orders, "+
+		"SUM(CASE WHEN status IN ? AND scheduled_end IS NOT NULL AND completed_at IS NOT NULL THEN 1 ELSE 0 END) AS scheduled_completions, "+
+		"SUM(CASE WHEN status IN ? AND completed_at <= scheduled_end THEN 1 ELSE 0 END) AS on_time_completions",
+		completed, completed, completed, completed).
+		Where("vendor_id IN ?", vendorIds).Group("vendor_id").Scan(&orders)
+	if result.Error != nil {
+		fmt.Println("Error counting vendor work orders: ", result.Error)
+		return nil, result.Error
+	}
+
+	// Average of all rating scores of each vendor
+	ratings := []struct {
+		VendorID      uint
+		Ratings       int
+		AverageRating float64
+	}{}
+	result = r.DB.Model(&db.VendorRating{}).
+		Select("vendor_id, COUNT(*) AS ratings, AVG((quality + timeliness + price + communication) / 4.0) AS average_rating").
+		Where("vendor_id IN ?", vendorIds).Group("vendor_id").Scan(&ratings)
+	if result.Error != nil {
+		fmt.Println("Error averaging vendor ratings: ", result.Error)
+		return nil, result.Error
+	}
+
+	workloads := []models.VendorWorkload{}
+	rows := map[uint]int{}
+	for i, vendor := range vendors {
+		rows[vendor.ID] = i
+		workloads = append(workloads, models.VendorWorkload{Vendor: vendor})
+	}
+	for _, row := range orders {
+		workload := &workloads[rows[row.VendorID]]
+		workload.CompletedWorkOrders = row.CompletedWorkOrders
+		workload.OpenWorkOrders = row.OpenWorkOrders
+		workload.ScheduledCompletions = row.ScheduledCompletions
+		workload.OnTimeCompletions = row.OnTimeCompletions
+	}
+	for _, row := range ratings {
+		workload := &workloads[rows[row.VendorID]]
+		workload.Ratings = row.Ratings
+		workload.AverageRating = row.AverageRating
+	}
+	return &workloads, nil
+}
+
+// Find vendors registered under any of the NPWPs
+func (r *vendorRepository) FindByNPWP(npwps []string) (*[]db.Vendor, error) {
+	vendors := []db.Vendor{}
+	result := r.DB.Where("npwp IN ?", npwps).Find(&vendors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &vendors, nil
+}
+
 // Delete vendor in database
 func (r *vendorRepository) Delete(id int) error {
 	// Create an empty ref object of type vendor
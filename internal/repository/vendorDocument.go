@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type VendorDocumentRepository interface {
+	FindAll(int, int, string, int, string) (*[]db.VendorDocument, error)
+	FindById(int) (*db.VendorDocument, error)
+	Create(*db.VendorDocument) (*db.VendorDocument, error)
+	Update(int, *db.VendorDocument) (*db.VendorDocument, error)
+	Delete(int) error
+	// Find all documents held by a vendor
+	FindByVendor(int) (*[]db.VendorDocument, error)
+	// Find documents expiring before a time that haven't been flagged yet
+	FindExpiringUnnotified(time.Time) (*[]db.VendorDocument, error)
+	// Records that the expiry of documents has been flagged
+	MarkExpiryNotified([]uint, time.Time) error
+}
+
+type vendorDocumentRepository struct {
+	DB *gorm.DB
+}
+
+func NewVendorDocumentRepository(db *gorm.DB) VendorDocumentRepository {
+	return &vendorDocumentRepository{db}
+}
+
+// Creates a vendor document in the database
+func (r *vendorDocumentRepository) Create(document *db.VendorDocument) (*db.VendorDocument, error) {
+	// Create new document in database
+	result := r.DB.Create(&document)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating vendor document: %w", result.Error)
+	}
+
+	return document, nil
+}
+
+// Find a list of vendor documents in the database. Filters by vendor and type if provided
+func (r *vendorDocumentRepository) FindAll(limit int, offset int, order string, vendorId int, documentType string) (*[]db.VendorDocument, error) {
+	// Query all documents based on the received parameters
+	documents, err := QueryAllVendorDocumentsBasedOnParams(limit, offset, order, vendorId, documentType, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of vendor documents: %s", err)
+		return nil, err
+	}
+
+	return &documents, nil
+}
+
+// Find a vendor document in database by ID
+func (r *vendorDocumentRepository) FindById(id int) (*db.VendorDocument, error) {
+	// Create an empty ref object of type vendor document
+	document := db.VendorDocument{}
+	// Grab document from db if exists
+	result := r.DB.Preload("Vendor").First(&document, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &document, nil
+}
+
+// Find all documents held by a vendor
+func (r *vendorDocumentRepository) FindByVendor(vendorId int) (*[]db.VendorDocument, error) {
+	documents := []db.VendorDocument{}
+	result := r.DB.Where("vendor_id = ?", vendorId).Order("type ASC, expiry_date DESC").Find(&documents)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &documents, nil
+}
+
+// Find documents expiring before a time that haven't been flagged yet
+func (r *vendorDocumentRepository) FindExpiringUnnotified(before time.Time) (*[]db.VendorDocument, error) {
+	documents := []db.VendorDocument{}
+	result := r.DB.Preload("Vendor").Where("expiry_date IS NOT NULL AND expiry_date <= ? AND expiry_notified_at IS NULL", before).
+		Order("expiry_date ASC").Find(&documents)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &documents, nil
+}
+
+// Records that the expiry of documents has been flagged
+func (r *vendorDocumentRepository) MarkExpiryNotified(ids []uint, notifiedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := r.DB.Model(&db.VendorDocument{}).Where("id IN ?", ids).Update("expiry_notified_at", notifiedAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed flagging vendor document expiry: %w", result.Error)
+	}
+	return nil
+}
+
+// Delete vendor document in database
+func (r *vendorDocumentRepository) Delete(id int) error {
+	// Create an empty ref object of type vendor document
+	document := db.VendorDocument{}
+	// Delete document from db if exists
+	result := r.DB.Delete(&document, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting vendor document: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates vendor document in database. A new expiry date clears the expiry flag
+func (r *vendorDocumentRepository) Update(id int, document *db.VendorDocument) (*db.VendorDocument, error) {
+	// Init
+	var err error
+	// Find document by id to ensure it exists
+	foundDocument, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Vendor document to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found document with incoming details
+	updateResult := r.DB.Model(&foundDocument).Omit("Vendor").Updates(document)
+	if updateResult.Error != nil {
+		fmt.Println("Vendor document update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+	// Renewed documents are flagged again before their new expiry
+	if document.ExpiryDate != nil {
+		updateResult = r.DB.Model(&foundDocument).Update("expiry_notified_at", nil)
+		if updateResult.Error != nil {
+			fmt.Println("Vendor document update failed: ", updateResult.Error)
+			return nil, updateResult.Error
+		}
+	}
+
+	// Retrieve updated document by id
+	updatedDocument, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated vendor document not found: ", err)
+		return nil, err
+	}
+	return updatedDocument, nil
+}
+
+// Takes limit, offset, order, vendor and type parameters, builds a query and executes returning a list of vendor documents
+func QueryAllVendorDocumentsBasedOnParams(limit int, offset int, order string, vendorId int, documentType string, dbClient *gorm.DB) ([]db.VendorDocument, error) {
+	// Build model to query database
+	documents := []db.VendorDocument{}
+	// Build base query for vendor documents table
+	query := dbClient.Model(&documents).Preload("Vendor")
+
+	// Add parameters into query as needed
+	if vendorId != 0 {
+		query.Where("vendor_id = ?", vendorId)
+	}
+	if documentType != "" {
+		query.Where("type = ?", documentType)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("expiry_date ASC")
+	}
+	// Query database
+	result := query.Find(&documents)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return documents, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type VendorInvoiceRepository interface {
+	FindAll(int, int, string, int, string) (*[]db.VendorInvoice, error)
+	FindById(int) (*db.VendorInvoice, error)
+	Create(*db.VendorInvoice) (*db.VendorInvoice, error)
+	Update(int, *db.VendorInvoice) (*db.VendorInvoice, error)
+	Delete(int) error
+	// Records a payment and updates the invoice's amount paid and status
+	AddPayment(*db.VendorInvoice, *db.VendorPayment) error
+	// Find invoices with an outstanding balance. Filters by vendor if id is not 0
+	FindOutstanding(int) (*[]db.VendorInvoice, error)
+}
+
+type vendorInvoiceRepository struct {
+	DB *gorm.DB
+}
+
+func NewVendorInvoiceRepository(db *gorm.DB) VendorInvoiceRepository {
+	return &vendorInvoiceRepository{db}
+}
+
+// Creates a vendor invoice with its line items and tax lines in the database
+func (r *vendorInvoiceRepository) Create(invoice *db.VendorInvoice) (*db.VendorInvoice, error) {
+	// Create new invoice in database
+	result := r.DB.Create(&invoice)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating vendor invoice: %w", result.Error)
+	}
+
+	return invoice, nil
+}
+
+// Find a list of vendor invoices in the database. Filters by vendor and status if provided
+func (r *vendorInvoiceRepository) FindAll(limit int, offset int, order string, vendorId int, status string) (*[]db.VendorInvoice, error) {
+	// Query all invoices based on the received parameters
+	invoices, err := QueryAllVendorInvoicesBasedOnParams(limit, offset, order, vendorId, status, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of vendor invoices: %s", err)
+		return nil, err
+	}
+
+	return &invoices, nil
+}
+
+// Find a vendor invoice in database by ID
+func (r *vendorInvoiceRepository) FindById(id int) (*db.VendorInvoice, error) {
+	// Create an empty ref object of type vendor invoice
+	invoice := db.VendorInvoice{}
+	// Grab invoice from db if exists
+	result := r.DB.Preload("Vendor").Preload("Lines").Preload("TaxLines").Preload("Payments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("paid_at ASC")
+	}).First(&invoice, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &invoice, nil
+}
+
+// Delete vendor invoice in database
+func (r *vendorInvoiceRepository) Delete(id int) error {
+	// Create an empty ref object of type vendor invoice
+	invoice := db.VendorInvoice{}
+	// Delete invoice from db if exists
+	result := r.DB.Delete(&invoice, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting vendor invoice: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates vendor invoice in database. Line items are replaced if not nil
+func (r *vendorInvoiceRepository) Update(id int, invoice *db.VendorInvoice) (*db.VendorInvoice, error) {
+	// Init
+	var err error
+	// Find invoice by id to ensure it exists
+	foundInvoice, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Vendor invoice to update not found: ", err)
+		return nil, err
+	}
+
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		// Update found invoice with incoming details
+		updateResult := tx.Model(&foundInvoice).Omit("Vendor", "Lines", "Payments", "TaxLines", "WorkOrder", "MaintenanceRequest").Updates(invoice)
+		if updateResult.Error != nil {
+			return updateResult.Error
+		}
+		if invoice.Lines == nil {
+			return nil
+		}
+		// Replace line items, their totals (which may be zero) and tax lines
+		totalsResult := tx.Model(&foundInvoice).Updates(moneyColumns(map[string]db.Money{"subtotal": invoice.Subtotal, "ppn": invoice.PPN, "total": invoice.Total, "withholding": invoice.Withholding}))
+		if totalsResult.Error != nil {
+			return totalsResult.Error
+		}
+		deleteResult := tx.Where("vendor_invoice_id = ?", id).Delete(&db.VendorInvoiceLine{})
+		if deleteResult.Error != nil {
+			return deleteResult.Error
+		}
+		for i := range invoice.Lines {
+			invoice.Lines[i].VendorInvoiceID = uint(id)
+		}
+		createResult := tx.Create(&invoice.Lines)
+		if createResult.Error != nil {
+			return createResult.Error
+		}
+		deleteResult = tx.Where("vendor_invoice_id = ?", id).Delete(&db.TaxLine{})
+		if deleteResult.Error != nil {
+			return deleteResult.Error
+		}
+		if len(invoice.TaxLines) == 0 {
+			return nil
+		}
+		vendorInvoiceId := uint(id)
+		for i := range invoice.TaxLines {
+			invoice.TaxLines[i].VendorInvoiceID = &vendorInvoiceId
+		}
+		return tx.Create(&invoice.TaxLines).Error
+	})
+	if err != nil {
+		fmt.Println("Vendor invoice update failed: ", err)
+		return nil, err
+	}
+
+	// Retrieve updated invoice by id
+	updatedInvoice, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated vendor invoice not found: ", err)
+		return nil, err
+	}
+	return updatedInvoice, nil
+}
+
+// Records a payment and updates the invoice's amount paid and status
+func (r *vendorInvoiceRepository) AddPayment(invoice *db.VendorInvoice, payment *db.VendorPayment) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		payment.VendorInvoiceID = invoice.ID
+		result := tx.Create(payment)
+		if result.Error != nil {
+			return fmt.Errorf("failed creating vendor payment: %w", result.Error)
+		}
+		columns := moneyColumns(map[string]db.Money{"amount_paid": invoice.AmountPaid})
+		columns["status"] = invoice.Status
+		result = tx.Model(&db.VendorInvoice{}).Where("id = ?", invoice.ID).Updates(columns)
+		if result.Error != nil {
+			return fmt.Errorf("failed updating vendor invoice balance: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// Find invoices with an outstanding balance. Filters by vendor if id is not 0
+func (r *vendorInvoiceRepository) FindOutstanding(vendorId int) (*[]db.VendorInvoice, error) {
+	invoices := []db.VendorInvoice{}
+	query := r.DB.Preload("Vendor").Where("status <> ?", "Paid")
+	if vendorId != 0 {
+		query.Where("vendor_id = ?", vendorId)
+	}
+	result := query.Order("vendor_id ASC, due_date ASC").Find(&invoices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invoices, nil
+}
+
+// Takes limit, offset, order, vendor and status parameters, builds a query and executes returning a list of vendor invoices
+func QueryAllVendorInvoicesBasedOnParams(limit int, offset int, order string, vendorId int, status string, dbClient *gorm.DB) ([]db.VendorInvoice, error) {
+	// Build model to query database
+	invoices := []db.VendorInvoice{}
+	// Build base query for vendor invoices table
+	query := dbClient.Model(&invoices).Preload("Vendor")
+
+	// Add parameters into query as needed
+	if vendorId != 0 {
+		query.Where("vendor_id = ?", vendorId)
+	}
+	if status != "" {
+		query.Where("status = ?", status)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("due_date ASC")
+	}
+	// Query database
+	result := query.Find(&invoices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return invoices, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type VendorQuoteRepository interface {
+	FindAll(int, int, string, int, int) (*[]db.VendorQuote, error)
+	FindById(int) (*db.VendorQuote, error)
+	Create(*db.VendorQuote) (*db.VendorQuote, error)
+	Update(int, *db.VendorQuote) (*db.VendorQuote, error)
+	Delete(int) error
+	// Returns the IDs of the vendors invited to quote on a maintenance request
+	FindInvitedVendorIds(int) ([]uint, error)
+	// Accepts a quote, rejecting the request's other open quotes and assigning the vendor and cost to the request
+	Accept(*db.VendorQuote) error
+}
+
+type vendorQuoteRepository struct {
+	DB *gorm.DB
+}
+
+func NewVendorQuoteRepository(db *gorm.DB) VendorQuoteRepository {
+	return &vendorQuoteRepository{db}
+}
+
+// Creates a vendor quote in the database
+func (r *vendorQuoteRepository) Create(quote *db.VendorQuote) (*db.VendorQuote, error) {
+	// Create new quote in database
+	result := r.DB.Create(&quote)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating vendor quote: %w", result.Error)
+	}
+
+	return quote, nil
+}
+
+// Find a list of vendor quotes in the database. Filters by maintenance request and vendor if ids are not 0
+func (r *vendorQuoteRepository) FindAll(limit int, offset int, order string, maintenanceId int, vendorId int) (*[]db.VendorQuote, error) {
+	// Query all quotes based on the received parameters
+	quotes, err := QueryAllVendorQuotesBasedOnParams(limit, offset, order, maintenanceId, vendorId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of vendor quotes: %s", err)
+		return nil, err
+	}
+
+	return &quotes, nil
+}
+
+// Find a vendor quote in database by ID
+func (r *vendorQuoteRepository) FindById(id int) (*db.VendorQuote, error) {
+	// Create an empty ref object of type vendor quote
+	quote := db.VendorQuote{}
+	// Grab quote from db if exists
+	result := r.DB.Preload("Vendor").Preload("MaintenanceRequest").Preload("Attachments").First(&quote, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &quote, nil
+}
+
+// Delete vendor quote in database
+func (r *vendorQuoteRepository) Delete(id int) error {
+	// Create an empty ref object of type vendor quote
+	quote := db.VendorQuote{}
+	// Delete quote from db if exists
+	result := r.DB.Delete(&quote, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting vendor quote: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates vendor quote in database. Attachments are replaced if not nil
+func (r *vendorQuoteRepository) Update(id int, quote *db.VendorQuote) (*db.VendorQuote, error) {
+	// Init
+	var err error
+	// Find quote by id to ensure it exists
+	foundQuote, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Vendor quote to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found quote with incoming details
+	updateResult := r.DB.Model(&foundQuote).Omit("Attachments").Updates(quote)
+	if updateResult.Error != nil {
+		fmt.Println("Vendor quote update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	if quote.Attachments != nil {
+		assResult := r.DB.Model(&foundQuote).Association("Attachments").Replace(quote.Attachments)
+		// Check if association update failed
+		if assResult != nil {
+			fmt.Println("Vendor quote attachment update failed: ", assResult)
+			return nil, assResult
+		}
+	}
+
+	// Retrieve updated quote by id
+	updatedQuote, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated vendor quote not found: ", err)
+		return nil, err
+	}
+	return updatedQuote, nil
+}
+
+// Returns the IDs of the vendors invited to quote on a maintenance request
+func (r *vendorQuoteRepository) FindInvitedVendorIds(maintenanceId int) ([]uint, error) {
+	vendorIds := []uint{}
+	result := r.DB.Model(&db.VendorQuote{}).Where("maintenance_request_id = ?", maintenanceId).Pluck("vendor_id", &vendorIds)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return vendorIds, nil
+}
+
+// Accepts a quote, rejecting the request's other open quotes and assigning the vendor and cost to the request
+func (r *vendorQuoteRepository) Accept(quote *db.VendorQuote) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&db.VendorQuote{}).Where("id = ?", quote.ID).Update("status", "Accepted")
+		if result.Error != nil {
+			return fmt.Errorf("failed accepting vendor quote: %w", result.Error)
+		}
+		result = tx.Model(&db.VendorQuote{}).
+			Where("maintenance_request_id = ? AND id <> ? AND status IN ?", quote.MaintenanceRequestID, quote.ID, []string{"Invited", "Submitted"}).
+			Update("status", "Rejected")
+		if result.Error != nil {
+			return fmt.Errorf("failed rejecting other vendor quotes: %w", result.Error)
+		}
+		columns := moneyColumns(map[string]db.Money{"total_cost": quote.Amount, "tax": quote.Tax})
+		columns["vendor_id"] = quote.VendorID
+		result = tx.Model(&db.MaintenanceRequest{}).Where("id = ?", quote.MaintenanceRequestID).Updates(columns)
+		if result.Error != nil {
+			return fmt.Errorf("failed assigning vendor to maintenance request: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// Takes limit, offset, order, maintenance request and vendor id parameters, builds a query and executes returning a list of vendor quotes
+func QueryAllVendorQuotesBasedOnParams(limit int, offset int, order string, maintenanceId int, vendorId int, dbClient *gorm.DB) ([]db.VendorQuote, error) {
+	// Build model to query database
+	quotes := []db.VendorQuote{}
+	// Build base query for vendor quotes table
+	query := dbClient.Model(&quotes).Preload("Vendor").Preload("Attachments")
+
+	// Add parameters into query as needed
+	if maintenanceId != 0 {
+		query.Where("maintenance_request_id = ?", maintenanceId)
+	}
+	if vendorId != 0 {
+		query.Where("vendor_id = ?", vendorId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("created_at DESC")
+	}
+	// Query database
+	result := query.Find(&quotes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return quotes, nil
+}
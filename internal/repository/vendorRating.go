@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type VendorRatingRepository interface {
+	FindAll(int, int, string, int, int) (*[]db.VendorRating, error)
+	FindById(int) (*db.VendorRating, error)
+	Create(*db.VendorRating) (*db.VendorRating, error)
+	Update(int, *db.VendorRating) (*db.VendorRating, error)
+	Delete(int) error
+	// Find a vendor's ratings created since a time, with each request's work type
+	FindByVendorSince(int, time.Time) (*[]db.VendorRating, error)
+}
+
+type vendorRatingRepository struct {
+	DB *gorm.DB
+}
+
+func NewVendorRatingRepository(db *gorm.DB) VendorRatingRepository {
+	return &vendorRatingRepository{db}
+}
+
+// Creates a vendor rating in the database
+func (r *vendorRatingRepository) Create(rating *db.VendorRating) (*db.VendorRating, error) {
+	// Create new rating in database
+	result := r.DB.Create(&rating)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating vendor rating: %w", result.Error)
+	}
+
+	return rating, nil
+}
+
+// Find a list of vendor ratings in the database. Filters by vendor and maintenance request if provided
+func (r *vendorRatingRepository) FindAll(limit int, offset int, order string, vendorId int, maintenanceId int) (*[]db.VendorRating, error) {
+	// Query all ratings based on the received parameters
+	ratings, err := QueryAllVendorRatingsBasedOnParams(limit, offset, order, vendorId, maintenanceId, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of vendor ratings: %s", err)
+		return nil, err
+	}
+
+	return &ratings, nil
+}
+
+// Find a vendor rating in database by ID
+func (r *vendorRatingRepository) FindById(id int) (*db.VendorRating, error) {
+	// Create an empty ref object of type vendor rating
+	rating := db.VendorRating{}
+	// Grab rating from db if exists
+	result := r.DB.Preload("Vendor").Preload("MaintenanceRequest.WorkType").First(&rating, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &rating, nil
+}
+
+// Find a vendor's ratings created since a time, with each request's work type
+func (r *vendorRatingRepository) FindByVendorSince(vendorId int, since time.Time) (*[]db.VendorRating, error) {
+	ratings := []db.VendorRating{}
+	result := r.DB.Preload("MaintenanceRequest.WorkType").Where("vendor_id = ? AND created_at >= ?", vendorId, since).
+		Order("created_at ASC").Find(&ratings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &ratings, nil
+}
+
+// Delete vendor rating in database
+func (r *vendorRatingRepository) Delete(id int) error {
+	// Create an empty ref object of type vendor rating
+	rating := db.VendorRating{}
+	// Delete rating from db if exists
+	result := r.DB.Delete(&rating, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting vendor rating: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates vendor rating in database
+func (r *vendorRatingRepository) Update(id int, rating *db.VendorRating) (*db.VendorRating, error) {
+	// Init
+	var err error
+	// Find rating by id to ensure it exists
+	foundRating, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Vendor rating to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found rating with incoming details
+	updateResult := r.DB.Model(&foundRating).Omit("Vendor", "MaintenanceRequest", "User").Updates(rating)
+	if updateResult.Error != nil {
+		fmt.Println("Vendor rating update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated rating by id
+	updatedRating, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated vendor rating not found: ", err)
+		return nil, err
+	}
+	return updatedRating, nil
+}
+
+// Takes limit, offset, order, vendor and maintenance request parameters, builds a query and executes returning a list of vendor ratings
+func QueryAllVendorRatingsBasedOnParams(limit int, offset int, order string, vendorId int, maintenanceId int, dbClient *gorm.DB) ([]db.VendorRating, error) {
+	// Build model to query database
+	ratings := []db.VendorRating{}
+	// Build base query for vendor ratings table
+	query := dbClient.Model(&ratings).Preload("Vendor")
+
+	// Add parameters into query as needed
+	if vendorId != 0 {
+		query.Where("vendor_id = ?", vendorId)
+	}
+	if maintenanceId != 0 {
+		query.Where("maintenance_request_id = ?", maintenanceId)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("created_at DESC")
+	}
+	// Query database
+	result := query.Find(&ratings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return ratings, nil
+}
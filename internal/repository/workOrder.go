@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"gorm.io/gorm"
+)
+
+type WorkOrderRepository interface {
+	FindAll(int, int, string, int, int, string) (*[]db.WorkOrder, error)
+	FindById(int) (*db.WorkOrder, error)
+	Create(*db.WorkOrder) (*db.WorkOrder, error)
+	Update(int, *db.WorkOrder) (*db.WorkOrder, error)
+	Delete(int) error
+}
+
+type workOrderRepository struct {
+	DB *gorm.DB
+}
+
+func NewWorkOrderRepository(db *gorm.DB) WorkOrderRepository {
+	return &workOrderRepository{db}
+}
+
+// Creates a work order in the database
+func (r *workOrderRepository) Create(order *db.WorkOrder) (*db.WorkOrder, error) {
+	// Create new work order in database
+	result := r.DB.Create(&order)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed creating work order: %w", result.Error)
+	}
+
+	return order, nil
+}
+
+// Find a list of work orders in the database. Filters by maintenance request, vendor and status if provided
+func (r *workOrderRepository) FindAll(limit int, offset int, order string, maintenanceId int, vendorId int, status string) (*[]db.WorkOrder, error) {
+	// Query all work orders based on the received parameters
+	orders, err := QueryAllWorkOrdersBasedOnParams(limit, offset, order, maintenanceId, vendorId, status, r.DB)
+	if err != nil {
+		fmt.Printf("Error querying db for list of work orders: %s", err)
+		return nil, err
+	}
+
+	return &orders, nil
+}
+
+// Find a work order in database by ID
+func (r *workOrderRepository) FindById(id int) (*db.WorkOrder, error) {
+	// Create an empty ref object of type work order
+	order := db.WorkOrder{}
+	// Grab work order from db if exists
+	result := r.DB.Preload("Vendor").Preload("MaintenanceRequest.Property").Preload("Task").First(&order, id)
+
+	// If error detected
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// else
+	return &order, nil
+}
+
+// Delete work order in database
+func (r *workOrderRepository) Delete(id int) error {
+	// Create an empty ref object of type work order
+	order := db.WorkOrder{}
+	// Delete work order from db if exists
+	result := r.DB.Delete(&order, id)
+
+	// If error detected
+	if result.Error != nil {
+		fmt.Println("error in deleting work order: ", result.Error)
+		return result.Error
+	}
+	// else
+	return nil
+}
+
+// Updates work order in database
+func (r *workOrderRepository) Update(id int, order *db.WorkOrder) (*db.WorkOrder, error) {
+	// Init
+	var err error
+	// Find work order by id to ensure it exists
+	foundOrder, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Work order to update not found: ", err)
+		return nil, err
+	}
+
+	// Update found work order with incoming details
+	updateResult := r.DB.Model(&foundOrder).Omit("Vendor", "MaintenanceRequest", "Task").Updates(order)
+	if updateResult.Error != nil {
+		fmt.Println("Work order update failed: ", updateResult.Error)
+		return nil, updateResult.Error
+	}
+
+	// Retrieve updated work order by id
+	updatedOrder, err := r.FindById(id)
+	if err != nil {
+		fmt.Println("Updated work order not found: ", err)
+		return nil, err
+	}
+	return updatedOrder, nil
+}
+
+// Takes limit, offset, order, maintenance request, vendor and status parameters, builds a query and executes returning a list of work orders
+func QueryAllWorkOrdersBasedOnParams(limit int, offset int, order string, maintenanceId int, vendorId int, status string, dbClient *gorm.DB) ([]db.WorkOrder, error) {
+	// Build model to query database
+	orders := []db.WorkOrder{}
+	// Build base query for work orders table
+	query := dbClient.Model(&orders).Preload("Vendor")
+
+	// Add parameters into query as needed
+	if maintenanceId != 0 {
+		query.Where("maintenance_request_id = ?", maintenanceId)
+	}
+	if vendorId != 0 {
+		query.Where("vendor_id = ?", vendorId)
+	}
+	if status != "" {
+		query.Where("status = ?", status)
+	}
+	if limit != 0 {
+		query.Limit(limit)
+	}
+	if offset != 0 {
+		query.Offset(offset)
+	}
+	// order format should be "column_name ASC/DESC" eg. "created_at ASC"
+	if order != "" {
+		query.Order(order)
+	} else {
+		query.Order("created_at DESC")
+	}
+	// Query database
+	result := query.Find(&orders)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Return if no errors with result
+	return orders, nil
+}
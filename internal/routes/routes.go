@@ -28,6 +28,26 @@ type api struct {
 	workType           controller.WorkTypeController
 	vendor             controller.VendorController
 	propertyAttach     controller.PropertyAttachmentController
+	taskComment        controller.TaskCommentController
+	notification       controller.NotificationController
+	taskChecklistItem  controller.TaskChecklistItemController
+	taskDependency     controller.TaskDependencyController
+	timeEntry          controller.TimeEntryController
+	vendorQuote        controller.VendorQuoteController
+	workOrder          controller.WorkOrderController
+	vendorInvoice      controller.VendorInvoiceController
+	vendorRating       controller.VendorRatingController
+	vendorDocument     controller.VendorDocumentController
+	maintenanceBudget  controller.MaintenanceBudgetController
+	tenantPortal       controller.TenantPortalController
+	lease              controller.LeaseController
+	ledgerEntry        controller.LedgerEntryController
+	ownerStatement     controller.OwnerStatementController
+	commission         controller.CommissionController
+	pipeline           controller.PipelineController
+	exchangeRate       controller.ExchangeRateController
+	tax                controller.TaxController
+	unit               controller.UnitController
 }
 
 func NewApi(user controller.UserController,
@@ -42,8 +62,28 @@ func NewApi(user controller.UserController,
 	workType controller.WorkTypeController,
 	vendor controller.VendorController,
 	propAttach controller.PropertyAttachmentController,
+	taskComment controller.TaskCommentController,
+	notification controller.NotificationController,
+	taskChecklistItem controller.TaskChecklistItemController,
+	taskDependency controller.TaskDependencyController,
+	timeEntry controller.TimeEntryController,
+	vendorQuote controller.VendorQuoteController,
+	workOrder controller.WorkOrderController,
+	vendorInvoice controller.VendorInvoiceController,
+	vendorRating controller.VendorRatingController,
+	vendorDocument controller.VendorDocumentController,
+	maintenanceBudget controller.MaintenanceBudgetController,
+	tenantPortal controller.TenantPortalController,
+	lease controller.LeaseController,
+	ledgerEntry controller.LedgerEntryController,
+	ownerStatement controller.OwnerStatementController,
+	commission controller.CommissionController,
+	pipeline controller.PipelineController,
+	exchangeRate controller.ExchangeRateController,
+	tax controller.TaxController,
+	unit controller.UnitController,
 ) Api {
-	return &api{user, property, feature, propertyLog, contact, task, taskLog, trans, maintenance, workType, vendor, propAttach}
+	return &api{user, property, feature, propertyLog, contact, task, taskLog, trans, maintenance, workType, vendor, propAttach, taskComment, notification, taskChecklistItem, taskDependency, timeEntry, vendorQuote, workOrder, vendorInvoice, vendorRating, vendorDocument, maintenanceBudget, tenantPortal, lease, ledgerEntry, ownerStatement, commission, pipeline, exchangeRate, tax, unit}
 }
 
 func (a api) Routes() http.Handler {
@@ -64,6 +104,10 @@ func (a api) Routes() http.Handler {
 		// Create new user
 		mux.Post("/api/users", a.user.Create)
 
+		// Tenant portal (authenticated by property portal token)
+		mux.Post("/portal/{propertyToken}/maintenance", a.tenantPortal.SubmitMaintenance)
+		mux.Get("/portal/{propertyToken}/maintenance/{code}", a.tenantPortal.MaintenanceStatus)
+
 		// Private routes
 		mux.Group(func(mux chi.Router) {
 			mux.Use(auth.AuthenticateJWT)
@@ -80,6 +124,19 @@ func (a api) Routes() http.Handler {
 			mux.Get("/api/me", a.user.GetMyUserDetails)
 			mux.Post("/api/me", controller.HealthCheck)
 			mux.Put("/api/me", a.user.UpdateMyProfile)
+			// My notifications
+			mux.Get("/api/me/notifications", a.notification.FindAll)
+			mux.Get("/api/me/notifications/{id}", a.notification.Find)
+			mux.Put("/api/me/notifications/{id}", a.notification.Update)
+			mux.Delete("/api/me/notifications/{id}", a.notification.Delete)
+			mux.Get("/api/me/notification-preferences", a.notification.FindPreferences)
+			mux.Put("/api/me/notification-preferences", a.notification.UpdatePreference)
+			// My tasks
+			mux.Get("/api/me/tasks", a.task.FindMyTasks)
+			// My timer
+			mux.Get("/api/me/timer", a.timeEntry.FindRunningTimer)
+			mux.Post("/api/me/timer/start", a.timeEntry.StartTimer)
+			mux.Post("/api/me/timer/stop", a.timeEntry.StopTimer)
 
 			// properties
 			mux.Post("/api/properties", a.property.Create)
@@ -87,6 +144,15 @@ func (a api) Routes() http.Handler {
 			mux.Get("/api/properties/{id}", a.property.Find)
 			mux.Put("/api/properties/{id}", a.property.Update)
 			mux.Delete("/api/properties/{id}", a.property.Delete)
+			mux.Post("/api/properties/portal-token/{id}", a.tenantPortal.RotatePortalToken)
+			mux.Get("/api/properties/occupancy/{id}", a.unit.Occupancy)
+
+			// units
+			mux.Post("/api/units", a.unit.Create)
+			mux.Get("/api/units", a.unit.FindAll)
+			mux.Get("/api/units/{id}", a.unit.Find)
+			mux.Put("/api/units/{id}", a.unit.Update)
+			mux.Delete("/api/units/{id}", a.unit.Delete)
 
 			// Property Attachments
 			mux.Post("/api/property-attachments", a.propertyAttach.Create)
@@ -123,9 +189,14 @@ func (a api) Routes() http.Handler {
 			// Tasks
 			mux.Post("/api/tasks", a.task.Create)
 			mux.Get("/api/tasks", a.task.FindAll)
+			mux.Get("/api/tasks/board", a.task.Board)
+			mux.Put("/api/tasks/board/{id}", a.task.Move)
 			mux.Get("/api/tasks/{id}", a.task.Find)
 			mux.Put("/api/tasks/{id}", a.task.Update)
 			mux.Delete("/api/tasks/{id}", a.task.Delete)
+			// Tasks created together with their transaction or maintenance request
+			mux.Post("/api/tasks/with-transaction", a.transaction.CreateWithTask)
+			mux.Post("/api/tasks/with-maintenance", a.maintenanceRequest.CreateWithTask)
 
 			// Task Logs
 			mux.Post("/api/task-logs", a.taskLog.Create)
@@ -134,6 +205,38 @@ func (a api) Routes() http.Handler {
 			mux.Put("/api/task-logs/{id}", a.taskLog.Update)
 			mux.Delete("/api/task-logs/{id}", a.taskLog.Delete)
 
+			// Task Checklist Items
+			mux.Post("/api/task-checklist-items", a.taskChecklistItem.Create)
+			mux.Get("/api/task-checklist-items", a.taskChecklistItem.FindAll)
+			mux.Get("/api/task-checklist-items/{id}", a.taskChecklistItem.Find)
+			mux.Put("/api/task-checklist-items/{id}", a.taskChecklistItem.Update)
+			mux.Delete("/api/task-checklist-items/{id}", a.taskChecklistItem.Delete)
+
+			// Task Dependencies
+			mux.Post("/api/task-dependencies", a.taskDependency.Create)
+			mux.Get("/api/task-dependencies", a.taskDependency.FindAll)
+			mux.Get("/api/task-dependencies/graph", a.taskDependency.Graph)
+			mux.Get("/api/task-dependencies/{id}", a.taskDependency.Find)
+			mux.Delete("/api/task-dependencies/{id}", a.taskDependency.Delete)
+
+			// Time Entries
+			mux.Post("/api/time-entries", a.timeEntry.Create)
+			mux.Get("/api/time-entries", a.timeEntry.FindAll)
+			mux.Get("/api/time-entries/rollup", a.timeEntry.Rollup)
+			mux.Get("/api/time-entries/{id}", a.timeEntry.Find)
+			mux.Put("/api/time-entries/{id}", a.timeEntry.Update)
+			mux.Delete("/api/time-entries/{id}", a.timeEntry.Delete)
+
+			// Task Comments
+			mux.Post("/api/task-comments", a.taskComment.Create)
+			mux.Get("/api/task-comments", a.taskComment.FindAll)
+			mux.Get("/api/task-comments/{id}", a.taskComment.Find)
+			mux.Put("/api/task-comments/{id}", a.taskComment.Update)
+			mux.Delete("/api/task-comments/{id}", a.taskComment.Delete)
+
+			// Notifications that failed delivery
+			mux.Get("/api/notification-dead-letters", a.notification.FindAllDeadLetters)
+
 			// Transactions
 			mux.Post("/api/transactions", a.transaction.Create)
 			mux.Get("/api/transactions", a.transaction.FindAll)
@@ -144,6 +247,7 @@ func (a api) Routes() http.Handler {
 			// Maintenance requests
 			mux.Post("/api/maintenance", a.maintenanceRequest.Create)
 			mux.Get("/api/maintenance", a.maintenanceRequest.FindAll)
+			mux.Get("/api/maintenance/{id}/suggested-vendors", a.maintenanceRequest.SuggestedVendors)
 			mux.Get("/api/maintenance/{id}", a.maintenanceRequest.Find)
 			mux.Put("/api/maintenance/{id}", a.maintenanceRequest.Update)
 			mux.Delete("/api/maintenance/{id}", a.maintenanceRequest.Delete)
@@ -161,6 +265,125 @@ func (a api) Routes() http.Handler {
 			mux.Get("/api/vendors/{id}", a.vendor.Find)
 			mux.Put("/api/vendors/{id}", a.vendor.Update)
 			mux.Delete("/api/vendors/{id}", a.vendor.Delete)
+
+			// Vendor Quotes
+			mux.Post("/api/vendor-quotes", a.vendorQuote.Invite)
+			mux.Get("/api/vendor-quotes", a.vendorQuote.FindAll)
+			mux.Get("/api/vendor-quotes/compare", a.vendorQuote.Compare)
+			mux.Post("/api/vendor-quotes/accept/{id}", a.vendorQuote.Accept)
+			mux.Get("/api/vendor-quotes/{id}", a.vendorQuote.Find)
+			mux.Put("/api/vendor-quotes/{id}", a.vendorQuote.Update)
+			mux.Delete("/api/vendor-quotes/{id}", a.vendorQuote.Delete)
+
+			// Work Orders
+			mux.Post("/api/work-orders", a.workOrder.Create)
+			mux.Get("/api/work-orders", a.workOrder.FindAll)
+			mux.Put("/api/work-orders/status/{id}", a.workOrder.UpdateStatus)
+			mux.Get("/api/work-orders/document/{id}", a.workOrder.Document)
+			mux.Get("/api/work-orders/{id}", a.workOrder.Find)
+			mux.Put("/api/work-orders/{id}", a.workOrder.Update)
+			mux.Delete("/api/work-orders/{id}", a.workOrder.Delete)
+
+			// Vendor Invoices
+			mux.Post("/api/vendor-invoices", a.vendorInvoice.Create)
+			mux.Get("/api/vendor-invoices", a.vendorInvoice.FindAll)
+			mux.Post("/api/vendor-invoices/payments/{id}", a.vendorInvoice.RecordPayment)
+			mux.Get("/api/vendor-invoices/{id}", a.vendorInvoice.Find)
+			mux.Put("/api/vendor-invoices/{id}", a.vendorInvoice.Update)
+			mux.Delete("/api/vendor-invoices/{id}", a.vendorInvoice.Delete)
+			// Payables aging
+			mux.Get("/api/payables", a.vendorInvoice.Payables)
+
+			// Vendor ratings
+			mux.Post("/api/vendor-ratings", a.vendorRating.Create)
+			mux.Get("/api/vendor-ratings", a.vendorRating.FindAll)
+			mux.Get("/api/vendor-ratings/{id}", a.vendorRating.Find)
+			mux.Put("/api/vendor-ratings/{id}", a.vendorRating.Update)
+			mux.Delete("/api/vendor-ratings/{id}", a.vendorRating.Delete)
+			mux.Get("/api/vendors/{id}/scorecard", a.vendorRating.Scorecard)
+
+			// Vendor documents
+			mux.Post("/api/vendor-documents", a.vendorDocument.Create)
+			mux.Get("/api/vendor-documents", a.vendorDocument.FindAll)
+			mux.Post("/api/vendor-documents/file/{id}", a.vendorDocument.Upload)
+			mux.Get("/api/vendor-documents/file/{id}", a.vendorDocument.Download)
+			mux.Get("/api/vendor-documents/{id}", a.vendorDocument.Find)
+			mux.Put("/api/vendor-documents/{id}", a.vendorDocument.Update)
+			mux.Delete("/api/vendor-documents/{id}", a.vendorDocument.Delete)
+			mux.Get("/api/vendors/compliance/{id}", a.vendorDocument.Compliance)
+
+			// Maintenance budgets
+			mux.Post("/api/maintenance-budgets", a.maintenanceBudget.Create)
+			mux.Get("/api/maintenance-budgets", a.maintenanceBudget.FindAll)
+			mux.Get("/api/maintenance-budgets/report/{id}", a.maintenanceBudget.Report)
+			mux.Get("/api/maintenance-budgets/{id}", a.maintenanceBudget.Find)
+			mux.Put("/api/maintenance-budgets/{id}", a.maintenanceBudget.Update)
+			mux.Delete("/api/maintenance-budgets/{id}", a.maintenanceBudget.Delete)
+
+			// Leases
+			mux.Post("/api/leases", a.lease.Create)
+			mux.Get("/api/leases", a.lease.FindAll)
+			mux.Post("/api/leases/renew/{id}", a.lease.Renew)
+			mux.Get("/api/leases/{id}", a.lease.Find)
+			mux.Put("/api/leases/{id}", a.lease.Update)
+			mux.Delete("/api/leases/{id}", a.lease.Delete)
+
+			// Lease ledger
+			mux.Post("/api/ledger-entries", a.ledgerEntry.Create)
+			mux.Get("/api/ledger-entries", a.ledgerEntry.FindAll)
+			mux.Get("/api/ledger-entries/{id}", a.ledgerEntry.Find)
+			mux.Delete("/api/ledger-entries/{id}", a.ledgerEntry.Delete)
+			mux.Get("/api/leases/{id}/statement", a.ledgerEntry.Statement)
+			mux.Get("/api/leases/arrears", a.ledgerEntry.Arrears)
+
+			// Owner statements
+			mux.Post("/api/owner-statements", a.ownerStatement.Generate)
+			mux.Get("/api/owner-statements", a.ownerStatement.FindAll)
+			mux.Post("/api/owner-statements/finalise/{id}", a.ownerStatement.Finalise)
+			mux.Get("/api/owner-statements/pdf/{id}", a.ownerStatement.PDF)
+			mux.Get("/api/owner-statements/csv/{id}", a.ownerStatement.CSV)
+			mux.Get("/api/owner-statements/{id}", a.ownerStatement.Find)
+			mux.Put("/api/owner-statements/{id}", a.ownerStatement.Update)
+			mux.Delete("/api/owner-statements/{id}", a.ownerStatement.Delete)
+
+			// Commissions
+			mux.Post("/api/commission-schemes", a.commission.CreateScheme)
+			mux.Get("/api/commission-schemes", a.commission.FindAllSchemes)
+			mux.Get("/api/commission-schemes/{id}", a.commission.FindScheme)
+			mux.Put("/api/commission-schemes/{id}", a.commission.UpdateScheme)
+			mux.Delete("/api/commission-schemes/{id}", a.commission.DeleteScheme)
+			mux.Put("/api/transactions/commission-splits/{id}", a.commission.SetSplits)
+			mux.Get("/api/commissions/report", a.commission.Report)
+
+			// Transaction pipeline
+			mux.Post("/api/pipeline-stages", a.pipeline.CreateStage)
+			mux.Get("/api/pipeline-stages", a.pipeline.FindAllStages)
+			mux.Get("/api/pipeline-stages/{id}", a.pipeline.FindStage)
+			mux.Put("/api/pipeline-stages/{id}", a.pipeline.UpdateStage)
+			mux.Delete("/api/pipeline-stages/{id}", a.pipeline.DeleteStage)
+			mux.Put("/api/transactions/stage/{id}", a.pipeline.Move)
+			mux.Get("/api/transactions/pipeline", a.pipeline.Summary)
+			mux.Post("/api/transaction-milestones", a.pipeline.CreateMilestone)
+			mux.Get("/api/transaction-milestones", a.pipeline.FindAllMilestones)
+			mux.Put("/api/transaction-milestones/{id}", a.pipeline.UpdateMilestone)
+			mux.Delete("/api/transaction-milestones/{id}", a.pipeline.DeleteMilestone)
+
+			// Exchange rates
+			mux.Post("/api/exchange-rates", a.exchangeRate.Create)
+			mux.Get("/api/exchange-rates", a.exchangeRate.FindAll)
+			mux.Get("/api/exchange-rates/{id}", a.exchangeRate.Find)
+			mux.Put("/api/exchange-rates/{id}", a.exchangeRate.Update)
+			mux.Delete("/api/exchange-rates/{id}", a.exchangeRate.Delete)
+			mux.Post("/api/exchange-rates/import", a.exchangeRate.Import)
+			mux.Get("/api/exchange-rates/convert", a.exchangeRate.Convert)
+
+			// Tax
+			mux.Post("/api/tax-rules", a.tax.CreateRule)
+			mux.Get("/api/tax-rules", a.tax.FindAllRules)
+			mux.Get("/api/tax-rules/{id}", a.tax.FindRule)
+			mux.Put("/api/tax-rules/{id}", a.tax.UpdateRule)
+			mux.Delete("/api/tax-rules/{id}", a.tax.DeleteRule)
+			mux.Get("/api/tax/summary", a.tax.Summary)
 		})
 
 	})
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a scheme is missing the rate, fee or tiers its method needs
+var ErrInvalidCommissionScheme = errors.New("percentage schemes need a rate, flat schemes a fee and tiered schemes ascending tiers ending with an open top band")
+
+// Returned when deleting a scheme that transactions are calculated with
+var ErrCommissionSchemeInUse = errors.New("commission scheme is used by transactions")
+
+// Returned when agent shares don't total 100 percent or an agent is listed twice
+var ErrInvalidCommissionSplit = errors.New("agent shares must be listed once per agent and total 100 percent")
+
+type CommissionService interface {
+	FindAllSchemes(int, int, string) (*[]db.CommissionScheme, error)
+	FindSchemeById(int) (*db.CommissionScheme, error)
+	CreateScheme(*models.CreateCommissionScheme) (*db.CommissionScheme, error)
+	UpdateScheme(int, *models.UpdateCommissionScheme) (*db.CommissionScheme, error)
+	DeleteScheme(int) error
+	// Find the scheme new transactions of a type use by default
+	DefaultScheme(string) (*db.CommissionScheme, error)
+	// Sets the agents sharing the agency's commission on a transaction
+	SetSplits(int, *models.SetCommissionSplits) (*db.Transaction, error)
+	// Calculates and saves the commission of a completed transaction and its agent splits
+	Calculate(*db.Transaction) error
+	// Commission per agent and month of transactions completed over a period
+	Report(time.Time, time.Time, string) (*models.CommissionReport, error)
+}
+
+type commissionService struct {
+	repo         repository.CommissionRepository
+	transactions repository.TransactionRepository
+	users        repository.UserRepository
+	rates        ExchangeRateService
+	taxes        TaxService
+}
+
+func NewCommissionService(repo repository.CommissionRepository, transactions repository.TransactionRepository, users repository.UserRepository, rates ExchangeRateService, taxes TaxService) CommissionService {
+	return &commissionService{repo, transactions, users, rates, taxes}
+}
+
+// Creates a commission scheme, making it the only default for its transaction type
+func (s *commissionService) CreateScheme(scheme *models.CreateCommissionScheme) (*db.CommissionScheme, error) {
+	schemeToCreate := db.CommissionScheme{
+		Name:          scheme.Name,
+		Method:        scheme.Method,
+		Rate:          scheme.Rate,
+		FlatFee:       scheme.FlatFee,
+		CoAgencySplit: scheme.CoAgencySplit,
+		DefaultFor:    scheme.DefaultFor,
+		Tiers:         commissionTiers(scheme.Tiers),
+	}
+	if !validCommissionScheme(&schemeToCreate) {
+		return nil, ErrInvalidCommissionScheme
+	}
+
+	createdScheme, err := s.repo.CreateScheme(&schemeToCreate)
+	if err != nil {
+		return nil, err
+	}
+	if createdScheme.DefaultFor != "" {
+		err = s.repo.ClearDefault(createdScheme.DefaultFor, createdScheme.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindSchemeById(int(createdScheme.ID))
+}
+
+// Find a list of commission schemes
+func (s *commissionService) FindAllSchemes(limit int, offset int, order string) (*[]db.CommissionScheme, error) {
+	schemes, err := s.repo.FindAllSchemes(limit, offset, order)
+	if err != nil {
+		return nil, err
+	}
+	return schemes, nil
+}
+
+// Find commission scheme in database by ID
+func (s *commissionService) FindSchemeById(id int) (*db.CommissionScheme, error) {
+	// Find by id
+	scheme, err := s.repo.FindSchemeById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return scheme, nil
+}
+
+// Find the scheme new transactions of a type use by default
+func (s *commissionService) DefaultScheme(transactionType string) (*db.CommissionScheme, error) {
+	return s.repo.FindDefaultScheme(transactionType)
+}
+
+// Delete a commission scheme that no transactions are calculated with
+func (s *commissionService) DeleteScheme(id int) error {
+	count, err := s.repo.CountSchemeTransactions(uint(id))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCommissionSchemeInUse
+	}
+	err = s.repo.DeleteScheme(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting commission scheme: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates a commission scheme, replacing its tiers if provided. Completed transactions keep their commission
+func (s *commissionService) UpdateScheme(id int, scheme *models.UpdateCommissionScheme) (*db.CommissionScheme, error) {
+	foundScheme, err := s.repo.FindSchemeById(id)
+	if err != nil {
+		return nil, err
+	}
+	schemeToUpdate := db.CommissionScheme{
+		Name:          scheme.Name,
+		Method:        scheme.Method,
+		Rate:          scheme.Rate,
+		FlatFee:       scheme.FlatFee,
+		CoAgencySplit: scheme.CoAgencySplit,
+		DefaultFor:    scheme.DefaultFor,
+	}
+
+	// Validate the scheme as it will be after the update
+	updated := *foundScheme
+	if scheme.Method != "" {
+		updated.Method = scheme.Method
+	}
+	if scheme.Rate != 0 {
+		updated.Rate = scheme.Rate
+	}
+	if !scheme.FlatFee.IsZero() {
+		updated.FlatFee = scheme.FlatFee
+	}
+	if scheme.Tiers != nil {
+		updated.Tiers = commissionTiers(scheme.Tiers)
+	}
+	if !validCommissionScheme(&updated) {
+		return nil, ErrInvalidCommissionScheme
+	}
+
+	_, err = s.repo.UpdateScheme(id, &schemeToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if scheme.Tiers != nil {
+		err = s.repo.ReplaceTiers(uint(id), updated.Tiers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if scheme.DefaultFor != "" {
+		err = s.repo.ClearDefault(scheme.DefaultFor, uint(id))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindSchemeById(id)
+}
+
+// Sets the agents sharing the agency's commission on a transaction. Split amounts are calculated if it's completed
+func (s *commissionService) SetSplits(transactionId int, request *models.SetCommissionSplits) (*db.Transaction, error) {
+	transaction, err := s.transactions.FindById(transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	splits := []db.CommissionSplit{}
+	agents := map[uint]bool{}
+	total := 0.0
+	for _, share := range request.Splits {
+		if agents[share.User.ID] || share.Share <= 0 {
+			return nil, ErrInvalidCommissionSplit
+		}
+		agents[share.User.ID] = true
+		// Ensure agent exists
+		_, err := s.users.FindById(int(share.User.ID))
+		if err != nil {
+			return nil, fmt.Errorf("agent not found: %w", err)
+		}
+		total += share.Share
+		splits = append(splits, db.CommissionSplit{Share: share.Share, UserID: share.User.ID})
+	}
+	if math.Abs(total-100) > 0.001 {
+		return nil, ErrInvalidCommissionSplit
+	}
+
+	err = s.repo.ReplaceSplits(transaction.ID, splits)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err = s.transactions.FindById(transactionId)
+	if err != nil {
+		return nil, err
+	}
+	if !transaction.TransactionCompletion.IsZero() {
+		err = s.Calculate(transaction)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.transactions.FindById(transactionId)
+}
+
+// Calculates and saves the commission of a completed transaction and its agent splits. Without a scheme the fee as entered is split
+func (s *commissionService) Calculate(transaction *db.Transaction) error {
+	coAgencySplit := 0.0
+	if transaction.CommissionSchemeID != nil {
+		scheme, err := s.repo.FindSchemeById(int(*transaction.CommissionSchemeID))
+		if err != nil {
+			return fmt.Errorf("commission scheme not found: %w", err)
+		}
+		transaction.Fee, err = commissionFor(scheme, transaction.TransactionValue)
+		if err != nil {
+			return err
+		}
+		coAgencySplit = scheme.CoAgencySplit
+	}
+
+	// Transactions through another agency share the commission with it
+	transaction.CoAgencyFee = db.Money{Currency: transaction.Fee.Currency}
+	if transaction.Agency == "Other" {
+		transaction.CoAgencyFee = transaction.Fee.Percent(coAgencySplit)
+	}
+	transaction.OwnAgencyFee = transaction.Fee.Sub(transaction.CoAgencyFee)
+
+	// Agents share the agency's commission, with any rounding left on the last agent
+	allocated := db.Money{Currency: transaction.Fee.Currency}
+	for i := range transaction.CommissionSplits {
+		split := &transaction.CommissionSplits[i]
+		split.Amount = transaction.OwnAgencyFee.Percent(split.Share)
+		if i == len(transaction.CommissionSplits)-1 {
+			split.Amount = transaction.OwnAgencyFee.Sub(allocated)
+		}
+		allocated = allocated.Add(split.Amount)
+	}
+
+	// Tax on the agency's share of the commission
+	var err error
+	transaction.TaxLines, err = s.taxes.Lines("Commission", transaction.OwnAgencyFee, transaction.TransactionCompletion)
+	if err != nil {
+		return err
+	}
+	return s.repo.SaveCommission(transaction)
+}
+
+// Commission per agent and month of transactions completed from (inclusive) to (exclusive). Amounts are
+// converted into the reporting currency at the rates effective on each transaction's completion
+func (s *commissionService) Report(from time.Time, to time.Time, currency string) (*models.CommissionReport, error) {
+	transactions, err := s.repo.FindCompletedTransactions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	zero := db.Money{Currency: currency}
+	report := &models.CommissionReport{From: from, To: to, Currency: currency, TotalFees: zero, OwnAgencyFees: zero,
+		CoAgencyFees: zero, Unallocated: zero, Agents: []models.AgentCommission{}}
+	// Index of each agent's month in the report
+	rows := map[string]int{}
+	for _, transaction := range *transactions {
+		report.Transactions++
+		// The agency and agent shares are in the currency of the commission
+		completed := transaction.TransactionCompletion
+		amounts := []*db.Money{&transaction.Fee, &transaction.OwnAgencyFee, &transaction.CoAgencyFee}
+		for i := range transaction.CommissionSplits {
+			amounts = append(amounts, &transaction.CommissionSplits[i].Amount)
+		}
+		err = s.rates.ConvertAll(currency, completed, amounts...)
+		if err != nil {
+			return nil, err
+		}
+		report.TotalFees = report.TotalFees.Add(transaction.Fee)
+		report.OwnAgencyFees = report.OwnAgencyFees.Add(transaction.OwnAgencyFee)
+		report.CoAgencyFees = report.CoAgencyFees.Add(transaction.CoAgencyFee)
+		if len(transaction.CommissionSplits) == 0 {
+			report.Unallocated = report.Unallocated.Add(transaction.OwnAgencyFee)
+			continue
+		}
+		period := completed.Format("2006-01")
+		for _, split := range transaction.CommissionSplits {
+			key := fmt.Sprintf("%d-%s", split.UserID, period)
+			i, found := rows[key]
+			if !found {
+				i = len(report.Agents)
+				rows[key] = i
+				report.Agents = append(report.Agents, models.AgentCommission{UserID: split.UserID, Name: split.User.Name, Period: period})
+			}
+			report.Agents[i].Transactions++
+			report.Agents[i].Amount = report.Agents[i].Amount.Add(split.Amount)
+		}
+	}
+
+	// Order by month then highest earning agent
+	sort.SliceStable(report.Agents, func(i, j int) bool {
+		if report.Agents[i].Period != report.Agents[j].Period {
+			return report.Agents[i].Period < report.Agents[j].Period
+		}
+		return report.Agents[i].Amount.Amount > report.Agents[j].Amount.Amount
+	})
+	return report, nil
+}
+
+// Commission on a transaction value under a scheme, in the currency of the value. Flat fees and
+// tier bands must be in the same currency
+func commissionFor(scheme *db.CommissionScheme, value db.Money) (db.Money, error) {
+	switch scheme.Method {
+	case "Flat":
+		if !scheme.FlatFee.SameCurrency(value) {
+			return db.Money{}, db.ErrCurrencyMismatch
+		}
+		return scheme.FlatFee, nil
+	case "Tiered":
+		// Each band of the value is charged at its own rate
+		commission, lower := 0.0, int64(0)
+		for _, tier := range scheme.Tiers {
+			if !tier.UpTo.SameCurrency(value) {
+				return db.Money{}, db.ErrCurrencyMismatch
+			}
+			upper := tier.UpTo.Amount
+			if upper == 0 || upper > value.Amount {
+				upper = value.Amount
+			}
+			if upper > lower {
+				commission += float64(upper-lower) * tier.Rate / 100
+				lower = upper
+			}
+			if tier.UpTo.IsZero() || tier.UpTo.Amount >= value.Amount {
+				break
+			}
+		}
+		return db.Money{Amount: int64(math.Round(commission)), Currency: value.Currency}, nil
+	default:
+		return value.Percent(scheme.Rate), nil
+	}
+}
+
+// Checks a scheme has what its method needs. Tiers must ascend and end with an open top band
+func validCommissionScheme(scheme *db.CommissionScheme) bool {
+	switch scheme.Method {
+	case "Percentage":
+		return scheme.Rate > 0
+	case "Flat":
+		return scheme.FlatFee.Amount > 0
+	case "Tiered":
+		if len(scheme.Tiers) == 0 {
+			return false
+		}
+		previous := db.Money{}
+		for i, tier := range scheme.Tiers {
+			last := i == len(scheme.Tiers)-1
+			if last != tier.UpTo.IsZero() || (!last && (tier.UpTo.Amount <= previous.Amount || !tier.UpTo.SameCurrency(previous))) {
+				return false
+			}
+			previous = tier.UpTo
+		}
+		return true
+	}
+	return false
+}
+
+// Converts tiers received into scheme tiers
+func commissionTiers(tiers []models.CommissionTier) []db.CommissionTier {
+	schemeTiers := []db.CommissionTier{}
+	for _, tier := range tiers {
+		schemeTiers = append(schemeTiers, db.CommissionTier{UpTo: tier.UpTo, Rate: tier.Rate})
+	}
+	return schemeTiers
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when there's no rate to convert between two currencies on a date
+var ErrNoExchangeRate = errors.New("no exchange rate between these currencies")
+
+// Returned when a currency pair already has a rate effective on a date
+var ErrExchangeRateExists = errors.New("currency pair already has a rate for this date")
+
+// Returned when an exchange rate converts a currency into itself
+var ErrSameCurrency = errors.New("exchange rates must be between two different currencies")
+
+// Returned when a report is requested in a currency amounts can't be recorded in
+var ErrUnsupportedCurrency = errors.New("currency must be one of IDR, USD or AUD")
+
+type ExchangeRateService interface {
+	FindAll(int, int, string, string, string) (*[]db.ExchangeRate, error)
+	FindById(int) (*db.ExchangeRate, error)
+	Create(*models.CreateExchangeRate) (*db.ExchangeRate, error)
+	Update(int, *models.UpdateExchangeRate) (*db.ExchangeRate, error)
+	Delete(int) error
+	// Creates or replaces rates from CSV rows of effective date, base currency, quote currency and rate
+	Import(io.Reader) (*models.ExchangeRateImport, error)
+	// Rate to convert a base currency into a quote currency on a date
+	Rate(string, string, time.Time) (float64, error)
+	// Converts an amount into a currency at the rate effective on a date
+	Convert(db.Money, string, time.Time) (db.Money, error)
+	// Converts amounts in place into a currency at the rates effective on a date
+	ConvertAll(string, time.Time, ...*db.Money) error
+	// Sum of amounts converted into a currency at the rates effective on a date
+	Total(string, time.Time, ...db.Money) (db.Money, error)
+	// Currency a user's report is shown in: the requested currency, else the user's reporting currency, else the default currency
+	ReportingCurrency(int, string) (string, error)
+}
+
+type exchangeRateService struct {
+	repo  repository.ExchangeRateRepository
+	users repository.UserRepository
+}
+
+func NewExchangeRateService(repo repository.ExchangeRateRepository, users repository.UserRepository) ExchangeRateService {
+	return &exchangeRateService{repo, users}
+}
+
+// Creates an exchange rate entered manually
+func (s *exchangeRateService) Create(rate *models.CreateExchangeRate) (*db.ExchangeRate, error) {
+	if rate.BaseCurrency == rate.QuoteCurrency {
+		return nil, ErrSameCurrency
+	}
+	effectiveDate := rateDate(rate.EffectiveDate)
+	if _, err := s.repo.FindByDate(rate.BaseCurrency, rate.QuoteCurrency, effectiveDate); err == nil {
+		return nil, ErrExchangeRateExists
+	}
+	rateToCreate := db.ExchangeRate{
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		EffectiveDate: effectiveDate,
+		Source:        "Manual",
+	}
+	return s.repo.Create(&rateToCreate)
+}
+
+// Find a list of exchange rates. Filters by base and quote currency if provided
+func (s *exchangeRateService) FindAll(limit int, offset int, order string, base string, quote string) (*[]db.ExchangeRate, error) {
+	rates, err := s.repo.FindAll(limit, offset, order, base, quote)
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Find exchange rate in database by ID
+func (s *exchangeRateService) FindById(id int) (*db.ExchangeRate, error) {
+	// Find by id
+	rate, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return rate, nil
+}
+
+// Delete exchange rate in database
+func (s *exchangeRateService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting exchange rate: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates the rate or effective date of an exchange rate
+func (s *exchangeRateService) Update(id int, rate *models.UpdateExchangeRate) (*db.ExchangeRate, error) {
+	foundRate, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	rateToUpdate := &db.ExchangeRate{Rate: rate.Rate}
+	if !rate.EffectiveDate.IsZero() {
+		rateToUpdate.EffectiveDate = rateDate(rate.EffectiveDate)
+		if existing, err := s.repo.FindByDate(foundRate.BaseCurrency, foundRate.QuoteCurrency, rateToUpdate.EffectiveDate); err == nil && existing.ID != foundRate.ID {
+			return nil, ErrExchangeRateExists
+		}
+	}
+	return s.repo.Update(id, rateToUpdate)
+}
+
+// Creates or replaces rates from CSV rows of effective date (YYYY-MM-DD), base currency, quote currency and rate.
+// A header row naming the columns (effective_date, base_currency, quote_currency, rate) may set their order
+func (s *exchangeRateService) Import(file io.Reader) (*models.ExchangeRateImport, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed reading exchange rates CSV: %w", err)
+	}
+
+	result := models.ExchangeRateImport{Errors: []string{}}
+	rates := []db.ExchangeRate{}
+	// Column positions of the date, base currency, quote currency and rate
+	columns := []int{0, 1, 2, 3}
+	for i, row := range rows {
+		if i == 0 && isRateHeader(row) {
+			columns, err = rateColumns(row)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rate, err := parseRateRow(row, columns)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %s", i+1, err))
+			continue
+		}
+		rates = append(rates, *rate)
+	}
+
+	// Valid rows are recorded together, replacing rates already recorded for the pair and date
+	result.Created, result.Updated, err = s.repo.Import(rates)
+	if err != nil {
+		return nil, fmt.Errorf("failed importing exchange rates: %w", err)
+	}
+	return &result, nil
+}
+
+// Rate to convert a base currency into a quote currency on a date. Uses the latest rate of the pair (or its inverse),
+// otherwise converts through the default currency
+func (s *exchangeRateService) Rate(base string, quote string, on time.Time) (float64, error) {
+	if base == quote {
+		return 1, nil
+	}
+	rate, err := s.pairRate(base, quote, on)
+	if err == nil {
+		return rate, nil
+	}
+	if base != db.DefaultCurrency && quote != db.DefaultCurrency {
+		toDefault, err := s.pairRate(base, db.DefaultCurrency, on)
+		if err == nil {
+			fromDefault, err := s.pairRate(db.DefaultCurrency, quote, on)
+			if err == nil {
+				return toDefault * fromDefault, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: %s to %s on %s", ErrNoExchangeRate, base, quote, on.Format("2006-01-02"))
+}
+
+// Converts an amount into a currency at the rate effective on a date
+func (s *exchangeRateService) Convert(amount db.Money, currency string, on time.Time) (db.Money, error) {
+	if currency == "" {
+		currency = db.DefaultCurrency
+	}
+	if amount.IsZero() || amount.Code() == currency {
+		return db.Money{Amount: amount.Amount, Currency: currency}, nil
+	}
+	rate, err := s.Rate(amount.Code(), currency, on)
+	if err != nil {
+		return db.Money{}, err
+	}
+	return db.NewMoney(amount.Float()*rate, currency), nil
+}
+
+// Converts amounts in place into a currency at the rates effective on a date
+func (s *exchangeRateService) ConvertAll(currency string, on time.Time, amounts ...*db.Money) error {
+	for _, amount := range amounts {
+		converted, err := s.Convert(*amount, currency, on)
+		if err != nil {
+			return err
+		}
+		*amount = converted
+	}
+	return nil
+}
+
+// Sum of amounts converted into a currency at the rates effective on a date
+func (s *exchangeRateService) Total(currency string, on time.Time, amounts ...db.Money) (db.Money, error) {
+	total := db.Money{Currency: currency}
+	for _, amount := range amounts {
+		converted, err := s.Convert(amount, currency, on)
+		if err != nil {
+			return db.Money{}, err
+		}
+		total = total.Add(converted)
+	}
+	return total, nil
+}
+
+// Currency a user's report is shown in: the requested currency, else the user's reporting currency, else the default currency
+func (s *exchangeRateService) ReportingCurrency(userId int, requested string) (string, error) {
+	if requested != "" {
+		requested = strings.ToUpper(requested)
+		if !db.IsCurrency(requested) {
+			return "", ErrUnsupportedCurrency
+		}
+		return requested, nil
+	}
+	if user, err := s.users.FindById(userId); err == nil && user.ReportingCurrency != "" {
+		return user.ReportingCurrency, nil
+	}
+	return db.DefaultCurrency, nil
+}
+
+// Latest rate of a currency pair on a date, from the pair's rates or the inverse of the reverse pair's rates
+func (s *exchangeRateService) pairRate(base string, quote string, on time.Time) (float64, error) {
+	on = rateDate(on)
+	direct, directErr := s.repo.FindEffective(base, quote, on)
+	inverse, inverseErr := s.repo.FindEffective(quote, base, on)
+	switch {
+	case directErr == nil && (inverseErr != nil || !inverse.EffectiveDate.After(direct.EffectiveDate)):
+		return direct.Rate, nil
+	case inverseErr == nil && inverse.Rate > 0:
+		return 1 / inverse.Rate, nil
+	}
+	return 0, ErrNoExchangeRate
+}
+
+// Date a rate is effective from, without its time
+func rateDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Checks whether a CSV row names its columns rather than holding a rate
+func isRateHeader(row []string) bool {
+	if len(row) == 0 {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", strings.TrimSpace(row[0]))
+	return err != nil
+}
+
+// Column positions of the date, base currency, quote currency and rate named by a CSV header
+func rateColumns(header []string) ([]int, error) {
+	names := [][]string{
+		{"effective_date", "date"},
+		{"base_currency", "base", "from"},
+		{"quote_currency", "quote", "to"},
+		{"rate"},
+	}
+	columns := []int{-1, -1, -1, -1}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		for j, aliases := range names {
+			for _, alias := range aliases {
+				if column == alias {
+					columns[j] = i
+				}
+			}
+		}
+	}
+	for j, position := range columns {
+		if position < 0 {
+			return nil, fmt.Errorf("exchange rates CSV header is missing a %s column", names[j][0])
+		}
+	}
+	return columns, nil
+}
+
+// Exchange rate of a CSV row
+func parseRateRow(row []string, columns []int) (*db.ExchangeRate, error) {
+	values := make([]string, len(columns))
+	for j, position := range columns {
+		if position >= len(row) {
+			return nil, errors.New("missing columns")
+		}
+		values[j] = strings.TrimSpace(row[position])
+	}
+	date, err := time.Parse("2006-01-02", values[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", values[0])
+	}
+	base, quote := strings.ToUpper(values[1]), strings.ToUpper(values[2])
+	if !db.IsCurrency(base) || !db.IsCurrency(quote) {
+		return nil, ErrUnsupportedCurrency
+	}
+	if base == quote {
+		return nil, ErrSameCurrency
+	}
+	rate, err := strconv.ParseFloat(values[3], 64)
+	if err != nil || rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return nil, fmt.Errorf("invalid rate %q", values[3])
+	}
+	return &db.ExchangeRate{BaseCurrency: base, QuoteCurrency: quote, Rate: rate, EffectiveDate: rateDate(date), Source: "CSV"}, nil
+}
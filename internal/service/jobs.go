@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Runs job in the background every interval until the context is cancelled
+func ScheduleJob(ctx context.Context, name string, interval time.Duration, job func() error) {
+	go func() {
+		// Build ticker for interval
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				fmt.Printf("Stopping scheduled job: %s\n", name)
+				return
+			case <-ticker.C:
+				// Run job and log any failure
+				if err := job(); err != nil {
+					fmt.Printf("Scheduled job (%s) failed: %v\n", name, err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a lease overlaps another lease of the property or unit
+var ErrLeaseOverlap = errors.New("the property or unit is already leased for part of this period")
+
+// Returned when renewing a lease that has already been renewed
+var ErrLeaseAlreadyRenewed = errors.New("lease has already been renewed")
+
+// Returned when a lease doesn't end after it starts
+var ErrInvalidLeaseDates = errors.New("lease end date must be after the start date")
+
+type LeaseService interface {
+	FindAll(int, int, string, int, int) (*[]db.Lease, error)
+	FindById(int) (*db.Lease, error)
+	Create(*models.CreateLease) (*db.Lease, error)
+	Update(int, *models.UpdateLease) (*db.Lease, error)
+	Delete(int) error
+	// Creates a new lease for the following term
+	Renew(int, *models.RenewLease) (*db.Lease, error)
+	// Reminds admins of leases entering their renewal notice period (scheduled daily)
+	ProcessRenewalReminders() error
+}
+
+type leaseService struct {
+	repo         repository.LeaseRepository
+	properties   repository.PropertyRepository
+	units        repository.UnitRepository
+	contacts     repository.ContactRepository
+	transactions repository.TransactionRepository
+	users        repository.UserRepository
+	notification NotificationService
+}
+
+func NewLeaseService(repo repository.LeaseRepository, properties repository.PropertyRepository, units repository.UnitRepository, contacts repository.ContactRepository, transactions repository.TransactionRepository, users repository.UserRepository, notification NotificationService) LeaseService {
+	return &leaseService{repo, properties, units, contacts, transactions, users, notification}
+}
+
+// Creates a lease and its rent schedule
+func (s *leaseService) Create(lease *models.CreateLease) (*db.Lease, error) {
+	if !lease.EndDate.After(lease.StartDate) {
+		return nil, ErrInvalidLeaseDates
+	}
+	if !inRentCurrency(lease.RentAmount, lease.Deposit, lease.LateFee) {
+		return nil, db.ErrCurrencyMismatch
+	}
+	// Ensure property exists
+	property, err := s.properties.FindById(int(lease.Property.ID))
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	unitId, err := findPropertyUnit(s.units, lease.Unit, property.ID)
+	if err != nil {
+		return nil, err
+	}
+	tenants, err := s.findTenants(lease.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	leaseToCreate := db.Lease{
+		StartDate:         lease.StartDate,
+		EndDate:           lease.EndDate,
+		RentAmount:        lease.RentAmount,
+		Frequency:         lease.Frequency,
+		Deposit:           lease.Deposit,
+		RenewalOption:     lease.RenewalOption,
+		RenewalNoticeDays: lease.RenewalNoticeDays,
+		LateFee:           lease.LateFee,
+		LateFeeGraceDays:  lease.LateFeeGraceDays,
+		Notes:             lease.Notes,
+		PropertyID:        property.ID,
+		UnitID:            unitId,
+		Tenants:           tenants,
+	}
+	// Ensure transaction exists if provided
+	if lease.Transaction.ID != 0 {
+		transaction, err := s.transactions.FindById(int(lease.Transaction.ID))
+		if err != nil {
+			return nil, fmt.Errorf("transaction not found: %w", err)
+		}
+		leaseToCreate.TransactionID = &transaction.ID
+	}
+
+	err = s.checkOverlap(0, property.ID, unitId, leaseToCreate.StartDate, leaseToCreate.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create lease in database
+	createdLease, err := s.repo.Create(&leaseToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating lease: %w", err)
+	}
+	err = s.repo.ReplaceSchedule(createdLease.ID, buildRentSchedule(createdLease, time.Time{}))
+	if err != nil {
+		return nil, err
+	}
+	return s.FindById(int(createdLease.ID))
+}
+
+// Find a list of leases
+func (s *leaseService) FindAll(limit int, offset int, order string, propertyId int, tenantId int) (*[]db.Lease, error) {
+	leases, err := s.repo.FindAll(limit, offset, order, propertyId, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range *leases {
+		(*leases)[i].Status = leaseStatus(&(*leases)[i], now)
+	}
+	return leases, nil
+}
+
+// Find lease in database by ID
+func (s *leaseService) FindById(id int) (*db.Lease, error) {
+	// Find by id
+	lease, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	lease.Status = leaseStatus(lease, time.Now())
+	return lease, nil
+}
+
+// Delete lease in database
+func (s *leaseService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting lease: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates lease in database. Changing the dates, rent or frequency regenerates the rent schedule
+// after any periods that have already been charged to the ledger
+func (s *leaseService) Update(id int, lease *models.UpdateLease) (*db.Lease, error) {
+	foundLease, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	// Check the resulting period
+	start, end := foundLease.StartDate, foundLease.EndDate
+	if !lease.StartDate.IsZero() {
+		start = lease.StartDate
+	}
+	if !lease.EndDate.IsZero() {
+		end = lease.EndDate
+	}
+	if !end.After(start) {
+		return nil, ErrInvalidLeaseDates
+	}
+	// The ledger is kept in the currency of the rent so it can't change
+	if !inRentCurrency(foundLease.RentAmount, lease.RentAmount, lease.Deposit, lease.LateFee) {
+		return nil, db.ErrCurrencyMismatch
+	}
+	err = s.checkOverlap(foundLease.ID, foundLease.PropertyID, foundLease.UnitID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new lease from DTO
+	leaseToUpdate := &db.Lease{
+		StartDate:         lease.StartDate,
+		EndDate:           lease.EndDate,
+		RentAmount:        lease.RentAmount,
+		Frequency:         lease.Frequency,
+		Deposit:           lease.Deposit,
+		RenewalOption:     lease.RenewalOption,
+		RenewalNoticeDays: lease.RenewalNoticeDays,
+		LateFee:           lease.LateFee,
+		LateFeeGraceDays:  lease.LateFeeGraceDays,
+		Notes:             lease.Notes,
+	}
+
+	// Update using repo
+	updatedLease, err := s.repo.Update(id, leaseToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if len(lease.Tenants) > 0 {
+		tenants, err := s.findTenants(lease.Tenants)
+		if err != nil {
+			return nil, err
+		}
+		err = s.repo.ReplaceTenants(updatedLease.ID, tenants)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !lease.StartDate.IsZero() || !lease.EndDate.IsZero() || !lease.RentAmount.IsZero() || lease.Frequency != "" {
+		chargedUntil, err := s.repo.ChargedUntil(updatedLease.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = s.repo.ReplaceSchedule(updatedLease.ID, buildRentSchedule(updatedLease, chargedUntil))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.FindById(id)
+}
+
+// Creates a new lease for the following term with the same property (or unit) and tenants
+func (s *leaseService) Renew(id int, renewal *models.RenewLease) (*db.Lease, error) {
+	lease, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindRenewalOf(lease.ID); err == nil {
+		return nil, ErrLeaseAlreadyRenewed
+	}
+
+	renewedLease := db.Lease{
+		StartDate:         lease.EndDate,
+		EndDate:           addMonths(lease.EndDate, renewal.TermMonths),
+		RentAmount:        renewal.RentAmount,
+		Frequency:         renewal.Frequency,
+		Deposit:           lease.Deposit,
+		RenewalOption:     lease.RenewalOption,
+		RenewalNoticeDays: lease.RenewalNoticeDays,
+		LateFee:           lease.LateFee,
+		LateFeeGraceDays:  lease.LateFeeGraceDays,
+		Notes:             renewal.Notes,
+		PropertyID:        lease.PropertyID,
+		UnitID:            lease.UnitID,
+		TransactionID:     lease.TransactionID,
+		RenewedFromID:     &lease.ID,
+		Tenants:           lease.Tenants,
+	}
+	if renewedLease.RentAmount.IsZero() {
+		renewedLease.RentAmount = lease.RentAmount.Mul(1 + renewal.RentIncreasePercent/100)
+	}
+	if !inRentCurrency(renewedLease.RentAmount, renewedLease.Deposit, renewedLease.LateFee) {
+		return nil, db.ErrCurrencyMismatch
+	}
+	if renewedLease.Frequency == "" {
+		renewedLease.Frequency = lease.Frequency
+	}
+	err = s.checkOverlap(lease.ID, lease.PropertyID, lease.UnitID, renewedLease.StartDate, renewedLease.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	createdLease, err := s.repo.Create(&renewedLease)
+	if err != nil {
+		return nil, fmt.Errorf("failed renewing lease: %w", err)
+	}
+	err = s.repo.ReplaceSchedule(createdLease.ID, buildRentSchedule(createdLease, time.Time{}))
+	if err != nil {
+		return nil, err
+	}
+	// A renewed lease no longer needs a reminder
+	if lease.RenewalReminderSentAt == nil {
+		err = s.repo.MarkReminderSent(lease.ID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.FindById(int(createdLease.ID))
+}
+
+// Reminds admins of leases that have entered their renewal notice period. Each lease reminds once
+func (s *leaseService) ProcessRenewalReminders() error {
+	now := time.Now()
+	// Notice periods are at most a year
+	leases, err := s.repo.FindUnremindedEnding(now, now.AddDate(1, 0, 1))
+	if err != nil {
+		return err
+	}
+	if len(*leases) == 0 {
+		return nil
+	}
+
+	// Notify admins
+	admins, err := s.users.FindByRole("admin")
+	if err != nil {
+		return err
+	}
+	adminIDs := []uint{}
+	for _, admin := range *admins {
+		adminIDs = append(adminIDs, admin.ID)
+	}
+
+	for _, lease := range *leases {
+		if leaseStatus(&lease, now) != "Expiring" {
+			continue
+		}
+		// Leases that have been renewed don't need a reminder
+		if _, err := s.repo.FindRenewalOf(lease.ID); err != nil {
+			s.notification.Dispatch(&models.NotificationEvent{
+				Type: "LeaseExpiring",
+				Message: fmt.Sprintf("The lease of %s to %s ends on %s (%d days). Renewal option: %s",
+					lease.Property.Property_Name, strings.Join(tenantNames(lease.Tenants), ", "), lease.EndDate.Format("2 Jan 2006"),
+					int(lease.EndDate.Sub(now).Hours()/24), lease.RenewalOption),
+				UserIDs: adminIDs,
+			})
+		}
+		err = s.repo.MarkReminderSent(lease.ID, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Finds the contacts leasing a property
+func (s *leaseService) findTenants(tenants []db.Contact) ([]db.Contact, error) {
+	foundTenants := []db.Contact{}
+	for _, tenant := range tenants {
+		found, err := s.contacts.FindById(int(tenant.ID))
+		if err != nil {
+			return nil, fmt.Errorf("tenant %d not found: %w", tenant.ID, err)
+		}
+		foundTenants = append(foundTenants, *found)
+	}
+	return foundTenants, nil
+}
+
+// Ensures no other lease of the property overlaps the period. Leases of different units of a property may overlap,
+// but a lease of the whole property overlaps leases of any of its units
+func (s *leaseService) checkOverlap(excludeId uint, propertyId uint, unitId *uint, start time.Time, end time.Time) error {
+	overlapping, err := s.repo.FindOverlapping(propertyId, unitId, start, end)
+	if err != nil {
+		return err
+	}
+	for _, found := range *overlapping {
+		if found.ID != excludeId {
+			return ErrLeaseOverlap
+		}
+	}
+	return nil
+}
+
+// Returns the status of a lease: Upcoming, Active, Expiring (within its renewal notice period) or Ended
+func leaseStatus(lease *db.Lease, now time.Time) string {
+	if now.Before(lease.StartDate) {
+		return "Upcoming"
+	}
+	if !now.Before(lease.EndDate) {
+		return "Ended"
+	}
+	if now.AddDate(0, 0, lease.RenewalNoticeDays).After(lease.EndDate) {
+		return "Expiring"
+	}
+	return "Active"
+}
+
+// Splits a lease into rent periods, each due in advance at its start. A final partial period is charged pro rata.
+// Periods before an already charged date are left out, and a period spanning it starts from it pro rata
+func buildRentSchedule(lease *db.Lease, chargedUntil time.Time) []db.RentScheduleItem {
+	months := frequencyMonths(lease.Frequency)
+	schedule := []db.RentScheduleItem{}
+	for period := 0; ; period++ {
+		// Periods are offset from the start date so month ends don't drift
+		start := addMonths(lease.StartDate, period*months)
+		if !start.Before(lease.EndDate) {
+			break
+		}
+		end := addMonths(lease.StartDate, (period+1)*months)
+		if !end.After(chargedUntil) {
+			continue
+		}
+		// Portion of the full period that is charged
+		fullPeriod := end.Sub(start).Hours()
+		if start.Before(chargedUntil) {
+			start = chargedUntil
+		}
+		if end.After(lease.EndDate) {
+			end = lease.EndDate
+		}
+		amount := lease.RentAmount
+		if end.Sub(start).Hours() != fullPeriod {
+			amount = lease.RentAmount.Mul(end.Sub(start).Hours() / fullPeriod)
+		}
+		schedule = append(schedule, db.RentScheduleItem{
+			DueDate:     start,
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Amount:      amount,
+			LeaseID:     lease.ID,
+		})
+	}
+	return schedule
+}
+
+// Number of months in a rent period
+func frequencyMonths(frequency string) int {
+	switch frequency {
+	case "Quarterly":
+		return 3
+	case "Yearly":
+		return 12
+	}
+	return 1
+}
+
+// Adds months to a date, keeping to the last day of shorter months (eg. 31 Jan + 1 month is 28 Feb)
+func addMonths(date time.Time, months int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := date.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// Checks that a lease's amounts are in the currency of its rent (the first amount)
+func inRentCurrency(rent db.Money, amounts ...db.Money) bool {
+	for _, amount := range amounts {
+		if !amount.SameCurrency(rent) {
+			return false
+		}
+	}
+	return true
+}
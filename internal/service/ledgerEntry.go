@@ -0,0 +1,458 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when deleting a rent charge, which is generated from the rent schedule
+var ErrRentChargeLocked = errors.New("rent charges can't be deleted, record an adjustment instead")
+
+// Returned when an entry's amount doesn't suit its type
+var ErrInvalidLedgerAmount = errors.New("only adjustments can have a negative amount")
+
+// Returned when refunding more deposit than is held
+var ErrDepositExceeded = errors.New("refund is more than the deposit held")
+
+type LedgerEntryService interface {
+	FindAll(int, int, string, int) (*[]db.LedgerEntry, error)
+	FindById(int) (*db.LedgerEntry, error)
+	Create(*models.CreateLedgerEntry) (*db.LedgerEntry, error)
+	Delete(int) error
+	// Statement of a lease's ledger between two times
+	Statement(int, time.Time, time.Time) (*models.LeaseStatement, error)
+	// Overdue charges of leases across managed properties
+	Arrears(string) (*models.ArrearsReport, error)
+	// Charges rent that has fallen due and late fees on overdue rent (scheduled hourly)
+	ProcessRentCharges() error
+}
+
+type ledgerEntryService struct {
+	repo   repository.LedgerEntryRepository
+	leases repository.LeaseRepository
+	rates  ExchangeRateService
+	taxes  TaxService
+}
+
+func NewLedgerEntryService(repo repository.LedgerEntryRepository, leases repository.LeaseRepository, rates ExchangeRateService, taxes TaxService) LedgerEntryService {
+	return &ledgerEntryService{repo, leases, rates, taxes}
+}
+
+// Records a receipt, adjustment, late fee or deposit movement against a lease
+func (s *ledgerEntryService) Create(entry *models.CreateLedgerEntry) (*db.LedgerEntry, error) {
+	// Ensure lease exists
+	lease, err := s.leases.FindById(int(entry.Lease.ID))
+	if err != nil {
+		return nil, fmt.Errorf("lease not found: %w", err)
+	}
+	if entry.Amount.Amount < 0 && entry.Type != "Adjustment" {
+		return nil, ErrInvalidLedgerAmount
+	}
+	// The ledger is kept in the currency of the rent
+	if !entry.Amount.SameCurrency(lease.RentAmount) {
+		return nil, db.ErrCurrencyMismatch
+	}
+	currency := lease.RentAmount.Currency
+	entryToCreate := db.LedgerEntry{
+		EntryDate:   entry.EntryDate,
+		Type:        entry.Type,
+		Debit:       db.Money{Currency: currency},
+		Credit:      db.Money{Currency: currency},
+		Description: entry.Description,
+		Reference:   entry.Reference,
+		LeaseID:     lease.ID,
+	}
+	if entryToCreate.EntryDate.IsZero() {
+		entryToCreate.EntryDate = time.Now()
+	}
+	amount := db.Money{Amount: entry.Amount.Abs().Amount, Currency: currency}
+	switch entry.Type {
+	case "Receipt":
+		entryToCreate.Account, entryToCreate.Credit = "Rent", amount
+	case "Late Fee":
+		entryToCreate.Account, entryToCreate.Debit = "Rent", amount
+	case "Adjustment":
+		// Positive adjustments add to what the tenant owes
+		entryToCreate.Account = "Rent"
+		if entry.Amount.Amount > 0 {
+			entryToCreate.Debit = amount
+		} else {
+			entryToCreate.Credit = amount
+		}
+	case "Deposit Received":
+		entryToCreate.Account, entryToCreate.Credit = "Deposit", amount
+	case "Deposit Refund":
+		entryToCreate.Account, entryToCreate.Debit = "Deposit", amount
+		entries, err := s.repo.FindByLease(lease.ID, time.Now().AddDate(100, 0, 0))
+		if err != nil {
+			return nil, err
+		}
+		_, held := ledgerBalances(*entries)
+		if amount.Amount > held.Amount {
+			return nil, ErrDepositExceeded
+		}
+	}
+
+	// Create entry in database
+	createdEntry, err := s.repo.Create(&entryToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating ledger entry: %w", err)
+	}
+	return createdEntry, nil
+}
+
+// Find a list of ledger entries
+func (s *ledgerEntryService) FindAll(limit int, offset int, order string, leaseId int) (*[]db.LedgerEntry, error) {
+	entries, err := s.repo.FindAll(limit, offset, order, leaseId)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Find ledger entry in database by ID
+func (s *ledgerEntryService) FindById(id int) (*db.LedgerEntry, error) {
+	// Find by id
+	entry, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return entry, nil
+}
+
+// Voids a ledger entry. Rent charges are corrected with adjustments instead
+func (s *ledgerEntryService) Delete(id int) error {
+	entry, err := s.repo.FindById(id)
+	if err != nil {
+		return err
+	}
+	if entry.Type == "Rent Charge" {
+		return ErrRentChargeLocked
+	}
+	err = s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting ledger entry: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Statement of a lease's ledger from (inclusive) to (exclusive). Defaults to the start of the lease until now
+func (s *ledgerEntryService) Statement(leaseId int, from time.Time, to time.Time) (*models.LeaseStatement, error) {
+	lease, err := s.leases.FindById(leaseId)
+	if err != nil {
+		return nil, fmt.Errorf("lease not found: %w", err)
+	}
+	now := time.Now()
+	// Rent that has fallen due but hasn't been charged yet is included so the statement is current
+	pending, err := s.pendingRentCharges(now)
+	if err != nil {
+		return nil, err
+	}
+	if from.IsZero() {
+		from = lease.StartDate
+	}
+	if to.IsZero() {
+		to = now
+	}
+	entries, err := s.repo.FindByLease(lease.ID, to)
+	if err != nil {
+		return nil, err
+	}
+	*entries = withPendingCharges(*entries, pending[lease.ID], to)
+
+	statement := models.LeaseStatement{
+		LeaseID:    lease.ID,
+		PropertyID: lease.PropertyID,
+		Property:   lease.Property.Property_Name,
+		Tenants:    tenantNames(lease.Tenants),
+		From:       from,
+		To:         to,
+		Lines:      []models.StatementLine{},
+	}
+	var rentBalance, depositBalance db.Money
+	for _, entry := range *entries {
+		if entry.EntryDate.Before(from) {
+			statement.OpeningRentBalance, statement.OpeningDepositBalance = applyLedgerEntry(entry, rentBalance, depositBalance)
+		}
+		rentBalance, depositBalance = applyLedgerEntry(entry, rentBalance, depositBalance)
+		if !entry.EntryDate.Before(from) {
+			statement.Lines = append(statement.Lines, models.StatementLine{LedgerEntry: entry, RentBalance: rentBalance, DepositBalance: depositBalance})
+		}
+	}
+	statement.ClosingRentBalance, statement.ClosingDepositBalance = rentBalance, depositBalance
+
+	// Arrears as of the end of the statement
+	asOf := to
+	if asOf.After(now) {
+		asOf = now
+	}
+	statement.Arrears, _ = arrearsAging(*entries, asOf)
+	return &statement, nil
+}
+
+// Overdue charges of leases across managed properties, converted into the reporting currency at today's rates
+func (s *ledgerEntryService) Arrears(currency string) (*models.ArrearsReport, error) {
+	now := time.Now()
+	// Rent that has fallen due but hasn't been charged yet is included so the report is current
+	pending, err := s.pendingRentCharges(now)
+	if err != nil {
+		return nil, err
+	}
+	leases, err := s.repo.FindManagedLeases(now)
+	if err != nil {
+		return nil, err
+	}
+
+	zero := db.Money{Currency: currency}
+	report := models.ArrearsReport{AsOf: now, Currency: currency, Leases: []models.LeaseArrears{},
+		Total: models.ArrearsAging{Days1To30: zero, Days31To60: zero, Days61To90: zero, Over90: zero, Total: zero}}
+	for _, lease := range *leases {
+		entries, err := s.repo.FindByLease(lease.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		*entries = withPendingCharges(*entries, pending[lease.ID], now)
+		aging, oldest := arrearsAging(*entries, now)
+		if aging.Total.Amount <= 0 {
+			continue
+		}
+		rentBalance, depositBalance := ledgerBalances(*entries)
+		err = s.rates.ConvertAll(currency, now, &rentBalance, &depositBalance,
+			&aging.Days1To30, &aging.Days31To60, &aging.Days61To90, &aging.Over90, &aging.Total)
+		if err != nil {
+			return nil, err
+		}
+		report.Leases = append(report.Leases, models.LeaseArrears{
+			LeaseID:        lease.ID,
+			PropertyID:     lease.PropertyID,
+			Property:       lease.Property.Property_Name,
+			Tenants:        tenantNames(lease.Tenants),
+			Status:         leaseStatus(&lease, now),
+			RentBalance:    rentBalance,
+			DepositBalance: depositBalance,
+			OldestDueDate:  oldest,
+			DaysOverdue:    daysOverdue(oldest, now),
+			ArrearsAging:   aging,
+		})
+		report.Total.Total = report.Total.Total.Add(aging.Total)
+		report.Total.Days1To30 = report.Total.Days1To30.Add(aging.Days1To30)
+		report.Total.Days31To60 = report.Total.Days31To60.Add(aging.Days31To60)
+		report.Total.Days61To90 = report.Total.Days61To90.Add(aging.Days61To90)
+		report.Total.Over90 = report.Total.Over90.Add(aging.Over90)
+	}
+	return &report, nil
+}
+
+// Charges rent that has fallen due, then late fees on rent still unpaid after each lease's grace period
+func (s *ledgerEntryService) ProcessRentCharges() error {
+	now := time.Now()
+	err := s.chargeDueRent(now)
+	if err != nil {
+		return err
+	}
+
+	leases, err := s.repo.FindLeasesWithLateFees(now)
+	if err != nil {
+		return err
+	}
+	for _, lease := range *leases {
+		entries, err := s.repo.FindByLease(lease.ID, now)
+		if err != nil {
+			return err
+		}
+		for _, charge := range unpaidCharges(*entries) {
+			if charge.Type != "Rent Charge" || charge.RentScheduleItemID == nil ||
+				!charge.EntryDate.AddDate(0, 0, lease.LateFeeGraceDays).Before(now) {
+				continue
+			}
+			// Each rent period is charged one late fee, and waived fees aren't charged again
+			charged, err := s.repo.HasLateFee(*charge.RentScheduleItemID)
+			if err != nil {
+				return err
+			}
+			if charged {
+				continue
+			}
+			_, err = s.repo.Create(&db.LedgerEntry{
+				EntryDate:          now,
+				Type:               "Late Fee",
+				Account:            "Rent",
+				Debit:              lease.LateFee,
+				Credit:             db.Money{Currency: lease.LateFee.Currency},
+				Description:        fmt.Sprintf("Late fee on rent due %s", charge.EntryDate.Format("2 Jan 2006")),
+				LeaseID:            lease.ID,
+				RentScheduleItemID: charge.RentScheduleItemID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Charges rent periods that have fallen due to their lease's ledger. Periods charged by a concurrent run are skipped
+func (s *ledgerEntryService) chargeDueRent(asOf time.Time) error {
+	items, err := s.repo.FindUnchargedRent(asOf)
+	if err != nil {
+		return err
+	}
+	// Entries are created individually so zero debits and credits aren't replaced by defaults
+	for _, item := range *items {
+		charge := rentCharge(item)
+		// Tax on the rent (eg. PPh 4(2)) is due when it's charged
+		charge.TaxLines, err = s.taxes.Lines("Rent", item.Amount, item.DueDate)
+		if err != nil {
+			return err
+		}
+		for i := range charge.TaxLines {
+			charge.TaxLines[i].LeaseID = &item.LeaseID
+		}
+		_, err = s.repo.CreateRentCharge(&charge)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rent periods that have fallen due but haven't been charged yet, as unsaved rent charges by lease
+func (s *ledgerEntryService) pendingRentCharges(asOf time.Time) (map[uint][]db.LedgerEntry, error) {
+	items, err := s.repo.FindUnchargedRent(asOf)
+	if err != nil {
+		return nil, err
+	}
+	pending := map[uint][]db.LedgerEntry{}
+	for _, item := range *items {
+		pending[item.LeaseID] = append(pending[item.LeaseID], rentCharge(item))
+	}
+	return pending, nil
+}
+
+// Rent charge of a rent period
+func rentCharge(item db.RentScheduleItem) db.LedgerEntry {
+	itemId := item.ID
+	return db.LedgerEntry{
+		EntryDate:          item.DueDate,
+		Type:               "Rent Charge",
+		Account:            "Rent",
+		Debit:              item.Amount,
+		Credit:             db.Money{Currency: item.Amount.Currency},
+		Description:        fmt.Sprintf("Rent %s to %s", item.PeriodStart.Format("2 Jan 2006"), item.PeriodEnd.Format("2 Jan 2006")),
+		LeaseID:            item.LeaseID,
+		RentScheduleItemID: &itemId,
+	}
+}
+
+// Adds pending rent charges dated before a time to a lease's ledger entries, keeping them in date order
+func withPendingCharges(entries []db.LedgerEntry, pending []db.LedgerEntry, before time.Time) []db.LedgerEntry {
+	for _, charge := range pending {
+		if charge.EntryDate.Before(before) {
+			entries = append(entries, charge)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EntryDate.Before(entries[j].EntryDate)
+	})
+	return entries
+}
+
+// Rent owed by the tenant and deposit held after a ledger entry
+func applyLedgerEntry(entry db.LedgerEntry, rentBalance db.Money, depositBalance db.Money) (db.Money, db.Money) {
+	if entry.Account == "Deposit" {
+		return rentBalance, depositBalance.Add(entry.Credit).Sub(entry.Debit)
+	}
+	return rentBalance.Add(entry.Debit).Sub(entry.Credit), depositBalance
+}
+
+// Rent owed by the tenant and deposit held across ledger entries
+func ledgerBalances(entries []db.LedgerEntry) (db.Money, db.Money) {
+	var rentBalance, depositBalance db.Money
+	for _, entry := range entries {
+		rentBalance, depositBalance = applyLedgerEntry(entry, rentBalance, depositBalance)
+	}
+	return rentBalance, depositBalance
+}
+
+// Rent account charges that remain unpaid, with the unpaid amount as the debit.
+// Receipts and credits pay off the oldest charges first
+func unpaidCharges(entries []db.LedgerEntry) []db.LedgerEntry {
+	var credits int64
+	for _, entry := range entries {
+		if entry.Account == "Rent" {
+			credits += entry.Credit.Amount
+		}
+	}
+	unpaid := []db.LedgerEntry{}
+	for _, entry := range entries {
+		if entry.Account != "Rent" || entry.Debit.IsZero() {
+			continue
+		}
+		paid := credits
+		if paid > entry.Debit.Amount {
+			paid = entry.Debit.Amount
+		}
+		credits -= paid
+		if remaining := entry.Debit.Amount - paid; remaining > 0 {
+			entry.Debit.Amount = remaining
+			unpaid = append(unpaid, entry)
+		}
+	}
+	return unpaid
+}
+
+// Unpaid charges past their due date by age, along with the oldest due date
+func arrearsAging(entries []db.LedgerEntry, asOf time.Time) (models.ArrearsAging, time.Time) {
+	aging := models.ArrearsAging{}
+	var oldest time.Time
+	for _, charge := range unpaidCharges(entries) {
+		days := daysOverdue(charge.EntryDate, asOf)
+		if days < 1 {
+			continue
+		}
+		if oldest.IsZero() {
+			oldest = charge.EntryDate
+		}
+		switch {
+		case days <= 30:
+			aging.Days1To30 = aging.Days1To30.Add(charge.Debit)
+		case days <= 60:
+			aging.Days31To60 = aging.Days31To60.Add(charge.Debit)
+		case days <= 90:
+			aging.Days61To90 = aging.Days61To90.Add(charge.Debit)
+		default:
+			aging.Over90 = aging.Over90.Add(charge.Debit)
+		}
+		aging.Total = aging.Total.Add(charge.Debit)
+	}
+	return aging, oldest
+}
+
+// Whole days since a charge fell due
+func daysOverdue(dueDate time.Time, asOf time.Time) int {
+	if dueDate.IsZero() {
+		return 0
+	}
+	return int(asOf.Sub(dueDate).Hours() / 24)
+}
+
+// Names of a lease's tenants
+func tenantNames(tenants []db.Contact) []string {
+	names := []string{}
+	for _, tenant := range tenants {
+		names = append(names, strings.TrimSpace(tenant.FirstName+" "+tenant.LastName))
+	}
+	return names
+}
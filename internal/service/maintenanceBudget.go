@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when the property already has a budget for the period and work type
+var ErrDuplicateBudget = errors.New("a maintenance budget already exists for this property, period and work type")
+
+type MaintenanceBudgetService interface {
+	FindAll(int, int, string, int, int) (*[]db.MaintenanceBudget, error)
+	FindById(int) (*db.MaintenanceBudget, error)
+	Create(*models.CreateMaintenanceBudget) (*db.MaintenanceBudget, error)
+	Update(int, *models.UpdateMaintenanceBudget) (*db.MaintenanceBudget, error)
+	Delete(int) error
+	// Actual vs budget of a property's maintenance for a year
+	Report(int, int, string) (*models.MaintenanceBudgetReport, error)
+	// Alerts admins of current budgets that have reached their threshold (scheduled hourly)
+	ProcessBudgetAlerts() error
+}
+
+type maintenanceBudgetService struct {
+	repo         repository.MaintenanceBudgetRepository
+	properties   repository.PropertyRepository
+	workTypes    repository.WorkTypeRepository
+	users        repository.UserRepository
+	notification NotificationService
+	rates        ExchangeRateService
+}
+
+func NewMaintenanceBudgetService(repo repository.MaintenanceBudgetRepository, properties repository.PropertyRepository, workTypes repository.WorkTypeRepository, users repository.UserRepository, notification NotificationService, rates ExchangeRateService) MaintenanceBudgetService {
+	return &maintenanceBudgetService{repo, properties, workTypes, users, notification, rates}
+}
+
+// Creates a maintenance budget
+func (s *maintenanceBudgetService) Create(budget *models.CreateMaintenanceBudget) (*db.MaintenanceBudget, error) {
+	// Ensure property exists
+	property, err := s.properties.FindById(int(budget.Property.ID))
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	budgetToCreate := db.MaintenanceBudget{
+		Year:           budget.Year,
+		Month:          budget.Month,
+		Amount:         budget.Amount,
+		AlertThreshold: budget.AlertThreshold,
+		Notes:          budget.Notes,
+		PropertyID:     property.ID,
+	}
+	// Ensure work type exists if provided
+	if budget.WorkType.ID != 0 {
+		workType, err := s.workTypes.FindById(int(budget.WorkType.ID))
+		if err != nil {
+			return nil, fmt.Errorf("work type not found: %w", err)
+		}
+		budgetToCreate.WorkTypeID = &workType.ID
+	}
+
+	// Only one budget per period and work type
+	existing, err := s.repo.FindByPropertyYear(property.ID, budget.Year)
+	if err != nil {
+		return nil, err
+	}
+	for _, found := range *existing {
+		if found.Month == budgetToCreate.Month && sameWorkType(found.WorkTypeID, budgetToCreate.WorkTypeID) {
+			return nil, ErrDuplicateBudget
+		}
+	}
+
+	// Create budget in database
+	createdBudget, err := s.repo.Create(&budgetToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating maintenance budget: %w", err)
+	}
+	return s.repo.FindById(int(createdBudget.ID))
+}
+
+// Find a list of maintenance budgets
+func (s *maintenanceBudgetService) FindAll(limit int, offset int, order string, propertyId int, year int) (*[]db.MaintenanceBudget, error) {
+	budgets, err := s.repo.FindAll(limit, offset, order, propertyId, year)
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// Find maintenance budget in database by ID
+func (s *maintenanceBudgetService) FindById(id int) (*db.MaintenanceBudget, error) {
+	// Find by id
+	budget, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return budget, nil
+}
+
+// Delete maintenance budget in database
+func (s *maintenanceBudgetService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting maintenance budget: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates maintenance budget in database
+func (s *maintenanceBudgetService) Update(id int, budget *models.UpdateMaintenanceBudget) (*db.MaintenanceBudget, error) {
+	// Create a new budget from DTO
+	budgetToUpdate := &db.MaintenanceBudget{
+		Amount:         budget.Amount,
+		AlertThreshold: budget.AlertThreshold,
+		Notes:          budget.Notes,
+	}
+
+	// Update using repo
+	updatedBudget, err := s.repo.Update(id, budgetToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedBudget, nil
+}
+
+// Actual vs budget of a property's maintenance for a year. Amounts are converted into the reporting currency
+// at the rates effective at the end of each period (or today for current periods)
+func (s *maintenanceBudgetService) Report(propertyId int, year int, currency string) (*models.MaintenanceBudgetReport, error) {
+	// Ensure property exists
+	property, err := s.properties.FindById(propertyId)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	report := models.MaintenanceBudgetReport{
+		PropertyID: property.ID,
+		Year:       year,
+		Currency:   currency,
+		Budgets:    []models.MaintenanceBudgetStatus{},
+		Months:     []models.MaintenanceCosts{},
+		Total:      models.MaintenanceCosts{Actual: db.Money{Currency: currency}},
+	}
+
+	// Status of each budget for the year
+	budgets, err := s.repo.FindByPropertyYear(property.ID, year)
+	if err != nil {
+		return nil, err
+	}
+	for _, budget := range *budgets {
+		status, err := s.budgetStatus(budget)
+		if err != nil {
+			return nil, err
+		}
+		// Spend is compared with the budget in the budget's currency, then shown in the reporting currency
+		err = s.rates.ConvertAll(currency, periodRateDate(status.PeriodEnd), &status.Budget.Amount, &status.Remaining,
+			&status.InvoicedCosts, &status.RequestCosts, &status.Actual)
+		if err != nil {
+			return nil, err
+		}
+		report.Budgets = append(report.Budgets, *status)
+	}
+
+	// Spend across all work types per month
+	for month := 1; month <= 12; month++ {
+		start, end := budgetPeriod(year, month)
+		costs, err := s.costs(property.ID, nil, start, end, currency)
+		if err != nil {
+			return nil, err
+		}
+		report.Months = append(report.Months, *costs)
+		report.Total.Actual = report.Total.Actual.Add(costs.Actual)
+		report.Total.InvoicedCosts = report.Total.InvoicedCosts.Add(costs.InvoicedCosts)
+		report.Total.RequestCosts = report.Total.RequestCosts.Add(costs.RequestCosts)
+	}
+	return &report, nil
+}
+
+// Alerts admins of current budgets that have reached their threshold. Each budget alerts once
+func (s *maintenanceBudgetService) ProcessBudgetAlerts() error {
+	now := time.Now()
+	budgets, err := s.repo.FindUnalerted(now.Year(), int(now.Month()))
+	if err != nil {
+		return err
+	}
+	if len(*budgets) == 0 {
+		return nil
+	}
+
+	// Notify admins
+	admins, err := s.users.FindByRole("admin")
+	if err != nil {
+		return err
+	}
+	adminIDs := []uint{}
+	for _, admin := range *admins {
+		adminIDs = append(adminIDs, admin.ID)
+	}
+
+	for _, budget := range *budgets {
+		status, err := s.budgetStatus(budget)
+		// Spend in other currencies can't be compared with the budget without a rate
+		if errors.Is(err, ErrNoExchangeRate) {
+			fmt.Printf("Skipping maintenance budget %d alert: %s\n", budget.ID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !status.OverThreshold {
+			continue
+		}
+		s.notification.Dispatch(&models.NotificationEvent{
+			Type: "MaintenanceBudgetExceeded",
+			Message: fmt.Sprintf("Maintenance at %s has reached %.1f%% of the %s budget (%s of %s)",
+				budget.Property.Property_Name, status.PercentUsed, budgetLabel(budget), status.Actual.Format(), budget.Amount.Format()),
+			UserIDs: adminIDs,
+		})
+		err = s.repo.MarkAlertSent(budget.ID, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Computes actual spend against a budget over its period, in the currency of the budget
+func (s *maintenanceBudgetService) budgetStatus(budget db.MaintenanceBudget) (*models.MaintenanceBudgetStatus, error) {
+	start, end := budgetPeriod(budget.Year, budget.Month)
+	costs, err := s.costs(budget.PropertyID, budget.WorkTypeID, start, end, budget.Amount.Code())
+	if err != nil {
+		return nil, err
+	}
+	status := models.MaintenanceBudgetStatus{
+		Budget:           budget,
+		PeriodStart:      start,
+		PeriodEnd:        end,
+		MaintenanceCosts: *costs,
+		Remaining:        budget.Amount.Sub(costs.Actual),
+	}
+	if budget.Amount.Amount > 0 {
+		status.PercentUsed = math.Round(float64(costs.Actual.Amount)/float64(budget.Amount.Amount)*1000) / 10
+	}
+	status.OverThreshold = status.PercentUsed >= budget.AlertThreshold
+	return &status, nil
+}
+
+// Sums maintenance costs of a property (and optional work type) over a period, converted into a currency at the
+// rates effective at the end of the period (or today for current periods)
+func (s *maintenanceBudgetService) costs(propertyId uint, workTypeId *uint, start time.Time, end time.Time, currency string) (*models.MaintenanceCosts, error) {
+	invoiced, requested, err := s.repo.SumCosts(propertyId, workTypeId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	on := periodRateDate(end)
+	costs := models.MaintenanceCosts{}
+	costs.InvoicedCosts, err = s.rates.Total(currency, on, invoiced...)
+	if err != nil {
+		return nil, err
+	}
+	costs.RequestCosts, err = s.rates.Total(currency, on, requested...)
+	if err != nil {
+		return nil, err
+	}
+	costs.Actual = costs.InvoicedCosts.Add(costs.RequestCosts)
+	return &costs, nil
+}
+
+// Returns the start (inclusive) and end (exclusive) of a budget period. Month 0 is the whole year
+func budgetPeriod(year int, month int) (time.Time, time.Time) {
+	if month == 0 {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(1, 0, 0)
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Date of the rates spend over a period ending (exclusive) on a date is converted at: the period's last day, or
+// today for current periods
+func periodRateDate(end time.Time) time.Time {
+	on := end.AddDate(0, 0, -1)
+	if now := time.Now(); on.After(now) {
+		return now
+	}
+	return on
+}
+
+// Describes a budget's period and work type (eg. "March 2026 Plumbing")
+func budgetLabel(budget db.MaintenanceBudget) string {
+	period := fmt.Sprint(budget.Year)
+	if budget.Month != 0 {
+		period = fmt.Sprintf("%s %d", time.Month(budget.Month), budget.Year)
+	}
+	if budget.WorkType != nil {
+		return fmt.Sprintf("%s %s", period, budget.WorkType.Name)
+	}
+	return period
+}
+
+// Determines whether two optional work types are the same
+func sameWorkType(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
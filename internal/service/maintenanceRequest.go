@@ -2,6 +2,8 @@ package service
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/dmawardi/Go-Template/internal/db"
 	"github.com/dmawardi/Go-Template/internal/models"
@@ -13,19 +15,79 @@ type MaintenanceRequestService interface {
 	FindById(int) (*db.MaintenanceRequest, error)
 	Create(*models.CreateMaintenanceRequest) (*db.MaintenanceRequest, error)
 	Update(int, *models.UpdateMaintenanceRequest) (*db.MaintenanceRequest, error)
+	// Deletes a maintenance request, archiving its task
 	Delete(int) error
+	// Creates a task together with its maintenance request
+	CreateWithTask(*models.CreateTaskWithMaintenanceRequest) (*db.Task, error)
+	// Ranks vendors for dispatch to a maintenance request
+	SuggestVendors(int) (*[]models.SuggestedVendor, error)
 }
 
 type maintenanceRequestService struct {
-	repo repository.MaintenanceRequestRepository
+	repo         repository.MaintenanceRequestRepository
+	notification NotificationService
+	vendors      repository.VendorRepository
+	tasks        repository.TaskRepository
+	units        repository.UnitRepository
 }
 
-func NewMaintenanceRequestService(repo repository.MaintenanceRequestRepository) MaintenanceRequestService {
-	return &maintenanceRequestService{repo}
+func NewMaintenanceRequestService(repo repository.MaintenanceRequestRepository, notification NotificationService, vendors repository.VendorRepository, tasks repository.TaskRepository, units repository.UnitRepository) MaintenanceRequestService {
+	return &maintenanceRequestService{repo, notification, vendors, tasks, units}
 }
 
 // Creates a maintenance request
 func (s *maintenanceRequestService) Create(request *models.CreateMaintenanceRequest) (*db.MaintenanceRequest, error) {
+	// Ensure the task exists so the request isn't left without one
+	_, err := s.tasks.FindById(int(request.Task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	requestToCreate, err := s.buildMaintenanceRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create request in database
+	createdRequest, err := s.repo.Create(requestToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating maintenance request: %w", err)
+	}
+
+	return createdRequest, nil
+}
+
+// Creates a task together with its maintenance request, so neither exists without the other
+func (s *maintenanceRequestService) CreateWithTask(create *models.CreateTaskWithMaintenanceRequest) (*db.Task, error) {
+	taskToCreate, err := buildTask(s.tasks, &create.Task)
+	if err != nil {
+		return nil, err
+	}
+	requestToCreate, err := s.buildMaintenanceRequest(&create.MaintenanceRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create both in one database transaction
+	err = s.tasks.CreateWithMaintenanceRequest(taskToCreate, requestToCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Notify assignees
+	notifyAssigned(s.notification, taskToCreate, taskToCreate.Assignment)
+
+	return s.tasks.FindById(int(taskToCreate.ID))
+}
+
+// Builds a maintenance request from DTO. Task is set from the DTO
+func (s *maintenanceRequestService) buildMaintenanceRequest(request *models.CreateMaintenanceRequest) (*db.MaintenanceRequest, error) {
+	if !request.TotalCost.SameCurrency(request.Tax) {
+		return nil, fmt.Errorf("%w: tax must be in %s", db.ErrCurrencyMismatch, request.TotalCost.Code())
+	}
+	unitId, err := findPropertyUnit(s.units, request.Unit, request.Property.ID)
+	if err != nil {
+		return nil, err
+	}
 	// Create a new maintenance request from DTO
 	requestToCreate := db.MaintenanceRequest{
 		WorkDefinition: request.WorkDefinition,
@@ -35,16 +97,11 @@ func (s *maintenanceRequestService) Create(request *models.CreateMaintenanceRequ
 		Tax:            request.Tax,
 		TotalCost:      request.TotalCost,
 		Property:       request.Property,
+		UnitID:         unitId,
 		TaskID:         request.Task.ID,
+		WorkTypeID:     request.WorkType.ID,
 	}
-
-	// Create request in database
-	createdRequest, err := s.repo.Create(&requestToCreate)
-	if err != nil {
-		return nil, fmt.Errorf("failed creating maintenance request: %w", err)
-	}
-
-	return createdRequest, nil
+	return &requestToCreate, nil
 }
 
 // Find a list of maintenance requests
@@ -69,7 +126,7 @@ func (s *maintenanceRequestService) FindById(id int) (*db.MaintenanceRequest, er
 	return request, nil
 }
 
-// Delete maintenance request in database
+// Delete maintenance request in database. Its task is archived
 func (s *maintenanceRequestService) Delete(id int) error {
 	err := s.repo.Delete(id)
 	// If error detected
@@ -83,6 +140,9 @@ func (s *maintenanceRequestService) Delete(id int) error {
 
 // Updates maintenance request in database
 func (s *maintenanceRequestService) Update(id int, request *models.UpdateMaintenanceRequest) (*db.MaintenanceRequest, error) {
+	if !request.TotalCost.SameCurrency(request.Tax) {
+		return nil, fmt.Errorf("%w: tax must be in %s", db.ErrCurrencyMismatch, request.TotalCost.Code())
+	}
 	// Create a new maintenance request from DTO
 	requestToUpdate := &db.MaintenanceRequest{
 		WorkDefinition: request.WorkDefinition,
@@ -92,6 +152,28 @@ func (s *maintenanceRequestService) Update(id int, request *models.UpdateMainten
 		Tax:            request.Tax,
 		TotalCost:      request.TotalCost,
 		PropertyID:     request.Property.ID,
+		WorkTypeID:     request.WorkType.ID,
+	}
+
+	// Find current request to determine if it is being escalated or moved to another property
+	currentRequest, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	previousScale := currentRequest.Scale
+
+	// Ensure the unit belongs to the property, including the current unit when the property changes
+	propertyId := currentRequest.PropertyID
+	if request.Property.ID != 0 {
+		propertyId = request.Property.ID
+	}
+	unit := request.Unit
+	if unit.ID == 0 && currentRequest.UnitID != nil {
+		unit.ID = *currentRequest.UnitID
+	}
+	requestToUpdate.UnitID, err = findPropertyUnit(s.units, unit, propertyId)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update using repo
@@ -100,5 +182,123 @@ func (s *maintenanceRequestService) Update(id int, request *models.UpdateMainten
 		return nil, err
 	}
 
+	// Notify task assignees if escalated to a more urgent scale
+	if maintenanceScaleRank(updatedRequest.Scale) > maintenanceScaleRank(previousScale) && previousScale != "" {
+		s.notification.Dispatch(&models.NotificationEvent{
+			Type:    "MaintenanceEscalated",
+			Message: fmt.Sprintf("Maintenance request #%d escalated from %s to %s", updatedRequest.ID, previousScale, updatedRequest.Scale),
+			TaskID:  updatedRequest.TaskID,
+		})
+	}
+
 	return updatedRequest, nil
 }
+
+// Ranks maintenance request scales by urgency (higher is more urgent)
+func maintenanceScaleRank(scale string) int {
+	switch scale {
+	case "Low":
+		return 1
+	case "Medium":
+		return 2
+	case "High":
+		return 3
+	case "Urgent":
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Ranks vendors offering a maintenance request's work type for dispatch by proximity to the property,
+// on time completion of past work orders, ratings and current open workload. All vendors are ranked for
+// requests without a work type
+func (s *maintenanceRequestService) SuggestVendors(id int) (*[]models.SuggestedVendor, error) {
+	// Find request with its property and work type
+	request, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := s.vendors.FindWithWorkload(int(request.WorkTypeID))
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []models.SuggestedVendor{}
+	for _, workload := range *workloads {
+		suggestion := models.SuggestedVendor{
+			VendorID:            workload.Vendor.ID,
+			CompanyName:         workload.Vendor.CompanyName,
+			Phone:               workload.Vendor.Phone,
+			Email:               workload.Vendor.Email,
+			WorkTypeMatch:       request.WorkTypeID != 0,
+			Proximity:           vendorProximity(&workload.Vendor, &request.Property),
+			CompletedWorkOrders: workload.CompletedWorkOrders,
+			OpenWorkOrders:      workload.OpenWorkOrders,
+			Ratings:             workload.Ratings,
+			AverageRating:       roundTo2(workload.AverageRating),
+		}
+		if workload.ScheduledCompletions > 0 {
+			suggestion.OnTimeRate = roundTo2(float64(workload.OnTimeCompletions) / float64(workload.ScheduledCompletions))
+		}
+
+		suggestion.Score = vendorSuggestionScore(&suggestion, workload.ScheduledCompletions > 0)
+		suggestions = append(suggestions, suggestion)
+	}
+
+	// Best score first, then least busy
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].OpenWorkOrders < suggestions[j].OpenWorkOrders
+	})
+	return &suggestions, nil
+}
+
+// Finds the closest location a vendor shares with a property
+func vendorProximity(vendor *db.Vendor, property *db.Property) string {
+	sameCity := property.City != "" && strings.EqualFold(strings.TrimSpace(vendor.City), strings.TrimSpace(property.City))
+	switch {
+	case property.Suburb != "" && strings.EqualFold(strings.TrimSpace(vendor.Suburb), strings.TrimSpace(property.Suburb)):
+		return "Suburb"
+	case sameCity:
+		return "City"
+	case property.Province != "" && strings.EqualFold(vendor.Province, property.Province):
+		return "Province"
+	default:
+		return "None"
+	}
+}
+
+// Scores a suggested vendor out of 100. Vendors without scheduled work history or ratings get neutral
+// performance and rating scores
+func vendorSuggestionScore(suggestion *models.SuggestedVendor, hasHistory bool) float64 {
+	score := 0.0
+	if suggestion.WorkTypeMatch {
+		score += 40
+	}
+	switch suggestion.Proximity {
+	case "Suburb":
+		score += 20
+	case "City":
+		score += 12
+	case "Province":
+		score += 6
+	}
+	if hasHistory {
+		score += 15 * suggestion.OnTimeRate
+	} else {
+		score += 7.5
+	}
+	if suggestion.Ratings > 0 {
+		score += 3 * suggestion.AverageRating
+	} else {
+		score += 7.5
+	}
+	// Up to 10 points for spare capacity, less for each open work order
+	if suggestion.OpenWorkOrders < 5 {
+		score += float64(10 - 2*suggestion.OpenWorkOrders)
+	}
+	return roundTo2(score)
+}
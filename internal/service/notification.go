@@ -0,0 +1,303 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Number of attempts made on each channel before a notification is dead lettered
+const maxDeliveryAttempts = 3
+
+// Delay before retrying a failed delivery (multiplied by attempt number)
+var deliveryRetryBackoff = 2 * time.Second
+
+// Recorded on dead letters of events dispatched while the delivery queue is full
+var errNotificationQueueFull = errors.New("notification queue full")
+
+type NotificationService interface {
+	// Inbox
+	FindAllByUser(int, int, int, string, bool) (*[]db.Notification, error)
+	FindById(int) (*db.Notification, error)
+	SetReadState(int, bool) (*db.Notification, error)
+	Delete(int) error
+	// Queues an event for asynchronous delivery to its recipients
+	Dispatch(*models.NotificationEvent)
+	// Blocks until all queued events have been delivered
+	Wait()
+	// Preferences
+	FindPreferences(int) (*[]db.NotificationPreference, error)
+	UpdatePreference(int, *models.UpdateNotificationPreference) (*db.NotificationPreference, error)
+	// Failed deliveries
+	FindAllDeadLetters(int, int, string) (*[]db.NotificationDeadLetter, error)
+}
+
+type notificationService struct {
+	repo      repository.NotificationRepository
+	users     repository.UserRepository
+	tasks     repository.TaskRepository
+	notifiers []Notifier
+	// Delivery queue
+	queue   chan *models.NotificationEvent
+	pending sync.WaitGroup
+}
+
+// Builds notification service and starts delivery worker. Events are delivered through each notifier (channel)
+func NewNotificationService(repo repository.NotificationRepository, users repository.UserRepository, tasks repository.TaskRepository, notifiers ...Notifier) NotificationService {
+	s := &notificationService{
+		repo:      repo,
+		users:     users,
+		tasks:     tasks,
+		notifiers: notifiers,
+		queue:     make(chan *models.NotificationEvent, 100),
+	}
+	// Start delivery worker
+	go s.work()
+	return s
+}
+
+// Queues an event for asynchronous delivery to its recipients. Never blocks: events dispatched while the queue is
+// full are dead lettered
+func (s *notificationService) Dispatch(event *models.NotificationEvent) {
+	s.pending.Add(1)
+	select {
+	case s.queue <- event:
+	default:
+		go func() {
+			defer s.pending.Done()
+			s.forEachDelivery(event, func(notifier Notifier, recipient *db.User, preference db.NotificationPreference) {
+				s.storeDeadLetter(notifier, recipient, event, 0, errNotificationQueueFull)
+			})
+		}()
+	}
+}
+
+// Blocks until all queued events have been delivered
+func (s *notificationService) Wait() {
+	s.pending.Wait()
+}
+
+// Find a list of notifications belonging to a user
+func (s *notificationService) FindAllByUser(userId int, limit int, offset int, order string, unreadOnly bool) (*[]db.Notification, error) {
+	notifications, err := s.repo.FindAllByUser(userId, limit, offset, order, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// Find notification in database by ID
+func (s *notificationService) FindById(id int) (*db.Notification, error) {
+	// Find notification by id
+	notification, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return notification, nil
+}
+
+// Delete notification in database
+func (s *notificationService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting notification: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Marks a notification as read or unread
+func (s *notificationService) SetReadState(id int, read bool) (*db.Notification, error) {
+	// Update using repo
+	updatedNotification, err := s.repo.SetReadState(id, read)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedNotification, nil
+}
+
+// Returns the user's preference for every event type (defaults used where not set)
+func (s *notificationService) FindPreferences(userId int) (*[]db.NotificationPreference, error) {
+	// Find stored preferences
+	stored, err := s.repo.FindPreferencesByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	// Map by event type
+	storedByType := make(map[string]db.NotificationPreference)
+	for _, preference := range *stored {
+		storedByType[preference.EventType] = preference
+	}
+
+	// Build full list of preferences
+	preferences := []db.NotificationPreference{}
+	for _, eventType := range models.NotificationEventTypes {
+		preference, ok := storedByType[eventType]
+		if !ok {
+			preference = defaultNotificationPreference(uint(userId), eventType)
+		}
+		preferences = append(preferences, preference)
+	}
+	return &preferences, nil
+}
+
+// Updates the user's delivery preference for an event type
+func (s *notificationService) UpdatePreference(userId int, preference *models.UpdateNotificationPreference) (*db.NotificationPreference, error) {
+	// Create db preference type from DTO
+	preferenceToUpdate := db.NotificationPreference{
+		UserID:     uint(userId),
+		EventType:  preference.EventType,
+		InApp:      preference.InApp,
+		Email:      preference.Email,
+		Webhook:    preference.Webhook,
+		WebhookURL: preference.WebhookURL,
+	}
+
+	// Update using repo
+	updatedPreference, err := s.repo.UpsertPreference(&preferenceToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedPreference, nil
+}
+
+// Find a list of notifications that failed delivery
+func (s *notificationService) FindAllDeadLetters(limit int, offset int, order string) (*[]db.NotificationDeadLetter, error) {
+	deadLetters, err := s.repo.FindAllDeadLetters(limit, offset, order)
+	if err != nil {
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+// Delivery worker: delivers queued events until queue is closed. Each channel is delivered (and retried) separately
+// so a slow channel doesn't hold up the queue
+func (s *notificationService) work() {
+	for event := range s.queue {
+		s.forEachDelivery(event, func(notifier Notifier, recipient *db.User, preference db.NotificationPreference) {
+			s.pending.Add(1)
+			go func() {
+				defer s.pending.Done()
+				s.deliverWithRetry(notifier, recipient, &preference, event)
+			}()
+		})
+		s.pending.Done()
+	}
+}
+
+// Calls send for each recipient of an event and each channel enabled in their preferences
+func (s *notificationService) forEachDelivery(event *models.NotificationEvent, send func(Notifier, *db.User, db.NotificationPreference)) {
+	// Determine recipients
+	recipientIDs, err := s.recipients(event)
+	if err != nil {
+		fmt.Printf("Failed to determine recipients of %s notification: %v\n", event.Type, err)
+		return
+	}
+
+	for _, recipientID := range recipientIDs {
+		// Actor is not notified of their own actions
+		if recipientID == event.ActorID {
+			continue
+		}
+		// Find recipient details
+		recipient, err := s.users.FindById(int(recipientID))
+		if err != nil {
+			fmt.Printf("Failed to find recipient %d of %s notification: %v\n", recipientID, event.Type, err)
+			continue
+		}
+		// Find recipient's preference for event type
+		preference := s.preference(recipientID, event.Type)
+
+		// Send through each enabled channel
+		for _, notifier := range s.notifiers {
+			if !notifier.Enabled(&preference) {
+				continue
+			}
+			send(notifier, recipient, preference)
+		}
+	}
+}
+
+// Attempts delivery through a channel, retrying on failure. Stores a dead letter if all attempts fail
+func (s *notificationService) deliverWithRetry(notifier Notifier, recipient *db.User, preference *db.NotificationPreference, event *models.NotificationEvent) {
+	var err error
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		err = notifier.Notify(recipient, preference, event)
+		if err == nil {
+			return
+		}
+		fmt.Printf("%s notification delivery to user %d failed (attempt %d): %v\n", notifier.Channel(), recipient.ID, attempt, err)
+		// Wait before retrying
+		if attempt < maxDeliveryAttempts {
+			time.Sleep(deliveryRetryBackoff * time.Duration(attempt))
+		}
+	}
+
+	// Store failed delivery
+	s.storeDeadLetter(notifier, recipient, event, maxDeliveryAttempts, err)
+}
+
+// Stores a delivery that failed or wasn't attempted
+func (s *notificationService) storeDeadLetter(notifier Notifier, recipient *db.User, event *models.NotificationEvent, attempts int, err error) {
+	_, dlErr := s.repo.CreateDeadLetter(&db.NotificationDeadLetter{
+		UserID:    recipient.ID,
+		EventType: event.Type,
+		Channel:   notifier.Channel(),
+		Message:   event.Message,
+		TaskID:    event.TaskID,
+		Attempts:  attempts,
+		LastError: err.Error(),
+	})
+	if dlErr != nil {
+		fmt.Println("Failed to store notification dead letter: ", dlErr)
+	}
+}
+
+// Returns the event's recipients, or the assignees of the event's task if none provided
+func (s *notificationService) recipients(event *models.NotificationEvent) ([]uint, error) {
+	if len(event.UserIDs) > 0 || event.TaskID == 0 {
+		return event.UserIDs, nil
+	}
+	// Find task assignees
+	task, err := s.tasks.FindById(int(event.TaskID))
+	if err != nil {
+		return nil, err
+	}
+	recipientIDs := []uint{}
+	for _, user := range task.Assignment {
+		recipientIDs = append(recipientIDs, user.ID)
+	}
+	return recipientIDs, nil
+}
+
+// Returns the user's stored preference for the event type or the default preference
+func (s *notificationService) preference(userId uint, eventType string) db.NotificationPreference {
+	stored, err := s.repo.FindPreferencesByUser(int(userId))
+	if err == nil {
+		for _, preference := range *stored {
+			if preference.EventType == eventType {
+				return preference
+			}
+		}
+	}
+	return defaultNotificationPreference(userId, eventType)
+}
+
+// Default preference: in app notifications only
+func defaultNotificationPreference(userId uint, eventType string) db.NotificationPreference {
+	return db.NotificationPreference{
+		UserID:    userId,
+		EventType: eventType,
+		InApp:     true,
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Delivery channel for notifications
+type Notifier interface {
+	// Name of the channel (matches NotificationDeadLetter.Channel)
+	Channel() string
+	// Whether the recipient has enabled this channel in their preference
+	Enabled(preference *db.NotificationPreference) bool
+	// Delivers the event to the recipient
+	Notify(recipient *db.User, preference *db.NotificationPreference, event *models.NotificationEvent) error
+}
+
+// In app (database inbox) notifications
+type inAppNotifier struct {
+	repo repository.NotificationRepository
+}
+
+func NewInAppNotifier(repo repository.NotificationRepository) Notifier {
+	return &inAppNotifier{repo}
+}
+
+func (n *inAppNotifier) Channel() string {
+	return "InApp"
+}
+
+func (n *inAppNotifier) Enabled(preference *db.NotificationPreference) bool {
+	return preference.InApp
+}
+
+// Stores the notification in the recipient's inbox
+func (n *inAppNotifier) Notify(recipient *db.User, preference *db.NotificationPreference, event *models.NotificationEvent) error {
+	_, err := n.repo.Create(&db.Notification{
+		UserID:        recipient.ID,
+		Type:          event.Type,
+		Message:       event.Message,
+		TaskID:        event.TaskID,
+		TaskCommentID: event.TaskCommentID,
+	})
+	return err
+}
+
+// Email notifications (sent using SMTP server from environment variables)
+type emailNotifier struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewEmailNotifier() Notifier {
+	return &emailNotifier{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     os.Getenv("SMTP_PORT"),
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+func (n *emailNotifier) Channel() string {
+	return "Email"
+}
+
+func (n *emailNotifier) Enabled(preference *db.NotificationPreference) bool {
+	return preference.Email
+}
+
+// Sends the notification to the recipient's email address
+func (n *emailNotifier) Notify(recipient *db.User, preference *db.NotificationPreference, event *models.NotificationEvent) error {
+	if n.host == "" {
+		return fmt.Errorf("smtp server not configured")
+	}
+	if recipient.Email == "" {
+		return fmt.Errorf("user %d has no email address", recipient.ID)
+	}
+
+	// Build email message
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", n.from, recipient.Email, notificationSubject(event.Type), event.Message)
+	// Authenticate with server if credentials provided
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+
+	return smtp.SendMail(fmt.Sprintf("%s:%s", n.host, n.port), auth, n.from, []string{recipient.Email}, []byte(message))
+}
+
+// Generic webhook notifications (JSON POST to the URL in user preference or environment)
+type webhookNotifier struct {
+	defaultURL string
+	client     *http.Client
+	// Client for URLs set by users, which can only connect to public addresses
+	publicClient *http.Client
+}
+
+func NewWebhookNotifier() Notifier {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		// Checked after the host is resolved so hosts can't point at internal addresses
+		Control: func(network string, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !helpers.IsPublicIP(ip) {
+				return fmt.Errorf("webhook address %s is not public", host)
+			}
+			return nil
+		},
+	}
+	return &webhookNotifier{
+		defaultURL:   os.Getenv("NOTIFICATION_WEBHOOK_URL"),
+		client:       &http.Client{Timeout: 10 * time.Second},
+		publicClient: &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{DialContext: dialer.DialContext}},
+	}
+}
+
+func (n *webhookNotifier) Channel() string {
+	return "Webhook"
+}
+
+func (n *webhookNotifier) Enabled(preference *db.NotificationPreference) bool {
+	return preference.Webhook
+}
+
+// Posts the notification as JSON to the webhook URL
+func (n *webhookNotifier) Notify(recipient *db.User, preference *db.NotificationPreference, event *models.NotificationEvent) error {
+	// Use preference URL if set, else default URL (configured by admins, so it may be internal)
+	url, client := preference.WebhookURL, n.publicClient
+	if url != "" && !helpers.IsPublicWebhookURL(url) {
+		return fmt.Errorf("webhook url must be https on a public host")
+	}
+	if url == "" {
+		url, client = n.defaultURL, n.client
+	}
+	if url == "" {
+		return fmt.Errorf("no webhook url configured")
+	}
+
+	// Build JSON payload
+	payload, err := json.Marshal(map[string]interface{}{
+		"event_type":      event.Type,
+		"message":         event.Message,
+		"user_id":         recipient.ID,
+		"username":        recipient.Username,
+		"task_id":         event.TaskID,
+		"task_comment_id": event.TaskCommentID,
+		"sent_at":         time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Post to webhook
+	response, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// Any non 2xx response is treated as a failure
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// Builds a readable subject line from an event type
+func notificationSubject(eventType string) string {
+	switch eventType {
+	case "Mention":
+		return "You were mentioned"
+	case "TaskAssigned":
+		return "Task assigned to you"
+	case "SnoozeExpired":
+		return "Snoozed task is due"
+	case "MaintenanceEscalated":
+		return "Maintenance request escalated"
+	case "TransactionCompleted":
+		return "Transaction completed"
+	case "VendorNonCompliant":
+		return "Non-compliant vendor assigned"
+	case "VendorDocumentExpiring":
+		return "Vendor document expiring"
+	case "MaintenanceBudgetExceeded":
+		return "Maintenance budget exceeded"
+	case "TenantRequestSubmitted":
+		return "New tenant maintenance request"
+	case "LeaseExpiring":
+		return "Lease expiring"
+	default:
+		return "Notification"
+	}
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when changing a statement that has been finalised
+var ErrStatementFinalised = errors.New("owner statement has been finalised")
+
+// Returned when generating a statement for a property that isn't managed
+var ErrPropertyNotManaged = errors.New("owner statements are only generated for managed properties")
+
+// Returned when finalising a statement before the statement it carries a balance forward from
+var ErrPreviousStatementDraft = errors.New("the previous month's owner statement must be finalised first")
+
+type OwnerStatementService interface {
+	FindAll(int, int, string, int, int) (*[]db.OwnerStatement, error)
+	FindById(int) (*db.OwnerStatement, error)
+	// Generates a property's statement for a month, regenerating it if it's still a draft
+	Generate(*models.GenerateOwnerStatement) (*db.OwnerStatement, error)
+	Update(int, *models.UpdateOwnerStatement) (*db.OwnerStatement, error)
+	Delete(int) error
+	// Regenerates a draft statement with the latest figures and locks it
+	Finalise(int) (*db.OwnerStatement, error)
+	// Statement as a PDF document with its file name
+	PDF(int, string) ([]byte, string, error)
+	// Statement as CSV with its file name
+	CSV(int, string) ([]byte, string, error)
+	// Generates last month's statements of managed properties (scheduled daily)
+	ProcessMonthlyStatements() error
+}
+
+type ownerStatementService struct {
+	repo       repository.OwnerStatementRepository
+	properties repository.PropertyRepository
+	rates      ExchangeRateService
+}
+
+func NewOwnerStatementService(repo repository.OwnerStatementRepository, properties repository.PropertyRepository, rates ExchangeRateService) OwnerStatementService {
+	return &ownerStatementService{repo, properties, rates}
+}
+
+// Generates a property's statement for a month, regenerating it if it's still a draft
+func (s *ownerStatementService) Generate(request *models.GenerateOwnerStatement) (*db.OwnerStatement, error) {
+	// Ensure property exists
+	property, err := s.properties.FindById(int(request.Property.ID))
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !property.Managed {
+		return nil, ErrPropertyNotManaged
+	}
+	statement, err := s.generate(property.ID, request.Year, request.Month)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindById(int(statement.ID))
+}
+
+// Find a list of owner statements
+func (s *ownerStatementService) FindAll(limit int, offset int, order string, propertyId int, year int) (*[]db.OwnerStatement, error) {
+	statements, err := s.repo.FindAll(limit, offset, order, propertyId, year)
+	if err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// Find owner statement in database by ID
+func (s *ownerStatementService) FindById(id int) (*db.OwnerStatement, error) {
+	// Find by id
+	statement, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return statement, nil
+}
+
+// Delete a draft owner statement in database
+func (s *ownerStatementService) Delete(id int) error {
+	statement, err := s.repo.FindById(id)
+	if err != nil {
+		return err
+	}
+	if statement.Status == "Final" {
+		return ErrStatementFinalised
+	}
+	err = s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting owner statement: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Records the payment to the owner of a draft statement and updates the closing balance
+func (s *ownerStatementService) Update(id int, update *models.UpdateOwnerStatement) (*db.OwnerStatement, error) {
+	statement, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if statement.Status == "Final" {
+		return nil, ErrStatementFinalised
+	}
+	if !statement.NetPayable.SameCurrency(update.PaidToOwner) {
+		return nil, fmt.Errorf("%w: payment must be in %s", db.ErrCurrencyMismatch, statement.NetPayable.Code())
+	}
+	statement.PaidToOwner = update.PaidToOwner
+	if update.PaymentReference != "" {
+		statement.PaymentReference = update.PaymentReference
+	}
+	if update.Notes != "" {
+		statement.Notes = update.Notes
+	}
+	statement.ClosingBalance = statement.OpeningBalance.Add(statement.NetPayable).Sub(statement.PaidToOwner)
+	return s.repo.Update(id, statement)
+}
+
+// Regenerates a draft statement with the latest figures and locks it. The previous month's statement must be final
+func (s *ownerStatementService) Finalise(id int) (*db.OwnerStatement, error) {
+	statement, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if statement.Status == "Final" {
+		return nil, ErrStatementFinalised
+	}
+	year, month := previousMonth(statement.Year, statement.Month)
+	if previous, err := s.repo.FindByPeriod(statement.PropertyID, year, month); err == nil && previous.Status != "Final" {
+		return nil, ErrPreviousStatementDraft
+	}
+
+	statement, err = s.generate(statement.PropertyID, statement.Year, statement.Month)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	statement.Status = "Final"
+	statement.FinalisedAt = &now
+	return s.repo.Update(int(statement.ID), statement)
+}
+
+// Generates last month's statements of managed properties that don't have one
+func (s *ownerStatementService) ProcessMonthlyStatements() error {
+	now := time.Now()
+	year, month := previousMonth(now.Year(), int(now.Month()))
+	properties, err := s.repo.FindManagedPropertiesWithoutStatement(year, month)
+	if err != nil {
+		return err
+	}
+	for _, property := range *properties {
+		_, err = s.generate(property.ID, year, month)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Statement as a PDF document with its file name, with amounts in the reporting currency
+func (s *ownerStatementService) PDF(id int, currency string) ([]byte, string, error) {
+	statement, conversion, err := s.exportStatement(id, currency)
+	if err != nil {
+		return nil, "", err
+	}
+	title := fmt.Sprintf("Owner Statement %s %d", time.Month(statement.Month), statement.Year)
+	lines := []helpers.PDFLine{
+		{Text: title, Size: 16, Font: helpers.PDFBold},
+		{Text: statement.Property.Property_Name, Size: 12, Font: helpers.PDFBold},
+		{Text: fmt.Sprintf("%s, %s, %s %d", statement.Property.Street_Address_1, statement.Property.Suburb, statement.Property.City, statement.Property.Postcode)},
+	}
+	if owners := propertyOwners(statement.Property); len(owners) > 0 {
+		lines = append(lines, helpers.PDFLine{Text: "Owner: " + strings.Join(owners, ", ")})
+	}
+	lines = append(lines, helpers.PDFLine{Text: fmt.Sprintf("Status: %s", statement.Status)})
+	if conversion != "" {
+		lines = append(lines, helpers.PDFLine{Text: conversion})
+	}
+	lines = append(lines, helpers.PDFLine{})
+
+	// Statement lines in fixed width columns
+	lines = append(lines, helpers.PDFLine{Text: fmt.Sprintf("%-11s %-15s %-38s %18s", "Date", "Category", "Description", "Amount"), Font: helpers.PDFMono, Size: 8})
+	for _, line := range statement.Lines {
+		description := line.Description
+		if line.Reference != "" {
+			description = fmt.Sprintf("%s (%s)", description, line.Reference)
+		}
+		if len(description) > 38 {
+			description = description[:35] + "..."
+		}
+		lines = append(lines, helpers.PDFLine{Text: fmt.Sprintf("%-11s %-15s %-38s %18s", line.Date.Format("02 Jan 2006"), line.Category, description, line.Amount.Format()), Font: helpers.PDFMono, Size: 8})
+	}
+	lines = append(lines, helpers.PDFLine{})
+
+	// Summary
+	for _, row := range statementSummary(statement, db.Money.Format) {
+		font := helpers.PDFMono
+		if row[0] == "Net payable" || row[0] == "Closing balance" {
+			font = helpers.PDFBold
+		}
+		lines = append(lines, helpers.PDFLine{Text: fmt.Sprintf("%-30s %20s", row[0], row[1]), Font: font, Size: 9})
+	}
+	if statement.PaymentReference != "" {
+		lines = append(lines, helpers.PDFLine{Text: "Payment reference: " + statement.PaymentReference})
+	}
+	if statement.Notes != "" {
+		lines = append(lines, helpers.PDFLine{}, helpers.PDFLine{Text: statement.Notes})
+	}
+	return helpers.RenderTextPDF(title, lines), statementFileName(statement, "pdf"), nil
+}
+
+// Statement as CSV with its file name, with amounts in the reporting currency
+func (s *ownerStatementService) CSV(id int, currency string) ([]byte, string, error) {
+	statement, conversion, err := s.exportStatement(id, currency)
+	if err != nil {
+		return nil, "", err
+	}
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	rows := [][]string{
+		{"Owner statement", statement.Property.Property_Name},
+		{"Period", fmt.Sprintf("%s %d", time.Month(statement.Month), statement.Year)},
+		{"Owner", strings.Join(propertyOwners(statement.Property), "; ")},
+		{"Status", statement.Status},
+	}
+	if conversion != "" {
+		rows = append(rows, []string{"Currency", conversion})
+	}
+	rows = append(rows, []string{}, []string{"Date", "Category", "Description", "Reference", "Amount"})
+	for _, line := range statement.Lines {
+		rows = append(rows, []string{line.Date.Format("2006-01-02"), line.Category, line.Description, line.Reference, line.Amount.String()})
+	}
+	rows = append(rows, []string{})
+	rows = append(rows, statementSummary(statement, db.Money.String)...)
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed writing owner statement CSV: %w", err)
+	}
+	return buffer.Bytes(), statementFileName(statement, "csv"), nil
+}
+
+// Statement to export with its amounts converted into a currency at the rates effective on the statement's last day
+// (or today for the current month). Describes the conversion if the statement was in another currency
+func (s *ownerStatementService) exportStatement(id int, currency string) (*db.OwnerStatement, string, error) {
+	statement, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, "", err
+	}
+	from := statement.NetPayable.Code()
+	if currency == "" || currency == from {
+		return statement, "", nil
+	}
+	on := time.Date(statement.Year, time.Month(statement.Month)+1, 0, 0, 0, 0, 0, time.UTC)
+	if now := time.Now(); on.After(now) {
+		on = now
+	}
+	amounts := []*db.Money{&statement.OpeningBalance, &statement.RentReceived, &statement.ManagementFees, &statement.MaintenanceCosts,
+		&statement.VendorInvoices, &statement.NetPayable, &statement.PaidToOwner, &statement.ClosingBalance}
+	for i := range statement.Lines {
+		amounts = append(amounts, &statement.Lines[i].Amount)
+	}
+	err = s.rates.ConvertAll(currency, on, amounts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return statement, fmt.Sprintf("Amounts in %s converted from %s at rates of %s", currency, from, on.Format("02 Jan 2006")), nil
+}
+
+// Builds the statement of a property for a month, creating it or replacing a draft
+func (s *ownerStatementService) generate(propertyId uint, year int, month int) (*db.OwnerStatement, error) {
+	statement := &db.OwnerStatement{Year: year, Month: month, Status: "Draft", PropertyID: propertyId}
+	existing, err := s.repo.FindByPeriod(propertyId, year, month)
+	if err == nil {
+		if existing.Status == "Final" {
+			return nil, ErrStatementFinalised
+		}
+		// Payment details are kept when regenerating
+		statement = existing
+	}
+	start, end := budgetPeriod(year, month)
+	// Fees and charges are dated on the last day of the month
+	lastDay := end.AddDate(0, 0, -1)
+	lines := []db.OwnerStatementLine{}
+
+	// Balance carried forward
+	statement.OpeningBalance = db.Money{}
+	previousYear, previous := previousMonth(year, month)
+	if previousStatement, err := s.repo.FindByPeriod(propertyId, previousYear, previous); err == nil {
+		statement.OpeningBalance = previousStatement.ClosingBalance
+	}
+
+	// Rent received
+	receipts, err := s.repo.FindRentReceipts(propertyId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	received := []db.Money{}
+	for _, receipt := range *receipts {
+		received = append(received, receipt.Credit)
+		lines = append(lines, db.OwnerStatementLine{Date: receipt.EntryDate, Category: "Rent Received", Description: fallback(receipt.Description, fmt.Sprintf("Rent receipt (lease #%d)", receipt.LeaseID)), Reference: receipt.Reference, Amount: receipt.Credit})
+	}
+	statement.RentReceived, err = db.Sum(received...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Management fee as a percentage of rent received
+	statement.ManagementFeeRate, statement.ManagementFees = 0, db.Money{}
+	if transaction, err := s.repo.FindManagementTransaction(propertyId); err == nil {
+		statement.ManagementFeeRate = managementFeeRate(transaction)
+	}
+	if statement.ManagementFeeRate > 0 {
+		statement.ManagementFees = statement.RentReceived.Percent(statement.ManagementFeeRate)
+		if statement.ManagementFees.Amount > 0 {
+			lines = append(lines, db.OwnerStatementLine{Date: lastDay, Category: "Management Fee", Description: fmt.Sprintf("Management fee (%g%% of rent received)", statement.ManagementFeeRate), Amount: statement.ManagementFees.Mul(-1)})
+		}
+	}
+
+	// Maintenance costs not yet invoiced by vendors
+	requests, err := s.repo.FindUninvoicedRequests(propertyId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	costs := []db.Money{}
+	for _, request := range *requests {
+		cost := request.TotalCost.Add(request.Tax)
+		if cost.Amount <= 0 {
+			continue
+		}
+		costs = append(costs, cost)
+		lines = append(lines, db.OwnerStatementLine{Date: request.CreatedAt, Category: "Maintenance", Description: fmt.Sprintf("%s %s (request #%d)", request.Type, strings.ToLower(request.WorkDefinition), request.ID), Amount: cost.Mul(-1)})
+	}
+	statement.MaintenanceCosts, err = db.Sum(costs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vendor invoices
+	invoices, err := s.repo.FindVendorInvoices(propertyId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	totals := []db.Money{}
+	for _, invoice := range *invoices {
+		totals = append(totals, invoice.Total)
+		lines = append(lines, db.OwnerStatementLine{Date: invoice.InvoiceDate, Category: "Vendor Invoice", Description: fmt.Sprintf("Invoice from %s", invoice.Vendor.CompanyName), Reference: invoice.InvoiceNumber, Amount: invoice.Total.Mul(-1)})
+	}
+	statement.VendorInvoices, err = db.Sum(totals...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Income and spending must be in the same currency to net them off
+	payable, err := db.Sum(statement.RentReceived, statement.ManagementFees.Mul(-1), statement.MaintenanceCosts.Mul(-1), statement.VendorInvoices.Mul(-1))
+	if err != nil {
+		return nil, err
+	}
+	statement.NetPayable = payable
+	closing, err := db.Sum(statement.OpeningBalance, statement.NetPayable, statement.PaidToOwner.Mul(-1))
+	if err != nil {
+		return nil, err
+	}
+	statement.ClosingBalance = closing
+
+	// Save statement and its lines
+	if statement.ID == 0 {
+		statement, err = s.repo.Create(statement)
+	} else {
+		statement.Lines = nil
+		statement, err = s.repo.Update(int(statement.ID), statement)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.ReplaceLines(statement.ID, lines)
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// Percentage of rent charged by a management agreement. Agreements without a rate of their own use the rate of a
+// Percentage commission scheme, otherwise no management fee is charged
+func managementFeeRate(transaction *db.Transaction) float64 {
+	if transaction.ManagementFeeRate > 0 {
+		return transaction.ManagementFeeRate
+	}
+	if transaction.CommissionScheme != nil && transaction.CommissionScheme.Method == "Percentage" {
+		return transaction.CommissionScheme.Rate
+	}
+	return 0
+}
+
+// Summary rows of a statement with amounts formatted for the document
+func statementSummary(statement *db.OwnerStatement, format func(db.Money) string) [][]string {
+	return [][]string{
+		{"Opening balance", format(statement.OpeningBalance)},
+		{"Rent received", format(statement.RentReceived)},
+		{fmt.Sprintf("Management fees (%g%%)", statement.ManagementFeeRate), format(statement.ManagementFees.Mul(-1))},
+		{"Maintenance costs", format(statement.MaintenanceCosts.Mul(-1))},
+		{"Vendor invoices", format(statement.VendorInvoices.Mul(-1))},
+		{"Net payable", format(statement.NetPayable)},
+		{"Paid to owner", format(statement.PaidToOwner.Mul(-1))},
+		{"Closing balance", format(statement.ClosingBalance)},
+	}
+}
+
+// Names of a property's owner contacts
+func propertyOwners(property db.Property) []string {
+	owners := []string{}
+	for _, contact := range property.Contacts {
+		if strings.EqualFold(contact.ContactType, "Owner") {
+			owners = append(owners, strings.TrimSpace(contact.FirstName+" "+contact.LastName))
+		}
+	}
+	return owners
+}
+
+// File name of an exported statement (eg. owner-statement-12-2026-09.pdf)
+func statementFileName(statement *db.OwnerStatement, extension string) string {
+	return fmt.Sprintf("owner-statement-%d-%d-%02d.%s", statement.PropertyID, statement.Year, statement.Month, extension)
+}
+
+// Returns the year and month before a month
+func previousMonth(year int, month int) (int, int) {
+	if month == 1 {
+		return year - 1, 12
+	}
+	return year, month - 1
+}
+
+// Returns the value, or the fallback if it's empty
+func fallback(value string, fallbackValue string) string {
+	if value == "" {
+		return fallbackValue
+	}
+	return value
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when moving a transaction to a stage of another transaction type's pipeline
+var ErrStageTypeMismatch = errors.New("stage belongs to the pipeline of another transaction type")
+
+// Returned when moving a transaction to the stage it's at
+var ErrAlreadyAtStage = errors.New("transaction is already at this stage")
+
+// Returned when deleting a stage transactions have been through
+var ErrStageInUse = errors.New("pipeline stage has transactions")
+
+// Transaction types with a pipeline
+var pipelineTypes = []string{"Sale", "Lease", "Management", "Other"}
+
+type PipelineService interface {
+	FindAllStages(int, int, string, string) (*[]db.PipelineStage, error)
+	FindStageById(int) (*db.PipelineStage, error)
+	CreateStage(*models.CreatePipelineStage) (*db.PipelineStage, error)
+	UpdateStage(int, *models.UpdatePipelineStage) (*db.PipelineStage, error)
+	DeleteStage(int) error
+	// Moves a transaction to a stage of its pipeline on behalf of a user
+	Move(int, *models.MoveTransactionStage, uint) (*db.Transaction, error)
+	FindAllMilestones(int, int, string, int, bool) (*[]db.TransactionMilestone, error)
+	CreateMilestone(*models.CreateTransactionMilestone) (*db.TransactionMilestone, error)
+	UpdateMilestone(int, *models.UpdateTransactionMilestone) (*db.TransactionMilestone, error)
+	DeleteMilestone(int) error
+	// Count and value of transactions per stage, for a transaction type or all types
+	Summary(string, string) (*[]models.PipelineSummary, error)
+}
+
+type pipelineService struct {
+	repo         repository.PipelineRepository
+	transactions TransactionService
+	rates        ExchangeRateService
+}
+
+func NewPipelineService(repo repository.PipelineRepository, transactions TransactionService, rates ExchangeRateService) PipelineService {
+	return &pipelineService{repo, transactions, rates}
+}
+
+// Creates a pipeline stage, at the end of the pipeline if no position is provided
+func (s *pipelineService) CreateStage(stage *models.CreatePipelineStage) (*db.PipelineStage, error) {
+	position := stage.Position
+	if position == 0 {
+		next, err := s.repo.NextStagePosition(stage.TransactionType)
+		if err != nil {
+			return nil, err
+		}
+		position = next
+	}
+	stageToCreate := db.PipelineStage{
+		Name:            stage.Name,
+		TransactionType: stage.TransactionType,
+		Position:        position,
+		DueDays:         stage.DueDays,
+		Completes:       stage.Completes,
+	}
+	return s.repo.CreateStage(&stageToCreate)
+}
+
+// Find a list of pipeline stages
+func (s *pipelineService) FindAllStages(limit int, offset int, order string, transactionType string) (*[]db.PipelineStage, error) {
+	stages, err := s.repo.FindAllStages(limit, offset, order, transactionType)
+	if err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// Find pipeline stage in database by ID
+func (s *pipelineService) FindStageById(id int) (*db.PipelineStage, error) {
+	// Find by id
+	stage, err := s.repo.FindStageById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return stage, nil
+}
+
+// Updates a pipeline stage
+func (s *pipelineService) UpdateStage(id int, stage *models.UpdatePipelineStage) (*db.PipelineStage, error) {
+	stageToUpdate := db.PipelineStage{
+		Name:      stage.Name,
+		Position:  stage.Position,
+		DueDays:   stage.DueDays,
+		Completes: stage.Completes,
+	}
+	return s.repo.UpdateStage(id, &stageToUpdate)
+}
+
+// Delete a pipeline stage that no transactions have been through
+func (s *pipelineService) DeleteStage(id int) error {
+	count, err := s.repo.CountStageTransactions(uint(id))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrStageInUse
+	}
+	err = s.repo.DeleteStage(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting pipeline stage: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Moves a transaction to a stage of its pipeline on behalf of a user. Entering a completing stage completes the transaction
+func (s *pipelineService) Move(id int, move *models.MoveTransactionStage, userId uint) (*db.Transaction, error) {
+	transaction, err := s.transactions.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	stage, err := s.repo.FindStageById(int(move.Stage.ID))
+	if err != nil {
+		return nil, fmt.Errorf("pipeline stage not found: %w", err)
+	}
+	if stage.TransactionType != transaction.Type {
+		return nil, ErrStageTypeMismatch
+	}
+	if transaction.StageID != nil && *transaction.StageID == stage.ID {
+		return nil, ErrAlreadyAtStage
+	}
+
+	now := time.Now()
+	err = enterStage(s.repo, transaction, stage, &userId, move.Notes, now)
+	if err != nil {
+		return nil, err
+	}
+	if stage.Completes && transaction.TransactionCompletion.IsZero() {
+		return s.transactions.Update(id, &models.UpdateTransaction{TransactionCompletion: now})
+	}
+	return s.transactions.FindById(id)
+}
+
+// Find a list of transaction milestones
+func (s *pipelineService) FindAllMilestones(limit int, offset int, order string, transactionId int, overdue bool) (*[]db.TransactionMilestone, error) {
+	milestones, err := s.repo.FindAllMilestones(limit, offset, order, transactionId, overdue)
+	if err != nil {
+		return nil, err
+	}
+	markOverdueMilestones(*milestones, time.Now())
+	return milestones, nil
+}
+
+// Creates a milestone of a transaction
+func (s *pipelineService) CreateMilestone(milestone *models.CreateTransactionMilestone) (*db.TransactionMilestone, error) {
+	// Ensure transaction exists
+	transaction, err := s.transactions.FindById(int(milestone.Transaction.ID))
+	if err != nil {
+		return nil, fmt.Errorf("transaction not found: %w", err)
+	}
+	createdMilestone, err := s.repo.CreateMilestone(&db.TransactionMilestone{Name: milestone.Name, DueDate: milestone.DueDate, TransactionID: transaction.ID})
+	if err != nil {
+		return nil, err
+	}
+	markOverdue(createdMilestone, time.Now())
+	return createdMilestone, nil
+}
+
+// Updates a transaction milestone, completing it if a completion time is provided
+func (s *pipelineService) UpdateMilestone(id int, milestone *models.UpdateTransactionMilestone) (*db.TransactionMilestone, error) {
+	milestoneToUpdate := db.TransactionMilestone{Name: milestone.Name, DueDate: milestone.DueDate}
+	if !milestone.CompletedAt.IsZero() {
+		milestoneToUpdate.CompletedAt = &milestone.CompletedAt
+	}
+	updatedMilestone, err := s.repo.UpdateMilestone(id, &milestoneToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	markOverdue(updatedMilestone, time.Now())
+	return updatedMilestone, nil
+}
+
+// Delete transaction milestone in database
+func (s *pipelineService) DeleteMilestone(id int) error {
+	err := s.repo.DeleteMilestone(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting transaction milestone: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Count and value of transactions per stage, for a transaction type or all types. Values are converted into the
+// reporting currency at today's rates
+func (s *pipelineService) Summary(transactionType string, currency string) (*[]models.PipelineSummary, error) {
+	now := time.Now()
+	types := pipelineTypes
+	if transactionType != "" {
+		types = []string{transactionType}
+	}
+	summaries := []models.PipelineSummary{}
+	for _, pipelineType := range types {
+		stages, err := s.repo.StageTotals(pipelineType)
+		if err != nil {
+			return nil, err
+		}
+		values, err := s.repo.StageValues(pipelineType)
+		if err != nil {
+			return nil, err
+		}
+		summary := models.PipelineSummary{TransactionType: pipelineType, Currency: currency, Stages: *stages}
+		summary.Unstaged, err = s.repo.UnstagedTotals(pipelineType)
+		if err != nil {
+			return nil, err
+		}
+		summary.UnstagedValue, err = s.rates.Total(currency, now, values[0]...)
+		if err != nil {
+			return nil, err
+		}
+		summary.Transactions, summary.Value = summary.Unstaged, summary.UnstagedValue
+		for i, stage := range summary.Stages {
+			summary.Stages[i].Value, err = s.rates.Total(currency, now, values[stage.StageID]...)
+			if err != nil {
+				return nil, err
+			}
+			summary.Transactions += stage.Transactions
+			summary.Value = summary.Value.Add(summary.Stages[i].Value)
+		}
+		summaries = append(summaries, summary)
+	}
+	return &summaries, nil
+}
+
+// Records a transaction entering a stage, completing the milestone of the stage it leaves and setting the new stage's milestone
+func enterStage(repo repository.PipelineRepository, transaction *db.Transaction, stage *db.PipelineStage, userId *uint, notes string, at time.Time) error {
+	if transaction.StageID != nil {
+		err := repo.CompleteStageMilestones(transaction.ID, *transaction.StageID, at)
+		if err != nil {
+			return err
+		}
+	}
+	err := repo.RecordStageChange(&db.TransactionStageChange{
+		ChangedAt:     at,
+		Notes:         notes,
+		TransactionID: transaction.ID,
+		FromStageID:   transaction.StageID,
+		ToStageID:     stage.ID,
+		UserID:        userId,
+	})
+	if err != nil {
+		return err
+	}
+	if stage.DueDays > 0 {
+		_, err = repo.CreateMilestone(&db.TransactionMilestone{
+			Name:            stage.Name,
+			DueDate:         at.AddDate(0, 0, stage.DueDays),
+			TransactionID:   transaction.ID,
+			PipelineStageID: &stage.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	transaction.StageID = &stage.ID
+	transaction.StageEnteredAt = &at
+	return nil
+}
+
+// Flags milestones past their due date that haven't been completed
+func markOverdueMilestones(milestones []db.TransactionMilestone, now time.Time) {
+	for i := range milestones {
+		markOverdue(&milestones[i], now)
+	}
+}
+
+// Flags a milestone past its due date that hasn't been completed
+func markOverdue(milestone *db.TransactionMilestone, now time.Time) {
+	milestone.Overdue = milestone.CompletedAt == nil && milestone.DueDate.Before(now)
+}
@@ -32,6 +32,7 @@ func (s *propertyService) Create(prop *models.CreateProperty) (*db.Property, err
 		Property_Name:    prop.Property_Name,
 		Suburb:           prop.Suburb,
 		City:             prop.City,
+		Province:         prop.Province,
 		Street_Address_1: prop.Street_Address_1,
 		Street_Address_2: prop.Street_Address_2,
 		Bedrooms:         prop.Bedrooms,
@@ -96,6 +97,7 @@ func (s *propertyService) Update(id int, prop *models.UpdateProperty) (*db.Prope
 		Property_Name:    prop.Property_Name,
 		Suburb:           prop.Suburb,
 		City:             prop.City,
+		Province:         prop.Province,
 		Street_Address_1: prop.Street_Address_1,
 		Street_Address_2: prop.Street_Address_2,
 		Bedrooms:         prop.Bedrooms,
@@ -1,45 +1,71 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/dmawardi/Go-Template/internal/db"
 	"github.com/dmawardi/Go-Template/internal/models"
 	"github.com/dmawardi/Go-Template/internal/repository"
 )
 
+// Returned when completing a task that requires its subtasks to be complete first
+var ErrIncompleteSubtasks = errors.New("all subtasks must be completed before completing this task")
+
+// Returned when a parent task would create a cycle of subtasks
+var ErrInvalidParentTask = errors.New("a task can't be a subtask of itself or of its own subtasks")
+
+// Returned when making a task active while a task blocking it is incomplete
+var ErrTaskBlocked = errors.New("task is blocked by an incomplete task and can't be made active")
+
+// Returned when deleting a task whose transaction or maintenance request must be kept
+var ErrTaskHasCompletedRecords = errors.New("task's transaction is completed or its maintenance request invoiced, archive the task instead")
+
 type TaskService interface {
 	FindAll(int, int, string) (*[]db.Task, error)
 	FindById(int) (*db.Task, error)
 	Create(*models.CreateTask) (*db.Task, error)
 	Update(int, *models.UpdateTask) (*db.Task, error)
+	// Deletes a task along with its open transaction or maintenance request
 	Delete(int) error
+	// Unsnoozes tasks with an expired snooze date and notifies their assignees
+	ProcessExpiredSnoozes() error
+	// Board
+	// Groups tasks by status (limit per column)
+	Board(int) (*models.TaskBoard, error)
+	// Moves task to a status column and position on the board
+	Move(int, *models.MoveTask) (*db.Task, error)
+	// Finds assigned, overdue, snoozed and recently completed tasks for user
+	FindMyTasks(int) (*models.MyTasks, error)
 }
 
 type taskService struct {
-	repo repository.TaskRepository
+	repo         repository.TaskRepository
+	notification NotificationService
 }
 
-func NewTaskService(repo repository.TaskRepository) TaskService {
-	return &taskService{repo}
+func NewTaskService(repo repository.TaskRepository, notification NotificationService) TaskService {
+	return &taskService{repo, notification}
 }
 
 // Creates a task in the database
 func (s *taskService) Create(task *models.CreateTask) (*db.Task, error) {
-	// Create a new struct of type task
-	taskToCreate := db.Task{
-		TaskName: task.TaskName,
-		Status:   task.Status,
-		Type:     task.Type,
-		Notes:    task.Notes,
+	taskToCreate, err := buildTask(s.repo, task)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create task in database
-	createdTask, err := s.repo.Create(&taskToCreate)
+	createdTask, err := s.repo.Create(taskToCreate)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating task: %w", err)
 	}
 
+	// Notify assignees
+	notifyAssigned(s.notification, createdTask, createdTask.Assignment)
+
 	return createdTask, nil
 }
 
@@ -65,9 +91,17 @@ func (s *taskService) FindById(id int) (*db.Task, error) {
 	return task, nil
 }
 
-// Delete task in database
+// Delete task in database along with its transaction or maintenance request. Tasks with a completed transaction or
+// an invoiced maintenance request can only be archived
 func (s *taskService) Delete(id int) error {
-	err := s.repo.Delete(id)
+	completed, err := s.repo.HasCompletedRecords(id)
+	if err != nil {
+		return err
+	}
+	if completed {
+		return ErrTaskHasCompletedRecords
+	}
+	err = s.repo.Delete(id)
 	// If error detected
 	if err != nil {
 		fmt.Println("error in deleting task: ", err)
@@ -81,11 +115,56 @@ func (s *taskService) Delete(id int) error {
 func (s *taskService) Update(id int, task *models.UpdateTask) (*db.Task, error) {
 	// Create db property type of incoming DTO
 	taskToCreate := db.Task{
-		TaskName:   task.TaskName,
-		Assignment: task.Assignment,
-		Status:     task.Status,
-		Type:       task.Type,
-		Notes:      task.Notes,
+		TaskName:                task.TaskName,
+		Assignment:              task.Assignment,
+		Status:                  task.Status,
+		Type:                    task.Type,
+		Notes:                   task.Notes,
+		Completed:               task.Completed,
+		Snoozed:                 task.Snoozed,
+		SnoozedTill:             task.SnoozedTill,
+		DueDate:                 task.DueDate,
+		RequireSubtasksComplete: task.RequireSubtasksComplete,
+	}
+
+	// Find current task
+	currentTask, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// If moving under a parent, ensure it doesn't create a cycle
+	if task.ParentID != 0 {
+		err := s.checkParent(id, int(task.ParentID))
+		if err != nil {
+			return nil, err
+		}
+		taskToCreate.ParentID = &task.ParentID
+	}
+
+	// If completing, ensure subtasks are complete where required
+	completing := task.Completed || task.Status == "Completed"
+	if completing && (currentTask.RequireSubtasksComplete || task.RequireSubtasksComplete) {
+		for _, subtask := range currentTask.Subtasks {
+			if !repository.TaskIsComplete(&subtask) {
+				return nil, ErrIncompleteSubtasks
+			}
+		}
+	}
+
+	// If activating, ensure no incomplete tasks are blocking
+	if task.Status == "Active" && currentTask.Status != "Active" {
+		for _, dependency := range currentTask.BlockedBy {
+			if repository.DependencyIsBlocking(&dependency) {
+				return nil, ErrTaskBlocked
+			}
+		}
+	}
+
+	// Find current assignees to determine who is newly assigned
+	previouslyAssigned := make(map[uint]bool)
+	for _, user := range currentTask.Assignment {
+		previouslyAssigned[user.ID] = true
 	}
 
 	// Update using repo
@@ -94,5 +173,198 @@ func (s *taskService) Update(id int, task *models.UpdateTask) (*db.Task, error)
 		return nil, err
 	}
 
+	// Notify newly assigned users
+	newlyAssigned := []db.User{}
+	for _, user := range updatedTask.Assignment {
+		if !previouslyAssigned[user.ID] {
+			newlyAssigned = append(newlyAssigned, user)
+		}
+	}
+	notifyAssigned(s.notification, updatedTask, newlyAssigned)
+
 	return updatedTask, nil
 }
+
+// Unsnoozes tasks with an expired snooze date and notifies their assignees
+func (s *taskService) ProcessExpiredSnoozes() error {
+	// Find tasks with expired snooze
+	tasks, err := s.repo.FindExpiredSnoozes(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, task := range *tasks {
+		// Clear snooze
+		err := s.repo.Unsnooze(int(task.ID))
+		if err != nil {
+			return err
+		}
+		// Notify assignees
+		s.notification.Dispatch(&models.NotificationEvent{
+			Type:    "SnoozeExpired",
+			Message: fmt.Sprintf("Snooze on task #%d (%s) has expired", task.ID, task.TaskName),
+			TaskID:  task.ID,
+		})
+	}
+	return nil
+}
+
+// Groups tasks by status in board order, listing up to limit tasks per column
+func (s *taskService) Board(limit int) (*models.TaskBoard, error) {
+	// Count tasks in each column
+	counts, err := s.repo.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	board := models.TaskBoard{Columns: []models.TaskBoardColumn{}}
+	for _, status := range models.TaskBoardStatuses {
+		tasks, err := s.repo.FindByStatus(status, limit)
+		if err != nil {
+			return nil, err
+		}
+		board.Columns = append(board.Columns, models.TaskBoardColumn{
+			Status: status,
+			Count:  counts[strings.ToLower(status)],
+			Tasks:  *tasks,
+		})
+	}
+	return &board, nil
+}
+
+// Moves task to a status column (following the same rules as updates) and ranks it at a position within the column
+func (s *taskService) Move(id int, move *models.MoveTask) (*db.Task, error) {
+	// Find task to move
+	task, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Change status if moving to another column
+	status := task.Status
+	if move.Status != "" && !strings.EqualFold(move.Status, task.Status) {
+		_, err = s.Update(id, &models.UpdateTask{Status: move.Status})
+		if err != nil {
+			return nil, err
+		}
+		status = move.Status
+	}
+
+	// Find other tasks in column
+	column, err := s.repo.FindByStatus(status, 0)
+	if err != nil {
+		return nil, err
+	}
+	rankedIds := []uint{}
+	for _, columnTask := range *column {
+		if columnTask.ID != task.ID {
+			rankedIds = append(rankedIds, columnTask.ID)
+		}
+	}
+
+	// Insert task at position (end of column if not provided or out of range)
+	position := len(rankedIds)
+	if move.Rank > 0 && move.Rank-1 < position {
+		position = move.Rank - 1
+	}
+	rankedIds = append(rankedIds[:position], append([]uint{task.ID}, rankedIds[position:]...)...)
+
+	// Save ranks
+	err = s.repo.SetBoardRanks(rankedIds)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindById(id)
+}
+
+// Finds open, overdue, snoozed and recently completed (last 14 days) tasks assigned to user
+func (s *taskService) FindMyTasks(userId int) (*models.MyTasks, error) {
+	now := time.Now()
+	// Find user's tasks
+	tasks, err := s.repo.FindAssignedToUser(userId, now.AddDate(0, 0, -14))
+	if err != nil {
+		return nil, err
+	}
+
+	myTasks := models.MyTasks{Assigned: []db.Task{}, Overdue: []db.Task{}, Snoozed: []db.Task{}, RecentlyCompleted: []db.Task{}}
+	for _, task := range *tasks {
+		// Completed tasks are only listed as recently completed
+		if repository.TaskIsComplete(&task) {
+			myTasks.RecentlyCompleted = append(myTasks.RecentlyCompleted, task)
+			continue
+		}
+		myTasks.Assigned = append(myTasks.Assigned, task)
+		if !task.DueDate.IsZero() && task.DueDate.Before(now) {
+			myTasks.Overdue = append(myTasks.Overdue, task)
+		}
+		if task.Snoozed {
+			myTasks.Snoozed = append(myTasks.Snoozed, task)
+		}
+	}
+	return &myTasks, nil
+}
+
+// Ensures parent task exists and is not the task itself or one of its subtasks
+func (s *taskService) checkParent(id int, parentId int) error {
+	// Walk up from parent to the top level task
+	for ancestorId := parentId; ancestorId != 0; {
+		if ancestorId == id {
+			return ErrInvalidParentTask
+		}
+		ancestor, err := s.repo.FindById(ancestorId)
+		if err != nil {
+			return fmt.Errorf("parent task not found: %w", err)
+		}
+		if ancestor.ParentID == nil {
+			break
+		}
+		ancestorId = int(*ancestor.ParentID)
+	}
+	return nil
+}
+
+// Sends a task assigned notification to users
+func notifyAssigned(notification NotificationService, task *db.Task, users []db.User) {
+	if len(users) == 0 {
+		return
+	}
+	// Build recipient list
+	recipientIDs := []uint{}
+	for _, user := range users {
+		recipientIDs = append(recipientIDs, user.ID)
+	}
+
+	notification.Dispatch(&models.NotificationEvent{
+		Type:    "TaskAssigned",
+		Message: fmt.Sprintf("You have been assigned to task #%d (%s)", task.ID, task.TaskName),
+		UserIDs: recipientIDs,
+		TaskID:  task.ID,
+	})
+}
+
+// Builds a task from DTO, ensuring its parent exists if a subtask
+func buildTask(repo repository.TaskRepository, task *models.CreateTask) (*db.Task, error) {
+	// Create a new struct of type task
+	taskToCreate := db.Task{
+		TaskName:                task.TaskName,
+		Status:                  task.Status,
+		Type:                    task.Type,
+		Notes:                   task.Notes,
+		Completed:               task.Completed,
+		Snoozed:                 task.Snoozed,
+		SnoozedTill:             task.SnoozedTill,
+		DueDate:                 task.DueDate,
+		RequireSubtasksComplete: task.RequireSubtasksComplete,
+		Assignment:              task.Assignment,
+	}
+
+	// If a subtask, ensure parent exists
+	if task.ParentID != 0 {
+		_, err := repo.FindById(int(task.ParentID))
+		if err != nil {
+			return nil, fmt.Errorf("parent task not found: %w", err)
+		}
+		taskToCreate.ParentID = &task.ParentID
+	}
+	return &taskToCreate, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+type TaskChecklistItemService interface {
+	FindAll(int, int, string, int) (*[]db.TaskChecklistItem, error)
+	FindById(int) (*db.TaskChecklistItem, error)
+	Create(*models.CreateTaskChecklistItem) (*db.TaskChecklistItem, error)
+	// Updates item (id) on behalf of user (id). User is recorded when item is marked done
+	Update(int, int, *models.UpdateTaskChecklistItem) (*db.TaskChecklistItem, error)
+	Delete(int) error
+}
+
+type taskChecklistItemService struct {
+	repo repository.TaskChecklistItemRepository
+}
+
+func NewTaskChecklistItemService(repo repository.TaskChecklistItemRepository) TaskChecklistItemService {
+	return &taskChecklistItemService{repo}
+}
+
+// Creates a task checklist item. Added to the end of the checklist if no position provided
+func (s *taskChecklistItemService) Create(item *models.CreateTaskChecklistItem) (*db.TaskChecklistItem, error) {
+	position := item.Position
+	// If no position, place after last item
+	if position == 0 {
+		maxPosition, err := s.repo.MaxPosition(int(item.Task.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed finding checklist position: %w", err)
+		}
+		position = maxPosition + 1
+	}
+
+	// Create a new item from DTO
+	itemToCreate := db.TaskChecklistItem{
+		Description: item.Description,
+		Position:    position,
+		TaskID:      item.Task.ID,
+	}
+
+	// Create item in database
+	createdItem, err := s.repo.Create(&itemToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating task checklist item: %w", err)
+	}
+
+	return createdItem, nil
+}
+
+// Find a list of task checklist items. Filters by task if task id is not 0
+func (s *taskChecklistItemService) FindAll(limit int, offset int, order string, taskId int) (*[]db.TaskChecklistItem, error) {
+	items, err := s.repo.FindAll(limit, offset, order, taskId)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Find task checklist item in database by ID
+func (s *taskChecklistItemService) FindById(id int) (*db.TaskChecklistItem, error) {
+	// Find item by id
+	item, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return item, nil
+}
+
+// Delete task checklist item in database
+func (s *taskChecklistItemService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting task checklist item: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates task checklist item in database. Records the user and time when marked as done
+func (s *taskChecklistItemService) Update(id int, userId int, item *models.UpdateTaskChecklistItem) (*db.TaskChecklistItem, error) {
+	// Create db item type from DTO
+	itemToUpdate := db.TaskChecklistItem{
+		Description: item.Description,
+		Position:    item.Position,
+	}
+
+	// Update using repo
+	updatedItem, err := s.repo.Update(id, &itemToUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update done state if provided and changed
+	if item.Done != nil && *item.Done != updatedItem.Done {
+		updatedItem, err = s.repo.SetDoneState(id, *item.Done, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedItem, nil
+}
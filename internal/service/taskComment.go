@@ -0,0 +1,205 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a user attempts to edit a comment they did not write
+var ErrNotCommentAuthor = errors.New("only the author can edit a task comment")
+
+type TaskCommentService interface {
+	FindAll(int, int, string, int) (*[]db.TaskComment, error)
+	FindById(int) (*db.TaskComment, error)
+	Create(*models.CreateTaskComment) (*db.TaskComment, error)
+	// Updates comment (id) on behalf of editor (user id)
+	Update(int, int, *models.UpdateTaskComment) (*db.TaskComment, error)
+	Delete(int) error
+}
+
+type taskCommentService struct {
+	repo         repository.TaskCommentRepository
+	users        UserService
+	notification NotificationService
+}
+
+func NewTaskCommentService(repo repository.TaskCommentRepository, users UserService, notification NotificationService) TaskCommentService {
+	return &taskCommentService{repo, users, notification}
+}
+
+// Creates a task comment and notifies any mentioned users
+func (s *taskCommentService) Create(comment *models.CreateTaskComment) (*db.TaskComment, error) {
+	// If a reply, ensure the parent comment belongs to the same task
+	var parentID *uint
+	if comment.ParentID != 0 {
+		parent, err := s.repo.FindById(int(comment.ParentID))
+		if err != nil {
+			return nil, fmt.Errorf("parent comment not found: %w", err)
+		}
+		if parent.TaskID != comment.Task.ID {
+			return nil, fmt.Errorf("parent comment does not belong to task %d", comment.Task.ID)
+		}
+		parentID = &parent.ID
+	}
+
+	// Resolve mentioned users from comment
+	mentions, err := s.resolveMentions(comment.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new comment from DTO
+	commentToCreate := db.TaskComment{
+		Comment:  comment.Comment,
+		UserID:   comment.User.ID,
+		TaskID:   comment.Task.ID,
+		ParentID: parentID,
+		Mentions: mentions,
+	}
+
+	// Create comment in database
+	createdComment, err := s.repo.Create(&commentToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating task comment: %w", err)
+	}
+
+	// Notify mentioned users
+	s.notifyMentions(createdComment, mentions)
+
+	return createdComment, nil
+}
+
+// Find a list of task comments. Filters by task if task id is not 0
+func (s *taskCommentService) FindAll(limit int, offset int, order string, taskId int) (*[]db.TaskComment, error) {
+	comments, err := s.repo.FindAll(limit, offset, order, taskId)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Find task comment in database by ID
+func (s *taskCommentService) FindById(id int) (*db.TaskComment, error) {
+	// Find comment by id
+	comment, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return comment, nil
+}
+
+// Delete task comment in database
+func (s *taskCommentService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting task comment: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates task comment in database. Stores previous version in history and notifies newly mentioned users
+func (s *taskCommentService) Update(id int, editorId int, comment *models.UpdateTaskComment) (*db.TaskComment, error) {
+	// Find existing comment
+	foundComment, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	// Only author may edit
+	if foundComment.UserID != uint(editorId) {
+		return nil, ErrNotCommentAuthor
+	}
+
+	// Store previous version in history
+	_, err = s.repo.CreateEdit(&db.TaskCommentEdit{
+		PreviousComment: foundComment.Comment,
+		TaskCommentID:   foundComment.ID,
+		UserID:          uint(editorId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve mentioned users from updated comment
+	mentions, err := s.resolveMentions(comment.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create db task comment type from DTO
+	commentToUpdate := db.TaskComment{
+		Comment:  comment.Comment,
+		Edited:   true,
+		Mentions: mentions,
+	}
+
+	// Update using repo
+	updatedComment, err := s.repo.Update(id, &commentToUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only notify users that weren't mentioned in the previous version
+	previouslyMentioned := make(map[uint]bool)
+	for _, user := range foundComment.Mentions {
+		previouslyMentioned[user.ID] = true
+	}
+	newMentions := []db.User{}
+	for _, user := range mentions {
+		if !previouslyMentioned[user.ID] {
+			newMentions = append(newMentions, user)
+		}
+	}
+	s.notifyMentions(updatedComment, newMentions)
+
+	return updatedComment, nil
+}
+
+// Parses @username mentions in comment and returns the matching users
+func (s *taskCommentService) resolveMentions(comment string) ([]db.User, error) {
+	// Extract usernames from comment
+	usernames := helpers.ExtractMentions(comment)
+	// Find users with matching usernames
+	users, err := s.users.FindByUsernames(usernames)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving mentions: %w", err)
+	}
+	return *users, nil
+}
+
+// Sends a mention notification to each user (excluding the author of the comment)
+func (s *taskCommentService) notifyMentions(comment *db.TaskComment, users []db.User) {
+	if len(users) == 0 {
+		return
+	}
+	// Grab author name for notification message
+	authorName := "Someone"
+	author, err := s.users.FindById(int(comment.UserID))
+	if err == nil {
+		authorName = author.Username
+	}
+
+	// Build recipient list
+	recipientIDs := []uint{}
+	for _, user := range users {
+		recipientIDs = append(recipientIDs, user.ID)
+	}
+
+	s.notification.Dispatch(&models.NotificationEvent{
+		Type:          "Mention",
+		Message:       fmt.Sprintf("%s mentioned you in a comment on task #%d", authorName, comment.TaskID),
+		UserIDs:       recipientIDs,
+		TaskID:        comment.TaskID,
+		TaskCommentID: comment.ID,
+		ActorID:       comment.UserID,
+	})
+}
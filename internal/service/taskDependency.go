@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a dependency would result in tasks blocking each other
+var ErrDependencyCycle = errors.New("task dependency would create a cycle")
+
+type TaskDependencyService interface {
+	FindAll(int, int, string, int) (*[]db.TaskDependency, error)
+	FindById(int) (*db.TaskDependency, error)
+	Create(*models.CreateTaskDependency) (*db.TaskDependency, error)
+	Delete(int) error
+	// Builds dependency graph of a property's open tasks
+	Graph(int) (*models.TaskDependencyGraph, error)
+}
+
+type taskDependencyService struct {
+	repo  repository.TaskDependencyRepository
+	tasks repository.TaskRepository
+}
+
+func NewTaskDependencyService(repo repository.TaskDependencyRepository, tasks repository.TaskRepository) TaskDependencyService {
+	return &taskDependencyService{repo, tasks}
+}
+
+// Creates a task dependency after ensuring both tasks exist and no cycle is created
+func (s *taskDependencyService) Create(dependency *models.CreateTaskDependency) (*db.TaskDependency, error) {
+	// Ensure both tasks exist
+	_, err := s.tasks.FindById(int(dependency.Task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	_, err = s.tasks.FindById(int(dependency.BlockedBy.ID))
+	if err != nil {
+		return nil, fmt.Errorf("blocking task not found: %w", err)
+	}
+
+	// Ensure task isn't (directly or indirectly) blocking the task that would block it
+	err = s.checkCycle(dependency.Task.ID, dependency.BlockedBy.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new dependency from DTO
+	dependencyToCreate := db.TaskDependency{
+		TaskID:      dependency.Task.ID,
+		BlockedByID: dependency.BlockedBy.ID,
+	}
+
+	// Create dependency in database
+	createdDependency, err := s.repo.Create(&dependencyToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating task dependency: %w", err)
+	}
+
+	return createdDependency, nil
+}
+
+// Find a list of task dependencies. Filters by task if task id is not 0
+func (s *taskDependencyService) FindAll(limit int, offset int, order string, taskId int) (*[]db.TaskDependency, error) {
+	dependencies, err := s.repo.FindAll(limit, offset, order, taskId)
+	if err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// Find task dependency in database by ID
+func (s *taskDependencyService) FindById(id int) (*db.TaskDependency, error) {
+	// Find dependency by id
+	dependency, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return dependency, nil
+}
+
+// Delete task dependency in database
+func (s *taskDependencyService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting task dependency: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Builds dependency graph of a property's open tasks. Blocking tasks outside of the open work are included as nodes
+func (s *taskDependencyService) Graph(propertyId int) (*models.TaskDependencyGraph, error) {
+	// Find property's open tasks
+	tasks, err := s.tasks.FindOpenByProperty(propertyId)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := models.TaskDependencyGraph{Nodes: []models.TaskGraphNode{}, Edges: []models.TaskGraphEdge{}}
+	// Track node positions by task id
+	nodeIndex := make(map[uint]int)
+	addNode := func(task *db.Task) int {
+		if i, ok := nodeIndex[task.ID]; ok {
+			return i
+		}
+		graph.Nodes = append(graph.Nodes, models.TaskGraphNode{ID: task.ID, TaskName: task.TaskName, Type: task.Type, Status: task.Status})
+		nodeIndex[task.ID] = len(graph.Nodes) - 1
+		return nodeIndex[task.ID]
+	}
+
+	for i := range *tasks {
+		task := &(*tasks)[i]
+		taskNode := addNode(task)
+		for _, dependency := range task.BlockedBy {
+			// Skip blocking tasks that have been deleted
+			if dependency.BlockedBy.ID == 0 {
+				continue
+			}
+			addNode(&dependency.BlockedBy)
+			graph.Edges = append(graph.Edges, models.TaskGraphEdge{From: dependency.BlockedByID, To: task.ID})
+			// Blocked while any blocking task is incomplete
+			if repository.DependencyIsBlocking(&dependency) {
+				graph.Nodes[taskNode].Blocked = true
+			}
+		}
+	}
+	return &graph, nil
+}
+
+// Returns an error if the task is the blocking task or already blocks it (directly or indirectly)
+func (s *taskDependencyService) checkCycle(taskId uint, blockedById uint) error {
+	// Walk the tasks blocking the blocking task
+	visited := make(map[uint]bool)
+	toVisit := []uint{blockedById}
+	for len(toVisit) > 0 {
+		current := toVisit[0]
+		toVisit = toVisit[1:]
+		if current == taskId {
+			return ErrDependencyCycle
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		blockerIds, err := s.repo.FindBlockerIds(int(current))
+		if err != nil {
+			return err
+		}
+		toVisit = append(toVisit, blockerIds...)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a tax already has a rule on what it applies to effective on a date
+var ErrTaxRuleExists = errors.New("tax already has a rule for this date")
+
+// Returned when a tax is applied to income or expenses it isn't charged on
+var ErrInvalidTaxRule = errors.New("PPN applies to commission and vendor invoices, PPh 4(2) to rent and PPh 23 to vendor invoices")
+
+// What each tax can be applied to
+var taxAppliesTo = map[string][]string{
+	"PPN":      {"Commission", "Vendor Invoice"},
+	"PPh 4(2)": {"Rent"},
+	"PPh 23":   {"Vendor Invoice"},
+}
+
+type TaxService interface {
+	FindAllRules(int, int, string, string, string) (*[]db.TaxRule, error)
+	FindRuleById(int) (*db.TaxRule, error)
+	CreateRule(*models.CreateTaxRule) (*db.TaxRule, error)
+	UpdateRule(int, *models.UpdateTaxRule) (*db.TaxRule, error)
+	DeleteRule(int) error
+	// Rules of each tax on rent, commission or vendor invoices in effect on a date
+	Rules(string, time.Time) ([]db.TaxRule, error)
+	// Tax lines on an amount of rent, commission or vendor invoices dated on a date
+	Lines(string, db.Money, time.Time) ([]db.TaxLine, error)
+	// Tax computed over a month
+	Summary(int, int, string) (*models.TaxSummary, error)
+}
+
+type taxService struct {
+	repo  repository.TaxRepository
+	rates ExchangeRateService
+}
+
+func NewTaxService(repo repository.TaxRepository, rates ExchangeRateService) TaxService {
+	return &taxService{repo, rates}
+}
+
+// Creates a tax rule, effective from a date until a later rule of the same tax
+func (s *taxService) CreateRule(rule *models.CreateTaxRule) (*db.TaxRule, error) {
+	if !taxApplies(rule.Tax, rule.AppliesTo) {
+		return nil, ErrInvalidTaxRule
+	}
+	effectiveDate := rateDate(rule.EffectiveDate)
+	if _, err := s.repo.FindRuleByDate(rule.Tax, rule.AppliesTo, effectiveDate); err == nil {
+		return nil, ErrTaxRuleExists
+	}
+	ruleToCreate := db.TaxRule{
+		Tax:           rule.Tax,
+		AppliesTo:     rule.AppliesTo,
+		Rate:          rule.Rate,
+		Withheld:      rule.Withheld,
+		EffectiveDate: effectiveDate,
+		Notes:         rule.Notes,
+	}
+	return s.repo.CreateRule(&ruleToCreate)
+}
+
+// Find a list of tax rules. Filters by tax and what it applies to if provided
+func (s *taxService) FindAllRules(limit int, offset int, order string, tax string, appliesTo string) (*[]db.TaxRule, error) {
+	rules, err := s.repo.FindAllRules(limit, offset, order, tax, appliesTo)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Find tax rule in database by ID
+func (s *taxService) FindRuleById(id int) (*db.TaxRule, error) {
+	// Find by id
+	rule, err := s.repo.FindRuleById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return rule, nil
+}
+
+// Delete tax rule in database. Tax already calculated with the rule is kept
+func (s *taxService) DeleteRule(id int) error {
+	err := s.repo.DeleteRule(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting tax rule: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates the rate, effective date or notes of a tax rule. Tax already calculated with the rule is kept
+func (s *taxService) UpdateRule(id int, rule *models.UpdateTaxRule) (*db.TaxRule, error) {
+	foundRule, err := s.repo.FindRuleById(id)
+	if err != nil {
+		return nil, err
+	}
+	ruleToUpdate := &db.TaxRule{Rate: rule.Rate, Notes: rule.Notes}
+	if !rule.EffectiveDate.IsZero() {
+		ruleToUpdate.EffectiveDate = rateDate(rule.EffectiveDate)
+		if existing, err := s.repo.FindRuleByDate(foundRule.Tax, foundRule.AppliesTo, ruleToUpdate.EffectiveDate); err == nil && existing.ID != foundRule.ID {
+			return nil, ErrTaxRuleExists
+		}
+	}
+	return s.repo.UpdateRule(id, ruleToUpdate)
+}
+
+// Rules of each tax on rent, commission or vendor invoices in effect on a date
+func (s *taxService) Rules(appliesTo string, on time.Time) ([]db.TaxRule, error) {
+	rules, err := s.repo.FindEffectiveRules(appliesTo, on)
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+// Tax lines on an amount of rent, commission or vendor invoices dated on a date. Taxes with a zero rate are left out
+func (s *taxService) Lines(appliesTo string, base db.Money, on time.Time) ([]db.TaxLine, error) {
+	rules, err := s.Rules(appliesTo, on)
+	if err != nil {
+		return nil, err
+	}
+	lines := []db.TaxLine{}
+	if base.IsZero() {
+		return lines, nil
+	}
+	for _, rule := range rules {
+		if rule.Rate == 0 {
+			continue
+		}
+		lines = append(lines, taxLine(rule, base, on))
+	}
+	return lines, nil
+}
+
+// Tax computed over a month, converted into the reporting currency at the rates effective on each line's date
+func (s *taxService) Summary(year int, month int, currency string) (*models.TaxSummary, error) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	lines, err := s.repo.FindLines(from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	zero := db.Money{Currency: currency}
+	summary := models.TaxSummary{Year: year, Month: month, Currency: currency, Taxes: []models.TaxSummaryLine{},
+		PPNOutput: zero, PPNInput: zero, RentIncomeTax: zero, WithheldTax: zero}
+	// Index of each tax and what it applies to in the summary
+	rows := map[string]int{}
+	for _, line := range *lines {
+		err = s.rates.ConvertAll(currency, line.TaxDate, &line.Base, &line.Amount)
+		if err != nil {
+			return nil, err
+		}
+		key := line.Tax + "/" + line.AppliesTo
+		i, found := rows[key]
+		if !found {
+			i = len(summary.Taxes)
+			rows[key] = i
+			summary.Taxes = append(summary.Taxes, models.TaxSummaryLine{Tax: line.Tax, AppliesTo: line.AppliesTo, Withheld: line.Withheld, Base: zero, Amount: zero})
+		}
+		summary.Taxes[i].Lines++
+		summary.Taxes[i].Base = summary.Taxes[i].Base.Add(line.Base)
+		summary.Taxes[i].Amount = summary.Taxes[i].Amount.Add(line.Amount)
+
+		switch {
+		case line.Tax == "PPN" && line.AppliesTo == "Vendor Invoice":
+			summary.PPNInput = summary.PPNInput.Add(line.Amount)
+		case line.Tax == "PPN":
+			summary.PPNOutput = summary.PPNOutput.Add(line.Amount)
+		case line.AppliesTo == "Rent":
+			summary.RentIncomeTax = summary.RentIncomeTax.Add(line.Amount)
+		case line.Withheld:
+			summary.WithheldTax = summary.WithheldTax.Add(line.Amount)
+		}
+	}
+	summary.PPNPayable = summary.PPNOutput.Sub(summary.PPNInput)
+
+	sort.SliceStable(summary.Taxes, func(i, j int) bool {
+		if summary.Taxes[i].Tax != summary.Taxes[j].Tax {
+			return summary.Taxes[i].Tax < summary.Taxes[j].Tax
+		}
+		return summary.Taxes[i].AppliesTo < summary.Taxes[j].AppliesTo
+	})
+	return &summary, nil
+}
+
+// Tax line of a rule on an amount
+func taxLine(rule db.TaxRule, base db.Money, on time.Time) db.TaxLine {
+	ruleId := rule.ID
+	return db.TaxLine{
+		Tax:       rule.Tax,
+		AppliesTo: rule.AppliesTo,
+		Rate:      rule.Rate,
+		Withheld:  rule.Withheld,
+		TaxDate:   on,
+		Base:      base,
+		Amount:    base.Percent(rule.Rate),
+		TaxRuleID: &ruleId,
+	}
+}
+
+// Checks whether a tax can be applied to rent, commission or vendor invoices
+func taxApplies(tax string, appliesTo string) bool {
+	for _, applies := range taxAppliesTo[tax] {
+		if applies == appliesTo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a portal token doesn't belong to a property
+var ErrInvalidPortalToken = errors.New("portal not found")
+
+// Returned when a tracking code doesn't belong to a request at the portal's property
+var ErrTrackingCodeNotFound = errors.New("maintenance request not found")
+
+// Returned when a tenant uploads too many photos or a file that isn't a photo
+var ErrInvalidPhoto = fmt.Errorf("photos must be jpg, jpeg, png, webp or heic files of up to %d MB (maximum %d)", models.TenantPortalMaxPhotoSize>>20, models.TenantPortalMaxPhotos)
+
+// File types accepted as tenant photos
+var tenantPhotoTypes = map[string]bool{"jpg": true, "jpeg": true, "png": true, "webp": true, "heic": true}
+
+type TenantPortalService interface {
+	// Creates a maintenance request and triage task from a tenant's submission
+	SubmitMaintenance(string, *models.TenantMaintenanceRequest, []*multipart.FileHeader) (*models.TenantMaintenanceReceipt, error)
+	// Progress of a tenant's maintenance request
+	MaintenanceStatus(string, string) (*models.TenantMaintenanceStatus, error)
+	// Generates a new portal token for a property, replacing any previous token
+	RotatePortalToken(int) (*models.PropertyPortalToken, error)
+}
+
+type tenantPortalService struct {
+	properties   repository.PropertyRepository
+	requests     repository.MaintenanceRequestRepository
+	tasks        repository.TaskRepository
+	attachments  repository.PropertyAttachmentRepository
+	users        repository.UserRepository
+	notification NotificationService
+	// Photos are stored in object storage
+	objectStorage db.ObjectRepository
+	ioService     helpers.FileIO
+}
+
+func NewTenantPortalService(properties repository.PropertyRepository, requests repository.MaintenanceRequestRepository, tasks repository.TaskRepository, attachments repository.PropertyAttachmentRepository, users repository.UserRepository, notification NotificationService, objStorage db.ObjectRepository, ioServ helpers.FileIO) TenantPortalService {
+	return &tenantPortalService{properties, requests, tasks, attachments, users, notification, objStorage, ioServ}
+}
+
+// Creates a maintenance request and triage task from a tenant's submission, storing any photos against the property
+func (s *tenantPortalService) SubmitMaintenance(token string, request *models.TenantMaintenanceRequest, photos []*multipart.FileHeader) (*models.TenantMaintenanceReceipt, error) {
+	property, err := s.properties.FindByPortalToken(token)
+	if err != nil {
+		return nil, ErrInvalidPortalToken
+	}
+	// Check photos before storing anything
+	if len(photos) > models.TenantPortalMaxPhotos {
+		return nil, ErrInvalidPhoto
+	}
+	for _, photo := range photos {
+		if !tenantPhotoTypes[strings.ToLower(strings.TrimPrefix(filepath.Ext(photo.Filename), "."))] || photo.Size > models.TenantPortalMaxPhotoSize {
+			return nil, ErrInvalidPhoto
+		}
+	}
+
+	trackingCode, err := generateTrackingCode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Save copies of the photos on the server, removing them once the submission is done
+	stagedPhotos := []string{}
+	defer func() {
+		for _, tempFilePath := range stagedPhotos {
+			err := s.ioService.DeleteFile(tempFilePath)
+			if err != nil {
+				fmt.Println("error in deleting tmp file: ", err)
+			}
+		}
+	}()
+	for i, photo := range photos {
+		tempFilePath, err := s.stagePhoto(trackingCode, i+1, photo)
+		if err != nil {
+			return nil, err
+		}
+		stagedPhotos = append(stagedPhotos, tempFilePath)
+	}
+
+	// Task is triaged by staff before work is assigned
+	createdTask := &db.Task{
+		TaskName: fmt.Sprintf("Tenant report: %s at %s", request.Category, property.Property_Name),
+		Type:     "Maintenance",
+		Status:   "Triage",
+		Notes:    request.Description,
+	}
+	createdRequest := &db.MaintenanceRequest{
+		WorkDefinition:  "Investigation",
+		Type:            request.Category,
+		Scale:           "Medium",
+		Notes:           request.Description,
+		TrackingCode:    &trackingCode,
+		ReporterName:    request.Name,
+		ReporterContact: request.Contact,
+		PropertyID:      property.ID,
+	}
+	err = s.tasks.CreateWithMaintenanceRequest(createdTask, createdRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating maintenance request: %w", err)
+	}
+
+	// Photos are uploaded once the request is recorded. The submission is discarded if they can't be stored
+	storedPhotos := []db.PropertyAttachment{}
+	for i, photo := range photos {
+		attachment, err := s.storePhoto(property.ID, trackingCode, stagedPhotos[i], filepath.Base(photo.Filename))
+		if err != nil {
+			s.discardSubmission(createdTask.ID, storedPhotos)
+			return nil, err
+		}
+		storedPhotos = append(storedPhotos, *attachment)
+	}
+	if len(storedPhotos) > 0 {
+		err = s.requests.AddPhotos(createdRequest.ID, storedPhotos)
+		if err != nil {
+			s.discardSubmission(createdTask.ID, storedPhotos)
+			return nil, fmt.Errorf("failed adding photos to maintenance request: %w", err)
+		}
+	}
+
+	// Let admins know there's a request to triage
+	admins, err := s.users.FindByRole("admin")
+	if err == nil {
+		adminIDs := []uint{}
+		for _, admin := range *admins {
+			adminIDs = append(adminIDs, admin.ID)
+		}
+		s.notification.Dispatch(&models.NotificationEvent{
+			Type:    "TenantRequestSubmitted",
+			Message: fmt.Sprintf("%s reported a %s issue at %s (%s)", request.Name, strings.ToLower(request.Category), property.Property_Name, trackingCode),
+			UserIDs: adminIDs,
+			TaskID:  createdTask.ID,
+		})
+	}
+
+	return &models.TenantMaintenanceReceipt{
+		TrackingCode: trackingCode,
+		Status:       tenantStatus(createdTask.Status, nil),
+		SubmittedAt:  createdRequest.CreatedAt,
+		Photos:       len(storedPhotos),
+	}, nil
+}
+
+// Progress of a tenant's maintenance request. Only requests at the portal's property are visible
+func (s *tenantPortalService) MaintenanceStatus(token string, trackingCode string) (*models.TenantMaintenanceStatus, error) {
+	property, err := s.properties.FindByPortalToken(token)
+	if err != nil {
+		return nil, ErrInvalidPortalToken
+	}
+	request, err := s.requests.FindByTrackingCode(strings.ToUpper(trackingCode))
+	if err != nil || request.PropertyID != property.ID {
+		return nil, ErrTrackingCodeNotFound
+	}
+	task, err := s.tasks.FindById(int(request.TaskID))
+	if err != nil {
+		return nil, ErrTrackingCodeNotFound
+	}
+
+	status := models.TenantMaintenanceStatus{
+		TrackingCode: *request.TrackingCode,
+		Category:     request.Type,
+		Description:  request.Notes,
+		SubmittedAt:  request.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+	}
+	// Next visit of a work order that hasn't been completed
+	for _, order := range request.WorkOrders {
+		if order.ScheduledStart != nil && order.CompletedAt == nil {
+			if status.ScheduledFor == nil || order.ScheduledStart.Before(*status.ScheduledFor) {
+				status.ScheduledFor = order.ScheduledStart
+			}
+		}
+	}
+	status.Status = tenantStatus(task.Status, status.ScheduledFor)
+	return &status, nil
+}
+
+// Generates a new portal token for a property, replacing any previous token
+func (s *tenantPortalService) RotatePortalToken(propertyId int) (*models.PropertyPortalToken, error) {
+	tokenBytes := make([]byte, 24)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating portal token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	err = s.properties.SetPortalToken(propertyId, token)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PropertyPortalToken{
+		PropertyID:      uint(propertyId),
+		PortalToken:     token,
+		MaintenancePath: fmt.Sprintf("/portal/%s/maintenance", token),
+	}, nil
+}
+
+// Saves a copy of a tenant's photo on the server named after the tracking code and the photo's position, so
+// submissions with the same file names don't overwrite each other. Returns the path of the copy
+func (s *tenantPortalService) stagePhoto(trackingCode string, position int, photo *multipart.FileHeader) (string, error) {
+	file, err := photo.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed reading photo: %w", err)
+	}
+	defer file.Close()
+
+	stagedPhoto := &multipart.FileHeader{Filename: fmt.Sprintf("%s-%d%s", trackingCode, position, strings.ToLower(filepath.Ext(photo.Filename)))}
+	err = s.ioService.SaveACopyOfTheFileOnTheServer(file, stagedPhoto, "./tmp/")
+	if err != nil {
+		return "", fmt.Errorf("failed saving a copy of the file on the server: %w", err)
+	}
+	return "./tmp/" + stagedPhoto.Filename, nil
+}
+
+// Uploads a tenant's photo saved on the server to object storage and records it against the property
+func (s *tenantPortalService) storePhoto(propertyId uint, trackingCode string, tempFilePath string, fileName string) (*db.PropertyAttachment, error) {
+	// Upload file to object storage. Grab variables and update file key path
+	fileKeyPath := fmt.Sprintf("property/%v/maintenance/%s/%s", propertyId, trackingCode, filepath.Base(tempFilePath))
+	fileKeyPath, eTag, fileSize, err := s.objectStorage.UploadFile(tempFilePath, fileKeyPath, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed uploading file to object storage: %w", err)
+	}
+
+	createdAttachment, err := s.attachments.Create(&db.PropertyAttachment{
+		Label:     fmt.Sprintf("Tenant photo (%s)", trackingCode),
+		FileName:  fileName,
+		FileSize:  fileSize,
+		FileType:  strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), ".")),
+		ObjectKey: fileKeyPath,
+		ETag:      eTag,
+		Property:  db.Property{ID: propertyId},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating property attachment: %w", err)
+	}
+	return createdAttachment, nil
+}
+
+// Removes a submission's triage task, maintenance request and stored photos after its photos fail to store
+func (s *tenantPortalService) discardSubmission(taskId uint, photos []db.PropertyAttachment) {
+	for _, photo := range photos {
+		err := s.attachments.Delete(int(photo.ID))
+		if err != nil {
+			fmt.Println("error in deleting tenant photo: ", err)
+		}
+	}
+	err := s.tasks.Delete(int(taskId))
+	if err != nil {
+		fmt.Println("error in deleting tenant maintenance request: ", err)
+	}
+}
+
+// Generates a short code tenants use to follow their request (eg. MR-7K3Q9X2A)
+func generateTrackingCode() (string, error) {
+	codeBytes := make([]byte, 5)
+	_, err := rand.Read(codeBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed generating tracking code: %w", err)
+	}
+	return "MR-" + base32.StdEncoding.EncodeToString(codeBytes), nil
+}
+
+// Converts a task's status to the progress shown to tenants
+func tenantStatus(taskStatus string, scheduledFor *time.Time) string {
+	switch taskStatus {
+	case "Triage", "Created", "created", "":
+		return "Received"
+	case "Completed", "Archived":
+		return "Completed"
+	case "Cancelled":
+		return "Closed"
+	}
+	if scheduledFor != nil {
+		return "Scheduled"
+	}
+	return "In Progress"
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when starting a timer while the user already has one running
+var ErrTimerRunning = errors.New("a timer is already running for this user")
+
+// Returned when stopping a timer while the user has none running
+var ErrNoTimerRunning = errors.New("no timer is running for this user")
+
+// Returned when changing the times of a running timer's entry
+var ErrTimerNotStopped = errors.New("times can't be changed while the timer is running")
+
+type TimeEntryService interface {
+	FindAll(int, int, string, int, int) (*[]db.TimeEntry, error)
+	FindById(int) (*db.TimeEntry, error)
+	Create(*models.CreateTimeEntry) (*db.TimeEntry, error)
+	Update(int, *models.UpdateTimeEntry) (*db.TimeEntry, error)
+	Delete(int) error
+	// Timers (user id)
+	StartTimer(int, *models.StartTimer) (*db.TimeEntry, error)
+	StopTimer(int) (*db.TimeEntry, error)
+	FindRunningTimer(int) (*db.TimeEntry, error)
+	// Totals time between two dates grouped by task, property or user
+	Rollup(string, time.Time, time.Time) (*[]models.TimeRollup, error)
+}
+
+type timeEntryService struct {
+	repo  repository.TimeEntryRepository
+	tasks repository.TaskRepository
+}
+
+func NewTimeEntryService(repo repository.TimeEntryRepository, tasks repository.TaskRepository) TimeEntryService {
+	return &timeEntryService{repo, tasks}
+}
+
+// Creates a completed time entry. Duration is calculated from end time if provided, otherwise end time from duration
+func (s *timeEntryService) Create(entry *models.CreateTimeEntry) (*db.TimeEntry, error) {
+	// Ensure task exists
+	_, err := s.tasks.FindById(int(entry.Task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	// Create a new entry from DTO
+	entryToCreate := db.TimeEntry{
+		UserID:          entry.User.ID,
+		TaskID:          entry.Task.ID,
+		StartedAt:       entry.StartedAt,
+		DurationMinutes: entry.DurationMinutes,
+		Billable:        entry.Billable,
+		Note:            entry.Note,
+	}
+	if !entry.EndedAt.IsZero() {
+		entryToCreate.EndedAt = &entry.EndedAt
+	}
+	err = completeTimeEntry(&entryToCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create entry in database
+	createdEntry, err := s.repo.Create(&entryToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating time entry: %w", err)
+	}
+
+	return createdEntry, nil
+}
+
+// Find a list of time entries. Filters by task and user if not 0
+func (s *timeEntryService) FindAll(limit int, offset int, order string, taskId int, userId int) (*[]db.TimeEntry, error) {
+	entries, err := s.repo.FindAll(limit, offset, order, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Find time entry in database by ID
+func (s *timeEntryService) FindById(id int) (*db.TimeEntry, error) {
+	// Find entry by id
+	entry, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return entry, nil
+}
+
+// Delete time entry in database
+func (s *timeEntryService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting time entry: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates time entry in database. Duration is recalculated when times change
+func (s *timeEntryService) Update(id int, entry *models.UpdateTimeEntry) (*db.TimeEntry, error) {
+	// Find existing entry
+	foundEntry, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Times of running timers are set when they are stopped
+	timesChanged := !entry.StartedAt.IsZero() || !entry.EndedAt.IsZero() || entry.DurationMinutes != 0
+	if foundEntry.EndedAt == nil && timesChanged {
+		return nil, ErrTimerNotStopped
+	}
+
+	// Apply provided fields to existing entry
+	entryToUpdate := *foundEntry
+	if !entry.StartedAt.IsZero() {
+		entryToUpdate.StartedAt = entry.StartedAt
+	}
+	if entry.Billable != nil {
+		entryToUpdate.Billable = *entry.Billable
+	}
+	if entry.Note != "" {
+		entryToUpdate.Note = entry.Note
+	}
+	// New end time takes priority over a new duration
+	if !entry.EndedAt.IsZero() {
+		entryToUpdate.EndedAt = &entry.EndedAt
+	} else if entry.DurationMinutes != 0 {
+		entryToUpdate.EndedAt = nil
+		entryToUpdate.DurationMinutes = entry.DurationMinutes
+	}
+	// Running timers are only completed by stopping them
+	if foundEntry.EndedAt != nil {
+		err = completeTimeEntry(&entryToUpdate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Update using repo
+	updatedEntry, err := s.repo.Update(id, &entryToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedEntry, nil
+}
+
+// Starts a timer on a task for the user. Only one timer can run per user
+func (s *timeEntryService) StartTimer(userId int, timer *models.StartTimer) (*db.TimeEntry, error) {
+	// Ensure no timer is already running
+	_, err := s.repo.FindRunningByUser(userId)
+	if err == nil {
+		return nil, ErrTimerRunning
+	}
+	// Ensure task exists
+	_, err = s.tasks.FindById(int(timer.Task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	// Create running entry
+	createdEntry, err := s.repo.Create(&db.TimeEntry{
+		UserID:    uint(userId),
+		TaskID:    timer.Task.ID,
+		StartedAt: time.Now(),
+		Billable:  timer.Billable,
+		Note:      timer.Note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed starting timer: %w", err)
+	}
+	return createdEntry, nil
+}
+
+// Stops the user's running timer and records its duration
+func (s *timeEntryService) StopTimer(userId int) (*db.TimeEntry, error) {
+	// Find running timer
+	runningEntry, err := s.repo.FindRunningByUser(userId)
+	if err != nil {
+		return nil, ErrNoTimerRunning
+	}
+
+	// Set end time and duration
+	endedAt := time.Now()
+	runningEntry.EndedAt = &endedAt
+	err = completeTimeEntry(runningEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update using repo
+	stoppedEntry, err := s.repo.Update(int(runningEntry.ID), runningEntry)
+	if err != nil {
+		return nil, err
+	}
+	return stoppedEntry, nil
+}
+
+// Find the user's running timer
+func (s *timeEntryService) FindRunningTimer(userId int) (*db.TimeEntry, error) {
+	runningEntry, err := s.repo.FindRunningByUser(userId)
+	if err != nil {
+		return nil, ErrNoTimerRunning
+	}
+	return runningEntry, nil
+}
+
+// Totals time for entries started between two dates, grouped by task, property or user
+func (s *timeEntryService) Rollup(groupBy string, from time.Time, to time.Time) (*[]models.TimeRollup, error) {
+	rollups, err := s.repo.Rollup(groupBy, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// Sets duration from end time, or end time from duration if no end time is set
+func completeTimeEntry(entry *db.TimeEntry) error {
+	if entry.EndedAt == nil {
+		if entry.DurationMinutes <= 0 {
+			return fmt.Errorf("time entry requires an end time or duration")
+		}
+		endedAt := entry.StartedAt.Add(time.Duration(entry.DurationMinutes) * time.Minute)
+		entry.EndedAt = &endedAt
+		return nil
+	}
+
+	if entry.EndedAt.Before(entry.StartedAt) {
+		return fmt.Errorf("time entry can't end before it starts")
+	}
+	entry.DurationMinutes = int(entry.EndedAt.Sub(entry.StartedAt).Round(time.Minute).Minutes())
+	return nil
+}
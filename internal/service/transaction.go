@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dmawardi/Go-Template/internal/db"
 	"github.com/dmawardi/Go-Template/internal/models"
@@ -13,42 +14,122 @@ type TransactionService interface {
 	FindById(int) (*db.Transaction, error)
 	Create(*models.CreateTransaction) (*db.Transaction, error)
 	Update(int, *models.UpdateTransaction) (*db.Transaction, error)
+	// Deletes a transaction, archiving its task
 	Delete(int) error
+	// Creates a task together with its transaction
+	CreateWithTask(*models.CreateTaskWithTransaction) (*db.Task, error)
 }
 
 type transactionService struct {
-	repo repository.TransactionRepository
+	repo         repository.TransactionRepository
+	pipeline     repository.PipelineRepository
+	commissions  CommissionService
+	notification NotificationService
+	tasks        repository.TaskRepository
+	units        repository.UnitRepository
 }
 
-func NewTransactionService(repo repository.TransactionRepository) TransactionService {
-	return &transactionService{repo}
+func NewTransactionService(repo repository.TransactionRepository, pipeline repository.PipelineRepository, commissions CommissionService, notification NotificationService, tasks repository.TaskRepository, units repository.UnitRepository) TransactionService {
+	return &transactionService{repo, pipeline, commissions, notification, tasks, units}
 }
 
-// Creates a transaction
+// Creates a transaction at the first stage of its type's pipeline. The commission is calculated with the scheme provided or the default scheme of its type
 func (s *transactionService) Create(transaction *models.CreateTransaction) (*db.Transaction, error) {
-	// Create a new transaction from DTO
-	transToCreate := db.Transaction{
-		Type:             transaction.Type,
-		Agency:           transaction.Agency,
-		AgencyName:       transaction.AgencyName,
-		IsLease:          transaction.IsLease,
-		TenancyType:      transaction.TenancyType,
-		TransactionNotes: transaction.TransactionNotes,
-		TransactionValue: transaction.TransactionValue,
-		Fee:              transaction.Fee,
-		Property:         transaction.Property,
-		TaskID:             transaction.Task.ID,
+	// Ensure the task exists so the transaction isn't left without one
+	_, err := s.tasks.FindById(int(transaction.Task.ID))
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	transToCreate, err := s.buildTransaction(transaction)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create transaction in database
-	createdTransaction, err := s.repo.Create(&transToCreate)
+	createdTransaction, err := s.repo.Create(transToCreate)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating transaction: %w", err)
 	}
 
+	err = s.enterFirstStage(createdTransaction)
+	if err != nil {
+		return nil, err
+	}
 	return createdTransaction, nil
 }
 
+// Creates a task together with its transaction, so neither exists without the other. The transaction starts at the
+// first stage of its type's pipeline
+func (s *transactionService) CreateWithTask(create *models.CreateTaskWithTransaction) (*db.Task, error) {
+	taskToCreate, err := buildTask(s.tasks, &create.Task)
+	if err != nil {
+		return nil, err
+	}
+	transToCreate, err := s.buildTransaction(&create.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create both in one database transaction
+	err = s.tasks.CreateWithTransaction(taskToCreate, transToCreate)
+	if err != nil {
+		return nil, err
+	}
+	err = s.enterFirstStage(transToCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Notify assignees
+	notifyAssigned(s.notification, taskToCreate, taskToCreate.Assignment)
+
+	return s.tasks.FindById(int(taskToCreate.ID))
+}
+
+// Builds a transaction from DTO with the scheme provided or the default scheme of its type. Task is set from the DTO
+func (s *transactionService) buildTransaction(transaction *models.CreateTransaction) (*db.Transaction, error) {
+	unitId, err := findPropertyUnit(s.units, transaction.Unit, transaction.Property.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Create a new transaction from DTO
+	transToCreate := db.Transaction{
+		Type:              transaction.Type,
+		Agency:            transaction.Agency,
+		AgencyName:        transaction.AgencyName,
+		IsLease:           transaction.IsLease,
+		TenancyType:       transaction.TenancyType,
+		TransactionNotes:  transaction.TransactionNotes,
+		TransactionValue:  transaction.TransactionValue,
+		Fee:               transaction.Fee,
+		ManagementFeeRate: transaction.ManagementFeeRate,
+		Property:          transaction.Property,
+		UnitID:            unitId,
+		TaskID:            transaction.Task.ID,
+	}
+	if transaction.CommissionScheme.ID != 0 {
+		// Ensure scheme exists
+		scheme, err := s.commissions.FindSchemeById(int(transaction.CommissionScheme.ID))
+		if err != nil {
+			return nil, fmt.Errorf("commission scheme not found: %w", err)
+		}
+		transToCreate.CommissionSchemeID = &scheme.ID
+	} else if scheme, err := s.commissions.DefaultScheme(transaction.Type); err == nil {
+		transToCreate.CommissionSchemeID = &scheme.ID
+	}
+	return &transToCreate, nil
+}
+
+// Enters a created transaction into the first stage of its type's pipeline, if the type has one
+func (s *transactionService) enterFirstStage(transaction *db.Transaction) error {
+	stage, err := s.pipeline.FindFirstStage(transaction.Type)
+	// Types without a pipeline aren't staged
+	if err != nil {
+		return nil
+	}
+	return enterStage(s.pipeline, transaction, stage, nil, "", time.Now())
+}
+
 // Find a list of transactions
 func (s *transactionService) FindAll(limit int, offset int, order string) (*[]db.Transaction, error) {
 	transactions, err := s.repo.FindAll(limit, offset, order)
@@ -68,10 +149,11 @@ func (s *transactionService) FindById(id int) (*db.Transaction, error) {
 		return nil, err
 	}
 	// else
+	markOverdueMilestones(transaction.Milestones, time.Now())
 	return transaction, nil
 }
 
-// Delete transaction in database
+// Delete transaction in database. Its task is archived
 func (s *transactionService) Delete(id int) error {
 	err := s.repo.Delete(id)
 	// If error detected
@@ -83,7 +165,7 @@ func (s *transactionService) Delete(id int) error {
 	return nil
 }
 
-// Updates transaction in database
+// Updates transaction in database. The commission is calculated when the transaction is completed and recalculated on later changes
 func (s *transactionService) Update(id int, transaction *models.UpdateTransaction) (*db.Transaction, error) {
 	// Create a new transaction from DTO
 	transToUpdate := db.Transaction{
@@ -95,9 +177,31 @@ func (s *transactionService) Update(id int, transaction *models.UpdateTransactio
 		TransactionNotes:      transaction.TransactionNotes,
 		TransactionValue:      transaction.TransactionValue,
 		Fee:                   transaction.Fee,
+		ManagementFeeRate:     transaction.ManagementFeeRate,
 		TransactionCompletion: transaction.TransactionCompletion,
 		Contacts:              transaction.Contacts,
 	}
+	if transaction.CommissionScheme.ID != 0 {
+		// Ensure scheme exists
+		scheme, err := s.commissions.FindSchemeById(int(transaction.CommissionScheme.ID))
+		if err != nil {
+			return nil, fmt.Errorf("commission scheme not found: %w", err)
+		}
+		transToUpdate.CommissionSchemeID = &scheme.ID
+	}
+
+	// Find current transaction to determine if it is being completed
+	currentTransaction, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	wasCompleted := !currentTransaction.TransactionCompletion.IsZero()
+
+	// Ensure the unit belongs to the transaction's property
+	transToUpdate.UnitID, err = findPropertyUnit(s.units, transaction.Unit, currentTransaction.PropertyID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Update using repo
 	updatedTransaction, err := s.repo.Update(id, &transToUpdate)
@@ -105,5 +209,27 @@ func (s *transactionService) Update(id int, transaction *models.UpdateTransactio
 		return nil, err
 	}
 
+	if !updatedTransaction.TransactionCompletion.IsZero() {
+		err = s.commissions.Calculate(updatedTransaction)
+		if err != nil {
+			return nil, err
+		}
+		updatedTransaction, err = s.repo.FindById(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	markOverdueMilestones(updatedTransaction.Milestones, time.Now())
+
+	// Notify task assignees when transaction is completed
+	if !wasCompleted && !updatedTransaction.TransactionCompletion.IsZero() {
+		s.notification.Dispatch(&models.NotificationEvent{
+			Type:    "TransactionCompleted",
+			Message: fmt.Sprintf("%s transaction #%d was completed", updatedTransaction.Type, updatedTransaction.ID),
+			TaskID:  updatedTransaction.TaskID,
+		})
+	}
+
 	return updatedTransaction, nil
 }
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a property already has a unit with the name
+var ErrUnitExists = errors.New("property already has a unit with this name")
+
+// Returned when deleting a unit that leases, maintenance requests or transactions target
+var ErrUnitInUse = errors.New("unit has leases, maintenance requests or transactions")
+
+// Returned when a lease, maintenance request or transaction targets a unit of another property
+var ErrUnitNotInProperty = errors.New("unit doesn't belong to the property")
+
+type UnitService interface {
+	FindAll(int, int, string, int) (*[]db.Unit, error)
+	FindById(int) (*db.Unit, error)
+	Create(*models.CreateUnit) (*db.Unit, error)
+	Update(int, *models.UpdateUnit) (*db.Unit, error)
+	Delete(int) error
+	// Occupancy and rent received of a property's units over a period
+	Occupancy(int, time.Time, time.Time, string) (*models.PropertyOccupancy, error)
+}
+
+type unitService struct {
+	repo       repository.UnitRepository
+	properties repository.PropertyRepository
+	leases     repository.LeaseRepository
+	rates      ExchangeRateService
+}
+
+func NewUnitService(repo repository.UnitRepository, properties repository.PropertyRepository, leases repository.LeaseRepository, rates ExchangeRateService) UnitService {
+	return &unitService{repo, properties, leases, rates}
+}
+
+// Creates a unit of a property
+func (s *unitService) Create(unit *models.CreateUnit) (*db.Unit, error) {
+	// Ensure property exists
+	property, err := s.properties.FindById(int(unit.Property.ID))
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if _, err := s.repo.FindByName(property.ID, unit.Name); err == nil {
+		return nil, ErrUnitExists
+	}
+	unitToCreate := db.Unit{
+		Name:        unit.Name,
+		Bedrooms:    unit.Bedrooms,
+		Bathrooms:   unit.Bathrooms,
+		Area:        unit.Area,
+		Area_Metric: unit.Area_Metric,
+		Description: unit.Description,
+		Notes:       unit.Notes,
+		PropertyID:  property.ID,
+		Features:    unit.Features,
+	}
+
+	// Create unit in database
+	createdUnit, err := s.repo.Create(&unitToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating unit: %w", err)
+	}
+	return s.repo.FindById(int(createdUnit.ID))
+}
+
+// Find a list of units. Filters by property if provided
+func (s *unitService) FindAll(limit int, offset int, order string, propertyId int) (*[]db.Unit, error) {
+	units, err := s.repo.FindAll(limit, offset, order, propertyId)
+	if err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// Find unit in database by ID
+func (s *unitService) FindById(id int) (*db.Unit, error) {
+	// Find by id
+	unit, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return unit, nil
+}
+
+// Delete unit in database. Units that leases, maintenance requests or transactions target are kept
+func (s *unitService) Delete(id int) error {
+	inUse, err := s.repo.IsInUse(uint(id))
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrUnitInUse
+	}
+	err = s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting unit: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates unit in database
+func (s *unitService) Update(id int, unit *models.UpdateUnit) (*db.Unit, error) {
+	foundUnit, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if unit.Name != "" {
+		if existing, err := s.repo.FindByName(foundUnit.PropertyID, unit.Name); err == nil && existing.ID != foundUnit.ID {
+			return nil, ErrUnitExists
+		}
+	}
+	unitToUpdate := &db.Unit{
+		Name:        unit.Name,
+		Bedrooms:    unit.Bedrooms,
+		Bathrooms:   unit.Bathrooms,
+		Area:        unit.Area,
+		Area_Metric: unit.Area_Metric,
+		Description: unit.Description,
+		Notes:       unit.Notes,
+		Features:    unit.Features,
+	}
+	return s.repo.Update(id, unitToUpdate)
+}
+
+// Occupancy and rent received of a property's units from (inclusive) to (exclusive), with rent converted into the
+// reporting currency at the rates effective on each receipt's date. A lease of the whole property occupies all of its
+// units, and properties without units are reported as a single unit
+func (s *unitService) Occupancy(propertyId int, from time.Time, to time.Time, currency string) (*models.PropertyOccupancy, error) {
+	property, err := s.properties.FindById(propertyId)
+	if err != nil {
+		return nil, err
+	}
+	// Receipts may be for leases that have ended, so all leases of the property are needed
+	leases, err := s.leases.FindAll(0, 0, "", propertyId, 0)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.repo.FindRentReceipts(property.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	zero := db.Money{Currency: currency}
+	occupancy := models.PropertyOccupancy{PropertyID: property.ID, PropertyName: property.Property_Name, From: from, To: to,
+		Currency: currency, UnitOccupancy: []models.UnitOccupancy{}, WholePropertyIncome: zero, Income: zero}
+	// Index of each unit in the occupancy
+	rows := map[uint]int{}
+	for i, unit := range property.Units {
+		rows[unit.ID] = i
+		occupancy.UnitOccupancy = append(occupancy.UnitOccupancy, models.UnitOccupancy{UnitID: unit.ID, Name: unit.Name, Income: zero})
+	}
+	if len(property.Units) == 0 {
+		occupancy.UnitOccupancy = append(occupancy.UnitOccupancy, models.UnitOccupancy{Name: property.Property_Name, Income: zero})
+	}
+
+	// Occupancy is measured on the last day of the period, or today if earlier
+	asOf := to.AddDate(0, 0, -1)
+	if now := time.Now(); now.Before(asOf) {
+		asOf = now
+	}
+	periodDays := wholeDays(from, to)
+	// Unit of each lease (nil for leases of the whole property)
+	leaseUnits := map[uint]*uint{}
+	for _, lease := range *leases {
+		leaseUnits[lease.ID] = lease.UnitID
+		start, end := lease.StartDate, lease.EndDate
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		days := wholeDays(start, end)
+		active := !asOf.Before(lease.StartDate) && asOf.Before(lease.EndDate)
+		for i := range occupancy.UnitOccupancy {
+			row := &occupancy.UnitOccupancy[i]
+			if lease.UnitID != nil && *lease.UnitID != row.UnitID {
+				continue
+			}
+			if days > 0 {
+				row.LeasedDays += days
+			}
+			row.Occupied = row.Occupied || active
+		}
+	}
+
+	leasedDays := 0
+	for i := range occupancy.UnitOccupancy {
+		row := &occupancy.UnitOccupancy[i]
+		// A unit leased on its own and with the whole property is only counted once
+		if row.LeasedDays > periodDays {
+			row.LeasedDays = periodDays
+		}
+		row.OccupancyRate = occupancyRate(row.LeasedDays, periodDays)
+		leasedDays += row.LeasedDays
+		occupancy.Units++
+		if row.Occupied {
+			occupancy.OccupiedUnits++
+		}
+	}
+	occupancy.VacantUnits = occupancy.Units - occupancy.OccupiedUnits
+	occupancy.OccupancyRate = occupancyRate(leasedDays, periodDays*occupancy.Units)
+
+	for _, receipt := range *receipts {
+		amount, err := s.rates.Convert(receipt.Credit, currency, receipt.EntryDate)
+		if err != nil {
+			return nil, err
+		}
+		occupancy.Income = occupancy.Income.Add(amount)
+		unitId := leaseUnits[receipt.LeaseID]
+		switch {
+		case len(property.Units) == 0:
+			occupancy.UnitOccupancy[0].Income = occupancy.UnitOccupancy[0].Income.Add(amount)
+		case unitId == nil:
+			occupancy.WholePropertyIncome = occupancy.WholePropertyIncome.Add(amount)
+		default:
+			if i, found := rows[*unitId]; found {
+				occupancy.UnitOccupancy[i].Income = occupancy.UnitOccupancy[i].Income.Add(amount)
+			}
+		}
+	}
+	return &occupancy, nil
+}
+
+// Finds the unit of a property that a lease, maintenance request or transaction targets. Returns nil if no unit is provided
+func findPropertyUnit(units repository.UnitRepository, unit db.Unit, propertyId uint) (*uint, error) {
+	if unit.ID == 0 {
+		return nil, nil
+	}
+	foundUnit, err := units.FindById(int(unit.ID))
+	if err != nil {
+		return nil, fmt.Errorf("unit not found: %w", err)
+	}
+	if foundUnit.PropertyID != propertyId {
+		return nil, ErrUnitNotInProperty
+	}
+	return &foundUnit.ID, nil
+}
+
+// Whole days between two times
+func wholeDays(from time.Time, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// Percentage of days leased, to one decimal place
+func occupancyRate(leasedDays int, days int) float64 {
+	if days <= 0 {
+		return 0
+	}
+	return math.Round(float64(leasedDays)/float64(days)*1000) / 10
+}
@@ -13,6 +13,7 @@ type UserService interface {
 	FindAll(int, int, string) (*[]db.User, error)
 	FindById(int) (*db.User, error)
 	FindByEmail(string) (*db.User, error)
+	FindByUsernames([]string) (*[]db.User, error)
 	Create(user *models.CreateUser) (*db.User, error)
 	Update(int, *models.UpdateUser) (*db.User, error)
 	Delete(int) error
@@ -85,6 +86,18 @@ func (s *userService) FindByEmail(email string) (*db.User, error) {
 	return user, nil
 }
 
+// Find users in database using a list of usernames
+func (s *userService) FindByUsernames(usernames []string) (*[]db.User, error) {
+	users, err := s.repo.FindByUsernames(usernames)
+	// If error detected
+	if err != nil {
+		fmt.Printf("Failed to find users: %v\n", err)
+		return nil, err
+	}
+	// else
+	return users, nil
+}
+
 // Delete user in database
 func (s *userService) Delete(id int) error {
 	err := s.repo.Delete(id)
@@ -100,7 +113,7 @@ func (s *userService) Delete(id int) error {
 // Updates user in database
 func (s *userService) Update(id int, user *models.UpdateUser) (*db.User, error) {
 	// Create db User type of incoming DTO
-	dbUser := &db.User{Name: user.Name, Username: user.Username, Email: user.Email, Password: user.Password}
+	dbUser := &db.User{Name: user.Name, Username: user.Username, Email: user.Email, Password: user.Password, ReportingCurrency: user.ReportingCurrency}
 
 	// Update using repo
 	updatedUser, err := s.repo.Update(id, dbUser)
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
 	"github.com/dmawardi/Go-Template/internal/models"
 	"github.com/dmawardi/Go-Template/internal/repository"
 )
@@ -16,6 +18,9 @@ type VendorService interface {
 	Delete(int) error
 }
 
+// Returned when another vendor is already registered under the NPWP
+var ErrDuplicateNPWP = errors.New("a vendor with this NPWP already exists")
+
 type vendorService struct {
 	repo repository.VendorRepository
 }
@@ -29,10 +34,10 @@ func (s *vendorService) Create(vendor *models.CreateVendor) (*db.Vendor, error)
 	// Create a new vendor from DTO
 	vendorToCreate := db.Vendor{
 		CompanyName:      vendor.CompanyName,
-		NPWP:             vendor.NPWP,
+		NPWP:             helpers.NormalizeNPWP(vendor.NPWP),
 		Email:            vendor.Email,
 		Phone:            vendor.Phone,
-		NIB:              vendor.NIB,
+		NIB:              helpers.NormalizeDigits(vendor.NIB),
 		Street_Address_1: vendor.Street_Address_1,
 		Street_Address_2: vendor.Street_Address_2,
 		City:             vendor.City,
@@ -42,6 +47,12 @@ func (s *vendorService) Create(vendor *models.CreateVendor) (*db.Vendor, error)
 		WorkTypes:        vendor.WorkTypes,
 	}
 
+	// NPWP must be unique across vendors
+	err := s.checkDuplicateNPWP(0, vendorToCreate.NPWP)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create vendor in database
 	createdVendor, err := s.repo.Create(&vendorToCreate)
 	if err != nil {
@@ -90,10 +101,10 @@ func (s *vendorService) Update(id int, vendor *models.UpdateVendor) (*db.Vendor,
 	// Create a new vendor from incoming DTO
 	vendorToUpdate := &db.Vendor{
 		CompanyName:      vendor.CompanyName,
-		NPWP:             vendor.NPWP,
+		NPWP:             helpers.NormalizeNPWP(vendor.NPWP),
 		Email:            vendor.Email,
 		Phone:            vendor.Phone,
-		NIB:              vendor.NIB,
+		NIB:              helpers.NormalizeDigits(vendor.NIB),
 		Street_Address_1: vendor.Street_Address_1,
 		Street_Address_2: vendor.Street_Address_2,
 		City:             vendor.City,
@@ -103,6 +114,14 @@ func (s *vendorService) Update(id int, vendor *models.UpdateVendor) (*db.Vendor,
 		WorkTypes:        vendor.WorkTypes,
 	}
 
+	// NPWP must be unique across vendors
+	if vendorToUpdate.NPWP != "" {
+		err := s.checkDuplicateNPWP(uint(id), vendorToUpdate.NPWP)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Update using repo
 	updatedVendor, err := s.repo.Update(id, vendorToUpdate)
 	if err != nil {
@@ -111,3 +130,17 @@ func (s *vendorService) Update(id int, vendor *models.UpdateVendor) (*db.Vendor,
 
 	return updatedVendor, nil
 }
+
+// Returns ErrDuplicateNPWP if a vendor (other than the excluded ID) is registered under the NPWP
+func (s *vendorService) checkDuplicateNPWP(excludeId uint, npwp string) error {
+	existing, err := s.repo.FindByNPWP(helpers.NPWPVariants(npwp))
+	if err != nil {
+		return fmt.Errorf("failed checking for duplicate NPWP: %w", err)
+	}
+	for _, vendor := range *existing {
+		if vendor.ID != excludeId {
+			return ErrDuplicateNPWP
+		}
+	}
+	return nil
+}
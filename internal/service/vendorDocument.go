@@ -0,0 +1,319 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Days before expiry that vendor documents are flagged
+const VendorDocumentExpiryWarningDays = 30
+
+// Returned when downloading a vendor document without an uploaded file
+var ErrNoDocumentFile = errors.New("vendor document has no uploaded file")
+
+type VendorDocumentService interface {
+	FindAll(int, int, string, int, string) (*[]db.VendorDocument, error)
+	FindById(int) (*db.VendorDocument, error)
+	Create(*models.CreateVendorDocument) (*db.VendorDocument, error)
+	Update(int, *models.UpdateVendorDocument) (*db.VendorDocument, error)
+	Delete(int) error
+	// Uploads the document's file to object storage
+	Upload(int, *http.Request) (*db.VendorDocument, error)
+	// Download document file from object storage and save it to tmp folder
+	Download(int) (string, error)
+	// Computes a vendor's compliance from their current documents
+	Compliance(int) (*models.VendorCompliance, error)
+	// Returns the compliance issues of a vendor being assigned to a task's work, notifying the task's assignees
+	CheckAssignment(int, uint) []string
+	// Flags documents expiring within the warning period to admins (scheduled daily)
+	ProcessExpiringDocuments() error
+}
+
+type vendorDocumentService struct {
+	repo          repository.VendorDocumentRepository
+	vendors       repository.VendorRepository
+	users         repository.UserRepository
+	notification  NotificationService
+	objectStorage db.ObjectRepository
+	// Local storage service
+	ioService helpers.FileIO
+}
+
+func NewVendorDocumentService(repo repository.VendorDocumentRepository, vendors repository.VendorRepository, users repository.UserRepository, notification NotificationService, objStorage db.ObjectRepository, ioServ helpers.FileIO) VendorDocumentService {
+	return &vendorDocumentService{repo, vendors, users, notification, objStorage, ioServ}
+}
+
+// Creates a vendor document
+func (s *vendorDocumentService) Create(document *models.CreateVendorDocument) (*db.VendorDocument, error) {
+	// Ensure vendor exists
+	vendor, err := s.vendors.FindById(int(document.Vendor.ID))
+	if err != nil {
+		return nil, fmt.Errorf("vendor not found: %w", err)
+	}
+	documentToCreate := db.VendorDocument{
+		Type:      document.Type,
+		Number:    document.Number,
+		IssueDate: document.IssueDate,
+		VendorID:  vendor.ID,
+	}
+	if !document.ExpiryDate.IsZero() {
+		if !document.ExpiryDate.After(document.IssueDate) {
+			return nil, errors.New("expiry date must be after issue date")
+		}
+		documentToCreate.ExpiryDate = &document.ExpiryDate
+	}
+
+	// Create document in database
+	createdDocument, err := s.repo.Create(&documentToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating vendor document: %w", err)
+	}
+	return s.repo.FindById(int(createdDocument.ID))
+}
+
+// Find a list of vendor documents. Filters by vendor and type if provided
+func (s *vendorDocumentService) FindAll(limit int, offset int, order string, vendorId int, documentType string) (*[]db.VendorDocument, error) {
+	documents, err := s.repo.FindAll(limit, offset, order, vendorId, documentType)
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// Find vendor document in database by ID
+func (s *vendorDocumentService) FindById(id int) (*db.VendorDocument, error) {
+	// Find document by id
+	document, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return document, nil
+}
+
+// Delete vendor document in database
+func (s *vendorDocumentService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting vendor document: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates vendor document in database
+func (s *vendorDocumentService) Update(id int, document *models.UpdateVendorDocument) (*db.VendorDocument, error) {
+	// Find existing document
+	foundDocument, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new document from DTO
+	documentToUpdate := &db.VendorDocument{
+		Number:    document.Number,
+		IssueDate: document.IssueDate,
+	}
+	if !document.ExpiryDate.IsZero() {
+		issueDate := foundDocument.IssueDate
+		if !document.IssueDate.IsZero() {
+			issueDate = document.IssueDate
+		}
+		if !document.ExpiryDate.After(issueDate) {
+			return nil, errors.New("expiry date must be after issue date")
+		}
+		documentToUpdate.ExpiryDate = &document.ExpiryDate
+	}
+
+	// Update using repo
+	updatedDocument, err := s.repo.Update(id, documentToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedDocument, nil
+}
+
+// Uploads the document's file to object storage, replacing any previous file
+func (s *vendorDocumentService) Upload(id int, r *http.Request) (*db.VendorDocument, error) {
+	// Find document to attach file to
+	document, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract file from request
+	file, handler, err := helpers.ExtractFileFromResponse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed extracting file from request: %w", err)
+	}
+	// Save a copy of the file on the server
+	err = s.ioService.SaveACopyOfTheFileOnTheServer(file, handler, "./tmp/")
+	if err != nil {
+		return nil, fmt.Errorf("failed saving a copy of the file on the server: %w", err)
+	}
+	fileName := handler.Filename
+	tempFilePath := "./tmp/" + fileName
+
+	// Upload file to object storage. Grab variables and update file key path
+	fileKeyPath := fmt.Sprintf("vendor/%v/documents/%v/%s", document.VendorID, document.ID, fileName)
+	fileKeyPath, eTag, fileSize, err := s.objectStorage.UploadFile(tempFilePath, fileKeyPath, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed uploading file to object storage: %w", err)
+	}
+
+	// Store file details on document
+	updatedDocument, err := s.repo.Update(id, &db.VendorDocument{
+		FileName:  fileName,
+		FileSize:  fileSize,
+		FileType:  strings.TrimPrefix(filepath.Ext(fileName), "."),
+		ObjectKey: fileKeyPath,
+		ETag:      eTag,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete tmp file
+	err = s.ioService.DeleteFile(tempFilePath)
+	if err != nil {
+		fmt.Println("error in deleting tmp file: ", err)
+	}
+	return updatedDocument, nil
+}
+
+// Download document file from object storage and save it to tmp folder
+func (s *vendorDocumentService) Download(id int) (string, error) {
+	// Find document by id
+	document, err := s.repo.FindById(id)
+	if err != nil {
+		return "", err
+	}
+	if document.ObjectKey == "" {
+		return "", ErrNoDocumentFile
+	}
+	// Download file from object storage
+	downloadedFilePath, err := s.objectStorage.DownloadTempFile(document.ObjectKey, document.FileName)
+	if err != nil {
+		return "", fmt.Errorf("failed downloading file from object storage: %w", err)
+	}
+	return downloadedFilePath, nil
+}
+
+// Computes a vendor's compliance. Each required document type needs a current document (issued and not expired).
+// Vendors are expiring soon if a required document expires within the warning period
+func (s *vendorDocumentService) Compliance(vendorId int) (*models.VendorCompliance, error) {
+	vendor, err := s.vendors.FindById(vendorId)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := s.repo.FindByVendor(vendorId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	warnBefore := now.AddDate(0, 0, VendorDocumentExpiryWarningDays)
+	// Find the longest lasting current document of each type
+	current := make(map[string]db.VendorDocument)
+	for _, document := range *documents {
+		if document.IssueDate.After(now) || (document.ExpiryDate != nil && !document.ExpiryDate.After(now)) {
+			continue
+		}
+		best, ok := current[document.Type]
+		if !ok || best.ExpiryDate != nil && (document.ExpiryDate == nil || document.ExpiryDate.After(*best.ExpiryDate)) {
+			current[document.Type] = document
+		}
+	}
+
+	compliance := models.VendorCompliance{VendorID: vendor.ID, CompanyName: vendor.CompanyName, Missing: []string{}, Expiring: []db.VendorDocument{}, Warnings: []string{}}
+	for _, documentType := range models.RequiredVendorDocumentTypes {
+		document, ok := current[documentType]
+		if !ok {
+			compliance.Missing = append(compliance.Missing, documentType)
+			compliance.Warnings = append(compliance.Warnings, fmt.Sprintf("%s has no current %s", vendor.CompanyName, documentType))
+			continue
+		}
+		if document.ExpiryDate != nil && document.ExpiryDate.Before(warnBefore) {
+			compliance.Expiring = append(compliance.Expiring, document)
+			compliance.Warnings = append(compliance.Warnings, fmt.Sprintf("%s %s of %s expires on %s", documentType, document.Number, vendor.CompanyName, document.ExpiryDate.Format("02 Jan 2006")))
+		}
+	}
+
+	switch {
+	case len(compliance.Missing) > 0:
+		compliance.Status = "Non-compliant"
+	case len(compliance.Expiring) > 0:
+		compliance.Status = "Expiring Soon"
+	default:
+		compliance.Status = "Compliant"
+	}
+	return &compliance, nil
+}
+
+// Returns the compliance issues of a vendor being assigned to a task's work (none if compliant).
+// The task's assignees are notified of any issues
+func (s *vendorDocumentService) CheckAssignment(vendorId int, taskId uint) []string {
+	compliance, err := s.Compliance(vendorId)
+	if err != nil {
+		fmt.Println("error in checking vendor compliance: ", err)
+		return nil
+	}
+	if len(compliance.Warnings) == 0 {
+		return nil
+	}
+	s.notification.Dispatch(&models.NotificationEvent{
+		Type:    "VendorNonCompliant",
+		Message: fmt.Sprintf("%s was assigned with compliance issues: %s", compliance.CompanyName, strings.Join(compliance.Warnings, "; ")),
+		TaskID:  taskId,
+	})
+	return compliance.Warnings
+}
+
+// Flags documents expiring within the warning period (or already expired) to admins once
+func (s *vendorDocumentService) ProcessExpiringDocuments() error {
+	now := time.Now()
+	documents, err := s.repo.FindExpiringUnnotified(now.AddDate(0, 0, VendorDocumentExpiryWarningDays))
+	if err != nil {
+		return err
+	}
+	if len(*documents) == 0 {
+		return nil
+	}
+
+	// Notify admins
+	admins, err := s.users.FindByRole("admin")
+	if err != nil {
+		return err
+	}
+	adminIDs := []uint{}
+	for _, admin := range *admins {
+		adminIDs = append(adminIDs, admin.ID)
+	}
+
+	flagged := []uint{}
+	for _, document := range *documents {
+		expiry := "expires"
+		if document.ExpiryDate.Before(now) {
+			expiry = "expired"
+		}
+		s.notification.Dispatch(&models.NotificationEvent{
+			Type:    "VendorDocumentExpiring",
+			Message: fmt.Sprintf("%s %s of %s %s on %s", document.Type, document.Number, document.Vendor.CompanyName, expiry, document.ExpiryDate.Format("02 Jan 2006")),
+			UserIDs: adminIDs,
+		})
+		flagged = append(flagged, document.ID)
+	}
+	return s.repo.MarkExpiryNotified(flagged, now)
+}
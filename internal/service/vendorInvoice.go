@@ -0,0 +1,395 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Standard Indonesian PPN (VAT) percentage applied when an invoice doesn't specify a rate and no PPN rule is in effect
+const StandardPPNRate = 11.0
+
+// Returned when the NPWP on an invoice doesn't match the vendor's NPWP
+var ErrNPWPMismatch = errors.New("invoice NPWP doesn't match the vendor's NPWP")
+
+// Returned when a payment exceeds an invoice's outstanding balance
+var ErrOverpayment = errors.New("payment exceeds the invoice's outstanding balance")
+
+// Returned when changing the line items of an invoice with recorded payments
+var ErrInvoiceHasPayments = errors.New("line items can't be changed once payments are recorded")
+
+type VendorInvoiceService interface {
+	FindAll(int, int, string, int, string) (*[]db.VendorInvoice, error)
+	FindById(int) (*db.VendorInvoice, error)
+	Create(int, *models.CreateVendorInvoice) (*db.VendorInvoice, error)
+	Update(int, *models.UpdateVendorInvoice) (*db.VendorInvoice, error)
+	Delete(int) error
+	// Records a (partial) payment against an invoice
+	RecordPayment(int, int, *models.RecordVendorPayment) (*db.VendorInvoice, error)
+	// Groups outstanding balances per vendor by days past due
+	Aging(time.Time, int, string) (*models.PayablesAging, error)
+}
+
+type vendorInvoiceService struct {
+	repo       repository.VendorInvoiceRepository
+	vendors    repository.VendorRepository
+	workOrders repository.WorkOrderRepository
+	requests   repository.MaintenanceRequestRepository
+	rates      ExchangeRateService
+	taxes      TaxService
+	orders     WorkOrderService
+}
+
+func NewVendorInvoiceService(repo repository.VendorInvoiceRepository, vendors repository.VendorRepository, workOrders repository.WorkOrderRepository, requests repository.MaintenanceRequestRepository, rates ExchangeRateService, taxes TaxService, orders WorkOrderService) VendorInvoiceService {
+	return &vendorInvoiceService{repo, vendors, workOrders, requests, rates, taxes, orders}
+}
+
+// Creates a vendor invoice for a work order or maintenance request after validating the vendor's NPWP. A completed
+// work order moves to Invoiced
+func (s *vendorInvoiceService) Create(userId int, invoice *models.CreateVendorInvoice) (*db.VendorInvoice, error) {
+	invoiceToCreate := db.VendorInvoice{
+		InvoiceNumber: invoice.InvoiceNumber,
+		VendorNPWP:    invoice.VendorNPWP,
+		InvoiceDate:   invoice.InvoiceDate,
+		DueDate:       invoice.DueDate,
+		Status:        "Unpaid",
+		Notes:         invoice.Notes,
+	}
+	// Tax rules in effect on the invoice date
+	rules, err := s.taxes.Rules("Vendor Invoice", invoice.InvoiceDate)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.PPNRate != nil {
+		invoiceToCreate.PPNRate = *invoice.PPNRate
+	} else {
+		invoiceToCreate.PPNRate = StandardPPNRate
+		if rule, found := findTaxRule(rules, "PPN"); found {
+			invoiceToCreate.PPNRate = rule.Rate
+		}
+	}
+
+	// Find what is being invoiced. Vendor defaults to the one assigned
+	vendorId := invoice.Vendor.ID
+	switch {
+	case invoice.WorkOrder.ID != 0:
+		workOrder, err := s.workOrders.FindById(int(invoice.WorkOrder.ID))
+		if err != nil {
+			return nil, fmt.Errorf("work order not found: %w", err)
+		}
+		if vendorId == 0 {
+			vendorId = workOrder.VendorID
+		}
+		if vendorId != workOrder.VendorID {
+			return nil, fmt.Errorf("work order %d was issued to another vendor", workOrder.ID)
+		}
+		invoiceToCreate.WorkOrderID = &workOrder.ID
+		invoiceToCreate.MaintenanceRequestID = &workOrder.MaintenanceRequestID
+	case invoice.MaintenanceRequest.ID != 0:
+		request, err := s.requests.FindById(int(invoice.MaintenanceRequest.ID))
+		if err != nil {
+			return nil, fmt.Errorf("maintenance request not found: %w", err)
+		}
+		if vendorId == 0 && request.VendorID != nil {
+			vendorId = *request.VendorID
+		}
+		invoiceToCreate.MaintenanceRequestID = &request.ID
+	default:
+		return nil, errors.New("invoice requires a work order or maintenance request")
+	}
+
+	// Invoice must be issued under the vendor's NPWP
+	vendor, err := s.vendors.FindById(int(vendorId))
+	if err != nil {
+		return nil, fmt.Errorf("vendor not found: %w", err)
+	}
+	if !helpers.SameNPWP(invoice.VendorNPWP, vendor.NPWP) {
+		return nil, ErrNPWPMismatch
+	}
+	invoiceToCreate.VendorID = vendor.ID
+
+	invoiceToCreate.Lines = buildInvoiceLines(invoice.Lines)
+	err = calculateInvoiceTotals(&invoiceToCreate, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create invoice in database
+	createdInvoice, err := s.repo.Create(&invoiceToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating vendor invoice: %w", err)
+	}
+	s.advanceWorkOrder(createdInvoice, userId, "Completed", "Invoiced")
+	return s.repo.FindById(int(createdInvoice.ID))
+}
+
+// Find a list of vendor invoices. Filters by vendor and status if provided
+func (s *vendorInvoiceService) FindAll(limit int, offset int, order string, vendorId int, status string) (*[]db.VendorInvoice, error) {
+	invoices, err := s.repo.FindAll(limit, offset, order, vendorId, status)
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// Find vendor invoice in database by ID
+func (s *vendorInvoiceService) FindById(id int) (*db.VendorInvoice, error) {
+	// Find invoice by id
+	invoice, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return invoice, nil
+}
+
+// Delete vendor invoice in database
+func (s *vendorInvoiceService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting vendor invoice: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates vendor invoice in database. Totals are recalculated when line items are replaced
+func (s *vendorInvoiceService) Update(id int, invoice *models.UpdateVendorInvoice) (*db.VendorInvoice, error) {
+	// Find existing invoice
+	foundInvoice, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new invoice from DTO
+	invoiceToUpdate := &db.VendorInvoice{
+		InvoiceNumber: invoice.InvoiceNumber,
+		InvoiceDate:   invoice.InvoiceDate,
+		DueDate:       invoice.DueDate,
+		Notes:         invoice.Notes,
+	}
+	if len(invoice.Lines) > 0 {
+		if len(foundInvoice.Payments) > 0 {
+			return nil, ErrInvoiceHasPayments
+		}
+		invoiceToUpdate.Lines = buildInvoiceLines(invoice.Lines)
+		invoiceToUpdate.PPNRate = foundInvoice.PPNRate
+		if invoiceToUpdate.InvoiceDate.IsZero() {
+			invoiceToUpdate.InvoiceDate = foundInvoice.InvoiceDate
+		}
+		rules, err := s.taxes.Rules("Vendor Invoice", invoiceToUpdate.InvoiceDate)
+		if err != nil {
+			return nil, err
+		}
+		err = calculateInvoiceTotals(invoiceToUpdate, rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Update using repo
+	updatedInvoice, err := s.repo.Update(id, invoiceToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedInvoice, nil
+}
+
+// Records a (partial) payment against an invoice, marking it paid once the total less withholding is covered. An
+// invoiced work order moves to Paid once its invoice is paid
+func (s *vendorInvoiceService) RecordPayment(id int, userId int, payment *models.RecordVendorPayment) (*db.VendorInvoice, error) {
+	// Find invoice being paid
+	invoice, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	// Payments are made in the invoice's currency
+	if !payment.Amount.SameCurrency(invoice.Total) {
+		return nil, fmt.Errorf("%w: payment must be in %s", db.ErrCurrencyMismatch, invoice.Total.Code())
+	}
+	paid := invoice.AmountPaid.Add(payment.Amount)
+	if payment.Amount.Amount <= 0 || paid.Amount > payable(invoice).Amount {
+		return nil, ErrOverpayment
+	}
+
+	paymentToCreate := db.VendorPayment{
+		Amount:    payment.Amount,
+		PaidAt:    payment.PaidAt,
+		Method:    payment.Method,
+		Reference: payment.Reference,
+	}
+	if paymentToCreate.PaidAt.IsZero() {
+		paymentToCreate.PaidAt = time.Now()
+	}
+
+	invoice.AmountPaid = paid
+	invoice.Status = "Partially Paid"
+	if invoice.AmountPaid.Amount >= payable(invoice).Amount {
+		invoice.Status = "Paid"
+	}
+	err = s.repo.AddPayment(invoice, &paymentToCreate)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status == "Paid" {
+		s.advanceWorkOrder(invoice, userId, "Invoiced", "Paid")
+	}
+	return s.repo.FindById(id)
+}
+
+// Groups outstanding balances per vendor by days past due as of a date, converted into the reporting currency at
+// the rates effective on that date. Filters by vendor if id is not 0
+func (s *vendorInvoiceService) Aging(asOf time.Time, vendorId int, currency string) (*models.PayablesAging, error) {
+	invoices, err := s.repo.FindOutstanding(vendorId)
+	if err != nil {
+		return nil, err
+	}
+
+	zero := db.Money{Currency: currency}
+	report := models.PayablesAging{AsOf: asOf, Currency: currency, Vendors: []models.VendorAging{},
+		Totals: models.PayablesAgingBucket{Current: zero, Days30: zero, Days60: zero, Days90: zero, Over90: zero, Total: zero}}
+	// Track vendor positions by vendor id
+	vendorIndex := make(map[uint]int)
+	for _, invoice := range *invoices {
+		i, ok := vendorIndex[invoice.VendorID]
+		if !ok {
+			report.Vendors = append(report.Vendors, models.VendorAging{VendorID: invoice.VendorID, CompanyName: invoice.Vendor.CompanyName})
+			i = len(report.Vendors) - 1
+			vendorIndex[invoice.VendorID] = i
+		}
+		outstanding, err := s.rates.Convert(payable(&invoice).Sub(invoice.AmountPaid), currency, asOf)
+		if err != nil {
+			return nil, err
+		}
+		daysPastDue := int(asOf.Sub(invoice.DueDate).Hours() / 24)
+		report.Vendors[i].Invoices++
+		err = addToAgingBucket(&report.Vendors[i].PayablesAgingBucket, daysPastDue, outstanding)
+		if err != nil {
+			return nil, err
+		}
+		err = addToAgingBucket(&report.Totals, daysPastDue, outstanding)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &report, nil
+}
+
+// Adds an outstanding amount to its bucket by days past due. Buckets hold a single currency
+// Moves an invoice's work order from one status to the next, logging the change on its parent task. Work orders in
+// any other status are left as they are
+func (s *vendorInvoiceService) advanceWorkOrder(invoice *db.VendorInvoice, userId int, from string, to string) {
+	if invoice.WorkOrderID == nil {
+		return
+	}
+	order, err := s.workOrders.FindById(int(*invoice.WorkOrderID))
+	if err != nil || order.Status != from {
+		return
+	}
+	_, err = s.orders.UpdateStatus(int(order.ID), userId, &models.UpdateWorkOrderStatus{Status: to})
+	if err != nil {
+		fmt.Println("error in advancing invoiced work order: ", err)
+	}
+}
+
+func addToAgingBucket(bucket *models.PayablesAgingBucket, daysPastDue int, amount db.Money) error {
+	total, err := db.Sum(bucket.Total, amount)
+	if err != nil {
+		return err
+	}
+	switch {
+	case daysPastDue <= 0:
+		bucket.Current = bucket.Current.Add(amount)
+	case daysPastDue <= 30:
+		bucket.Days30 = bucket.Days30.Add(amount)
+	case daysPastDue <= 60:
+		bucket.Days60 = bucket.Days60.Add(amount)
+	case daysPastDue <= 90:
+		bucket.Days90 = bucket.Days90.Add(amount)
+	default:
+		bucket.Over90 = bucket.Over90.Add(amount)
+	}
+	bucket.Total = total
+	return nil
+}
+
+// Builds invoice line items from DTO, calculating line amounts
+func buildInvoiceLines(lines []models.VendorInvoiceLine) []db.VendorInvoiceLine {
+	invoiceLines := []db.VendorInvoiceLine{}
+	for _, line := range lines {
+		invoiceLines = append(invoiceLines, db.VendorInvoiceLine{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Amount:      line.UnitPrice.Mul(line.Quantity),
+			PPNExempt:   line.PPNExempt,
+		})
+	}
+	return invoiceLines
+}
+
+// Calculates an invoice's subtotal, PPN (on lines that aren't exempt), total and income tax withheld under the tax
+// rules in effect on the invoice date, along with its tax lines. Lines must share a currency
+func calculateInvoiceTotals(invoice *db.VendorInvoice, rules []db.TaxRule) error {
+	amounts, taxable := []db.Money{}, []db.Money{}
+	for _, line := range invoice.Lines {
+		amounts = append(amounts, line.Amount)
+		if !line.PPNExempt {
+			taxable = append(taxable, line.Amount)
+		}
+	}
+	subtotal, err := db.Sum(amounts...)
+	if err != nil {
+		return err
+	}
+	taxableTotal, _ := db.Sum(taxable...)
+	invoice.Subtotal = subtotal
+	invoice.PPN = taxableTotal.Percent(invoice.PPNRate)
+	invoice.Total = invoice.Subtotal.Add(invoice.PPN)
+
+	invoice.Withholding = db.Money{Currency: subtotal.Currency}
+	invoice.TaxLines = []db.TaxLine{}
+	if !invoice.PPN.IsZero() {
+		line := db.TaxLine{Tax: "PPN", AppliesTo: "Vendor Invoice", Rate: invoice.PPNRate, TaxDate: invoice.InvoiceDate, Base: taxableTotal, Amount: invoice.PPN}
+		// Only linked to the PPN rule when the invoice's rate wasn't overridden
+		if rule, found := findTaxRule(rules, "PPN"); found && rule.Rate == invoice.PPNRate {
+			line.TaxRuleID = &rule.ID
+		}
+		invoice.TaxLines = append(invoice.TaxLines, line)
+	}
+	// Income tax is charged on the subtotal, excluding PPN
+	for _, rule := range rules {
+		if rule.Tax == "PPN" || rule.Rate == 0 || subtotal.IsZero() {
+			continue
+		}
+		line := taxLine(rule, subtotal, invoice.InvoiceDate)
+		if line.Withheld {
+			invoice.Withholding = invoice.Withholding.Add(line.Amount)
+		}
+		invoice.TaxLines = append(invoice.TaxLines, line)
+	}
+	return nil
+}
+
+// Amount payable to the vendor, being the invoice's total less income tax withheld
+func payable(invoice *db.VendorInvoice) db.Money {
+	return invoice.Total.Sub(invoice.Withholding)
+}
+
+// Finds the rule of a tax among rules in effect
+func findTaxRule(rules []db.TaxRule, tax string) (db.TaxRule, bool) {
+	for _, rule := range rules {
+		if rule.Tax == tax {
+			return rule, true
+		}
+	}
+	return db.TaxRule{}, false
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when changing a quote that has already been accepted or rejected
+var ErrQuoteClosed = errors.New("vendor quote has already been accepted or rejected")
+
+// Returned when accepting a quote that hasn't been submitted or has expired
+var ErrQuoteNotAcceptable = errors.New("only submitted quotes that haven't expired can be accepted")
+
+type VendorQuoteService interface {
+	FindAll(int, int, string, int, int) (*[]db.VendorQuote, error)
+	FindById(int) (*db.VendorQuote, error)
+	Update(int, *models.UpdateVendorQuote) (*db.VendorQuote, error)
+	Delete(int) error
+	// Invites vendors to quote on a maintenance request
+	Invite(*models.InviteVendorQuotes) (*[]db.VendorQuote, error)
+	// Compares a maintenance request's quotes side by side in a currency
+	Compare(int, string) (*models.VendorQuoteComparison, error)
+	// Accepts a quote, assigning the vendor and cost to the maintenance request
+	Accept(int) (*db.VendorQuote, error)
+}
+
+type vendorQuoteService struct {
+	repo        repository.VendorQuoteRepository
+	requests    repository.MaintenanceRequestRepository
+	vendors     repository.VendorRepository
+	attachments repository.PropertyAttachmentRepository
+	compliance  VendorDocumentService
+	rates       ExchangeRateService
+}
+
+func NewVendorQuoteService(repo repository.VendorQuoteRepository, requests repository.MaintenanceRequestRepository, vendors repository.VendorRepository, attachments repository.PropertyAttachmentRepository, compliance VendorDocumentService, rates ExchangeRateService) VendorQuoteService {
+	return &vendorQuoteService{repo, requests, vendors, attachments, compliance, rates}
+}
+
+// Invites vendors offering the maintenance request's work type to quote. Vendors already invited are skipped
+func (s *vendorQuoteService) Invite(invite *models.InviteVendorQuotes) (*[]db.VendorQuote, error) {
+	// Find maintenance request to quote on
+	request, err := s.requests.FindById(int(invite.MaintenanceRequest.ID))
+	if err != nil {
+		return nil, fmt.Errorf("maintenance request not found: %w", err)
+	}
+	if request.WorkTypeID == 0 {
+		return nil, fmt.Errorf("maintenance request %d has no work type to invite vendors for", request.ID)
+	}
+
+	// Find vendors offering the work type
+	matchingVendors, err := s.vendors.FindByWorkType(int(request.WorkTypeID))
+	if err != nil {
+		return nil, err
+	}
+	matching := make(map[uint]bool)
+	for _, vendor := range *matchingVendors {
+		matching[vendor.ID] = true
+	}
+
+	// Invite all matching vendors if none selected
+	vendorIds := []uint{}
+	if len(invite.Vendors) == 0 {
+		for _, vendor := range *matchingVendors {
+			vendorIds = append(vendorIds, vendor.ID)
+		}
+	}
+	for _, vendor := range invite.Vendors {
+		if !matching[vendor.ID] {
+			return nil, fmt.Errorf("vendor %d doesn't offer the maintenance request's work type", vendor.ID)
+		}
+		vendorIds = append(vendorIds, vendor.ID)
+	}
+
+	// Skip vendors already invited
+	invitedIds, err := s.repo.FindInvitedVendorIds(int(request.ID))
+	if err != nil {
+		return nil, err
+	}
+	invited := make(map[uint]bool)
+	for _, id := range invitedIds {
+		invited[id] = true
+	}
+
+	createdQuotes := []db.VendorQuote{}
+	for _, vendorId := range vendorIds {
+		if invited[vendorId] {
+			continue
+		}
+		invited[vendorId] = true
+		createdQuote, err := s.repo.Create(&db.VendorQuote{
+			Status:               "Invited",
+			MaintenanceRequestID: request.ID,
+			VendorID:             vendorId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		createdQuotes = append(createdQuotes, *createdQuote)
+	}
+	return &createdQuotes, nil
+}
+
+// Find a list of vendor quotes. Filters by maintenance request and vendor if ids are not 0
+func (s *vendorQuoteService) FindAll(limit int, offset int, order string, maintenanceId int, vendorId int) (*[]db.VendorQuote, error) {
+	quotes, err := s.repo.FindAll(limit, offset, order, maintenanceId, vendorId)
+	if err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// Find vendor quote in database by ID
+func (s *vendorQuoteService) FindById(id int) (*db.VendorQuote, error) {
+	// Find quote by id
+	quote, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return quote, nil
+}
+
+// Delete vendor quote in database
+func (s *vendorQuoteService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting vendor quote: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Records the vendor's quote. Invited quotes are marked submitted once an amount is recorded
+func (s *vendorQuoteService) Update(id int, quote *models.UpdateVendorQuote) (*db.VendorQuote, error) {
+	// Find existing quote
+	foundQuote, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if foundQuote.Status == "Accepted" || foundQuote.Status == "Rejected" {
+		return nil, ErrQuoteClosed
+	}
+
+	// Create a new quote from DTO
+	quoteToUpdate := &db.VendorQuote{
+		Amount:     quote.Amount,
+		Tax:        quote.Tax,
+		ValidUntil: quote.ValidUntil,
+		Notes:      quote.Notes,
+	}
+	if !quote.Amount.SameCurrency(quote.Tax) {
+		return nil, fmt.Errorf("%w: tax must be in %s", db.ErrCurrencyMismatch, quote.Amount.Code())
+	}
+	if !quote.Amount.IsZero() {
+		quoteToUpdate.Status = "Submitted"
+	}
+
+	// Attachments must belong to the maintenance request's property
+	if quote.Attachments != nil {
+		quoteToUpdate.Attachments = []db.PropertyAttachment{}
+		for _, attachment := range quote.Attachments {
+			foundAttachment, err := s.attachments.FindById(int(attachment.ID))
+			if err != nil {
+				return nil, fmt.Errorf("attachment not found: %w", err)
+			}
+			if foundAttachment.PropertyID != foundQuote.MaintenanceRequest.PropertyID {
+				return nil, fmt.Errorf("attachment %d doesn't belong to the maintenance request's property", attachment.ID)
+			}
+			quoteToUpdate.Attachments = append(quoteToUpdate.Attachments, *foundAttachment)
+		}
+	}
+
+	// Update using repo
+	updatedQuote, err := s.repo.Update(id, quoteToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedQuote, nil
+}
+
+// Compares a maintenance request's quotes side by side, cheapest first. Quotes in other currencies are ranked by their
+// total converted into the currency at today's rate. Quotes without an amount are listed last
+func (s *vendorQuoteService) Compare(maintenanceId int, currency string) (*models.VendorQuoteComparison, error) {
+	// Ensure maintenance request exists
+	request, err := s.requests.FindById(maintenanceId)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := s.repo.FindAll(0, 0, "", maintenanceId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := models.VendorQuoteComparison{MaintenanceRequestID: request.ID, Currency: currency, Quotes: []models.VendorQuoteSummary{}}
+	now := time.Now()
+	for _, quote := range *quotes {
+		total := quote.Amount.Add(quote.Tax)
+		comparableTotal, err := s.rates.Convert(total, currency, now)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Quotes = append(comparison.Quotes, models.VendorQuoteSummary{
+			ID:              quote.ID,
+			VendorID:        quote.VendorID,
+			CompanyName:     quote.Vendor.CompanyName,
+			Status:          quote.Status,
+			Amount:          quote.Amount,
+			Tax:             quote.Tax,
+			Total:           total,
+			ComparableTotal: comparableTotal,
+			ValidUntil:      quote.ValidUntil,
+			Attachments:     len(quote.Attachments),
+			Expired:         quoteExpired(&quote, now),
+		})
+	}
+	sort.SliceStable(comparison.Quotes, func(i, j int) bool {
+		a, b := comparison.Quotes[i], comparison.Quotes[j]
+		if a.Amount.IsZero() != b.Amount.IsZero() {
+			return b.Amount.IsZero()
+		}
+		return a.ComparableTotal.Amount < b.ComparableTotal.Amount
+	})
+
+	// Flag cheapest quote that can be accepted
+	for i := range comparison.Quotes {
+		if comparison.Quotes[i].Status == "Submitted" && !comparison.Quotes[i].Expired {
+			comparison.Quotes[i].Lowest = true
+			break
+		}
+	}
+	return &comparison, nil
+}
+
+// Accepts a submitted quote. The request's other open quotes are rejected and the vendor and cost are set on the request. Compliance issues of the vendor are returned as warnings
+func (s *vendorQuoteService) Accept(id int) (*db.VendorQuote, error) {
+	// Find quote to accept
+	quote, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status == "Accepted" || quote.Status == "Rejected" {
+		return nil, ErrQuoteClosed
+	}
+	if quote.Status != "Submitted" || quoteExpired(quote, time.Now()) {
+		return nil, ErrQuoteNotAcceptable
+	}
+
+	err = s.repo.Accept(quote)
+	if err != nil {
+		return nil, err
+	}
+
+	acceptedQuote, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	// Warn if the vendor now assigned to the request isn't compliant
+	acceptedQuote.ComplianceWarnings = s.compliance.CheckAssignment(int(acceptedQuote.VendorID), acceptedQuote.MaintenanceRequest.TaskID)
+	return acceptedQuote, nil
+}
+
+// Returns true if the quote's validity date has passed
+func quoteExpired(quote *db.VendorQuote, now time.Time) bool {
+	return !quote.ValidUntil.IsZero() && quote.ValidUntil.Before(now)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when rating a vendor that hasn't completed work on a maintenance request
+var ErrRequestNotCompleted = errors.New("vendor has no completed work order for the maintenance request")
+
+// Returned when a vendor has already been rated for a maintenance request
+var ErrAlreadyRated = errors.New("vendor has already been rated for the maintenance request")
+
+type VendorRatingService interface {
+	FindAll(int, int, string, int, int) (*[]db.VendorRating, error)
+	FindById(int) (*db.VendorRating, error)
+	Create(int, *models.CreateVendorRating) (*db.VendorRating, error)
+	Update(int, *models.UpdateVendorRating) (*db.VendorRating, error)
+	Delete(int) error
+	// Summarizes a vendor's ratings, response and completion times and cost variance since a time
+	Scorecard(int, time.Time) (*models.VendorScorecard, error)
+}
+
+type vendorRatingService struct {
+	repo       repository.VendorRatingRepository
+	vendors    repository.VendorRepository
+	requests   repository.MaintenanceRequestRepository
+	workOrders repository.WorkOrderRepository
+	quotes     repository.VendorQuoteRepository
+	invoices   repository.VendorInvoiceRepository
+}
+
+func NewVendorRatingService(repo repository.VendorRatingRepository, vendors repository.VendorRepository, requests repository.MaintenanceRequestRepository, workOrders repository.WorkOrderRepository, quotes repository.VendorQuoteRepository, invoices repository.VendorInvoiceRepository) VendorRatingService {
+	return &vendorRatingService{repo, vendors, requests, workOrders, quotes, invoices}
+}
+
+// Rates a vendor's work on a maintenance request once they have completed a work order for it
+func (s *vendorRatingService) Create(userId int, rating *models.CreateVendorRating) (*db.VendorRating, error) {
+	// Find request being rated. Vendor defaults to the one assigned
+	request, err := s.requests.FindById(int(rating.MaintenanceRequest.ID))
+	if err != nil {
+		return nil, fmt.Errorf("maintenance request not found: %w", err)
+	}
+	vendorId := rating.Vendor.ID
+	if vendorId == 0 && request.VendorID != nil {
+		vendorId = *request.VendorID
+	}
+	if vendorId == 0 {
+		return nil, fmt.Errorf("maintenance request %d has no vendor to rate", request.ID)
+	}
+
+	// Vendor must have completed work on the request
+	orders, err := s.workOrders.FindAll(0, 0, "", int(request.ID), int(vendorId), "")
+	if err != nil {
+		return nil, err
+	}
+	completed := false
+	for _, order := range *orders {
+		if workOrderStatusIndex(order.Status) >= workOrderStatusIndex("Completed") {
+			completed = true
+		}
+	}
+	if !completed {
+		return nil, ErrRequestNotCompleted
+	}
+	existing, err := s.repo.FindAll(1, 0, "", int(vendorId), int(request.ID))
+	if err != nil {
+		return nil, err
+	}
+	if len(*existing) > 0 {
+		return nil, ErrAlreadyRated
+	}
+
+	ratingToCreate := db.VendorRating{
+		Quality:              rating.Quality,
+		Timeliness:           rating.Timeliness,
+		Price:                rating.Price,
+		Communication:        rating.Communication,
+		Comment:              rating.Comment,
+		MaintenanceRequestID: request.ID,
+		VendorID:             vendorId,
+		UserID:               uint(userId),
+	}
+
+	// Create rating in database
+	createdRating, err := s.repo.Create(&ratingToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating vendor rating: %w", err)
+	}
+	return s.repo.FindById(int(createdRating.ID))
+}
+
+// Find a list of vendor ratings. Filters by vendor and maintenance request if provided
+func (s *vendorRatingService) FindAll(limit int, offset int, order string, vendorId int, maintenanceId int) (*[]db.VendorRating, error) {
+	ratings, err := s.repo.FindAll(limit, offset, order, vendorId, maintenanceId)
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// Find vendor rating in database by ID
+func (s *vendorRatingService) FindById(id int) (*db.VendorRating, error) {
+	// Find rating by id
+	rating, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return rating, nil
+}
+
+// Delete vendor rating in database
+func (s *vendorRatingService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting vendor rating: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates vendor rating in database
+func (s *vendorRatingService) Update(id int, rating *models.UpdateVendorRating) (*db.VendorRating, error) {
+	// Create a new rating from DTO
+	ratingToUpdate := &db.VendorRating{
+		Quality:       rating.Quality,
+		Timeliness:    rating.Timeliness,
+		Price:         rating.Price,
+		Communication: rating.Communication,
+		Comment:       rating.Comment,
+	}
+
+	// Update using repo
+	updatedRating, err := s.repo.Update(id, ratingToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedRating, nil
+}
+
+// Summarizes a vendor's ratings (overall and per work type), response and completion times of work orders
+// and variance of invoiced amounts against accepted quotes since a time
+func (s *vendorRatingService) Scorecard(vendorId int, since time.Time) (*models.VendorScorecard, error) {
+	vendor, err := s.vendors.FindById(vendorId)
+	if err != nil {
+		return nil, err
+	}
+	scorecard := models.VendorScorecard{VendorID: vendor.ID, CompanyName: vendor.CompanyName, Since: since, WorkTypes: []models.VendorWorkTypeScore{}}
+
+	// Average ratings overall and per work type
+	ratings, err := s.repo.FindByVendorSince(vendorId, since)
+	if err != nil {
+		return nil, err
+	}
+	workTypeRatings := make(map[uint][]db.VendorRating)
+	for _, rating := range *ratings {
+		workType := rating.MaintenanceRequest.WorkType
+		if _, ok := workTypeRatings[workType.ID]; !ok {
+			scorecard.WorkTypes = append(scorecard.WorkTypes, models.VendorWorkTypeScore{WorkTypeID: workType.ID, Name: workType.Name})
+		}
+		workTypeRatings[workType.ID] = append(workTypeRatings[workType.ID], rating)
+	}
+	scorecard.VendorRatingAverages = averageVendorRatings(*ratings)
+	for i, workType := range scorecard.WorkTypes {
+		scorecard.WorkTypes[i].VendorRatingAverages = averageVendorRatings(workTypeRatings[workType.WorkTypeID])
+	}
+	sort.SliceStable(scorecard.WorkTypes, func(i, j int) bool {
+		return scorecard.WorkTypes[i].Name < scorecard.WorkTypes[j].Name
+	})
+
+	// Response and completion times of work orders issued in the period
+	orders, err := s.workOrders.FindAll(0, 0, "", 0, vendorId, "")
+	if err != nil {
+		return nil, err
+	}
+	responseHours, completionHours := []float64{}, []float64{}
+	for _, order := range *orders {
+		if order.CreatedAt.Before(since) {
+			continue
+		}
+		if order.AcceptedAt != nil {
+			responseHours = append(responseHours, order.AcceptedAt.Sub(order.CreatedAt).Hours())
+		}
+		if order.CompletedAt != nil {
+			completionHours = append(completionHours, order.CompletedAt.Sub(order.CreatedAt).Hours())
+		}
+	}
+	scorecard.AverageResponseHours = averageOf(responseHours)
+	scorecard.AverageCompletionHours = averageOf(completionHours)
+
+	// Compare invoiced subtotals against accepted quotes for the same request
+	quotes, err := s.quotes.FindAll(0, 0, "", 0, vendorId)
+	if err != nil {
+		return nil, err
+	}
+	invoices, err := s.invoices.FindAll(0, 0, "", vendorId, "")
+	if err != nil {
+		return nil, err
+	}
+	invoiced := make(map[uint]db.Money)
+	for _, invoice := range *invoices {
+		if invoice.MaintenanceRequestID != nil {
+			invoiced[*invoice.MaintenanceRequestID] = invoiced[*invoice.MaintenanceRequestID].Add(invoice.Subtotal)
+		}
+	}
+	variances := []float64{}
+	for _, quote := range *quotes {
+		actual, ok := invoiced[quote.MaintenanceRequestID]
+		// Quotes invoiced in another currency can't be compared
+		if quote.Status != "Accepted" || quote.Amount.IsZero() || !ok || !actual.SameCurrency(quote.Amount) || quote.CreatedAt.Before(since) {
+			continue
+		}
+		variances = append(variances, float64(actual.Amount-quote.Amount.Amount)/float64(quote.Amount.Amount)*100)
+	}
+	scorecard.QuotesCompared = len(variances)
+	scorecard.CostVariancePercent = averageOf(variances)
+
+	return &scorecard, nil
+}
+
+// Averages each rating score, and all scores combined
+func averageVendorRatings(ratings []db.VendorRating) models.VendorRatingAverages {
+	averages := models.VendorRatingAverages{Ratings: len(ratings)}
+	if len(ratings) == 0 {
+		return averages
+	}
+	for _, rating := range ratings {
+		averages.Quality += float64(rating.Quality)
+		averages.Timeliness += float64(rating.Timeliness)
+		averages.Price += float64(rating.Price)
+		averages.Communication += float64(rating.Communication)
+	}
+	count := float64(len(ratings))
+	averages.Overall = roundTo2((averages.Quality + averages.Timeliness + averages.Price + averages.Communication) / (count * 4))
+	averages.Quality = roundTo2(averages.Quality / count)
+	averages.Timeliness = roundTo2(averages.Timeliness / count)
+	averages.Price = roundTo2(averages.Price / count)
+	averages.Communication = roundTo2(averages.Communication / count)
+	return averages
+}
+
+// Averages values rounded to two decimal places. Zero if empty
+func averageOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return roundTo2(total / float64(len(values)))
+}
+
+// Rounds a value to two decimal places
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
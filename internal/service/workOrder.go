@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/dmawardi/Go-Template/internal/db"
+	"github.com/dmawardi/Go-Template/internal/helpers"
+	"github.com/dmawardi/Go-Template/internal/models"
+	"github.com/dmawardi/Go-Template/internal/repository"
+)
+
+// Returned when a work order status change skips or reverses its lifecycle
+var ErrInvalidWorkOrderTransition = errors.New("work orders can only move to the next status in their lifecycle")
+
+// Returned when a work order's visit window is missing or ends before it starts
+var ErrInvalidVisitWindow = errors.New("a visit window with an end after its start is required to schedule a work order")
+
+type WorkOrderService interface {
+	FindAll(int, int, string, int, int, string) (*[]db.WorkOrder, error)
+	FindById(int) (*db.WorkOrder, error)
+	// Issues a work order, logging it on the parent task as the user
+	Create(int, *models.CreateWorkOrder) (*db.WorkOrder, error)
+	Update(int, *models.UpdateWorkOrder) (*db.WorkOrder, error)
+	Delete(int) error
+	// Moves a work order to its next status, logging the change on the parent task as the user
+	UpdateStatus(int, int, *models.UpdateWorkOrderStatus) (*db.WorkOrder, error)
+	// Renders a printable HTML work order
+	Document(int) ([]byte, error)
+}
+
+type workOrderService struct {
+	repo       repository.WorkOrderRepository
+	requests   repository.MaintenanceRequestRepository
+	vendors    repository.VendorRepository
+	log        TaskLogService
+	compliance VendorDocumentService
+}
+
+func NewWorkOrderService(repo repository.WorkOrderRepository, requests repository.MaintenanceRequestRepository, vendors repository.VendorRepository, log TaskLogService, compliance VendorDocumentService) WorkOrderService {
+	return &workOrderService{repo, requests, vendors, log, compliance}
+}
+
+// Issues a work order for a maintenance request. Vendor, task and cost default to those of the request. Compliance issues of the vendor are returned as warnings
+func (s *workOrderService) Create(userId int, order *models.CreateWorkOrder) (*db.WorkOrder, error) {
+	// Find maintenance request the work is for
+	request, err := s.requests.FindById(int(order.MaintenanceRequest.ID))
+	if err != nil {
+		return nil, fmt.Errorf("maintenance request not found: %w", err)
+	}
+
+	// Use vendor assigned to request if not provided
+	vendorId := order.Vendor.ID
+	if vendorId == 0 && request.VendorID != nil {
+		vendorId = *request.VendorID
+	}
+	if vendorId == 0 {
+		return nil, fmt.Errorf("maintenance request %d has no assigned vendor", request.ID)
+	}
+	vendor, err := s.vendors.FindById(int(vendorId))
+	if err != nil {
+		return nil, fmt.Errorf("vendor not found: %w", err)
+	}
+
+	// Create a new work order from DTO
+	orderToCreate := db.WorkOrder{
+		Description:          order.Description,
+		Status:               "Issued",
+		Cost:                 order.Cost,
+		Tax:                  order.Tax,
+		Notes:                order.Notes,
+		MaintenanceRequestID: request.ID,
+		VendorID:             vendor.ID,
+		TaskID:               request.TaskID,
+	}
+	if order.Cost.IsZero() {
+		orderToCreate.Cost = request.TotalCost
+		orderToCreate.Tax = request.Tax
+	}
+	if !order.ScheduledStart.IsZero() || !order.ScheduledEnd.IsZero() {
+		err = applyVisitWindow(&orderToCreate, order.ScheduledStart, order.ScheduledEnd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create work order in database
+	createdOrder, err := s.repo.Create(&orderToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating work order: %w", err)
+	}
+
+	s.logOnTask(createdOrder.TaskID, userId, fmt.Sprintf("Work order #%d issued to %s", createdOrder.ID, vendor.CompanyName))
+
+	issuedOrder, err := s.repo.FindById(int(createdOrder.ID))
+	if err != nil {
+		return nil, err
+	}
+	// Warn if the vendor isn't compliant
+	issuedOrder.ComplianceWarnings = s.compliance.CheckAssignment(int(vendor.ID), issuedOrder.TaskID)
+	return issuedOrder, nil
+}
+
+// Find a list of work orders. Filters by maintenance request, vendor and status if provided
+func (s *workOrderService) FindAll(limit int, offset int, order string, maintenanceId int, vendorId int, status string) (*[]db.WorkOrder, error) {
+	orders, err := s.repo.FindAll(limit, offset, order, maintenanceId, vendorId, status)
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// Find work order in database by ID
+func (s *workOrderService) FindById(id int) (*db.WorkOrder, error) {
+	// Find work order by id
+	order, err := s.repo.FindById(id)
+	// If error detected
+	if err != nil {
+		return nil, err
+	}
+	// else
+	return order, nil
+}
+
+// Delete work order in database
+func (s *workOrderService) Delete(id int) error {
+	err := s.repo.Delete(id)
+	// If error detected
+	if err != nil {
+		fmt.Println("error in deleting work order: ", err)
+		return err
+	}
+	// else
+	return nil
+}
+
+// Updates work order details in database. Status is changed through UpdateStatus
+func (s *workOrderService) Update(id int, order *models.UpdateWorkOrder) (*db.WorkOrder, error) {
+	// Find existing work order
+	foundOrder, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new work order from DTO
+	orderToUpdate := &db.WorkOrder{
+		Description: order.Description,
+		Cost:        order.Cost,
+		Tax:         order.Tax,
+		Notes:       order.Notes,
+	}
+	// Apply visit window, keeping existing start or end if not provided
+	if !order.ScheduledStart.IsZero() || !order.ScheduledEnd.IsZero() {
+		start, end := order.ScheduledStart, order.ScheduledEnd
+		if start.IsZero() && foundOrder.ScheduledStart != nil {
+			start = *foundOrder.ScheduledStart
+		}
+		if end.IsZero() && foundOrder.ScheduledEnd != nil {
+			end = *foundOrder.ScheduledEnd
+		}
+		err = applyVisitWindow(orderToUpdate, start, end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Update using repo
+	updatedOrder, err := s.repo.Update(id, orderToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return updatedOrder, nil
+}
+
+// Moves a work order to its next status. Scheduled work orders may be rescheduled with a new visit window
+func (s *workOrderService) UpdateStatus(id int, userId int, status *models.UpdateWorkOrderStatus) (*db.WorkOrder, error) {
+	// Find existing work order
+	foundOrder, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rescheduling := foundOrder.Status == "Scheduled" && status.Status == "Scheduled"
+	if !rescheduling && workOrderStatusIndex(status.Status) != workOrderStatusIndex(foundOrder.Status)+1 {
+		return nil, ErrInvalidWorkOrderTransition
+	}
+
+	orderToUpdate := &db.WorkOrder{Status: status.Status}
+	switch status.Status {
+	case "Scheduled":
+		// Use existing visit window if not provided
+		start, end := status.ScheduledStart, status.ScheduledEnd
+		if start.IsZero() && end.IsZero() && foundOrder.ScheduledStart != nil && foundOrder.ScheduledEnd != nil {
+			start, end = *foundOrder.ScheduledStart, *foundOrder.ScheduledEnd
+		}
+		err = applyVisitWindow(orderToUpdate, start, end)
+		if err != nil {
+			return nil, err
+		}
+	case "Accepted":
+		now := time.Now()
+		orderToUpdate.AcceptedAt = &now
+	case "Completed":
+		now := time.Now()
+		orderToUpdate.CompletedAt = &now
+	}
+
+	// Update using repo
+	updatedOrder, err := s.repo.Update(id, orderToUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record status change on parent task
+	message := fmt.Sprintf("Work order #%d status changed from %s to %s", updatedOrder.ID, foundOrder.Status, updatedOrder.Status)
+	if rescheduling {
+		message = fmt.Sprintf("Work order #%d rescheduled", updatedOrder.ID)
+	}
+	if updatedOrder.Status == "Scheduled" {
+		message = fmt.Sprintf("%s for %s - %s", message, updatedOrder.ScheduledStart.Format("02 Jan 2006 15:04"), updatedOrder.ScheduledEnd.Format("02 Jan 2006 15:04"))
+	}
+	s.logOnTask(updatedOrder.TaskID, userId, message)
+
+	return updatedOrder, nil
+}
+
+// Renders a printable HTML work order
+func (s *workOrderService) Document(id int) ([]byte, error) {
+	order, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bytes.Buffer
+	err = workOrderDocument.Execute(&document, order)
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering work order document: %w", err)
+	}
+	return document.Bytes(), nil
+}
+
+// Creates a generated log message on the work order's parent task
+func (s *workOrderService) logOnTask(taskId uint, userId int, message string) {
+	if taskId == 0 {
+		return
+	}
+	_, err := s.log.Create(&models.CreateTaskLog{
+		Task:       db.Task{ID: taskId},
+		User:       db.User{ID: uint(userId)},
+		LogMessage: message,
+		Type:       "GEN",
+	})
+	if err != nil {
+		fmt.Println("error in logging work order on task: ", err)
+	}
+}
+
+// Sets the visit window of a work order after ensuring it ends after it starts
+func applyVisitWindow(order *db.WorkOrder, start time.Time, end time.Time) error {
+	if start.IsZero() || end.IsZero() || !end.After(start) {
+		return ErrInvalidVisitWindow
+	}
+	order.ScheduledStart = &start
+	order.ScheduledEnd = &end
+	return nil
+}
+
+// Returns position of status in work order lifecycle (-1 if unknown)
+func workOrderStatusIndex(status string) int {
+	for i, lifecycleStatus := range models.WorkOrderStatuses {
+		if lifecycleStatus == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// Printable work order
+var workOrderDocument = template.Must(template.New("workOrder").Funcs(template.FuncMap{
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return "To be scheduled"
+		}
+		return t.Format("02 Jan 2006 15:04")
+	},
+	"money": func(amount db.Money) string {
+		return amount.Format()
+	},
+	"add": func(a db.Money, b db.Money) db.Money {
+		return a.Add(b)
+	},
+	"npwp": helpers.FormatNPWP,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>Work Order #{{.ID}}</title>
+	<style>
+		body { font-family: sans-serif; margin: 2em; }
+		table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+		th, td { border: 1px solid #999; padding: 0.4em; text-align: left; vertical-align: top; }
+		.signatures td { height: 4em; width: 50%; }
+	</style>
+</head>
+<body>
+	<h1>Work Order #{{.ID}}</h1>
+	<p>Issued {{.CreatedAt.Format "02 Jan 2006"}} &middot; Status: {{.Status}}</p>
+	<table>
+		<tr><th>Vendor</th><th>Property</th></tr>
+		<tr>
+			<td>
+				{{.Vendor.CompanyName}}<br>
+				NPWP: {{npwp .Vendor.NPWP}}<br>
+				{{with .Vendor.Street_Address_1}}{{.}}<br>{{end}}
+				{{with .Vendor.Phone}}{{.}}<br>{{end}}
+				{{with .Vendor.Email}}{{.}}{{end}}
+			</td>
+			<td>
+				{{.MaintenanceRequest.Property.Property_Name}}<br>
+				{{.MaintenanceRequest.Property.Street_Address_1}}<br>
+				{{with .MaintenanceRequest.Property.Street_Address_2}}{{.}}<br>{{end}}
+				{{.MaintenanceRequest.Property.Suburb}}, {{.MaintenanceRequest.Property.City}} {{.MaintenanceRequest.Property.Postcode}}
+			</td>
+		</tr>
+	</table>
+	<table>
+		<tr><th>Work requested</th></tr>
+		<tr><td>{{.MaintenanceRequest.WorkDefinition}} ({{.MaintenanceRequest.Type}}, {{.MaintenanceRequest.Scale}})<br>{{.Description}}</td></tr>
+		{{with .Notes}}<tr><td>Notes: {{.}}</td></tr>{{end}}
+	</table>
+	<table>
+		<tr><th>Visit from</th><th>Visit until</th></tr>
+		<tr><td>{{date .ScheduledStart}}</td><td>{{date .ScheduledEnd}}</td></tr>
+	</table>
+	<table>
+		<tr><th>Cost</th><td>{{money .Cost}}</td></tr>
+		<tr><th>Tax</th><td>{{money .Tax}}</td></tr>
+		<tr><th>Total</th><td>{{money (add .Cost .Tax)}}</td></tr>
+	</table>
+	<table class="signatures">
+		<tr><th>Issued by</th><th>Accepted by vendor</th></tr>
+		<tr><td></td><td></td></tr>
+	</table>
+</body>
+</html>
+`))